@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+
+	"sft/internal/config"
+	"sft/internal/logfile"
+	"sft/internal/middleware"
+)
+
+// withAccessLog wraps h in the access log cfg.LogFormat selects, written to
+// cfg.AccessLogPath or stdout. It sits outside the reloadable handler, so
+// it logs every response, redirects included, and keeps one log file open
+// across reloads; changing it needs a restart. The returned func closes
+// the log file.
+func withAccessLog(cfg config.Config, h http.Handler) (http.Handler, func(), error) {
+	if cfg.LogFormat == "" || cfg.LogFormat == config.LogFormatOff {
+		return h, func() {}, nil
+	}
+
+	var out io.Writer = os.Stdout
+	closeLog := func() {}
+	if cfg.AccessLogPath != "" {
+		f, err := logfile.Open(cfg.AccessLogPath, cfg.AccessLogMB<<20, cfg.AccessLogKeep)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, closeLog = f, func() { _ = f.Close() }
+	}
+
+	accessLog, err := middleware.AccessLog(cfg.LogFormat, out)
+	if err != nil {
+		closeLog()
+		return nil, nil, err
+	}
+	return accessLog(h), closeLog, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"sft/internal/services"
+)
+
+// runDiff implements `sft diff OLD NEW`, printing what changed between two
+// set data files. Like diff(1), it exits 0 when they match, 1 when they
+// differ and 2 on trouble.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the diff as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sft diff [-json] OLD.json NEW.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	diff, err := services.DiffSetFiles(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 2
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(diff)
+	} else {
+		printDiff(os.Stdout, diff)
+	}
+	if diff.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// printDiff writes diff in a patch-notes-like text form.
+func printDiff(w io.Writer, diff *services.SetDiff) {
+	for _, u := range diff.Added {
+		fmt.Fprintf(w, "+ %s (%d)\n", u.Name, u.Cost)
+	}
+	for _, u := range diff.Removed {
+		fmt.Fprintf(w, "- %s (%d)\n", u.Name, u.Cost)
+	}
+	for _, u := range diff.Changed {
+		fmt.Fprintf(w, "~ %s (%d)\n", u.Name, u.Cost)
+		for _, c := range u.Stats {
+			fmt.Fprintf(w, "    %-20s %s => %s\n", c.Field, orNone(c.Old), orNone(c.New))
+		}
+		for _, c := range u.Ability {
+			fmt.Fprintf(w, "    ability %-12s %s => %s\n", c.Field, orNone(c.Old), orNone(c.New))
+		}
+	}
+	fmt.Fprintf(w, "%s => %s: %d added, %d removed, %d changed\n",
+		diff.From, diff.To, len(diff.Added), len(diff.Removed), len(diff.Changed))
+}
+
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
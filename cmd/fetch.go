@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"sft/internal/httpx"
+	"sft/internal/services"
+)
+
+// runFetch implements `sft fetch`, downloading the newest set data, its
+// art and the team planner mapping from CommunityDragon into the
+// configured data path, asset directories and planner path. It takes serve's config flags and returns the process exit
+// code.
+func runFetch(args []string) int {
+	var (
+		fetch    services.CDragonFetch
+		out      string
+		noAssets bool
+		force    bool
+	)
+	cfg, err := loadConfig("fetch", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&fetch.Root, "root", services.CDragonRoot, "CommunityDragon mirror")
+		fs.StringVar(&fetch.Version, "patch", "latest", "patch directory, e.g. 15.1")
+		fs.StringVar(&fetch.Locale, "locale", "en_us", "set data language")
+		fs.IntVar(&fetch.Set, "set", 0, "set number; 0 fetches the newest")
+		fs.StringVar(&out, "out", "", "set file to write; defaults to -data")
+		fs.BoolVar(&noAssets, "no-assets", false, "write the set file only")
+		fs.BoolVar(&force, "force", false, "replace art already on disk")
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errBadFlags):
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+		return 1
+	}
+	if out == "" {
+		out = cfg.SetDataPath
+	}
+	fetch.Client = httpx.NewHTTPClient(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	set, err := fetch.SetData(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+		return 1
+	}
+	if err := services.WriteSetFile(out, set); err != nil {
+		fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "%s: set %d, %d champions, %d traits\n", out, set.Set, len(set.Champions), len(set.Traits))
+	if cfg.PlannerPath != "" {
+		n, err := fetch.TeamPlanner(ctx, cfg.PlannerPath, cfg.PlannerSet)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fetch: team planner: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stdout, "%s: %d %s champions\n", cfg.PlannerPath, n, cfg.PlannerSet)
+	}
+	if noAssets {
+		return 0
+	}
+
+	dirs := map[string]string{"unit": cfg.UnitAssetsDir, "spell": cfg.SpellAssetsDir, "trait": cfg.TraitAssetsDir}
+	var saved, failed int
+	for _, asset := range set.Assets {
+		ok, err := fetch.Download(ctx, asset, dirs[asset.Kind], force)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "fetch: %s %s: %v\n", asset.Kind, asset.Name, err)
+		case ok:
+			saved++
+		}
+		if ctx.Err() != nil {
+			return 1
+		}
+	}
+	fmt.Fprintf(os.Stdout, "assets: %d downloaded, %d kept, %d failed\n", saved, len(set.Assets)-saved-failed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"sft/internal/config"
+)
+
+// applyFlags overlays the command-line flags on cfg, which already holds
+// the defaults, config file and environment. Only flags given on the
+// command line change it. extra, if not nil, adds the command's own flags.
+func applyFlags(name string, cfg *config.Config, args []string, extra func(*flag.FlagSet)) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.String("config", "", "YAML or TOML config file (also SFT_CONFIG)")
+	fs.Func("port", "listen port or address, e.g. 8080 or :8080 (PORT)", func(v string) error {
+		cfg.Port = ":" + strings.TrimPrefix(v, ":")
+		return nil
+	})
+	fs.StringVar(&cfg.SetDataPath, "data", cfg.SetDataPath, "set data JSON (SET_DATA_PATH)")
+	fs.StringVar(&cfg.TraitAssetsDir, "trait-assets", cfg.TraitAssetsDir, "trait icon directory (TRAIT_ASSETS_DIR)")
+	fs.StringVar(&cfg.UnitAssetsDir, "unit-assets", cfg.UnitAssetsDir, "unit portrait directory (UNIT_ASSETS_DIR)")
+	fs.StringVar(&cfg.SpellAssetsDir, "spell-assets", cfg.SpellAssetsDir, "spell icon directory (SPELL_ASSETS_DIR)")
+	fs.StringVar(&cfg.ItemAssetsDir, "item-assets", cfg.ItemAssetsDir, "item icon directory (ITEM_ASSETS_DIR)")
+	fs.StringVar(&cfg.StaticOverride, "static-override", cfg.StaticOverride, "directory whose files shadow ./static (STATIC_OVERRIDE_DIR)")
+	fs.StringVar(&cfg.TemplatesDir, "templates", cfg.TemplatesDir, "directory of template overrides (TEMPLATES_OVERRIDE_DIR)")
+	fs.BoolVar(&cfg.Dev, "dev", cfg.Dev, "reload templates per request and disable page caching (DEV)")
+	if extra != nil {
+		extra(fs)
+	}
+	return fs.Parse(args)
+}
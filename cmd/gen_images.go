@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sft/internal/imaging"
+)
+
+// runGenImages implements `sft gen-images`, writing the WebP size variants
+// of unit and spell art and recording them in the asset manifest. It
+// returns the process exit code.
+func runGenImages(args []string) int {
+	fs := flag.NewFlagSet("gen-images", flag.ContinueOnError)
+	static := fs.String("static", "static", "static files root")
+	units := fs.String("units", "assets/Units/SET16", "unit portraits, relative to --static")
+	unitWidths := fs.String("unit-widths", "64,256,600", "comma-separated unit variant widths")
+	spells := fs.String("spells", "assets/Spells/SET16", "spell icons, relative to --static")
+	spellWidths := fs.String("spell-widths", "64", "comma-separated spell variant widths")
+	manifest := fs.String("manifest", "static/dist/manifest.json", "asset manifest to update; empty skips it")
+	quality := fs.Int("quality", 90, "WebP quality (1-100)")
+	cwebp := fs.String("cwebp", "", "cwebp executable; defaults to cwebp in PATH")
+	force := fs.Bool("force", false, "rewrite variants that are already current")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	jobs := make([]imaging.Job, 0, 2)
+	for _, spec := range []struct{ dir, widths string }{{*units, *unitWidths}, {*spells, *spellWidths}} {
+		widths, err := parseWidths(spec.widths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gen-images: %v\n", err)
+			return 2
+		}
+		if spec.dir != "" && len(widths) > 0 {
+			jobs = append(jobs, imaging.Job{Dir: filepath.Join(*static, spec.dir), Widths: widths})
+		}
+	}
+
+	enc := imaging.CWebP{Path: *cwebp, Quality: *quality}
+	if err := enc.Check(); err != nil {
+		fmt.Fprintf(os.Stderr, "gen-images: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var variants []string
+	for _, job := range jobs {
+		res, err := imaging.Generate(ctx, job, enc, *force)
+		variants = append(variants, res.Generated...)
+		variants = append(variants, res.Current...)
+		fmt.Fprintf(os.Stdout, "%s: %d generated, %d current\n", job.Dir, len(res.Generated), len(res.Current))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gen-images: %v\n", err)
+			return 1
+		}
+	}
+
+	if *manifest != "" {
+		if err := imaging.RecordManifest(*manifest, *static, variants); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-images: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stdout, "%s: %d variants recorded\n", *manifest, len(variants))
+	}
+	return 0
+}
+
+func parseWidths(list string) ([]int, error) {
+	var widths []int
+	for _, part := range strings.Split(list, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		w, err := strconv.Atoi(part)
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("invalid width %q", part)
+		}
+		widths = append(widths, w)
+	}
+	return widths, nil
+}
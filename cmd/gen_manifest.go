@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"sft/internal/assetmanifest"
+)
+
+// runGenManifest implements `sft gen-manifest`, hashing static/dist and
+// any asset directories into the manifest ManifestAssetResolver reads. It
+// returns the process exit code.
+func runGenManifest(args []string) int {
+	fs := flag.NewFlagSet("gen-manifest", flag.ContinueOnError)
+	static := fs.String("static", "static", "static files root")
+	dist := fs.String("dist", "dist", "bundle directory, relative to --static")
+	assets := fs.String("assets", "", "comma-separated asset directories to hash too, relative to --static, e.g. assets/Units/SET16,assets/Traits/SET16")
+	manifest := fs.String("manifest", "static/dist/manifest.json", "asset manifest to update")
+	integrity := fs.Bool("integrity", true, "record sha384 SRI hashes for the bundle's JS and CSS")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	var dirs []string
+	for _, dir := range strings.Split(*assets, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	entries, err := assetmanifest.Build(assetmanifest.Options{
+		StaticDir: *static,
+		DistDir:   *dist,
+		AssetDirs: dirs,
+		Integrity: *integrity,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gen-manifest: %v\n", err)
+		return 1
+	}
+	if err := assetmanifest.Merge(*manifest, entries); err != nil {
+		fmt.Fprintf(os.Stderr, "gen-manifest: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "%s: %d entries recorded\n", *manifest, len(entries))
+	return 0
+}
@@ -1,88 +1,63 @@
 package main
 
 import (
-	"context"
-	"log"
-	"mime"
-	"net/http"
+	"fmt"
+	"io"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
-	"time"
-
-	"sft/internal/config"
-	"sft/internal/httpx"
-
-	"github.com/joho/godotenv"
 )
 
-func main() {
-	// Load optional .env files. Default env = dev unless APP_ENV/GO_ENV/ENV is set.
-	envName := strings.ToLower(strings.TrimSpace(firstNonEmpty(
-		os.Getenv("APP_ENV"),
-		os.Getenv("GO_ENV"),
-		os.Getenv("ENV"),
-	)))
-	switch envName {
-	case "", "dev", "development":
-		envName = "dev"
-	case "prod", "production":
-		envName = "prod"
-	}
-	for _, f := range []string{".env", ".env." + envName} {
-		_ = godotenv.Overload(f)
-	}
+// command is a subcommand of the sft binary.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int // returns the process exit code
+}
 
-	cfg := config.Load()
+// commands lists the subcommands in the order help shows them. The first
+// is the default, run when no command is named.
+var commands = []command{
+	{"serve", "run the web server (default)", runServe},
+	{"validate", "report problems in the set data and assets", runValidate},
+	{"diff", "show what changed between two set data files", runDiff},
+	{"fetch", "download the newest set data and art from CommunityDragon", runFetch},
+	{"smoke", "check a live deployment after a release", runSmoke},
+	{"gen-images", "write WebP size variants of unit and spell art", runGenImages},
+	{"gen-manifest", "hash the bundle and assets into the asset manifest", runGenManifest},
+}
 
-	// Ensure correct MIME type for .mjs modules.
-	_ = mime.AddExtensionType(".mjs", "text/javascript")
-	_ = mime.AddExtensionType(".woff2", "font/woff2")
-	_ = mime.AddExtensionType(".woff", "font/woff")
+func main() {
+	os.Exit(dispatch(os.Args[1:], os.Stderr))
+}
 
-	handler, err := httpx.NewRouter(cfg)
-	if err != nil {
-		log.Fatalf("router init failed: %v", err)
+// dispatch runs the command args name, or the default command when args
+// is empty or starts with a flag, so `sft -dev` still serves.
+func dispatch(args []string, stderr io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return commands[0].run(args)
 	}
-
-	addr := cfg.Port
-	logger := log.New(os.Stdout, "", log.LstdFlags)
-	logger.Printf("Server starting on http://localhost%s", addr)
-
-	server := &http.Server{
-		Addr:    addr,
-		Handler: handler,
+	name := args[0]
+	if name == "help" {
+		usage(stderr)
+		return 0
 	}
-
-	// graceful shutdown
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatalf("server error: %v", err)
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args[1:])
 		}
-	}()
-
-	<-ctx.Done()
-	stop()
-
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Printf("server shutdown error: %v", err)
-	} else {
-		logger.Printf("server stopped gracefully")
 	}
+	fmt.Fprintf(stderr, "sft: unknown command %q\n\n", name)
+	usage(stderr)
+	return 2
 }
 
-// firstNonEmpty returns the first non-empty string from the provided values.
-func firstNonEmpty(values ...string) string {
-	for _, v := range values {
-		if strings.TrimSpace(v) != "" {
-			return v
-		}
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: sft [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.summary)
 	}
-	return ""
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "sft <command> -h" for a command's flags.`)
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"sft/internal/config"
+	"sft/internal/httpx"
+)
+
+// reloadDrain is how long the services behind a replaced router keep
+// running, so requests it is still serving can finish.
+const reloadDrain = 30 * time.Second
+
+// liveServer serves through a router that a config reload replaces
+// without closing the listener or dropping connections.
+type liveServer struct {
+	handler atomic.Pointer[http.Handler]
+	process *httpx.Process // state kept across reloads
+
+	mu       sync.Mutex // serializes load and stop
+	cfg      config.Config
+	services *httpx.Container
+}
+
+func (s *liveServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*s.handler.Load()).ServeHTTP(w, r)
+}
+
+// load builds a router and services for cfg, starts them and swaps them in.
+// On error the current router keeps serving. The replaced services stop
+// once reloadDrain has passed.
+func (s *liveServer) load(ctx context.Context, cfg config.Config) error {
+	cfg = s.process.Config(cfg)
+	services := s.process.NewContainer(cfg)
+	handler, err := httpx.NewRouterWithContainer(cfg, services)
+	if err != nil {
+		return err
+	}
+	if err := services.Start(ctx); err != nil {
+		return errors.Join(err, services.Stop(context.Background()))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.services
+	s.cfg, s.services = cfg, services
+	s.handler.Store(&handler)
+
+	if old != nil {
+		time.AfterFunc(reloadDrain, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := old.Stop(ctx); err != nil {
+				log.Printf("reload: stopping replaced services: %v", err)
+			}
+		})
+	}
+	return nil
+}
+
+// reloadOnHangup reloads the configuration on each SIGHUP until ctx ends.
+// The listen address and access log cannot change without a restart.
+func (s *liveServer) reloadOnHangup(ctx context.Context, args []string, logger *log.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		cfg, err := loadConfig("serve", args, nil)
+		if err != nil {
+			logger.Printf("reload: %v; keeping the current configuration", err)
+			continue
+		}
+		s.mu.Lock()
+		current := s.cfg
+		s.mu.Unlock()
+		if cfg.Port != current.Port {
+			logger.Printf("reload: listen address stays %s until restart", current.Port)
+		}
+		if cfg.LogFormat != current.LogFormat || cfg.AccessLogPath != current.AccessLogPath {
+			logger.Printf("reload: access log settings apply after restart")
+		}
+		if err := s.load(ctx, cfg); err != nil {
+			logger.Printf("reload: %v; keeping the current configuration", err)
+			continue
+		}
+		logger.Printf("reload: configuration reloaded")
+	}
+}
+
+// stop stops the current services.
+func (s *liveServer) stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.services == nil {
+		return nil
+	}
+	return s.services.Stop(ctx)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"sft/internal/buildinfo"
+	"sft/internal/config"
+	"sft/internal/features/share"
+	"sft/internal/httpx"
+	"sft/internal/preview"
+
+	"github.com/joho/godotenv"
+)
+
+// runServe implements `sft serve`, the default command: it runs the web
+// server, or the render worker when RENDER_MODE=worker. It returns the
+// process exit code.
+func runServe(args []string) int {
+	cfg, err := loadConfig("serve", args, nil)
+	if err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errBadFlags):
+			return 2
+		}
+		log.Print(err)
+		return 1
+	}
+
+	// Ensure correct MIME type for .mjs modules.
+	_ = mime.AddExtensionType(".mjs", "text/javascript")
+	_ = mime.AddExtensionType(".woff2", "font/woff2")
+	_ = mime.AddExtensionType(".woff", "font/woff")
+
+	if cfg.RenderMode == config.RenderWorker {
+		runRenderWorker(cfg)
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	live := &liveServer{process: httpx.NewProcess()}
+	if err := live.load(ctx, cfg); err != nil {
+		log.Fatalf("router init failed: %v", err)
+	}
+
+	handler, closeLog, err := withAccessLog(cfg, live)
+	if err != nil {
+		log.Fatalf("access log: %v", err)
+	}
+	defer closeLog()
+
+	addr := cfg.Port
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	logger.Printf("Server starting on http://localhost%s (build %s)", addr, buildinfo.Get())
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+	go live.reloadOnHangup(ctx, args, logger)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("server error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Printf("server shutdown error: %v", err)
+	} else {
+		logger.Printf("server stopped gracefully")
+	}
+	if err := live.stop(shutdownCtx); err != nil {
+		logger.Printf("services shutdown error: %v", err)
+	}
+	return 0
+}
+
+// errBadFlags reports command-line flags that did not parse; the flag
+// package has already printed the problem and usage.
+var errBadFlags = errors.New("invalid flags")
+
+// loadConfig builds the configuration from, in increasing precedence,
+// defaults, the config file, the environment and .env files, and args.
+// extra registers the command's own flags; see applyFlags. For serve it
+// runs again on each SIGHUP.
+func loadConfig(name string, args []string, extra func(*flag.FlagSet)) (config.Config, error) {
+	// Load optional .env files. Default env = dev unless APP_ENV/GO_ENV/ENV is set.
+	envName := strings.ToLower(strings.TrimSpace(firstNonEmpty(
+		os.Getenv("APP_ENV"),
+		os.Getenv("GO_ENV"),
+		os.Getenv("ENV"),
+	)))
+	switch envName {
+	case "", "dev", "development":
+		envName = "dev"
+	case "prod", "production":
+		envName = "prod"
+	}
+	for _, f := range []string{".env", ".env." + envName} {
+		_ = godotenv.Overload(f)
+	}
+	// The config file fills in whatever the environment and .env files leave
+	// unset; command-line flags then override all of them.
+	if path := config.FilePath(args); path != "" {
+		if err := config.LoadFile(path); err != nil {
+			return config.Config{}, err
+		}
+	}
+
+	cfg := config.Load()
+	if err := config.LoadSecretFiles(&cfg); err != nil {
+		return config.Config{}, err
+	}
+	if err := applyFlags(name, &cfg, args, extra); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return config.Config{}, err
+		}
+		return config.Config{}, errBadFlags
+	}
+	return cfg, nil
+}
+
+// firstNonEmpty returns the first non-empty string from the provided values.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// runRenderWorker drains the preview render queue until interrupted.
+func runRenderWorker(cfg config.Config) {
+	services := httpx.NewContainer(cfg)
+	deps, err := services.Deps()
+	if err != nil {
+		log.Fatalf("worker init failed: %v", err)
+	}
+	if deps.Renders == nil {
+		log.Fatal("render worker needs DATABASE_PATH or DATABASE_URL for the shared queue")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := services.Start(ctx); err != nil {
+		log.Fatalf("worker init failed: %v", err)
+	}
+	defer func() {
+		if err := services.Stop(context.Background()); err != nil {
+			log.Printf("services shutdown error: %v", err)
+		}
+	}()
+
+	log.Printf("Render worker started (build %s)", buildinfo.Get())
+	worker := share.NewRenderWorker(deps.Renders, deps.Units, preview.NewRenderer("."))
+	if err := worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("render worker: %v", err)
+	}
+	log.Printf("render worker stopped")
+}
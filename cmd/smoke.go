@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"sft/internal/smoke"
+)
+
+// runSmoke implements `sft smoke`, checking a live deployment after a
+// release. It returns the process exit code.
+func runSmoke(args []string) int {
+	fs := flag.NewFlagSet("smoke", flag.ContinueOnError)
+	baseURL := fs.String("base-url", "http://localhost:8080", "deployment to check")
+	version := fs.String("expect-version", "", "dataset version the deployment must serve")
+	timeout := fs.Duration("timeout", 15*time.Second, "timeout per request")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	results := smoke.Run(ctx, smoke.Options{
+		BaseURL:       *baseURL,
+		ExpectVersion: *version,
+		Client:        &http.Client{Timeout: *timeout},
+	})
+	for _, r := range results {
+		status, detail := "ok  ", r.Detail
+		switch {
+		case r.Err != nil:
+			status, detail = "FAIL", r.Err.Error()
+		case r.Skipped:
+			status = "skip"
+		}
+		fmt.Fprintf(os.Stdout, "%s %-20s %6s  %s\n", status, r.Name, r.Duration.Round(time.Millisecond), detail)
+	}
+	if smoke.Failed(results) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"sft/internal/httpx"
+	"sft/internal/services"
+)
+
+// runValidate implements `sft validate`, loading the set data and asset
+// directories as the server would and reporting the problems players
+// would see. It takes serve's config flags and returns the process exit
+// code: 1 when there are problems.
+func runValidate(args []string) int {
+	var asJSON bool
+	cfg, err := loadConfig("validate", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&asJSON, "json", false, "print problems as JSON")
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errBadFlags):
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+		return 1
+	}
+
+	// Only data the server loaded belongs in the history.
+	cfg.HistoryDir = ""
+	data, err := httpx.NewUnitsLoader(cfg).LoadUnits(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+		return 1
+	}
+	problems := services.ValidateUnits(data)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(problems)
+	} else {
+		for _, p := range problems {
+			fmt.Fprintf(os.Stdout, "%-20s %-24s %s\n", p.Kind, p.Subject, p.Detail)
+		}
+		fmt.Fprintf(os.Stdout, "%s: %d units, %d traits, %d problems\n", cfg.SetDataPath, len(data.Units), len(data.Traits), len(problems))
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
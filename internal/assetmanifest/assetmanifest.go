@@ -0,0 +1,130 @@
+// Package assetmanifest writes the static/dist/manifest.json that
+// ManifestAssetResolver reads, for deployments without a frontend build
+// that produces one.
+package assetmanifest
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Options selects the files to fingerprint.
+type Options struct {
+	StaticDir string   // served at the static base URL, e.g. "static"
+	DistDir   string   // bundle directory under StaticDir, e.g. "dist"
+	AssetDirs []string // further directories under StaticDir, e.g. "assets/Units/SET16"
+	Integrity bool     // add "<name>.integrity" SRI hashes for bundle files
+}
+
+// Build returns manifest entries for the files in DistDir and AssetDirs.
+// Bundle files are keyed by their name within DistDir, as in "app.js";
+// assets by their path under StaticDir. Each maps to its URL path with a
+// content hash query, e.g. "/dist/app.js?v=3f2a9c1e", so a changed file
+// gets a new URL without renaming it. Source maps and the manifest itself
+// are skipped.
+func Build(opts Options) (map[string]string, error) {
+	entries := make(map[string]string)
+	dist := filepath.Join(opts.StaticDir, opts.DistDir)
+	err := walkFiles(dist, func(path string, data []byte) {
+		name := relSlash(dist, path)
+		entries[name] = versionedURL(opts.StaticDir, path, data)
+		if opts.Integrity && (strings.HasSuffix(name, ".js") || strings.HasSuffix(name, ".css")) {
+			sum := sha512.Sum384(data)
+			entries[name+".integrity"] = "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range opts.AssetDirs {
+		err := walkFiles(filepath.Join(opts.StaticDir, dir), func(path string, data []byte) {
+			entries[relSlash(opts.StaticDir, path)] = versionedURL(opts.StaticDir, path, data)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// Merge adds entries to the manifest at path, keeping the entries other
+// tools wrote, such as the image variants from `sft gen-images`. A missing
+// manifest is created.
+func Merge(path string, entries map[string]string) error {
+	manifest := make(map[string]string)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("read manifest: %w", err)
+	default:
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return fmt.Errorf("parse manifest %s: %w", path, err)
+		}
+	}
+	for k, v := range entries {
+		manifest[k] = v
+	}
+
+	out, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}
+
+// walkFiles calls fn for each regular file under dir in path order,
+// skipping source maps and manifests.
+func walkFiles(dir string, fn func(path string, data []byte)) error {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() || strings.HasSuffix(name, ".map") || name == "manifest.json" || strings.HasPrefix(name, ".") {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("manifest: %w", err)
+		}
+		fn(path, data)
+	}
+	return nil
+}
+
+func versionedURL(staticDir, path string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return "/" + relSlash(staticDir, path) + "?v=" + hex.EncodeToString(sum[:4])
+}
+
+func relSlash(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
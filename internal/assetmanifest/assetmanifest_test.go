@@ -0,0 +1,75 @@
+package assetmanifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildAndMerge(t *testing.T) {
+	static := t.TempDir()
+	files := map[string]string{
+		"dist/app.js":                 "console.log(1)",
+		"dist/app.css":                "body{}",
+		"dist/app.js.map":             "{}",
+		"dist/manifest.json":          `{"assets/Units/SET16/Ahri-64.webp":"/assets/Units/SET16/Ahri-64.webp"}`,
+		"assets/Units/SET16/Ahri.png": "png",
+	}
+	for name, content := range files {
+		path := filepath.Join(static, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Build(Options{StaticDir: static, DistDir: "dist", AssetDirs: []string{"assets/Units/SET16"}, Integrity: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, prefix := range map[string]string{
+		"app.js":                      "/dist/app.js?v=",
+		"app.css":                     "/dist/app.css?v=",
+		"app.js.integrity":            "sha384-",
+		"app.css.integrity":           "sha384-",
+		"assets/Units/SET16/Ahri.png": "/assets/Units/SET16/Ahri.png?v=",
+	} {
+		if !strings.HasPrefix(entries[key], prefix) {
+			t.Errorf("entries[%q] = %q, want prefix %q", key, entries[key], prefix)
+		}
+	}
+	for _, key := range []string{"app.js.map", "manifest.json"} {
+		if _, ok := entries[key]; ok {
+			t.Errorf("entries has %q", key)
+		}
+	}
+
+	first := entries["app.js"]
+	os.WriteFile(filepath.Join(static, "dist", "app.js"), []byte("console.log(2)"), 0o644)
+	if entries, _ = Build(Options{StaticDir: static, DistDir: "dist"}); entries["app.js"] == first {
+		t.Error("changed file kept its URL")
+	}
+
+	path := filepath.Join(static, "dist", "manifest.json")
+	if err := Merge(path, entries); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest["app.js"] != entries["app.js"] {
+		t.Errorf("manifest app.js = %q, want %q", manifest["app.js"], entries["app.js"])
+	}
+	if _, ok := manifest["assets/Units/SET16/Ahri-64.webp"]; !ok {
+		t.Error("Merge dropped an existing entry")
+	}
+}
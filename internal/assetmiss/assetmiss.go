@@ -0,0 +1,183 @@
+// Package assetmiss counts requests for static files that do not exist.
+// Misses are grouped by path pattern so a broken reference shows up as one
+// entry however many hashed or numbered variants are requested.
+package assetmiss
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxPatterns bounds memory when clients probe random paths. Misses on
+	// new patterns past the limit are only counted in Dropped.
+	maxPatterns = 256
+	// maxSamples is the number of distinct example paths kept per pattern.
+	maxSamples = 3
+)
+
+var (
+	// contentHash matches build hashes in file names, e.g. "app-7CR4C5LR.js"
+	// or "sprite.3f9a0c1e.svg".
+	contentHash = regexp.MustCompile(`[.-]([0-9A-Z]{8,}|[0-9a-f]{8,})\.`)
+	numeric     = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Pattern returns the grouping key for path: content hashes and numeric
+// segments are replaced with "*".
+func Pattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if numeric.MatchString(s) {
+			segments[i] = "*"
+			continue
+		}
+		segments[i] = contentHash.ReplaceAllStringFunc(s, func(m string) string {
+			hash := m[1 : len(m)-1]
+			if !strings.ContainsAny(hash, "0123456789") {
+				return m // a plain word such as "-champions."
+			}
+			return m[:1] + "*."
+		})
+	}
+	return strings.Join(segments, "/")
+}
+
+// Miss is the aggregate for one path pattern.
+type Miss struct {
+	Pattern   string    `json:"pattern"`
+	Count     int       `json:"count"`
+	Samples   []string  `json:"samples"`
+	Referer   string    `json:"referer,omitempty"` // most recent referring page
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Summary is a snapshot of the tracker for the admin endpoint.
+type Summary struct {
+	Total    int    `json:"total"`
+	Patterns int    `json:"patterns"`
+	Dropped  int    `json:"dropped"`
+	Top      []Miss `json:"top"`
+}
+
+// Tracker is safe for concurrent use. The zero value is not usable; call New.
+type Tracker struct {
+	logger *log.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	misses  map[string]*Miss
+	total   int
+	dropped int
+}
+
+// New creates an empty tracker that logs to the standard logger.
+func New() *Tracker {
+	return &Tracker{logger: log.Default(), now: time.Now, misses: make(map[string]*Miss)}
+}
+
+// Record counts a miss for path. Logging is sampled: a pattern is logged on
+// its 1st, 2nd, 4th, 8th... miss, so a broken reference on a busy page
+// produces a handful of lines rather than one per request.
+func (t *Tracker) Record(path, referer string) {
+	pattern := Pattern(path)
+	now := t.now()
+
+	t.mu.Lock()
+	t.total++
+	m, ok := t.misses[pattern]
+	if !ok {
+		if len(t.misses) >= maxPatterns {
+			t.dropped++
+			t.mu.Unlock()
+			return
+		}
+		m = &Miss{Pattern: pattern, FirstSeen: now}
+		t.misses[pattern] = m
+	}
+	m.Count++
+	m.LastSeen = now
+	if referer != "" {
+		m.Referer = referer
+	}
+	if len(m.Samples) < maxSamples && !slices.Contains(m.Samples, path) {
+		m.Samples = append(m.Samples, path)
+	}
+	count := m.Count
+	t.mu.Unlock()
+
+	if count&(count-1) == 0 {
+		t.logger.Printf("static 404 %s (%d misses, e.g. %s, referer %q)", pattern, count, path, referer)
+	}
+}
+
+// Summary returns the n most frequent patterns, most recent first on ties.
+func (t *Tracker) Summary(n int) Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	top := make([]Miss, 0, len(t.misses))
+	for _, m := range t.misses {
+		c := *m
+		c.Samples = append([]string(nil), m.Samples...)
+		top = append(top, c)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].LastSeen.After(top[j].LastSeen)
+	})
+	if n >= 0 && len(top) > n {
+		top = top[:n]
+	}
+	return Summary{Total: t.total, Patterns: len(t.misses), Dropped: t.dropped, Top: top}
+}
+
+// Reset clears all counts, e.g. after a deploy has fixed the references.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.misses = make(map[string]*Miss)
+	t.total, t.dropped = 0, 0
+}
+
+// Middleware records every 404 written by next.
+func (t *Tracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == http.StatusNotFound {
+			t.Record(r.URL.Path, r.Referer())
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (s *statusRecorder) Unwrap() http.ResponseWriter { return s.ResponseWriter }
@@ -0,0 +1,65 @@
+package assetmiss
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPattern(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"/dist/app-7CR4C5LR.js", "/dist/app-*.js"},
+		{"/assets/sprite.3f9a0c1e.svg", "/assets/sprite.*.svg"},
+		{"/assets/icons/12/ahri.png", "/assets/icons/*/ahri.png"},
+		{"/assets/set-champions.json", "/assets/set-champions.json"},
+		{"/assets/champions/tft16_ahri.png", "/assets/champions/tft16_ahri.png"},
+	}
+	for _, tt := range tests {
+		if got := Pattern(tt.path); got != tt.want {
+			t.Errorf("Pattern(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var logs strings.Builder
+	tracker := New()
+	tracker.logger = log.New(&logs, "", 0)
+	h := tracker.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dist/app.css" {
+			_, _ = io.WriteString(w, "body{}")
+			return
+		}
+		http.NotFound(w, r)
+	}))
+
+	for _, path := range []string{
+		"/dist/app-AAAAAAA1.js", "/dist/app-BBBBBBB2.js", "/dist/app-AAAAAAA1.js",
+		"/dist/app-CCCCCCC3.js", "/dist/app-DDDDDDD4.js", "/dist/app.css", "/fonts/missing.woff2",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Referer", "/units/ahri")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	s := tracker.Summary(1)
+	if s.Total != 6 || s.Patterns != 2 || len(s.Top) != 1 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	top := s.Top[0]
+	if top.Pattern != "/dist/app-*.js" || top.Count != 5 || len(top.Samples) != maxSamples || top.Referer != "/units/ahri" {
+		t.Errorf("unexpected top miss %+v", top)
+	}
+	// Logged on the 1st, 2nd and 4th miss of the pattern, plus the font.
+	if n := strings.Count(logs.String(), "\n"); n != 4 {
+		t.Errorf("logged %d lines, want 4:\n%s", n, logs.String())
+	}
+
+	tracker.Reset()
+	if s := tracker.Summary(10); s.Total != 0 || len(s.Top) != 0 {
+		t.Errorf("summary after Reset = %+v", s)
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import "testing"
+
+func TestLinkSigner(t *testing.T) {
+	s := NewLinkSigner("secret")
+	sig := s.Sign("lobby", 42)
+
+	tests := []struct {
+		name string
+		kind string
+		id   int64
+		sig  string
+		want bool
+	}{
+		{"valid", "lobby", 42, sig, true},
+		{"other id", "lobby", 43, sig, false},
+		{"other kind", "comp", 42, sig, false},
+		{"garbage", "lobby", 42, "not base64!", false},
+		{"empty", "lobby", 42, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Verify(tt.kind, tt.id, tt.sig); got != tt.want {
+				t.Errorf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if NewLinkSigner("other").Verify("lobby", 42, sig) {
+		t.Error("signature should not verify with another secret")
+	}
+}
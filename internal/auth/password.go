@@ -0,0 +1,51 @@
+// Package auth handles password hashing and cookie-based login sessions.
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length limits. bcrypt ignores input beyond 72 bytes.
+const (
+	MinPasswordLen = 8
+	MaxPasswordLen = 72
+)
+
+// ErrPasswordLength is returned for passwords outside the allowed length.
+var ErrPasswordLength = errors.New("password must be between 8 and 72 characters")
+
+// HashPassword returns a bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLen || len(password) > MaxPasswordLen {
+		return "", ErrPasswordLength
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the stored hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// DummyHash returns a hash of a random secret, of the cost HashPassword
+// uses. Checking logins of unknown users against it makes them take as
+// long as real ones, so timing does not reveal which usernames exist.
+var DummyHash = sync.OnceValue(func() string {
+	secret := make([]byte, MaxPasswordLen)
+	if _, err := rand.Read(secret); err != nil {
+		panic("auth: generating dummy hash: " + err.Error())
+	}
+	hash, err := bcrypt.GenerateFromPassword(secret, bcrypt.DefaultCost)
+	if err != nil {
+		panic("auth: generating dummy hash: " + err.Error())
+	}
+	return string(hash)
+})
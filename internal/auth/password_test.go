@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestDummyHash(t *testing.T) {
+	hash := DummyHash()
+	if hash != DummyHash() {
+		t.Error("dummy hash changed between calls")
+	}
+	if cost, err := bcrypt.Cost([]byte(hash)); err != nil || cost != bcrypt.DefaultCost {
+		t.Errorf("cost = %d, %v; want %d so unknown users take as long", cost, err, bcrypt.DefaultCost)
+	}
+	if CheckPassword(hash, "") || CheckPassword(hash, "hunter2hunter2") {
+		t.Error("a password matched the dummy hash")
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sft/internal/middleware"
+	"sft/internal/store"
+)
+
+// DefaultSessionTTL is how long a login stays valid.
+const DefaultSessionTTL = 30 * 24 * time.Hour
+
+// Keys of the login in the cookie session.
+const (
+	sessionUserKey    = "user_id"
+	sessionExpiresKey = "login_expires"
+)
+
+// errNoSession reports a login attempted outside middleware.Session.
+var errNoSession = errors.New("auth: no session; wrap the handler in middleware.Session")
+
+type contextKey struct{}
+
+// Sessions logs users in and out of the cookie session of
+// middleware.Session, which keeps the user's ID and when the login
+// expires. Its middleware must run inside middleware.Session.
+type Sessions struct {
+	users store.UserStore
+	ttl   time.Duration
+}
+
+// NewSessions creates a session manager resolving logins against users.
+func NewSessions(users store.UserStore) *Sessions {
+	return &Sessions{users: users, ttl: DefaultSessionTTL}
+}
+
+// Middleware stores the session's logged-in user in the request context.
+// Requests without a valid login pass through anonymously.
+func (s *Sessions) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := middleware.SessionFrom(r.Context())
+		id, err := strconv.ParseInt(sess.Get(sessionUserKey), 10, 64)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		expires, _ := strconv.ParseInt(sess.Get(sessionExpiresKey), 10, 64)
+		if time.Now().Unix() >= expires {
+			s.clear(sess)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := s.users.GetUser(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				s.clear(sess) // the account is gone
+			} else {
+				log.Printf("session user lookup failed: %v", err)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, user)))
+	})
+}
+
+// Login logs user in to the request's session, moving it to a new ID so
+// an ID planted before the login is worthless.
+func (s *Sessions) Login(w http.ResponseWriter, r *http.Request, user *store.User) error {
+	sess := middleware.SessionFrom(r.Context())
+	if sess == nil {
+		return errNoSession
+	}
+	sess.Renew()
+	sess.Set(sessionUserKey, strconv.FormatInt(user.ID, 10))
+	sess.Set(sessionExpiresKey, strconv.FormatInt(time.Now().Add(s.ttl).Unix(), 10))
+	return nil
+}
+
+// Logout ends the request's session, if any, and clears its cookie.
+func (s *Sessions) Logout(w http.ResponseWriter, r *http.Request) error {
+	middleware.SessionFrom(r.Context()).Destroy()
+	return nil
+}
+
+func (s *Sessions) clear(sess *middleware.SessionData) {
+	sess.Set(sessionUserKey, "")
+	sess.Set(sessionExpiresKey, "")
+}
+
+// UserFrom returns the logged-in user for the request, or nil.
+func UserFrom(ctx context.Context) *store.User {
+	user, _ := ctx.Value(contextKey{}).(*store.User)
+	return user
+}
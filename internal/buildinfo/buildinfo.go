@@ -0,0 +1,83 @@
+// Package buildinfo exposes version metadata for the running binary.
+package buildinfo
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// These are overridable at link time, e.g.
+//
+//	go build -ldflags "-X sft/internal/buildinfo.Version=v1.2.0 -X sft/internal/buildinfo.Commit=abc123"
+var (
+	Version = ""
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes the current build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get merges ldflags values with the module and VCS data embedded by the Go toolchain.
+// Values set via ldflags always win.
+func Get() Info {
+	info := Info{
+		Version: Version,
+		Commit:  Commit,
+		Date:    Date,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
+}
+
+// String returns a compact "version (commit)" label suitable for headers.
+func (i Info) String() string {
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	var b strings.Builder
+	b.WriteString(i.Version)
+	if commit != "" {
+		b.WriteString(" (")
+		b.WriteString(commit)
+		if i.Modified {
+			b.WriteString("-dirty")
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+// Package cache stores derived data (loaded datasets, rendered fragments,
+// computed results) that is costly to rebuild, either in process memory or
+// in Redis so that every instance of the site shares a warm cache.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache maps keys to byte values that expire. A cache is best effort:
+// failures to reach it are misses, so callers rebuild the value instead.
+type Cache interface {
+	// Get returns the value stored under key and whether there was one.
+	Get(ctx context.Context, key string) ([]byte, bool)
+	// Set stores value under key for ttl; 0 keeps it until evicted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+}
+
+// Memory is an in-process Cache of up to a fixed number of entries, least
+// recently used evicted first.
+type Memory struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List               // front is most recently used; values are *memoryEntry
+	entries map[string]*list.Element // key → element in lru
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // zero never expires
+}
+
+// NewMemory creates a cache of up to maxEntries values.
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key and marks it recently used.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if !e.expires.IsZero() && !m.now().Before(e.expires) {
+		m.lru.Remove(el)
+		delete(m.entries, key)
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value under key. The cache keeps value, so callers must not
+// modify it afterwards.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = m.now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		e := el.Value.(*memoryEntry)
+		e.value, e.expires = value, expires
+		m.lru.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.lru.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+	for m.lru.Len() > m.maxEntries {
+		oldest := m.lru.Back()
+		m.lru.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of stored values, expired ones included until
+// they are looked up or evicted.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
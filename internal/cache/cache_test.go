@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	m := NewMemory(2)
+	m.now = func() time.Time { return now }
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	m.Set(ctx, "b", []byte("2"), 0)
+	if v, ok := m.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Fatalf("a = %q, %v", v, ok)
+	}
+
+	// b is now least recently used.
+	m.Set(ctx, "c", []byte("3"), 0)
+	if _, ok := m.Get(ctx, "b"); ok {
+		t.Error("b survived eviction")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := m.Get(ctx, "a"); ok {
+		t.Error("a outlived its ttl")
+	}
+	if v, ok := m.Get(ctx, "c"); !ok || string(v) != "3" {
+		t.Errorf("c = %q, %v; values without ttl keep", v, ok)
+	}
+	if m.Len() != 1 {
+		t.Errorf("len = %d, want 1", m.Len())
+	}
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisTimeout = 500 * time.Millisecond // per command when ctx has no deadline
+	redisIdle    = 8                      // idle connections kept for reuse
+)
+
+// Redis is a Cache in a Redis server shared by all instances. It speaks
+// just enough of the protocol for GET, SET and PING, over a small pool of
+// connections. Errors are logged and treated as misses: a cache outage
+// slows pages down but does not fail them.
+type Redis struct {
+	addr     string
+	tls      *tls.Config // nil for plain TCP
+	username string
+	password string
+	db       int
+	prefix   string
+	logger   *log.Logger
+
+	idle chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// redisError is an error reply; the connection stays usable after one.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// NewRedis connects lazily to the server at rawURL,
+// redis://[user:password@]host:port[/db] or rediss:// for TLS. Every key
+// is stored under prefix, so several sites can share a server.
+func NewRedis(rawURL, prefix string) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("redis url: %w", err)
+	}
+	r := &Redis{
+		addr:   u.Host,
+		prefix: prefix,
+		logger: log.Default(),
+		idle:   make(chan *redisConn, redisIdle),
+	}
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		r.tls = &tls.Config{ServerName: u.Hostname()}
+	default:
+		return nil, fmt.Errorf("redis url: unsupported scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if r.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("redis url: database %q is not a number", db)
+		}
+	}
+	return r, nil
+}
+
+// Get returns the value stored under key.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool) {
+	reply, err := r.do(ctx, "GET", r.prefix+key)
+	if err != nil {
+		r.logger.Printf("Cache get %s: %v", key, err)
+		return nil, false
+	}
+	value, ok := reply.([]byte)
+	return value, ok
+}
+
+// Set stores value under key for ttl, rounded to milliseconds.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	args := []string{"SET", r.prefix + key, string(value)}
+	if ms := ttl.Milliseconds(); ms > 0 {
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	if _, err := r.do(ctx, args...); err != nil {
+		r.logger.Printf("Cache set %s: %v", key, err)
+	}
+}
+
+// Ping checks that the server answers, for health checks.
+func (r *Redis) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections.
+func (r *Redis) Close() error {
+	for {
+		select {
+		case c := <-r.idle:
+			c.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends one command and reads its reply: a string, []byte, int64, nil
+// or []any. A connection is reused only after a complete exchange.
+func (r *Redis) do(ctx context.Context, args ...string) (any, error) {
+	c, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.exchange(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.Close()
+		return nil, err
+	}
+	select {
+	case r.idle <- c:
+	default:
+		c.Close()
+	}
+	return reply, err
+}
+
+func (r *Redis) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.idle:
+		return c, nil
+	default:
+	}
+
+	var d net.Dialer
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	nc, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, err
+	}
+	if r.tls != nil {
+		tc := tls.Client(nc, r.tls)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			nc.Close()
+			return nil, err
+		}
+		nc = tc
+	}
+	c := &redisConn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if r.password != "" {
+		auth := []string{"AUTH", r.password}
+		if r.username != "" {
+			auth = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := c.exchange(ctx, auth); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.exchange(ctx, []string{"SELECT", strconv.Itoa(r.db)}); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *redisConn) exchange(ctx context.Context, args []string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// readReply reads one RESP2 reply.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch kind, rest := line[0], line[1:]; kind {
+	case '+':
+		return rest, nil
+	case '-':
+		return nil, redisError(rest)
+	case ':':
+		return strconv.ParseInt(rest, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return nil, err // $-1 is a nil reply
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
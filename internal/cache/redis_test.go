@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers AUTH, SELECT, PING, GET and SET from a map, and
+// records every command.
+func fakeRedis(t *testing.T) (addr string, commands func() []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	store := map[string][]byte{}
+	var seen []string
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					reply, err := readReply(r)
+					if err != nil {
+						return
+					}
+					var args []string
+					for _, a := range reply.([]any) {
+						args = append(args, string(a.([]byte)))
+					}
+					mu.Lock()
+					seen = append(seen, args[0])
+					switch args[0] {
+					case "GET":
+						if v, ok := store[args[1]]; ok {
+							fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+						} else {
+							fmt.Fprint(conn, "$-1\r\n")
+						}
+					case "SET":
+						store[args[1]] = []byte(args[2])
+						fmt.Fprint(conn, "+OK\r\n")
+					case "AUTH":
+						if args[1] != "hunter2" {
+							fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+							break
+						}
+						fmt.Fprint(conn, "+OK\r\n")
+					case "PING":
+						fmt.Fprint(conn, "+PONG\r\n")
+					default:
+						fmt.Fprint(conn, "+OK\r\n")
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	addr, commands := fakeRedis(t)
+	r, err := NewRedis("redis://:hunter2@"+addr+"/2", "sft:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ctx := context.Background()
+
+	if _, ok := r.Get(ctx, "missing"); ok {
+		t.Error("hit on a missing key")
+	}
+	r.Set(ctx, "k", []byte("v\r\nwith newline"), time.Minute)
+	if v, ok := r.Get(ctx, "k"); !ok || string(v) != "v\r\nwith newline" {
+		t.Errorf("get = %q, %v", v, ok)
+	}
+	if err := r.Ping(ctx); err != nil {
+		t.Errorf("ping: %v", err)
+	}
+
+	// One pooled connection authenticates and selects the database once.
+	got := fmt.Sprint(commands())
+	if want := "[AUTH SELECT GET SET GET PING]"; got != want {
+		t.Errorf("commands = %s, want %s", got, want)
+	}
+
+	bad, _ := NewRedis("redis://:wrong@"+addr, "")
+	if err := bad.Ping(ctx); err == nil {
+		t.Error("ping with a wrong password succeeded")
+	}
+	if _, err := NewRedis("http://"+addr, ""); err == nil {
+		t.Error("accepted a non-redis URL")
+	}
+}
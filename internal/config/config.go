@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,26 +11,146 @@ import (
 type Config struct {
 	Port           string        // http listen address, e.g. ":8080"
 	SetDataPath    string        // path to generated set JSON
+	PrevSetPath    string        // older set JSON the data diff page compares against; optional
+	HistoryDir     string        // archive of every loaded set data version; empty disables it
 	TraitAssetsDir string        // path to trait SVG assets
 	UnitAssetsDir  string        // path to unit image assets
 	SpellAssetsDir string        // path to spell/ability icons
+	ItemsDataPath  string        // path to generated item JSON; empty disables items
+	ItemAssetsDir  string        // path to item icons
+	ShopOddsPath   string        // path to roll odds JSON; empty hides shop odds
 	StaticBaseURL  string        // base URL for serving static files
+	CDNBaseURL     string        // optional CDN origin pages load static files from (e.g., https://cdn.example.com); HTML stays here
+	StaticOverride string        // optional directory whose files shadow ./static (per-site logos, theme.css)
+	TemplatesDir   string        // optional directory of .gohtml partials that replace the built-in ones
 	StaticCacheSec int           // cache max-age for static files (seconds); 0 disables caching
 	SiteURL        string        // absolute site URL for canonical/meta (e.g., https://example.com)
+	SiteName       string        // brand name used in titles and structured data
+	Theme          string        // optional theme name exposed to CSS via data-theme
+	ThemeColor     string        // browser UI color of the installed app and the theme-color meta tag
+	SitesConfig    string        // JSON file with per-host site profiles; empty serves a single site
 	HTTPTimeout    time.Duration // default HTTP timeout for outbound calls
+	HTTPRetries    int           // retries of a failed outbound GET
+	HTTPBackoff    time.Duration // wait before the first outbound retry, doubled per retry
+	HTTPPerHost    int           // outbound connections per host; 0 is unlimited
+	AssetWatch     time.Duration // how often asset directories are rescanned; 0 disables watching
+	DataRefresh    time.Duration // how often set data is re-fetched from CommunityDragon; 0 disables refreshing
+	RefreshSet     int           // set number DataRefresh fetches; 0 fetches the newest
+	BucketEndpoint string        // S3-compatible endpoint of the data bucket, e.g. https://storage.googleapis.com
+	BucketName     string        // bucket holding the set JSON and assets; empty reads them from disk
+	BucketRegion   string        // signing region of the bucket; GCS takes "auto"
+	BucketKey      string        // access key ID of the bucket; empty reads a public bucket anonymously
+	BucketSecret   string        // secret access key of the bucket (or BUCKET_SECRET_KEY_FILE)
+	BucketAssetURL string        // where browsers load bucket assets from, e.g. a CDN; empty links to the bucket
+	BucketTTL      time.Duration // how long bucket data is served before it is re-read
+	Indexing       bool          // allow search engines to index the site; disable on staging
+	AssetIntegrity bool          // emit Subresource Integrity hashes on the CSS and JS bundle tags
+	AdminToken     string        // bearer token for /admin endpoints; empty disables them (or ADMIN_TOKEN_FILE)
+	LobbySecret    string        // HMAC key for shared lobby links; empty uses a per-process key (or LOBBY_SECRET_FILE)
+	SessionSecret  string        // HMAC key for session cookies; empty uses a per-process key (or SESSION_SECRET_FILE)
+	VoterSecret    string        // HMAC key for anonymous comp voters' addresses; empty derives one from SessionSecret (or VOTER_SECRET_FILE)
+	DatabasePath   string        // SQLite file for saved comps; empty disables persistence
+	DatabaseURL    string        // Postgres DSN, used instead of DatabasePath (or DATABASE_URL_FILE)
+	RedirectsPath  string        // JSON or YAML file of legacy URL redirects, applied with the stored ones
+	ChangelogPath  string        // markdown file of patch notes; empty serves the stored changelog
+	PlannerPath    string        // CommunityDragon team planner ID mapping, written by `sft fetch`; enables comp import
+	PlannerSet     string        // set key within PlannerPath, e.g. "TFTSet16"
+	RenderMode     string        // preview images: RenderInline, RenderQueue or RenderWorker
+	ImageCacheDir  string        // directory for rendered preview images; empty disables caching
+	ImageCacheMB   int64         // size limit of ImageCacheDir in megabytes
+	BatchBodyKB    int64         // decoded size limit of batch API request bodies in kilobytes
+	RenderCache    int           // rendered builder pages kept in memory; 0 disables the cache
+	RedisURL       string        // redis://[user:pass@]host:port/db of the cache instances share; empty caches in memory (or REDIS_URL_FILE)
+	CacheEntries   int           // values the in-memory cache keeps when RedisURL is empty
+	Dev            bool          // re-parse templates per request and skip page caching
+	Warmup         string        // startup render of the builder page: WarmupOff, WarmupLog or WarmupStrict
+	LogFormat      string        // access log: LogFormatOff, LogFormatJSON or LogFormatCombined
+	AccessLogPath  string        // file for the access log; empty writes to stdout
+	AccessLogMB    int64         // rotate AccessLogPath past this many megabytes; 0 never rotates
+	AccessLogKeep  int           // rotated access log files kept
+	// PreconnectOrigins are third-party origins (CDN, fonts, analytics) the
+	// page hints with <link rel=preconnect>, e.g. "https://cdn.example.com".
+	PreconnectOrigins []string
+	// TrustedProxies are the reverse proxies, as CIDR ranges or IPs, whose
+	// X-Forwarded-For names the client; without them every request
+	// behind a proxy appears to come from the proxy.
+	TrustedProxies []string
+	// RecommendedItemsPath is a JSON file of recommended items per unit,
+	// overriding the set data; empty uses only the set data.
+	RecommendedItemsPath string
+	// AppIcons are square icons for the web app manifest, as paths under
+	// the static directory (e.g. "icons/app-512.png"); their sizes are read
+	// from the files.
+	AppIcons []string
+	// ScalingIconsPath is a JSON file of extra scaling icon classes, keyed
+	// by scaling (e.g. {"SOULS": "ability-token ability-icon ..."}); empty
+	// uses only the built-in icons.
+	ScalingIconsPath string
 }
 
+// Startup warmup modes. Strict refuses to start when the builder page
+// cannot be rendered; log reports the failure and serves anyway.
+const (
+	WarmupOff    = "off"
+	WarmupLog    = "log"
+	WarmupStrict = "strict"
+)
+
+// Access log formats. The format names match middleware.AccessLog.
+const (
+	LogFormatOff      = "off"
+	LogFormatJSON     = "json"
+	LogFormatCombined = "combined"
+)
+
+// Preview image render modes. Queue and worker share the database queue.
+const (
+	RenderInline = "inline" // web process renders on request
+	RenderQueue  = "queue"  // web process enqueues; workers render
+	RenderWorker = "worker" // process only drains the queue, no HTTP server
+)
+
 func Default() Config {
 	return Config{
 		Port:           ":8080",
 		SetDataPath:    "data/set16_champions.json",
+		HistoryDir:     "data/history",
 		TraitAssetsDir: "static/assets/Traits/SET16",
 		UnitAssetsDir:  "static/assets/Units/SET16",
 		SpellAssetsDir: "static/assets/Spells/SET16/webp-64",
+		ItemsDataPath:  "data/set16_items.json",
+		ItemAssetsDir:  "static/assets/Items/SET16",
+		ShopOddsPath:   "data/set16_shop_odds.json",
 		StaticBaseURL:  "/static",
 		StaticCacheSec: 0, // default to no cache in dev; set STATIC_CACHE_SECONDS in prod
 		SiteURL:        "http://localhost:8080",
+		SiteName:       "TFT Builder",
+		ThemeColor:     "#000000",
 		HTTPTimeout:    20 * time.Second,
+		HTTPRetries:    2,
+		HTTPBackoff:    500 * time.Millisecond,
+		HTTPPerHost:    4,
+		AssetWatch:     30 * time.Second,
+		RefreshSet:     16,
+		BucketEndpoint: "https://s3.amazonaws.com",
+		BucketRegion:   "us-east-1",
+		BucketTTL:      5 * time.Minute,
+		Indexing:       true,
+		DatabasePath:   "data/sft.db",
+		PlannerPath:    "data/set16_teamplanner.json",
+		PlannerSet:     "TFTSet16",
+		RenderMode:     RenderInline,
+		ImageCacheMB:   256,
+		BatchBodyKB:    1024,
+		RenderCache:    512,
+		CacheEntries:   4096,
+		Warmup:         WarmupLog,
+		LogFormat:      LogFormatOff,
+		AccessLogMB:    100,
+		AccessLogKeep:  5,
+
+		AppIcons:             []string{"icons/app-192.png", "icons/app-512.png"},
+		RecommendedItemsPath: "data/set16_recommended_items.json",
 	}
 }
 
@@ -44,6 +165,12 @@ func Load() Config {
 	if v := os.Getenv("SET_DATA_PATH"); v != "" {
 		cfg.SetDataPath = v
 	}
+	if v := os.Getenv("PREVIOUS_SET_DATA_PATH"); v != "" {
+		cfg.PrevSetPath = v
+	}
+	if v, ok := os.LookupEnv("DATA_HISTORY_DIR"); ok {
+		cfg.HistoryDir = v
+	}
 	if v := os.Getenv("TRAIT_ASSETS_DIR"); v != "" {
 		cfg.TraitAssetsDir = v
 	}
@@ -53,9 +180,30 @@ func Load() Config {
 	if v := os.Getenv("SPELL_ASSETS_DIR"); v != "" {
 		cfg.SpellAssetsDir = v
 	}
+	if v, ok := os.LookupEnv("ITEMS_DATA_PATH"); ok {
+		cfg.ItemsDataPath = v
+	}
+	if v := os.Getenv("ITEM_ASSETS_DIR"); v != "" {
+		cfg.ItemAssetsDir = v
+	}
+	if v, ok := os.LookupEnv("SHOP_ODDS_PATH"); ok {
+		cfg.ShopOddsPath = v
+	}
+	if v, ok := os.LookupEnv("RECOMMENDED_ITEMS_PATH"); ok {
+		cfg.RecommendedItemsPath = v
+	}
 	if v := os.Getenv("STATIC_BASE_URL"); v != "" {
 		cfg.StaticBaseURL = v
 	}
+	if v := os.Getenv("CDN_BASE_URL"); v != "" {
+		cfg.CDNBaseURL = strings.TrimRight(v, "/")
+	}
+	if v := os.Getenv("STATIC_OVERRIDE_DIR"); v != "" {
+		cfg.StaticOverride = v
+	}
+	if v := os.Getenv("TEMPLATES_OVERRIDE_DIR"); v != "" {
+		cfg.TemplatesDir = v
+	}
 	if v := os.Getenv("STATIC_CACHE_SECONDS"); v != "" {
 		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
 			cfg.StaticCacheSec = seconds
@@ -64,15 +212,197 @@ func Load() Config {
 	if v := os.Getenv("SITE_URL"); v != "" {
 		cfg.SiteURL = v
 	}
+	if v := os.Getenv("SITE_NAME"); v != "" {
+		cfg.SiteName = v
+	}
+	if v := os.Getenv("THEME"); v != "" {
+		cfg.Theme = v
+	}
+	if v := os.Getenv("THEME_COLOR"); v != "" {
+		cfg.ThemeColor = v
+	}
+	if v := os.Getenv("APP_ICONS"); v != "" {
+		cfg.AppIcons = splitList(v)
+	}
+	if v := os.Getenv("SITES_CONFIG"); v != "" {
+		cfg.SitesConfig = v
+	}
 	if v := os.Getenv("HTTP_TIMEOUT_SECONDS"); v != "" {
 		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
 			cfg.HTTPTimeout = time.Duration(seconds) * time.Second
 		}
 	}
+	if v := os.Getenv("HTTP_RETRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.HTTPRetries = n
+		}
+	}
+	if v := os.Getenv("HTTP_BACKOFF_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms >= 0 {
+			cfg.HTTPBackoff = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if v := os.Getenv("HTTP_MAX_CONNS_PER_HOST"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.HTTPPerHost = n
+		}
+	}
+	if v := os.Getenv("ASSET_WATCH_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			cfg.AssetWatch = time.Duration(seconds) * time.Second
+		}
+	}
+	if v := os.Getenv("DATA_REFRESH_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			cfg.DataRefresh = time.Duration(seconds) * time.Second
+		}
+	}
+	if v := os.Getenv("DATA_REFRESH_SET"); v != "" {
+		if set, err := strconv.Atoi(v); err == nil && set >= 0 {
+			cfg.RefreshSet = set
+		}
+	}
+	if v := os.Getenv("BUCKET_ENDPOINT"); v != "" {
+		cfg.BucketEndpoint = v
+	}
+	if v := os.Getenv("BUCKET_NAME"); v != "" {
+		cfg.BucketName = v
+	}
+	if v := os.Getenv("BUCKET_REGION"); v != "" {
+		cfg.BucketRegion = v
+	}
+	if v := os.Getenv("BUCKET_ACCESS_KEY"); v != "" {
+		cfg.BucketKey = v
+	}
+	if v := os.Getenv("BUCKET_SECRET_KEY"); v != "" {
+		cfg.BucketSecret = v
+	}
+	if v := os.Getenv("BUCKET_ASSET_URL"); v != "" {
+		cfg.BucketAssetURL = v
+	}
+	if v := os.Getenv("BUCKET_TTL_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			cfg.BucketTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if v, ok := os.LookupEnv("DATABASE_PATH"); ok {
+		cfg.DatabasePath = v
+	}
+	if v := os.Getenv("DATABASE_URL"); v != "" {
+		cfg.DatabaseURL = v
+	}
+	if v := os.Getenv("REDIRECTS_PATH"); v != "" {
+		cfg.RedirectsPath = v
+	}
+	if v := os.Getenv("CHANGELOG_PATH"); v != "" {
+		cfg.ChangelogPath = v
+	}
+	if v := os.Getenv("TEAM_PLANNER_PATH"); v != "" {
+		cfg.PlannerPath = v
+	}
+	if v := os.Getenv("TEAM_PLANNER_SET"); v != "" {
+		cfg.PlannerSet = v
+	}
+	if v := strings.ToLower(strings.TrimSpace(os.Getenv("RENDER_MODE"))); v != "" {
+		cfg.RenderMode = v
+	}
+	if v := os.Getenv("IMAGE_CACHE_DIR"); v != "" {
+		cfg.ImageCacheDir = v
+	}
+	if v := os.Getenv("IMAGE_CACHE_MAX_MB"); v != "" {
+		if mb, err := strconv.ParseInt(v, 10, 64); err == nil && mb > 0 {
+			cfg.ImageCacheMB = mb
+		}
+	}
+	if v := os.Getenv("BATCH_BODY_MAX_KB"); v != "" {
+		if kb, err := strconv.ParseInt(v, 10, 64); err == nil && kb > 0 {
+			cfg.BatchBodyKB = kb
+		}
+	}
+	if v := os.Getenv("DEV"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Dev = enabled
+		}
+	}
+	if v := strings.ToLower(strings.TrimSpace(os.Getenv("WARMUP"))); v != "" {
+		cfg.Warmup = v
+	}
+	if v := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_FORMAT"))); v != "" {
+		cfg.LogFormat = v
+	}
+	if v := os.Getenv("ACCESS_LOG_PATH"); v != "" {
+		cfg.AccessLogPath = v
+	}
+	if v := os.Getenv("ACCESS_LOG_MAX_MB"); v != "" {
+		if mb, err := strconv.ParseInt(v, 10, 64); err == nil && mb >= 0 {
+			cfg.AccessLogMB = mb
+		}
+	}
+	if v := os.Getenv("ACCESS_LOG_BACKUPS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.AccessLogKeep = n
+		}
+	}
+	if v := os.Getenv("RENDER_CACHE_ENTRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.RenderCache = n
+		}
+	}
+	if v := os.Getenv("REDIS_URL"); v != "" {
+		cfg.RedisURL = v
+	}
+	if v := os.Getenv("CACHE_ENTRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.CacheEntries = n
+		}
+	}
+	if v := os.Getenv("SCALING_ICONS_PATH"); v != "" {
+		cfg.ScalingIconsPath = v
+	}
+	if v := os.Getenv("PRECONNECT_ORIGINS"); v != "" {
+		cfg.PreconnectOrigins = splitList(v)
+	}
+	if v := os.Getenv("TRUSTED_PROXIES"); v != "" {
+		cfg.TrustedProxies = splitList(v)
+	}
+	if v := os.Getenv("ADMIN_TOKEN"); v != "" {
+		cfg.AdminToken = v
+	}
+	if v := os.Getenv("LOBBY_SECRET"); v != "" {
+		cfg.LobbySecret = v
+	}
+	if v := os.Getenv("SESSION_SECRET"); v != "" {
+		cfg.SessionSecret = v
+	}
+	if v := os.Getenv("VOTER_SECRET"); v != "" {
+		cfg.VoterSecret = v
+	}
+	if v := os.Getenv("ASSET_INTEGRITY"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.AssetIntegrity = enabled
+		}
+	}
+	if v := os.Getenv("INDEXING"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Indexing = enabled
+		}
+	}
 
 	return cfg
 }
 
+// splitList parses a comma-separated env value, dropping empty entries.
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 // ensurePortFormat accepts "8080" or ":8080" and always returns ":port".
 func ensurePortFormat(port string) string {
 	if port == "" {
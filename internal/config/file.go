@@ -0,0 +1,215 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"sft/internal/yamlite"
+)
+
+// fileEnv holds the variables LoadFile set and their values, so a later
+// load can tell them from ones the environment set.
+var fileEnv = struct {
+	sync.Mutex
+	values map[string]string
+}{values: make(map[string]string)}
+
+// DefaultFiles are the config files read from the working directory when
+// neither --config nor SFT_CONFIG names one.
+var DefaultFiles = []string{"sft.yaml", "sft.yml", "sft.toml"}
+
+// FilePath returns the config file to read: the --config argument, else
+// SFT_CONFIG, else the first of DefaultFiles that exists. It returns ""
+// when there is none, which is not an error.
+func FilePath(args []string) string {
+	for i, arg := range args {
+		if v, ok := strings.CutPrefix(arg, "--config="); ok {
+			return v
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	if v := os.Getenv("SFT_CONFIG"); v != "" {
+		return v
+	}
+	for _, name := range DefaultFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// LoadFile reads a YAML or TOML config file into the environment, for Load
+// to pick up. Keys are the environment variable names, in any case and
+// with '-' or nesting in place of '_':
+//
+//	static:
+//	  cache-seconds: 3600   # STATIC_CACHE_SECONDS
+//	preconnect_origins: [https://cdn.example.com]
+//
+// Variables already set in the environment win over the file. Calling
+// LoadFile again, as a config reload does, updates the variables an
+// earlier call set and unsets those the file no longer has.
+func LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values, err = parseYAML(f)
+	case ".toml":
+		values, err = parseTOML(f)
+	default:
+		return fmt.Errorf("config: %s: unsupported format, want .yaml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	fileEnv.Lock()
+	defer fileEnv.Unlock()
+	prev := fileEnv.values
+	fileEnv.values = make(map[string]string, len(values))
+	for key, old := range prev {
+		if _, ok := values[key]; !ok && os.Getenv(key) == old {
+			os.Unsetenv(key)
+		}
+	}
+	for key, value := range values {
+		if current, set := os.LookupEnv(key); set {
+			if old, ours := prev[key]; !ours || current != old {
+				continue
+			}
+		}
+		os.Setenv(key, value)
+		fileEnv.values[key] = value
+	}
+	return nil
+}
+
+// parseYAML flattens a settings file into environment keys: nested
+// mapping keys are joined with '_' and lists with ','.
+func parseYAML(r io.Reader) (map[string]string, error) {
+	doc, err := yamlite.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	if doc == nil {
+		return values, nil
+	}
+	if doc.Kind != yamlite.Map {
+		return nil, fmt.Errorf("line %d: want a mapping of settings", doc.Line)
+	}
+	return values, flattenYAML(values, "", doc)
+}
+
+func flattenYAML(values map[string]string, prefix string, m *yamlite.Node) error {
+	for _, p := range m.Keys {
+		key := envKey(p.Key)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch v := p.Value; v.Kind {
+		case yamlite.Map:
+			if err := flattenYAML(values, key, v); err != nil {
+				return err
+			}
+		case yamlite.List:
+			items := make([]string, 0, len(v.Items))
+			for _, item := range v.Items {
+				if item.Kind != yamlite.Scalar {
+					return fmt.Errorf("line %d: want a list of values", item.Line)
+				}
+				items = append(items, item.Value)
+			}
+			values[key] = strings.Join(items, ",")
+		default:
+			values[key] = v.Value
+		}
+	}
+	return nil
+}
+
+// parseTOML reads the subset of TOML a flat settings file needs: tables,
+// dotted keys, scalars and single-line arrays of scalars.
+func parseTOML(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	var table string
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		content := strings.TrimSpace(yamlite.StripComment(scanner.Text()))
+		if content == "" {
+			continue
+		}
+		if strings.HasPrefix(content, "[[") {
+			return nil, fmt.Errorf("line %d: arrays of tables are not supported", n)
+		}
+		if name, ok := strings.CutPrefix(content, "["); ok {
+			name, ok = strings.CutSuffix(name, "]")
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated table header", n)
+			}
+			table = envKey(strings.TrimSpace(name))
+			continue
+		}
+
+		name, value, ok := strings.Cut(content, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("line %d: want key = value", n)
+		}
+		key := envKey(strings.TrimSpace(name))
+		if table != "" {
+			key = table + "_" + key
+		}
+		v, err := scalarOrList(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		values[key] = v
+	}
+	return values, scanner.Err()
+}
+
+// scalarOrList returns a scalar's value, or a [a, b] list joined with
+// commas as splitList expects.
+func scalarOrList(value string) (string, error) {
+	inner, ok := strings.CutPrefix(value, "[")
+	if !ok {
+		return yamlite.Unquote(value)
+	}
+	inner, ok = strings.CutSuffix(inner, "]")
+	if !ok {
+		return "", fmt.Errorf("unterminated list %s", value)
+	}
+	var items []string
+	for _, item := range strings.Split(inner, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		v, err := yamlite.Unquote(item)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, v)
+	}
+	return strings.Join(items, ","), nil
+}
+
+// envKey maps a file key such as "cache-seconds" or "site.url" to its
+// environment variable form.
+func envKey(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+}
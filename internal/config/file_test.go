@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigFiles(t *testing.T) {
+	want := map[string]string{
+		"SITE_URL":             "https://tft.example.com/#top",
+		"SITE_NAME":            "It's TFT",
+		"STATIC_CACHE_SECONDS": "3600",
+		"PRECONNECT_ORIGINS":   "https://cdn.example.com,https://fonts.example.com",
+		"ASSET_WATCH_SECONDS":  "0",
+	}
+
+	yaml := `
+# sft.yaml
+site_url: https://tft.example.com/#top
+site-name: 'It''s TFT'   # quoted
+static:
+  cache_seconds: 3600
+preconnect_origins:
+  - https://cdn.example.com
+  - "https://fonts.example.com"
+asset_watch_seconds: 0
+`
+	got, err := parseYAML(strings.NewReader(yaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAML = %v\nwant %v", got, want)
+	}
+
+	toml := `
+site_url = "https://tft.example.com/#top"
+site-name = 'It''s TFT'
+preconnect_origins = ["https://cdn.example.com", "https://fonts.example.com"] # hints
+asset_watch_seconds = 0
+
+[static]
+cache_seconds = 3600
+`
+	got, err = parseTOML(strings.NewReader(toml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTOML = %v\nwant %v", got, want)
+	}
+}
+
+func TestLoadFile_EnvWins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sft.yaml")
+	if err := os.WriteFile(path, []byte("site_name: From File\nstatic_cache_seconds: 60\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SITE_NAME", "From Env")
+	t.Setenv("STATIC_CACHE_SECONDS", "")
+	os.Unsetenv("STATIC_CACHE_SECONDS")
+
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Load()
+	if cfg.SiteName != "From Env" || cfg.StaticCacheSec != 60 {
+		t.Errorf("SiteName = %q, StaticCacheSec = %d; want the env name and the file's cache", cfg.SiteName, cfg.StaticCacheSec)
+	}
+
+	// A reload picks up edited file values but still not over the env.
+	if err := os.WriteFile(path, []byte("site_name: Edited\nstatic_cache_seconds: 120\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := Load(); cfg.SiteName != "From Env" || cfg.StaticCacheSec != 120 {
+		t.Errorf("after reload SiteName = %q, StaticCacheSec = %d", cfg.SiteName, cfg.StaticCacheSec)
+	}
+
+	if got := FilePath([]string{"--config=" + path}); got != path {
+		t.Errorf("FilePath = %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secrets are the settings that may be read from a file named by
+// <NAME>_FILE, the Docker and Kubernetes secrets convention, instead of
+// the environment, where values show up in process inspection and crash
+// dumps. New secret settings belong here.
+var secrets = []struct {
+	env   string
+	field func(*Config) *string
+}{
+	{"ADMIN_TOKEN", func(c *Config) *string { return &c.AdminToken }},
+	{"LOBBY_SECRET", func(c *Config) *string { return &c.LobbySecret }},
+	{"SESSION_SECRET", func(c *Config) *string { return &c.SessionSecret }},
+	{"VOTER_SECRET", func(c *Config) *string { return &c.VoterSecret }},
+	{"BUCKET_SECRET_KEY", func(c *Config) *string { return &c.BucketSecret }},
+	{"REDIS_URL", func(c *Config) *string { return &c.RedisURL }},
+	{"DATABASE_URL", func(c *Config) *string { return &c.DatabaseURL }},
+}
+
+// LoadSecretFiles sets each secret whose <NAME>_FILE variable is set from
+// that file, without its trailing newline. Setting both NAME and
+// NAME_FILE is an error, as is a file that cannot be read: starting
+// without a configured secret would fail open or silently.
+func LoadSecretFiles(cfg *Config) error {
+	for _, s := range secrets {
+		path := os.Getenv(s.env + "_FILE")
+		if path == "" {
+			continue
+		}
+		if os.Getenv(s.env) != "" {
+			return fmt.Errorf("config: both %s and %s_FILE are set", s.env, s.env)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("config: %s_FILE: %w", s.env, err)
+		}
+		*s.field(cfg) = strings.TrimRight(string(data), "\r\n")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSecretFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "admin_token")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ADMIN_TOKEN", "")
+	t.Setenv("ADMIN_TOKEN_FILE", path)
+	t.Setenv("LOBBY_SECRET_FILE", "")
+
+	cfg := Load()
+	if err := LoadSecretFiles(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AdminToken != "s3cret" {
+		t.Errorf("AdminToken = %q, want the file contents without the newline", cfg.AdminToken)
+	}
+
+	t.Setenv("ADMIN_TOKEN", "plain")
+	if err := LoadSecretFiles(&cfg); err == nil {
+		t.Error("want an error when both ADMIN_TOKEN and ADMIN_TOKEN_FILE are set")
+	}
+
+	t.Setenv("ADMIN_TOKEN", "")
+	t.Setenv("ADMIN_TOKEN_FILE", path+".missing")
+	if err := LoadSecretFiles(&cfg); err == nil {
+		t.Error("want an error for an unreadable ADMIN_TOKEN_FILE")
+	}
+}
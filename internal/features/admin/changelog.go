@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"sft/internal/features/changelog"
+	"sft/internal/store"
+)
+
+const maxChangelogBodySize = 64 << 10
+
+// NewChangelogHandler manages the stored patch notes at /admin/changelog:
+// GET lists entries, PUT creates or replaces the entry in the JSON body,
+// and DELETE ?version=<version> removes one.
+func NewChangelogHandler(token string, entries store.ChangelogStore) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var entry store.ChangelogEntry
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxChangelogBodySize)).Decode(&entry); err != nil {
+				http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+				return
+			}
+			if err := changelog.Validate(&entry); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := entries.PutChangelogEntry(r.Context(), &entry); err != nil {
+				logger.Printf("admin changelog: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		case http.MethodDelete:
+			err := entries.DeleteChangelogEntry(r.Context(), r.URL.Query().Get("version"))
+			if errors.Is(err, store.ErrNotFound) {
+				http.Error(w, "Changelog entry not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				logger.Printf("admin changelog: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		list, err := entries.ListChangelog(r.Context(), 1000)
+		if err != nil {
+			logger.Printf("admin changelog: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(list)
+	}
+}
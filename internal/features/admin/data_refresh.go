@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"sft/internal/services"
+)
+
+// NewRefreshStatsHandler serves GET /admin/data-refresh, the counters of
+// the scheduled set data refresh, as JSON to bearer-token holders.
+func NewRefreshStatsHandler(token string, stats func() services.RefreshStats) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(stats())
+	}
+}
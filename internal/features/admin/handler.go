@@ -0,0 +1,76 @@
+// Package admin provides operator-only endpoints.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Target is a named dependency that can be reloaded on demand.
+type Target struct {
+	Name   string
+	Reload func(ctx context.Context) error
+}
+
+type reloadResponse struct {
+	Reloaded []string          `json:"reloaded"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// NewReloadHandler returns a handler that reloads every target when called
+// with POST and a matching "Authorization: Bearer <token>" header.
+func NewReloadHandler(token string, targets []Target) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !Authorized(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		resp := reloadResponse{Reloaded: []string{}}
+		for _, t := range targets {
+			if err := t.Reload(r.Context()); err != nil {
+				logger.Printf("admin reload %s failed: %v", t.Name, err)
+				if resp.Errors == nil {
+					resp.Errors = make(map[string]string)
+				}
+				resp.Errors[t.Name] = err.Error()
+				continue
+			}
+			resp.Reloaded = append(resp.Reloaded, t.Name)
+		}
+
+		status := http.StatusOK
+		if len(resp.Errors) > 0 {
+			status = http.StatusInternalServerError
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// Authorized reports whether the request carries the expected bearer token.
+// An empty token never authorizes anything.
+func Authorized(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) == 1
+}
@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"sft/internal/assetmiss"
+)
+
+const defaultMissingAssetsLimit = 20
+
+// NewMissingAssetsHandler serves the static 404 counts at
+// /admin/missing-assets: GET returns the top patterns (?limit=, default 20)
+// and DELETE clears the counts.
+func NewMissingAssetsHandler(token string, tracker *assetmiss.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			limit := defaultMissingAssetsLimit
+			if v := r.URL.Query().Get("limit"); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 {
+					http.Error(w, "Invalid limit", http.StatusBadRequest)
+					return
+				}
+				limit = n
+			}
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			_ = json.NewEncoder(w).Encode(tracker.Summary(limit))
+		case http.MethodDelete:
+			tracker.Reset()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
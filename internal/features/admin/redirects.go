@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"sft/internal/redirects"
+	"sft/internal/store"
+)
+
+const maxRedirectBodySize = 4 << 10
+
+// NewRedirectsHandler manages the redirect table at /admin/redirects:
+// GET lists rules with their hit counts, PUT creates or replaces the rule in
+// the JSON body, and DELETE ?from=<path> removes one. Writes reload table.
+func NewRedirectsHandler(token string, rules store.RedirectStore, table *redirects.Table) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var rule store.Redirect
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRedirectBodySize)).Decode(&rule); err != nil {
+				http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+				return
+			}
+			if err := redirects.Validate(&rule); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := rules.PutRedirect(r.Context(), &rule); err != nil {
+				logger.Printf("admin redirects: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		case http.MethodDelete:
+			err := rules.DeleteRedirect(r.Context(), r.URL.Query().Get("from"))
+			if errors.Is(err, store.ErrNotFound) {
+				http.Error(w, "Redirect not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				logger.Printf("admin redirects: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Method != http.MethodGet {
+			if err := table.Reload(r.Context()); err != nil {
+				logger.Printf("admin redirects: %v", err)
+			}
+		}
+		list, err := rules.ListRedirects(r.Context())
+		if err != nil {
+			logger.Printf("admin redirects: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(list)
+	}
+}
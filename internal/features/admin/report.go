@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"sft/internal/services"
+)
+
+// Report summarizes the health of the loaded dataset for operators.
+type Report struct {
+	Version       string                  `json:"version"`
+	Source        string                  `json:"source,omitempty"` // source that served the data, for fallback chains
+	Units         int                     `json:"units"`
+	Traits        int                     `json:"traits"`
+	AbilityIssues []services.AbilityIssue `json:"abilityIssues"`
+	IconIssues    []services.IconIssue    `json:"iconIssues"`
+}
+
+// BuildReport runs the dataset checks against the current units.
+// Icon issues are checked once at startup and passed through as-is.
+func BuildReport(ctx context.Context, units services.UnitsSource, icons []services.IconIssue) (Report, error) {
+	data, err := units.LoadUnits(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	var source string
+	if fallback, ok := units.(interface{ Served() string }); ok {
+		source = fallback.Served()
+	}
+	return Report{
+		Version:       data.Version,
+		Source:        source,
+		Units:         len(data.Units),
+		Traits:        len(data.Traits),
+		AbilityIssues: services.CheckAbilityConsistency(data.Units),
+		IconIssues:    icons,
+	}, nil
+}
+
+// NewReportHandler serves GET /admin/report as JSON to bearer-token holders.
+func NewReportHandler(token string, units services.UnitsSource, icons []services.IconIssue) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !Authorized(r, token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		report, err := BuildReport(r.Context(), units, icons)
+		if err != nil {
+			logger.Printf("admin report failed: %v", err)
+			http.Error(w, "Dataset unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"sft/internal/auth"
+	"sft/internal/store"
+)
+
+const maxAccountBodySize = 4 << 10
+
+var usernameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{3,32}$`)
+
+// AccountAPI serves signup, login and logout under /api/v1/account.
+type AccountAPI struct {
+	users    store.UserStore
+	sessions *auth.Sessions
+	logger   *log.Logger
+}
+
+// NewAccountAPI wires the account endpoints to the user store and session manager.
+func NewAccountAPI(users store.UserStore, sessions *auth.Sessions) *AccountAPI {
+	return &AccountAPI{users: users, sessions: sessions, logger: log.Default()}
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func decodeCredentials(w http.ResponseWriter, r *http.Request) (credentials, bool) {
+	var c credentials
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccountBodySize)).Decode(&c); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return c, false
+	}
+	c.Username = strings.TrimSpace(c.Username)
+	return c, true
+}
+
+// Signup handles POST /api/v1/account/signup and logs the new user in.
+func (a *AccountAPI) Signup(w http.ResponseWriter, r *http.Request) {
+	creds, ok := decodeCredentials(w, r)
+	if !ok {
+		return
+	}
+	if !usernameRe.MatchString(creds.Username) {
+		writeError(w, http.StatusBadRequest, "username must be 3-32 letters, digits, '-' or '_'")
+		return
+	}
+
+	hash, err := auth.HashPassword(creds.Password)
+	if errors.Is(err, auth.ErrPasswordLength) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		a.logger.Printf("account: hash password: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not create account")
+		return
+	}
+
+	user := &store.User{Username: creds.Username, PasswordHash: hash}
+	err = a.users.CreateUser(r.Context(), user)
+	if errors.Is(err, store.ErrConflict) {
+		writeError(w, http.StatusConflict, "username already taken")
+		return
+	}
+	if err != nil {
+		a.logger.Printf("account: create user: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not create account")
+		return
+	}
+
+	if err := a.sessions.Login(w, r, user); err != nil {
+		a.logger.Printf("account: login after signup: %v", err)
+	}
+	writeJSON(w, http.StatusCreated, user)
+}
+
+// Login handles POST /api/v1/account/login.
+func (a *AccountAPI) Login(w http.ResponseWriter, r *http.Request) {
+	creds, ok := decodeCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := a.users.GetUserByUsername(r.Context(), creds.Username)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		a.logger.Printf("account: lookup %q: %v", creds.Username, err)
+		writeError(w, http.StatusInternalServerError, "could not log in")
+		return
+	}
+	hash := auth.DummyHash()
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	if !auth.CheckPassword(hash, creds.Password) || user == nil {
+		writeError(w, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+
+	if err := a.sessions.Login(w, r, user); err != nil {
+		a.logger.Printf("account: create session: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not log in")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// Logout handles POST /api/v1/account/logout.
+func (a *AccountAPI) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := a.sessions.Logout(w, r); err != nil {
+		a.logger.Printf("account: logout: %v", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me handles GET /api/v1/account/me.
+func (a *AccountAPI) Me(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFrom(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "not logged in")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, user)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sft/internal/auth"
+	"sft/internal/middleware"
+	"sft/internal/models"
+	"sft/internal/store"
+)
+
+func newTestAccountHandler(t *testing.T) http.Handler {
+	t.Helper()
+	db := openTestStore(t)
+
+	account := NewAccountAPI(db, auth.NewSessions(db))
+	comps := NewCompsAPI(db, staticUnits{data: &models.UnitsData{
+		Units: []models.Unit{{Name: "Ahri", Slug: "ahri"}},
+	}})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/account/signup", account.Signup)
+	mux.HandleFunc("POST /api/v1/account/login", account.Login)
+	mux.HandleFunc("POST /api/v1/account/logout", account.Logout)
+	mux.HandleFunc("GET /api/v1/account/me", account.Me)
+	mux.HandleFunc("POST /api/v1/comps", comps.Create)
+	mux.HandleFunc("GET /api/v1/comps", comps.List)
+	mux.HandleFunc("DELETE /api/v1/comps/{id}", comps.Delete)
+	return withLogin(db, mux)
+}
+
+// withLogin wraps h in the cookie session and login middleware, as the
+// router does.
+func withLogin(db *store.SQLiteStore, h http.Handler) http.Handler {
+	session := middleware.Session(db, middleware.SessionOptions{Secret: "test"})
+	return session(auth.NewSessions(db).Middleware(h))
+}
+
+func do(h http.Handler, method, path, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func sessionCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == middleware.DefaultSessionCookie {
+			if !c.HttpOnly {
+				t.Error("session cookie must be HttpOnly")
+			}
+			return c
+		}
+	}
+	t.Fatal("expected session cookie")
+	return nil
+}
+
+func TestAccountAPI_SignupLoginOwnership(t *testing.T) {
+	h := newTestAccountHandler(t)
+	creds := `{"username": "scout", "password": "hunter2hunter2"}`
+
+	rec := do(h, http.MethodPost, "/api/v1/account/signup", creds)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("signup: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "$2a$") {
+		t.Error("password hash must not be exposed")
+	}
+	cookie := sessionCookie(t, rec)
+
+	if rec := do(h, http.MethodPost, "/api/v1/account/signup", creds); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate signup: expected 409, got %d", rec.Code)
+	}
+
+	if rec := do(h, http.MethodGet, "/api/v1/account/me", "", cookie); rec.Code != http.StatusOK {
+		t.Errorf("me: expected 200, got %d", rec.Code)
+	}
+
+	rec = do(h, http.MethodPost, "/api/v1/comps", `{"name": "Mine", "board": "1~001ahri"}`, cookie)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create comp: expected 201, got %d", rec.Code)
+	}
+	location := rec.Header().Get("Location")
+
+	rec = do(h, http.MethodGet, "/api/v1/comps?owner=me", "", cookie)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"Mine"`) {
+		t.Errorf("list mine: got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := do(h, http.MethodDelete, location, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("anonymous delete of owned comp: expected 403, got %d", rec.Code)
+	}
+
+	if rec := do(h, http.MethodPost, "/api/v1/account/logout", "", cookie); rec.Code != http.StatusNoContent {
+		t.Errorf("logout: expected 204, got %d", rec.Code)
+	}
+	if rec := do(h, http.MethodGet, "/api/v1/account/me", "", cookie); rec.Code != http.StatusUnauthorized {
+		t.Errorf("me after logout: expected 401, got %d", rec.Code)
+	}
+
+	if rec := do(h, http.MethodPost, "/api/v1/account/login", `{"username": "scout", "password": "wrong-password"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad login: expected 401, got %d", rec.Code)
+	}
+	rec = do(h, http.MethodPost, "/api/v1/account/login", creds)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", rec.Code)
+	}
+	if rec := do(h, http.MethodDelete, location, "", sessionCookie(t, rec)); rec.Code != http.StatusNoContent {
+		t.Errorf("owner delete: expected 204, got %d", rec.Code)
+	}
+}
+
+func TestAccountAPI_SignupValidation(t *testing.T) {
+	h := newTestAccountHandler(t)
+
+	tests := map[string]string{
+		"short username": `{"username": "ab", "password": "longenough"}`,
+		"bad characters": `{"username": "a b c", "password": "longenough"}`,
+		"short password": `{"username": "valid", "password": "short"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if rec := do(h, http.MethodPost, "/api/v1/account/signup", body); rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"sft/internal/cache"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+// maxBatchSize caps the entries of one batch request.
+const maxBatchSize = 1000
+
+// decodeBatch reads a batch request body of at most maxBody bytes into v,
+// answering 413 or 400 and returning false when it cannot.
+func decodeBatch(w http.ResponseWriter, r *http.Request, maxBody int64, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+// checkBatchSize answers 413 and returns false for more than maxBatchSize
+// entries.
+func checkBatchSize(w http.ResponseWriter, n int, what string) bool {
+	if n > maxBatchSize {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d %s per request", maxBatchSize, what))
+		return false
+	}
+	return true
+}
+
+type unitsBatchRequest struct {
+	Queries []string `json:"queries"` // query strings as for GET /api/v1/units, e.g. "cost=4&trait=mage"
+}
+
+type unitsBatchResult struct {
+	Query   string        `json:"query"`
+	Version string        `json:"version,omitempty"`
+	Units   []models.Unit `json:"units,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+type unitsBatchResponse struct {
+	Results []unitsBatchResult `json:"results"`
+}
+
+// NewUnitsBatchHandler serves POST /api/v1/units/batch, answering many
+// GET /api/v1/units queries in one call. Results keep request order; a
+// malformed query or unknown version fails only its own entry. Bodies over
+// maxBody bytes are rejected.
+func NewUnitsBatchHandler(units services.UnitsSource, maxBody int64) http.HandlerFunc {
+	logger := log.Default()
+	history, _ := units.(VersionHistory)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req unitsBatchRequest
+		if !decodeBatch(w, r, maxBody, &req) || !checkBatchSize(w, len(req.Queries), "queries") {
+			return
+		}
+
+		current, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("units batch: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		resp := unitsBatchResponse{Results: make([]unitsBatchResult, len(req.Queries))}
+		for i, query := range req.Queries {
+			result := unitsBatchResult{Query: query}
+			values, err := url.ParseQuery(query)
+			data := current
+			if version := values.Get("version"); err == nil && version != "" && version != current.Version {
+				if history == nil {
+					err = services.ErrUnknownVersion
+				} else {
+					data, err = history.LoadVersion(r.Context(), version)
+				}
+			}
+			switch {
+			case errors.Is(err, services.ErrUnknownVersion):
+				result.Error = "unknown version"
+			case err != nil:
+				result.Error = err.Error()
+			default:
+				result.Version = data.Version
+				result.Units = services.ParseUnitFilter(values).Apply(data.Units)
+				if result.Units == nil {
+					result.Units = []models.Unit{}
+				}
+			}
+			resp.Results[i] = result
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+type synergiesBatchRequest struct {
+	Boards []string `json:"boards"` // encoded models.BoardState values
+}
+
+type synergiesBatchResult struct {
+	Board     string             `json:"board"`
+	Synergies []services.Synergy `json:"synergies,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+type synergiesBatchResponse struct {
+	Results []synergiesBatchResult `json:"results"`
+}
+
+// NewSynergiesBatchHandler serves POST /api/v1/synergies/batch, resolving
+// the synergies of many board codes in one call. Results keep request
+// order and share results with NewSynergiesHandler; an invalid code fails
+// only its own entry. Bodies over maxBody bytes are rejected.
+func NewSynergiesBatchHandler(units services.UnitsSource, results cache.Cache, maxBody int64) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req synergiesBatchRequest
+		if !decodeBatch(w, r, maxBody, &req) || !checkBatchSize(w, len(req.Boards), "boards") {
+			return
+		}
+
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("synergies batch: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		resp := synergiesBatchResponse{Results: make([]synergiesBatchResult, len(req.Boards))}
+		for i, code := range req.Boards {
+			result := synergiesBatchResult{Board: code}
+			if state, err := models.DecodeBoardState(code); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Synergies = boardSynergies(r, results, data, code, state)
+			}
+			resp.Results[i] = result
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"sft/internal/cache"
+	"sft/internal/models"
+)
+
+func TestUnitsBatchHandler(t *testing.T) {
+	units := versionedUnits{
+		staticUnits: staticUnits{data: &models.UnitsData{Version: "new", Units: []models.Unit{
+			{Name: "Garen", Slug: "garen", Cost: 1},
+			{Name: "Lux", Slug: "lux", Cost: 4},
+		}}},
+		past: map[string]*models.UnitsData{
+			"old": {Version: "old", Units: []models.Unit{{Name: "Garen", Cost: 2}}},
+		},
+	}
+	h := NewUnitsBatchHandler(units, 256)
+
+	rec := do(h, http.MethodPost, "/api/v1/units/batch", `{"queries": ["cost=4", "", "version=old&cost=2", "version=nope", "q=%zz"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got unitsBatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Results) != 5 {
+		t.Fatalf("got %d results, want 5", len(got.Results))
+	}
+	if r := got.Results[0]; len(r.Units) != 1 || r.Units[0].Name != "Lux" || r.Version != "new" {
+		t.Errorf("cost=4: %+v", r)
+	}
+	if r := got.Results[1]; len(r.Units) != 2 {
+		t.Errorf("empty query: %+v", r)
+	}
+	if r := got.Results[2]; len(r.Units) != 1 || r.Version != "old" {
+		t.Errorf("old version: %+v", r)
+	}
+	if r := got.Results[3]; r.Error != "unknown version" || r.Units != nil {
+		t.Errorf("unknown version should fail alone, got %+v", r)
+	}
+	if r := got.Results[4]; r.Error == "" {
+		t.Errorf("malformed query should fail alone, got %+v", r)
+	}
+
+	large := `{"queries": ["` + strings.Repeat("q=garen&", 40) + `"]}`
+	if rec := do(h, http.MethodPost, "/api/v1/units/batch", large); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body: expected 413, got %d", rec.Code)
+	}
+}
+
+func TestSynergiesBatchHandler(t *testing.T) {
+	results := cache.NewMemory(16)
+	h := NewSynergiesBatchHandler(staticUnits{data: &models.UnitsData{Traits: []models.TraitInfo{
+		{Name: "Bruiser", Slug: "bruiser", Units: []string{"sion", "chogath"},
+			Breakpoints: []models.TraitBreakpoint{{MinUnits: 2, Style: 1}}},
+	}}}, results, 256)
+
+	rec := do(h, http.MethodPost, "/api/v1/synergies/batch", `{"boards": ["1~001sion.011chogath", "2~x", "1~001sion.011chogath"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got synergiesBatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(got.Results))
+	}
+	for _, i := range []int{0, 2} {
+		if r := got.Results[i]; len(r.Synergies) != 1 || !r.Synergies[0].Active || r.Error != "" {
+			t.Errorf("result %d: %+v", i, r)
+		}
+	}
+	if r := got.Results[1]; r.Synergies != nil || r.Error == "" {
+		t.Errorf("invalid code should fail alone, got %+v", r)
+	}
+	if results.Len() != 1 {
+		t.Errorf("cached %d results, want one per distinct board", results.Len())
+	}
+
+	many := `{"boards": [` + strings.TrimSuffix(strings.Repeat(`"1~",`, maxBatchSize+1), ",") + `]}`
+	if rec := do(NewSynergiesBatchHandler(staticUnits{data: &models.UnitsData{}}, nil, 1<<20), http.MethodPost, "/api/v1/synergies/batch", many); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("too many boards: expected 413, got %d", rec.Code)
+	}
+}
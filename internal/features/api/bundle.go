@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+
+	"sft/internal/i18n"
+	"sft/internal/middleware"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+type bundleResponse struct {
+	Version string             `json:"version"`
+	Locale  string             `json:"locale"`
+	Units   []models.Unit      `json:"units"`
+	Traits  []models.TraitInfo `json:"traits"`
+	Items   []models.Item      `json:"items"`
+	Shop    *models.ShopOdds   `json:"shop"`
+}
+
+// encodedBundle is one rendering of the bundle, kept until the data changes.
+type encodedBundle struct {
+	etag string
+	json []byte
+	gz   []byte
+}
+
+// NewBundleHandler serves GET /api/v1/bundle: units, traits, items and
+// shop odds in one document for clients that sync everything at once.
+// The body is encoded and gzipped once per data revision and locale, and
+// revalidated by ETag, so polling clients cost little.
+func NewBundleHandler(units services.UnitsSource) http.HandlerFunc {
+	logger := log.Default()
+	var mu sync.Mutex
+	bundles := make(map[string]*encodedBundle) // by locale
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("bundle: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+		locale := i18n.FromContext(r.Context())
+		etag := bundleETag(data.Revision(), locale)
+
+		mu.Lock()
+		b := bundles[locale]
+		if b == nil || b.etag != etag {
+			if b, err = encodeBundle(data, locale, etag); err == nil {
+				bundles[locale] = b
+			}
+		}
+		mu.Unlock()
+		if err != nil {
+			logger.Printf("bundle: %v", err)
+			writeError(w, http.StatusInternalServerError, "could not build bundle")
+			return
+		}
+
+		w.Header().Set("ETag", b.etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if !slices.Contains(w.Header().Values("Vary"), "Accept-Encoding") {
+			w.Header().Add("Vary", "Accept-Encoding")
+		}
+		if r.Header.Get("If-None-Match") == b.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		body := b.json
+		if middleware.AcceptsGzip(r.Header.Get("Accept-Encoding")) {
+			w.Header().Set("Content-Encoding", "gzip")
+			body = b.gz
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}
+}
+
+func bundleETag(revision, locale string) string {
+	sum := sha256.Sum256([]byte(revision + "\x00" + locale))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+func encodeBundle(data *models.UnitsData, locale, etag string) (*encodedBundle, error) {
+	resp := bundleResponse{
+		Version: data.Version,
+		Locale:  locale,
+		Units:   data.Units,
+		Traits:  data.Traits,
+		Items:   data.Items,
+		Shop:    data.Shop,
+	}
+	// Clients iterate these; send empty lists rather than null.
+	if resp.Units == nil {
+		resp.Units = []models.Unit{}
+	}
+	if resp.Traits == nil {
+		resp.Traits = []models.TraitInfo{}
+	}
+	if resp.Items == nil {
+		resp.Items = []models.Item{}
+	}
+
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("encode bundle: %w", err)
+	}
+	var gz bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("compress bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress bundle: %w", err)
+	}
+	return &encodedBundle{etag: etag, json: raw, gz: gz.Bytes()}, nil
+}
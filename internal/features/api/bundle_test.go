@@ -0,0 +1,72 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestBundleHandler(t *testing.T) {
+	units := &staticUnits{data: &models.UnitsData{
+		Version: "v1",
+		Units:   []models.Unit{{Name: "Ahri", Slug: "ahri"}},
+		Shop:    &models.ShopOdds{},
+	}}
+	handler := NewBundleHandler(units)
+
+	get := func(header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/bundle", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	plain := get(nil)
+	if plain.Code != http.StatusOK || plain.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("identity: got %d, encoding %q", plain.Code, plain.Header().Get("Content-Encoding"))
+	}
+	var bundle bundleResponse
+	if err := json.Unmarshal(plain.Body.Bytes(), &bundle); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if bundle.Version != "v1" || len(bundle.Units) != 1 || bundle.Traits == nil || bundle.Items == nil || bundle.Shop == nil {
+		t.Errorf("unexpected bundle: %+v", bundle)
+	}
+
+	zipped := get(map[string]string{"Accept-Encoding": "gzip"})
+	if zipped.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzipped body, headers %v", zipped.Header())
+	}
+	zr, err := gzip.NewReader(zipped.Body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if body, _ := io.ReadAll(zr); string(body) != plain.Body.String() {
+		t.Error("gzipped body differs from the identity body")
+	}
+
+	etag := plain.Header().Get("ETag")
+	if zipped.Header().Get("ETag") != etag {
+		t.Error("the ETag should not depend on the encoding")
+	}
+	if rec := get(map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation: expected 304, got %d", rec.Code)
+	}
+
+	units.data = &models.UnitsData{Version: "v2"}
+	rec := get(map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("after a data change: got %d with ETag %s", rec.Code, rec.Header().Get("ETag"))
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &bundle); err != nil || bundle.Version != "v2" {
+		t.Errorf("bundle not rebuilt: %v %+v", err, bundle)
+	}
+}
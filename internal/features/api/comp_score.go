@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+type compScoreRequest struct {
+	Board string `json:"board"` // encoded models.BoardState
+}
+
+// NewCompScoreHandler serves POST /api/v1/comps/score with a
+// services.CompScore breakdown of a board code.
+func NewCompScoreHandler(units services.UnitsSource) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req compScoreRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSynergiesBodySize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		state, err := models.DecodeBoardState(req.Board)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("comp score: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, services.ScoreComp(state, data))
+	}
+}
+
+type compScoreBatchRequest struct {
+	Boards []string `json:"boards"` // encoded models.BoardState values
+}
+
+type compScoreBatchResult struct {
+	Board string              `json:"board"`
+	Score *services.CompScore `json:"score,omitempty"`
+	Error string              `json:"error,omitempty"`
+}
+
+type compScoreBatchResponse struct {
+	Results []compScoreBatchResult `json:"results"`
+}
+
+// NewCompScoreBatchHandler serves POST /api/v1/comps/score/batch, scoring
+// many board codes in one call for analysis tools. Results keep request
+// order; an invalid code fails only its own entry. Bodies over maxBody
+// bytes are rejected.
+func NewCompScoreBatchHandler(units services.UnitsSource, maxBody int64) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req compScoreBatchRequest
+		if !decodeBatch(w, r, maxBody, &req) || !checkBatchSize(w, len(req.Boards), "boards") {
+			return
+		}
+
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("comp score batch: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		resp := compScoreBatchResponse{Results: make([]compScoreBatchResult, len(req.Boards))}
+		for i, code := range req.Boards {
+			result := compScoreBatchResult{Board: code}
+			if state, err := models.DecodeBoardState(code); err != nil {
+				result.Error = err.Error()
+			} else {
+				score := services.ScoreComp(state, data)
+				result.Score = &score
+			}
+			resp.Results[i] = result
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
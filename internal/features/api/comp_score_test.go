@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+func TestCompScoreHandler(t *testing.T) {
+	h := NewCompScoreHandler(staticUnits{data: &models.UnitsData{Units: []models.Unit{
+		{Slug: "sion", Cost: 1, Stats: models.UnitStats{Range: 1}},
+		{Slug: "lux", Cost: 3, Stats: models.UnitStats{Range: 4}},
+	}}})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		units  int
+	}{
+		{"scored board", `{"board": "1~001sion.311lux"}`, http.StatusOK, 2},
+		{"empty board", `{"board": "1~"}`, http.StatusOK, 0},
+		{"invalid code", `{"board": "2~x"}`, http.StatusBadRequest, 0},
+		{"bad json", `[`, http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(h, http.MethodPost, "/api/v1/comps/score", tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var got services.CompScore
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if got.Units != tt.units {
+				t.Errorf("got %d units, want %d", got.Units, tt.units)
+			}
+		})
+	}
+}
+
+func TestCompScoreBatchHandler(t *testing.T) {
+	h := NewCompScoreBatchHandler(staticUnits{data: &models.UnitsData{Units: []models.Unit{
+		{Slug: "sion", Cost: 1, Stats: models.UnitStats{Range: 1}},
+	}}}, 256)
+
+	rec := do(h, http.MethodPost, "/api/v1/comps/score/batch", `{"boards": ["1~001sion", "2~x", "1~"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got compScoreBatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(got.Results))
+	}
+	if r := got.Results[0]; r.Score == nil || r.Score.Units != 1 || r.Error != "" {
+		t.Errorf("unexpected first result %+v", r)
+	}
+	if r := got.Results[1]; r.Score != nil || r.Error == "" {
+		t.Errorf("invalid code should fail alone, got %+v", r)
+	}
+
+	large := `{"boards": ["` + strings.Repeat("1~001sion.", 40) + `"]}`
+	if rec := do(h, http.MethodPost, "/api/v1/comps/score/batch", large); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body: expected 413, got %d", rec.Code)
+	}
+}
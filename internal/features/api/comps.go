@@ -0,0 +1,438 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"sft/internal/auth"
+	"sft/internal/models"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+const (
+	maxCompNameLen  = 100
+	maxCompNotesLen = 2000
+	maxCompBodySize = 16 << 10
+	defaultCompList = 50
+	maxCompList     = 200
+)
+
+// CompsAPI serves the saved comps endpoints under /api/v1/comps.
+type CompsAPI struct {
+	comps   store.CompStore
+	votes   store.CompVoteStore
+	units   services.UnitsSource
+	planner services.TeamPlannerCodes
+	signer  *auth.LinkSigner // signs delete tokens of anonymous comps
+	voters  []byte           // HMAC key of anonymous voters' addresses
+	logger  *log.Logger
+}
+
+// NewCompsAPI wires the comps endpoints to a store and the units source used for validation.
+func NewCompsAPI(comps store.CompStore, units services.UnitsSource) *CompsAPI {
+	voters := make([]byte, 32)
+	if _, err := rand.Read(voters); err != nil {
+		panic("api: generating voter key: " + err.Error())
+	}
+	return &CompsAPI{comps: comps, units: units, voters: voters, logger: log.Default()}
+}
+
+// WithVoterSecret keys the hashes of anonymous voters' addresses with
+// secret, so votes stay deduplicated across restarts and instances. An
+// empty secret keeps the per-instance random key.
+func (a *CompsAPI) WithVoterSecret(secret string) *CompsAPI {
+	if secret != "" {
+		a.voters = []byte(secret)
+	}
+	return a
+}
+
+// WithPlanner enables POST /api/v1/comps/import using the given champion ID mapping.
+func (a *CompsAPI) WithPlanner(codes services.TeamPlannerCodes) *CompsAPI {
+	a.planner = codes
+	return a
+}
+
+// WithDeleteTokens hands out a delete token for each anonymous comp on
+// creation, which DELETE then requires in the X-Delete-Token header.
+// Without it anonymous comps cannot be deleted.
+func (a *CompsAPI) WithDeleteTokens(signer *auth.LinkSigner) *CompsAPI {
+	a.signer = signer
+	return a
+}
+
+// WithVotes enables PUT and DELETE /api/v1/comps/{id}/vote.
+func (a *CompsAPI) WithVotes(votes store.CompVoteStore) *CompsAPI {
+	a.votes = votes
+	return a
+}
+
+type compRequest struct {
+	Name  string `json:"name"`
+	Board string `json:"board"`
+	Notes string `json:"notes"`
+}
+
+type compResponse struct {
+	store.Comp
+	URL string `json:"url"` // builder link pre-populated with the board
+	// DeleteToken lets the creator of an anonymous comp delete it; it is
+	// only returned on creation.
+	DeleteToken string `json:"deleteToken,omitempty"`
+}
+
+// compDeleteKind is the LinkSigner kind of comp delete tokens.
+const compDeleteKind = "comp-delete"
+
+func newCompResponse(c store.Comp) compResponse {
+	return compResponse{Comp: c, URL: "/?b=" + url.QueryEscape(c.Board)}
+}
+
+// Create handles POST /api/v1/comps.
+func (a *CompsAPI) Create(w http.ResponseWriter, r *http.Request) {
+	var req compRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCompBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	data, err := a.units.LoadUnits(r.Context())
+	if err != nil {
+		a.logger.Printf("comps: loading units: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+		return
+	}
+
+	a.save(w, r, req, data)
+}
+
+// save validates req and stores it as a comp owned by the caller, if any.
+func (a *CompsAPI) save(w http.ResponseWriter, r *http.Request, req compRequest, data *models.UnitsData) {
+	comp, err := validateComp(req, data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if user := auth.UserFrom(r.Context()); user != nil {
+		comp.OwnerID = user.ID
+	}
+
+	if err := a.comps.CreateComp(r.Context(), comp); err != nil {
+		a.logger.Printf("comps: create: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not save comp")
+		return
+	}
+
+	resp := newCompResponse(*comp)
+	if comp.OwnerID == 0 && a.signer != nil {
+		resp.DeleteToken = a.signer.Sign(compDeleteKind, comp.ID)
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/comps/%d", comp.ID))
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+type importRequest struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Notes string `json:"notes"`
+}
+
+// Import handles POST /api/v1/comps/import, converting an in-game team planner
+// code into a saved comp. Champions fill the board front row first.
+func (a *CompsAPI) Import(w http.ResponseWriter, r *http.Request) {
+	if a.planner == nil {
+		writeError(w, http.StatusServiceUnavailable, "team planner import is not configured")
+		return
+	}
+
+	var req importRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCompBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	planned, err := a.planner.Decode(req.Code)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := a.units.LoadUnits(r.Context())
+	if err != nil {
+		a.logger.Printf("comps: loading units: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+		return
+	}
+
+	state, err := plannerBoard(planned, data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name := req.Name
+	if strings.TrimSpace(name) == "" {
+		name = "Imported comp"
+	}
+	a.save(w, r, compRequest{Name: name, Board: state.Encode(), Notes: req.Notes}, data)
+}
+
+// plannerBoard places planner champions on consecutive hexes, row by row.
+// Codes with more champions than the board has hexes are rejected.
+func plannerBoard(planned services.TeamPlannerComp, data *models.UnitsData) (models.BoardState, error) {
+	if hexes := models.BoardRows * models.BoardCols; len(planned.APINames) > hexes {
+		return models.BoardState{}, fmt.Errorf("more than %d champions", hexes)
+	}
+	byAPIName := make(map[string]string, len(data.Units))
+	for _, u := range data.Units {
+		byAPIName[u.APIName] = u.Slug
+	}
+
+	var state models.BoardState
+	for i, apiName := range planned.APINames {
+		slug, ok := byAPIName[apiName]
+		if !ok {
+			return models.BoardState{}, fmt.Errorf("unknown champion %q", apiName)
+		}
+		state.Placements = append(state.Placements, models.Placement{
+			Row:   i / models.BoardCols,
+			Col:   i % models.BoardCols,
+			Unit:  slug,
+			Stars: 1,
+		})
+	}
+	return state, nil
+}
+
+// List handles GET /api/v1/comps?limit=&sort=new|top; ?owner=me restricts
+// to the caller's comps.
+func (a *CompsAPI) List(w http.ResponseWriter, r *http.Request) {
+	limit := defaultCompList
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(n, maxCompList)
+	}
+	sort := store.CompSortNew
+	switch v := store.CompSort(r.URL.Query().Get("sort")); v {
+	case "", store.CompSortNew:
+	case store.CompSortTop:
+		sort = v
+	default:
+		writeError(w, http.StatusBadRequest, "sort must be 'new' or 'top'")
+		return
+	}
+
+	var comps []store.Comp
+	var err error
+	switch owner := r.URL.Query().Get("owner"); owner {
+	case "":
+		comps, err = a.comps.ListComps(r.Context(), sort, limit)
+	case "me":
+		user := auth.UserFrom(r.Context())
+		if user == nil {
+			writeError(w, http.StatusUnauthorized, "not logged in")
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		comps, err = a.comps.ListCompsByOwner(r.Context(), user.ID, limit)
+	default:
+		writeError(w, http.StatusBadRequest, "owner must be 'me'")
+		return
+	}
+	if err != nil {
+		a.logger.Printf("comps: list: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not list comps")
+		return
+	}
+
+	resp := make([]compResponse, 0, len(comps))
+	for _, c := range comps {
+		resp = append(resp, newCompResponse(c))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Get handles GET /api/v1/comps/{id}.
+func (a *CompsAPI) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := compID(w, r)
+	if !ok {
+		return
+	}
+
+	comp, err := a.comps.GetComp(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "comp not found")
+		return
+	}
+	if err != nil {
+		a.logger.Printf("comps: get %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "could not load comp")
+		return
+	}
+	writeJSON(w, http.StatusOK, newCompResponse(*comp))
+}
+
+// Delete handles DELETE /api/v1/comps/{id}. Owned comps can only be
+// deleted by their owner, anonymous ones with the delete token they were
+// created with.
+func (a *CompsAPI) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := compID(w, r)
+	if !ok {
+		return
+	}
+
+	comp, err := a.comps.GetComp(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "comp not found")
+		return
+	}
+	if err != nil {
+		a.logger.Printf("comps: get %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "could not delete comp")
+		return
+	}
+	if comp.OwnerID != 0 {
+		user := auth.UserFrom(r.Context())
+		if user == nil || user.ID != comp.OwnerID {
+			writeError(w, http.StatusForbidden, "comp belongs to another user")
+			return
+		}
+	} else if a.signer == nil || !a.signer.Verify(compDeleteKind, id, r.Header.Get("X-Delete-Token")) {
+		writeError(w, http.StatusForbidden, "missing or invalid delete token")
+		return
+	}
+
+	err = a.comps.DeleteComp(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "comp not found")
+		return
+	}
+	if err != nil {
+		a.logger.Printf("comps: delete %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "could not delete comp")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type voteResponse struct {
+	Votes int64 `json:"votes"`
+	Voted bool  `json:"voted"`
+}
+
+// Vote handles PUT /api/v1/comps/{id}/vote. Each user, or each IP address
+// for anonymous visitors, counts once however often it votes.
+func (a *CompsAPI) Vote(w http.ResponseWriter, r *http.Request) {
+	a.vote(w, r, true)
+}
+
+// Unvote handles DELETE /api/v1/comps/{id}/vote.
+func (a *CompsAPI) Unvote(w http.ResponseWriter, r *http.Request) {
+	a.vote(w, r, false)
+}
+
+func (a *CompsAPI) vote(w http.ResponseWriter, r *http.Request, up bool) {
+	id, ok := compID(w, r)
+	if !ok {
+		return
+	}
+
+	change := a.votes.UnvoteComp
+	if up {
+		change = a.votes.VoteComp
+	}
+	votes, err := change(r.Context(), id, a.voterKey(r))
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "comp not found")
+		return
+	}
+	if err != nil {
+		a.logger.Printf("comps: vote %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "could not record vote")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, voteResponse{Votes: votes, Voted: up})
+}
+
+// voterKey identifies who is voting: the logged-in user, otherwise an
+// HMAC of the client IP, which cannot be reversed without the key, so
+// addresses are not stored. Behind a reverse proxy the client IP is only
+// known with TRUSTED_PROXIES set; see middleware.RealIP.
+func (a *CompsAPI) voterKey(r *http.Request) string {
+	if user := auth.UserFrom(r.Context()); user != nil {
+		return "user:" + strconv.FormatInt(user.ID, 10)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	mac := hmac.New(sha256.New, a.voters)
+	mac.Write([]byte(host))
+	return "ip:" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func compID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid comp id")
+		return 0, false
+	}
+	return id, true
+}
+
+// validateComp checks the request against limits and the loaded dataset.
+func validateComp(req compRequest, data *models.UnitsData) (*store.Comp, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if len(name) > maxCompNameLen {
+		return nil, fmt.Errorf("name exceeds %d characters", maxCompNameLen)
+	}
+	notes := strings.TrimSpace(req.Notes)
+	if len(notes) > maxCompNotesLen {
+		return nil, fmt.Errorf("notes exceed %d characters", maxCompNotesLen)
+	}
+
+	state, err := models.DecodeBoardState(req.Board)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(data.Units))
+	for _, u := range data.Units {
+		known[u.Slug] = true
+	}
+	for _, p := range state.Placements {
+		if !known[p.Unit] {
+			return nil, fmt.Errorf("unknown unit %q", p.Unit)
+		}
+		if !p.OnBoard() {
+			return nil, fmt.Errorf("unit %q is off the board at row %d, column %d", p.Unit, p.Row, p.Col)
+		}
+	}
+
+	return &store.Comp{
+		Name:       name,
+		Board:      state.Encode(),
+		Notes:      notes,
+		SetVersion: data.Version,
+	}, nil
+}
@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sft/internal/auth"
+	"sft/internal/middleware"
+	"sft/internal/models"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+type staticUnits struct {
+	data *models.UnitsData
+}
+
+func (s staticUnits) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	return s.data, nil
+}
+
+// openTestStore opens a SQLite store in a temporary directory, closed
+// when the test ends.
+func openTestStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	db, err := store.OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestCompsMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+	db := openTestStore(t)
+
+	units := staticUnits{data: &models.UnitsData{
+		Version: "v1",
+		Units:   []models.Unit{{Name: "Ahri", Slug: "ahri", APIName: "TFT16_Ahri"}},
+	}}
+	comps := NewCompsAPI(db, units).
+		WithPlanner(services.TeamPlannerCodes{26: "TFT16_Ahri", 27: "TFT16_Unreleased"}).
+		WithVotes(db).
+		WithDeleteTokens(auth.NewLinkSigner("test"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/comps", comps.Create)
+	mux.HandleFunc("POST /api/v1/comps/import", comps.Import)
+	mux.HandleFunc("GET /api/v1/comps", comps.List)
+	mux.HandleFunc("GET /api/v1/comps/{id}", comps.Get)
+	mux.HandleFunc("DELETE /api/v1/comps/{id}", comps.Delete)
+	mux.HandleFunc("PUT /api/v1/comps/{id}/vote", comps.Vote)
+	mux.HandleFunc("DELETE /api/v1/comps/{id}/vote", comps.Unvote)
+	return mux
+}
+
+func TestCompsAPI_CreateGetDelete(t *testing.T) {
+	mux := newTestCompsMux(t)
+
+	body := `{"name": "Ahri carry", "board": "1~032ahri", "notes": "flex"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/comps", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created compResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if created.SetVersion != "v1" || created.URL == "" {
+		t.Errorf("unexpected comp: %+v", created)
+	}
+
+	location := rec.Header().Get("Location")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, location, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on get, got %d", rec.Code)
+	}
+
+	if created.DeleteToken == "" {
+		t.Fatal("anonymous comp created without a delete token")
+	}
+	// A stranger cannot delete the anonymous comp by guessing its ID.
+	for _, token := range []string{"", "forged"} {
+		req := httptest.NewRequest(http.MethodDelete, location, nil)
+		req.Header.Set("X-Delete-Token", token)
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("delete with token %q: expected 403, got %d", token, rec.Code)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, location, nil)
+	req.Header.Set("X-Delete-Token", created.DeleteToken)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 on delete, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, location, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestCompsAPI_CreateValidation(t *testing.T) {
+	mux := newTestCompsMux(t)
+
+	tests := map[string]string{
+		"missing name": `{"board": "1~032ahri"}`,
+		"bad board":    `{"name": "x", "board": "nope"}`,
+		"unknown unit": `{"name": "x", "board": "1~001teemo"}`,
+		"off board":    `{"name": "x", "board": "1~071ahri"}`,
+		"past bench":   `{"name": "x", "board": "1~491ahri"}`,
+		"below bench":  `{"name": "x", "board": "1~501ahri"}`,
+		"invalid json": `{`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comps", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestCompsAPI_Import(t *testing.T) {
+	mux := newTestCompsMux(t)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		board  string
+	}{
+		{"valid", `{"code": "0201a01a000000000000000000000000TFTSet16"}`, http.StatusCreated, "1~001ahri.011ahri"},
+		{"bad code", `{"code": "nope"}`, http.StatusBadRequest, ""},
+		{"champion not in dataset", `{"code": "0201b000000000000000000000000000"}`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/comps/import", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if tt.board == "" {
+				return
+			}
+			var created compResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if created.Board != tt.board || created.Name != "Imported comp" {
+				t.Errorf("unexpected comp: %+v", created)
+			}
+		})
+	}
+}
+
+func TestPlannerBoard_TooManyChampions(t *testing.T) {
+	data := &models.UnitsData{Units: []models.Unit{{Slug: "ahri", APIName: "TFT16_Ahri"}}}
+	planned := services.TeamPlannerComp{APINames: make([]string, 29)}
+	for i := range planned.APINames {
+		planned.APINames[i] = "TFT16_Ahri"
+	}
+	if _, err := plannerBoard(planned, data); err == nil {
+		t.Error("expected an error for 29 champions")
+	}
+	state, err := plannerBoard(services.TeamPlannerComp{APINames: planned.APINames[:28]}, data)
+	if err != nil {
+		t.Fatalf("28 champions: %v", err)
+	}
+	if last := state.Placements[27]; last.Row != models.BoardRows-1 || last.Col != models.BoardCols-1 {
+		t.Errorf("28th champion at %+v, want the last hex", last)
+	}
+}
+
+func TestCompsAPI_VoteAndSort(t *testing.T) {
+	mux := newTestCompsMux(t)
+
+	var ids []string
+	for _, name := range []string{"First", "Second"} {
+		body := `{"name": "` + name + `", "board": "1~032ahri"}`
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/comps", strings.NewReader(body)))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create: expected 201, got %d", rec.Code)
+		}
+		ids = append(ids, rec.Header().Get("Location"))
+	}
+
+	vote := func(method, location, remote string) voteResponse {
+		t.Helper()
+		req := httptest.NewRequest(method, location+"/vote", nil)
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s vote: expected 200, got %d: %s", method, rec.Code, rec.Body.String())
+		}
+		var resp voteResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return resp
+	}
+
+	vote(http.MethodPut, ids[0], "192.0.2.1:1000")
+	if got := vote(http.MethodPut, ids[0], "192.0.2.1:2000"); got.Votes != 1 || !got.Voted {
+		t.Errorf("same IP voting twice = %+v, want 1 vote", got)
+	}
+	if got := vote(http.MethodPut, ids[0], "192.0.2.2:1000"); got.Votes != 2 {
+		t.Errorf("second IP = %+v, want 2 votes", got)
+	}
+	if got := vote(http.MethodDelete, ids[0], "192.0.2.2:1000"); got.Votes != 1 || got.Voted {
+		t.Errorf("unvote = %+v, want 1 vote", got)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/comps/999/vote", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("vote on missing comp: expected 404, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/comps?sort=top", nil))
+	var list []compResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "First" || list[0].Votes != 1 {
+		t.Errorf("top list = %+v", list)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/comps?sort=hot", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown sort: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCompsAPI_VotersBehindProxy(t *testing.T) {
+	proxies, err := middleware.ParseProxies([]string{"10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := middleware.RealIP(proxies)(newTestCompsMux(t))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/comps", strings.NewReader(`{"name": "Proxied", "board": "1~032ahri"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d", rec.Code)
+	}
+	location := rec.Header().Get("Location")
+
+	var got voteResponse
+	for _, client := range []string{"198.51.100.1", "198.51.100.2"} {
+		req := httptest.NewRequest(http.MethodPut, location+"/vote", nil)
+		req.RemoteAddr = "10.0.0.1:443"
+		req.Header.Set("X-Forwarded-For", client)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+	}
+	if got.Votes != 2 {
+		t.Errorf("two clients behind one proxy = %d votes, want 2", got.Votes)
+	}
+}
+
+func TestCompsAPI_VoterKeyIsKeyed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/comps/1/vote", nil)
+	req.RemoteAddr = "192.0.2.1:1000"
+
+	a := NewCompsAPI(nil, nil).WithVoterSecret("one")
+	b := NewCompsAPI(nil, nil).WithVoterSecret("two")
+	if a.voterKey(req) == b.voterKey(req) {
+		t.Error("voter keys do not depend on the secret")
+	}
+	if a.voterKey(req) != NewCompsAPI(nil, nil).WithVoterSecret("one").voterKey(req) {
+		t.Error("voter keys differ for the same secret")
+	}
+	sum := sha256.Sum256([]byte("192.0.2.1"))
+	if a.voterKey(req) == "ip:"+hex.EncodeToString(sum[:16]) {
+		t.Error("voter key is a plain hash of the address")
+	}
+}
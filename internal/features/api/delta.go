@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+
+	"sft/internal/i18n"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+// DeltaSource diffs archived dataset versions against the current one; see
+// services.LocalUnitsLoader.
+type DeltaSource interface {
+	services.UnitsSource
+	VersionHistory
+	DiffSince(ctx context.Context, version string) (*services.SetDiff, error)
+}
+
+type deltaResponse struct {
+	From          string             `json:"from"`
+	To            string             `json:"to"`
+	Units         []models.Unit      `json:"units"`         // added or changed, whole
+	RemovedUnits  []string           `json:"removedUnits"`  // apiNames
+	Traits        []models.TraitInfo `json:"traits"`        // added or changed, whole
+	RemovedTraits []string           `json:"removedTraits"` // slugs
+	Changes       *services.SetDiff  `json:"changes"`
+}
+
+// NewExportDeltaHandler serves GET /api/export/delta?since=<version>: the
+// units and traits that differ between an archived dataset version and
+// the current one, so clients caching /api/v1/bundle can patch it instead
+// of downloading it again. Units come from the set diff, plus any whose
+// traits or items changed; changes carries the diff itself. The delta is
+// in the dataset's own language.
+func NewExportDeltaHandler(source DeltaSource) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		since := r.URL.Query().Get("since")
+		if since == "" {
+			writeError(w, http.StatusBadRequest, "since is required")
+			return
+		}
+		ctx := i18n.WithLocale(r.Context(), i18n.Fallback)
+		current, err := source.LoadUnits(ctx)
+		if err != nil {
+			logger.Printf("delta: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+		diff, err := source.DiffSince(ctx, since)
+		var past *models.UnitsData
+		if err == nil {
+			past, err = source.LoadVersion(ctx, since)
+		}
+		if errors.Is(err, services.ErrUnknownVersion) {
+			writeError(w, http.StatusNotFound, "unknown version")
+			return
+		}
+		if err != nil {
+			logger.Printf("delta: diff since %s: %v", since, err)
+			writeError(w, http.StatusServiceUnavailable, "history unavailable")
+			return
+		}
+
+		resp := deltaResponse{
+			From:          since,
+			To:            current.Version,
+			Units:         []models.Unit{},
+			RemovedUnits:  []string{},
+			Traits:        []models.TraitInfo{},
+			RemovedTraits: []string{},
+			Changes:       diff,
+		}
+		named := make(map[string]bool, len(diff.Added)+len(diff.Changed))
+		for _, ref := range diff.Added {
+			named[unitRefKey(ref)] = true
+		}
+		for _, c := range diff.Changed {
+			named[unitRefKey(c.UnitRef)] = true
+		}
+		for _, ref := range diff.Removed {
+			resp.RemovedUnits = append(resp.RemovedUnits, unitRefKey(ref))
+		}
+
+		before := make(map[string]*models.Unit, len(past.Units))
+		for i := range past.Units {
+			before[unitKey(&past.Units[i])] = &past.Units[i]
+		}
+		for i := range current.Units {
+			u := &current.Units[i]
+			key := unitKey(u)
+			if prev, ok := before[key]; named[key] || !ok || !reflect.DeepEqual(prev.Traits, u.Traits) ||
+				!reflect.DeepEqual(prev.RecommendedItems, u.RecommendedItems) {
+				resp.Units = append(resp.Units, *u)
+			}
+		}
+
+		traits := make(map[string]*models.TraitInfo, len(past.Traits))
+		for i := range past.Traits {
+			traits[past.Traits[i].Slug] = &past.Traits[i]
+		}
+		for _, t := range current.Traits {
+			if prev, ok := traits[t.Slug]; !ok || !reflect.DeepEqual(*prev, t) {
+				resp.Traits = append(resp.Traits, t)
+			}
+			delete(traits, t.Slug)
+		}
+		for _, t := range past.Traits {
+			if _, gone := traits[t.Slug]; gone {
+				resp.RemovedTraits = append(resp.RemovedTraits, t.Slug)
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// unitKey matches units across versions the way the set diff does: by
+// apiName, else by name.
+func unitKey(u *models.Unit) string {
+	if u.APIName != "" {
+		return u.APIName
+	}
+	return u.Name
+}
+
+func unitRefKey(ref services.UnitRef) string {
+	if ref.APIName != "" {
+		return ref.APIName
+	}
+	return ref.Name
+}
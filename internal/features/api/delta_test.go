@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+type diffingUnits struct {
+	versionedUnits
+	diffs map[string]*services.SetDiff
+}
+
+func (d diffingUnits) DiffSince(_ context.Context, version string) (*services.SetDiff, error) {
+	if diff, ok := d.diffs[version]; ok {
+		return diff, nil
+	}
+	return nil, services.ErrUnknownVersion
+}
+
+func TestExportDeltaHandler(t *testing.T) {
+	mage := models.Trait{Name: "Mage", Slug: "mage"}
+	units := diffingUnits{
+		versionedUnits: versionedUnits{
+			staticUnits: staticUnits{data: &models.UnitsData{
+				Version: "new",
+				Units: []models.Unit{
+					{Name: "Garen", APIName: "TFT_Garen", Cost: 1},
+					{Name: "Lux", APIName: "TFT_Lux", Cost: 4, Traits: []models.Trait{mage}},
+					{Name: "Zed", APIName: "TFT_Zed", Cost: 2},
+				},
+				Traits: []models.TraitInfo{{Name: "Mage", Slug: "mage", Units: []string{"lux"}}},
+			}},
+			past: map[string]*models.UnitsData{"old": {
+				Version: "old",
+				Units: []models.Unit{
+					{Name: "Ahri", APIName: "TFT_Ahri", Cost: 3},
+					{Name: "Garen", APIName: "TFT_Garen", Cost: 1},
+					{Name: "Lux", APIName: "TFT_Lux", Cost: 4},
+					{Name: "Zed", APIName: "TFT_Zed", Cost: 3},
+				},
+				Traits: []models.TraitInfo{{Name: "Spirit", Slug: "spirit", Units: []string{"ahri"}}},
+			}},
+		},
+		diffs: map[string]*services.SetDiff{"old": {
+			From:    "old",
+			To:      "new",
+			Added:   []services.UnitRef{},
+			Removed: []services.UnitRef{{APIName: "TFT_Ahri", Name: "Ahri", Cost: 3}},
+			Changed: []services.UnitDiff{{
+				UnitRef: services.UnitRef{APIName: "TFT_Zed", Name: "Zed", Cost: 2},
+				Stats:   []services.ValueChange{{Field: "cost", Old: "3", New: "2"}},
+			}},
+		}},
+	}
+	h := NewExportDeltaHandler(units)
+
+	rec := do(h, http.MethodGet, "/api/export/delta?since=old", "")
+	var got deltaResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("got %d %v", rec.Code, err)
+	}
+	if got.From != "old" || got.To != "new" || got.Changes == nil || len(got.Changes.Changed) != 1 {
+		t.Errorf("delta = %+v", got)
+	}
+	// Zed changed in the set diff; Lux gained a trait. Garen is unchanged.
+	if len(got.Units) != 2 || got.Units[0].Name != "Lux" || got.Units[1].Name != "Zed" {
+		t.Errorf("units = %+v", got.Units)
+	}
+	if len(got.RemovedUnits) != 1 || got.RemovedUnits[0] != "TFT_Ahri" {
+		t.Errorf("removed units = %v", got.RemovedUnits)
+	}
+	if len(got.Traits) != 1 || got.Traits[0].Slug != "mage" || len(got.RemovedTraits) != 1 || got.RemovedTraits[0] != "spirit" {
+		t.Errorf("traits = %+v, removed %v", got.Traits, got.RemovedTraits)
+	}
+
+	if rec := do(h, http.MethodGet, "/api/export/delta", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("without since: expected 400, got %d", rec.Code)
+	}
+	if rec := do(h, http.MethodGet, "/api/export/delta?since=nope", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown version: expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sft/internal/auth"
+	"sft/internal/models"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+const maxDrillBodySize = 4 << 10
+
+// DrillsAPI serves generated practice drills under /api/v1/drills.
+// Answers are graded when possible and stored for logged-in users.
+type DrillsAPI struct {
+	drills store.DrillStore
+	units  services.UnitsSource
+	logger *log.Logger
+}
+
+// NewDrillsAPI wires the drill endpoints to the attempt store and dataset.
+func NewDrillsAPI(drills store.DrillStore, units services.UnitsSource) *DrillsAPI {
+	return &DrillsAPI{drills: drills, units: units, logger: log.Default()}
+}
+
+type drillAnswerRequest struct {
+	Answer string `json:"answer"` // gold amount for econ drills, board code for positioning
+}
+
+type drillAnswerResponse struct {
+	Correct  *bool  `json:"correct"`            // nil for ungraded (positioning) drills
+	Expected string `json:"expected,omitempty"` // revealed for graded drills
+	Stored   bool   `json:"stored"`             // false for anonymous callers
+}
+
+// Generate handles GET /api/v1/drills/{kind}?seed=. Without a seed a random one is picked.
+func (a *DrillsAPI) Generate(w http.ResponseWriter, r *http.Request) {
+	seed := rand.Uint64()
+	if v := r.URL.Query().Get("seed"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid seed")
+			return
+		}
+		seed = n
+	}
+
+	drill, ok := a.generate(w, r, seed)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, drill)
+}
+
+// Answer handles POST /api/v1/drills/{kind}/{seed}/answers.
+func (a *DrillsAPI) Answer(w http.ResponseWriter, r *http.Request) {
+	seed, err := strconv.ParseUint(r.PathValue("seed"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid seed")
+		return
+	}
+	drill, ok := a.generate(w, r, seed)
+	if !ok {
+		return
+	}
+
+	var req drillAnswerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDrillBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	resp, answer, err := gradeDrill(drill, strings.TrimSpace(req.Answer))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if user := auth.UserFrom(r.Context()); user != nil {
+		scenario, _ := json.Marshal(drill)
+		attempt := &store.DrillAttempt{
+			UserID:   user.ID,
+			Kind:     drill.Kind,
+			Seed:     drill.Seed,
+			Scenario: scenario,
+			Answer:   answer,
+			Correct:  resp.Correct,
+		}
+		if err := a.drills.CreateDrillAttempt(r.Context(), attempt); err != nil {
+			a.logger.Printf("drills: store attempt: %v", err)
+			writeError(w, http.StatusInternalServerError, "could not save answer")
+			return
+		}
+		resp.Stored = true
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// History handles GET /api/v1/drills/answers?limit= for the logged-in user.
+func (a *DrillsAPI) History(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFrom(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "not logged in")
+		return
+	}
+
+	limit := defaultCompList
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(n, maxCompList)
+	}
+
+	attempts, err := a.drills.ListDrillAttempts(r.Context(), user.ID, limit)
+	if err != nil {
+		a.logger.Printf("drills: list attempts: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not list answers")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, attempts)
+}
+
+func (a *DrillsAPI) generate(w http.ResponseWriter, r *http.Request, seed uint64) (services.Drill, bool) {
+	data, err := a.units.LoadUnits(r.Context())
+	if err != nil {
+		a.logger.Printf("drills: loading units: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+		return services.Drill{}, false
+	}
+
+	drill, err := services.GenerateDrill(r.PathValue("kind"), seed, data.Units)
+	if errors.Is(err, services.ErrUnknownDrill) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return services.Drill{}, false
+	}
+	if err != nil {
+		a.logger.Printf("drills: generate: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset cannot produce this drill")
+		return services.Drill{}, false
+	}
+	return drill, true
+}
+
+// gradeDrill checks answer against drill and returns the normalized answer to store.
+func gradeDrill(drill services.Drill, answer string) (drillAnswerResponse, string, error) {
+	switch drill.Kind {
+	case services.DrillEcon:
+		gold, err := strconv.Atoi(answer)
+		if err != nil {
+			return drillAnswerResponse{}, "", errors.New("answer must be a gold amount")
+		}
+		expected := services.EconAnswer(drill)
+		correct := gold == expected
+		return drillAnswerResponse{Correct: &correct, Expected: strconv.Itoa(expected)}, strconv.Itoa(gold), nil
+
+	case services.DrillPositioning:
+		state, err := models.DecodeBoardState(answer)
+		if err != nil {
+			return drillAnswerResponse{}, "", err
+		}
+		allowed := make(map[string]bool, len(drill.Units))
+		for _, slug := range drill.Units {
+			allowed[slug] = true
+		}
+		for _, p := range state.Placements {
+			if !allowed[p.Unit] {
+				return drillAnswerResponse{}, "", errors.New("answer places units outside the drill: " + p.Unit)
+			}
+		}
+		return drillAnswerResponse{}, state.Encode(), nil
+	}
+	return drillAnswerResponse{}, "", services.ErrUnknownDrill
+}
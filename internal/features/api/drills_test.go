@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"sft/internal/auth"
+	"sft/internal/models"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+func newTestDrillsHandler(t *testing.T) http.Handler {
+	t.Helper()
+	db := openTestStore(t)
+
+	var units []models.Unit
+	for i := 0; i < 12; i++ {
+		units = append(units, models.Unit{Name: fmt.Sprintf("Unit %d", i), Slug: fmt.Sprintf("unit%d", i)})
+	}
+
+	account := NewAccountAPI(db, auth.NewSessions(db))
+	drills := NewDrillsAPI(db, staticUnits{data: &models.UnitsData{Units: units}})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/account/signup", account.Signup)
+	mux.HandleFunc("GET /api/v1/drills/answers", drills.History)
+	mux.HandleFunc("GET /api/v1/drills/{kind}", drills.Generate)
+	mux.HandleFunc("POST /api/v1/drills/{kind}/{seed}/answers", drills.Answer)
+	return withLogin(db, mux)
+}
+
+func TestDrillsAPI(t *testing.T) {
+	h := newTestDrillsHandler(t)
+
+	rec := do(h, http.MethodGet, "/api/v1/drills/econ?seed=9", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("generate: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var drill services.Drill
+	if err := json.Unmarshal(rec.Body.Bytes(), &drill); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	// Anonymous answers are graded but not stored.
+	answer := fmt.Sprintf(`{"answer": "%d"}`, services.EconAnswer(drill))
+	rec = do(h, http.MethodPost, "/api/v1/drills/econ/9/answers", answer)
+	var graded drillAnswerResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &graded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if graded.Correct == nil || !*graded.Correct || graded.Stored {
+		t.Errorf("unexpected anonymous result: %+v", graded)
+	}
+	if rec := do(h, http.MethodGet, "/api/v1/drills/answers", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("history: expected 401, got %d", rec.Code)
+	}
+
+	rec = do(h, http.MethodPost, "/api/v1/account/signup", `{"username": "drills", "password": "hunter2hunter2"}`)
+	cookie := sessionCookie(t, rec)
+
+	rec = do(h, http.MethodGet, "/api/v1/drills/positioning?seed=3", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &drill); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	board := models.BoardState{Placements: []models.Placement{{Row: 3, Col: 0, Unit: drill.Units[0], Stars: 1}}}
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"positioning answer", "/api/v1/drills/positioning/3/answers", `{"answer": "` + board.Encode() + `"}`, http.StatusOK},
+		{"foreign unit", "/api/v1/drills/positioning/3/answers", `{"answer": "1~001` + drill.Opponent + `"}`, http.StatusBadRequest},
+		{"econ not a number", "/api/v1/drills/econ/9/answers", `{"answer": "lots"}`, http.StatusBadRequest},
+		{"unknown kind", "/api/v1/drills/draft/1/answers", `{"answer": "1"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(h, http.MethodPost, tt.path, tt.body, cookie); rec.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	rec = do(h, http.MethodGet, "/api/v1/drills/answers", "", cookie)
+	var history []store.DrillAttempt
+	if err := json.Unmarshal(rec.Body.Bytes(), &history); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(history) != 1 || history[0].Kind != services.DrillPositioning || history[0].Correct != nil || history[0].Seed != 3 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// eventsHeartbeat is how often an idle stream sends a comment, keeping
+// proxies from timing it out.
+const eventsHeartbeat = 25 * time.Second
+
+// eventsRetry tells browsers how long to wait before reconnecting, in ms.
+const eventsRetry = 5000
+
+// ChangeSignal is implemented by sources that close the channel Changed
+// returns when they reload, such as the asset manifest resolver.
+type ChangeSignal interface {
+	Changed() <-chan struct{}
+}
+
+type reloadEvent struct {
+	Revision string `json:"revision,omitempty"`
+}
+
+// NewReloadEventsHandler streams reload notifications as server-sent events
+// so open pages can offer a refresh after a patch update. It sends
+// "dataset" with the new data revision when the units change, and "assets"
+// when the frontend bundle changes; assets may be nil.
+//
+// A client passing ?current=<revision> it rendered with gets "dataset"
+// at once if the data already moved on, so reloads during a reconnect
+// are not missed.
+func NewReloadEventsHandler(units VersionSource, assets ChangeSignal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		rc := http.NewResponseController(w)
+
+		// Grab the channels before reading the revision so a reload in between isn't missed.
+		unitsChanged := units.Changed()
+		var assetsChanged <-chan struct{}
+		if assets != nil {
+			assetsChanged = assets.Changed()
+		}
+		revision, err := dataRevision(r, units)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Accel-Buffering", "no") // nginx would hold events back
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", eventsRetry)
+		if current := r.URL.Query().Get("current"); current != "" && current != revision {
+			writeEvent(w, "dataset", reloadEvent{Revision: revision})
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventsHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				io.WriteString(w, ": ping\n\n")
+			case <-unitsChanged:
+				unitsChanged = units.Changed()
+				latest, err := dataRevision(r, units)
+				if err != nil || latest == revision {
+					continue
+				}
+				revision = latest
+				writeEvent(w, "dataset", reloadEvent{Revision: revision})
+			case <-assetsChanged:
+				assetsChanged = assets.Changed()
+				writeEvent(w, "assets", reloadEvent{})
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dataRevision returns the revision pages render the units data at.
+func dataRevision(r *http.Request, units VersionSource) (string, error) {
+	data, err := units.LoadUnits(r.Context())
+	if err != nil {
+		return "", err
+	}
+	return data.Revision(), nil
+}
+
+// writeEvent writes one server-sent event with a JSON data line.
+func writeEvent(w io.Writer, name string, data any) {
+	body, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, body)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// readEvent returns the next named event and its data line, skipping the
+// retry hint and heartbeats.
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var name, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && name != "":
+			return name, data
+		}
+	}
+}
+
+func openEvents(t *testing.T, srv *httptest.Server, query string) *bufio.Reader {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/api/events" + query)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestReloadEvents_StreamsReloads(t *testing.T) {
+	units := newFakeVersionSource("v1")
+	assets := newFakeVersionSource("")
+	srv := httptest.NewServer(NewReloadEventsHandler(units, assets))
+	t.Cleanup(srv.Close)
+
+	// The handler subscribes before sending headers, so reloads from here on are seen.
+	stream := openEvents(t, srv, "?current=v1")
+
+	units.bump("v2")
+	if name, data := readEvent(t, stream); name != "dataset" || data != `{"revision":"v2"}` {
+		t.Errorf("got %s %s, want dataset v2", name, data)
+	}
+
+	assets.bump("")
+	if name, _ := readEvent(t, stream); name != "assets" {
+		t.Errorf("got %s, want assets", name)
+	}
+}
+
+func TestReloadEvents_ReportsMissedReload(t *testing.T) {
+	srv := httptest.NewServer(NewReloadEventsHandler(newFakeVersionSource("v2"), nil))
+	t.Cleanup(srv.Close)
+
+	stream := openEvents(t, srv, "?current=v1")
+	if name, data := readEvent(t, stream); name != "dataset" || data != `{"revision":"v2"}` {
+		t.Errorf("got %s %s, want dataset v2 right away", name, data)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"sft/internal/auth"
+	"sft/internal/models"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+// FavoritesAPI lets logged-in users star units under /api/v1/favorites.
+// Units are named by apiName, e.g. TFT16_Ahri, which stays stable when a
+// display name is reworded.
+type FavoritesAPI struct {
+	favorites store.FavoriteStore
+	units     services.UnitsSource
+	logger    *log.Logger
+}
+
+// NewFavoritesAPI wires the favorite endpoints to the store and dataset.
+func NewFavoritesAPI(favorites store.FavoriteStore, units services.UnitsSource) *FavoritesAPI {
+	return &FavoritesAPI{favorites: favorites, units: units, logger: log.Default()}
+}
+
+// List handles GET /api/v1/favorites.
+func (a *FavoritesAPI) List(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFrom(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "not logged in")
+		return
+	}
+	favorites, err := a.favorites.ListFavorites(r.Context(), user.ID)
+	if err != nil {
+		a.logger.Printf("favorites: list: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not list favorites")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, favorites)
+}
+
+// Add handles PUT /api/v1/favorites/{unit}.
+func (a *FavoritesAPI) Add(w http.ResponseWriter, r *http.Request) {
+	a.update(w, r, a.favorites.AddFavorite)
+}
+
+// Remove handles DELETE /api/v1/favorites/{unit}.
+func (a *FavoritesAPI) Remove(w http.ResponseWriter, r *http.Request) {
+	a.update(w, r, a.favorites.RemoveFavorite)
+}
+
+func (a *FavoritesAPI) update(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, userID int64, apiName string) error) {
+	user := auth.UserFrom(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "not logged in")
+		return
+	}
+
+	apiName := r.PathValue("unit")
+	data, err := a.units.LoadUnits(r.Context())
+	if err != nil {
+		a.logger.Printf("favorites: loading units: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+		return
+	}
+	if !hasUnit(data.Units, apiName) {
+		writeError(w, http.StatusNotFound, "unit not found")
+		return
+	}
+
+	if err := apply(r.Context(), user.ID, apiName); err != nil {
+		a.logger.Printf("favorites: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not update favorites")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func hasUnit(units []models.Unit, apiName string) bool {
+	for _, u := range units {
+		if u.APIName == apiName {
+			return true
+		}
+	}
+	return false
+}
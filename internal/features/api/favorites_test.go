@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sft/internal/auth"
+	"sft/internal/models"
+)
+
+func TestFavoritesAPI(t *testing.T) {
+	db := openTestStore(t)
+
+	units := staticUnits{data: &models.UnitsData{Units: []models.Unit{
+		{Name: "Ahri", APIName: "TFT16_Ahri"},
+		{Name: "Jinx", APIName: "TFT16_Jinx"},
+	}}}
+	account := NewAccountAPI(db, auth.NewSessions(db))
+	favorites := NewFavoritesAPI(db, units)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/account/signup", account.Signup)
+	mux.HandleFunc("GET /api/v1/favorites", favorites.List)
+	mux.HandleFunc("PUT /api/v1/favorites/{unit}", favorites.Add)
+	mux.HandleFunc("DELETE /api/v1/favorites/{unit}", favorites.Remove)
+	h := withLogin(db, mux)
+
+	if rec := do(h, http.MethodPut, "/api/v1/favorites/TFT16_Ahri", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous add: expected 401, got %d", rec.Code)
+	}
+
+	cookie := sessionCookie(t, do(h, http.MethodPost, "/api/v1/account/signup", `{"username": "stars", "password": "hunter2hunter2"}`))
+
+	tests := []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodPut, "/api/v1/favorites/TFT16_Ahri", http.StatusNoContent},
+		{http.MethodPut, "/api/v1/favorites/TFT16_Jinx", http.StatusNoContent},
+		{http.MethodPut, "/api/v1/favorites/TFT16_Ahri", http.StatusNoContent},
+		{http.MethodPut, "/api/v1/favorites/TFT16_Nobody", http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/favorites/TFT16_Jinx", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		if rec := do(h, tt.method, tt.path, "", cookie); rec.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := do(h, http.MethodGet, "/api/v1/favorites", "", cookie)
+	var got []string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 1 || got[0] != "TFT16_Ahri" {
+		t.Errorf("favorites = %v, want [TFT16_Ahri]", got)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"sft/internal/models"
+	"sft/internal/store"
+)
+
+const maxLinkBodySize = 8 << 10
+
+// LinksAPI creates short permalinks for boards under /api/v1/links.
+type LinksAPI struct {
+	links  store.ShortLinkStore
+	logger *log.Logger
+}
+
+// NewLinksAPI wires the shortener endpoint to a store.
+func NewLinksAPI(links store.ShortLinkStore) *LinksAPI {
+	return &LinksAPI{links: links, logger: log.Default()}
+}
+
+type linkRequest struct {
+	Board string `json:"board"`
+}
+
+type linkResponse struct {
+	store.ShortLink
+	URL string `json:"url"` // site-relative short link
+}
+
+// Create handles POST /api/v1/links. The same board always gets the same code.
+func (a *LinksAPI) Create(w http.ResponseWriter, r *http.Request) {
+	var req linkRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLinkBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	state, err := models.DecodeBoardState(req.Board)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(state.Placements) == 0 {
+		writeError(w, http.StatusBadRequest, "board is empty")
+		return
+	}
+
+	// Re-encode so equivalent codes (e.g. overwritten hexes) share a link.
+	link, err := a.links.CreateShortLink(r.Context(), state.Encode())
+	if err != nil {
+		a.logger.Printf("create short link: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not create link")
+		return
+	}
+	writeJSON(w, http.StatusCreated, linkResponse{ShortLink: *link, URL: "/c/" + link.Code})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestLinksAPI_Create(t *testing.T) {
+	db := openTestStore(t)
+	h := http.HandlerFunc(NewLinksAPI(db).Create)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"valid", `{"board": "1~001ahri"}`, http.StatusCreated},
+		{"invalid code", `{"board": "nope"}`, http.StatusBadRequest},
+		{"empty board", `{"board": "1~"}`, http.StatusBadRequest},
+		{"bad json", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(h, http.MethodPost, "/api/v1/links", tt.body); rec.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	// Overwritten hexes normalize to the same board, so they share a code.
+	var first, second linkResponse
+	_ = json.Unmarshal(do(h, http.MethodPost, "/api/v1/links", `{"board": "1~001ahri"}`).Body.Bytes(), &first)
+	_ = json.Unmarshal(do(h, http.MethodPost, "/api/v1/links", `{"board": "1~001garen.001ahri"}`).Body.Bytes(), &second)
+	if first.Code == "" || first.Code != second.Code || first.URL != "/c/"+first.Code {
+		t.Errorf("expected shared code, got %+v and %+v", first, second)
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"sft/internal/auth"
+	"sft/internal/features/lobby"
+	"sft/internal/store"
+)
+
+const maxLobbyBodySize = 32 << 10
+
+// LobbiesAPI serves the tournament lobby endpoints under /api/v1/lobbies.
+// Reads and writes are authorized by the signed link returned on creation.
+type LobbiesAPI struct {
+	lobbies store.LobbyStore
+	signer  *auth.LinkSigner
+	logger  *log.Logger
+}
+
+// NewLobbiesAPI wires the lobby endpoints to a store and link signer.
+func NewLobbiesAPI(lobbies store.LobbyStore, signer *auth.LinkSigner) *LobbiesAPI {
+	return &LobbiesAPI{lobbies: lobbies, signer: signer, logger: log.Default()}
+}
+
+type lobbyRequest struct {
+	Name    string   `json:"name"`
+	Players []string `json:"players"` // optional seat names in order
+}
+
+type lobbyResponse struct {
+	store.Lobby
+	URL string `json:"url"` // signed page link
+	Sig string `json:"sig"` // signature for API calls
+}
+
+func (a *LobbiesAPI) newLobbyResponse(l store.Lobby) lobbyResponse {
+	return lobbyResponse{Lobby: l, URL: lobby.Link(a.signer, l.ID), Sig: a.signer.Sign(lobby.SigKind, l.ID)}
+}
+
+// Create handles POST /api/v1/lobbies.
+func (a *LobbiesAPI) Create(w http.ResponseWriter, r *http.Request) {
+	var req lobbyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLobbyBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	name, err := lobby.NormalizeName(req.Name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Players) > store.LobbySize {
+		writeError(w, http.StatusBadRequest, "a lobby has "+strconv.Itoa(store.LobbySize)+" players")
+		return
+	}
+
+	l := &store.Lobby{Name: name}
+	for i, playerName := range req.Players {
+		p, err := lobby.NormalizePlayer(store.LobbyPlayer{Slot: i + 1, Name: playerName})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		l.Players = append(l.Players, p)
+	}
+	if user := auth.UserFrom(r.Context()); user != nil {
+		l.OwnerID = user.ID
+	}
+
+	if err := a.lobbies.CreateLobby(r.Context(), l); err != nil {
+		a.logger.Printf("lobbies: create: %v", err)
+		writeError(w, http.StatusInternalServerError, "could not save lobby")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/lobbies/"+strconv.FormatInt(l.ID, 10))
+	writeJSON(w, http.StatusCreated, a.newLobbyResponse(*l))
+}
+
+// Get handles GET /api/v1/lobbies/{id}?sig=.
+func (a *LobbiesAPI) Get(w http.ResponseWriter, r *http.Request) {
+	l, ok := a.load(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, a.newLobbyResponse(*l))
+}
+
+// UpdatePlayer handles PUT /api/v1/lobbies/{id}/players/{slot}?sig=.
+func (a *LobbiesAPI) UpdatePlayer(w http.ResponseWriter, r *http.Request) {
+	l, ok := a.load(w, r)
+	if !ok {
+		return
+	}
+
+	var req store.LobbyPlayer
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLobbyBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.Slot, _ = strconv.Atoi(r.PathValue("slot"))
+	player, err := lobby.NormalizePlayer(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := a.lobbies.UpdateLobbyPlayer(r.Context(), l.ID, player); err != nil {
+		a.logger.Printf("lobbies: update %d: %v", l.ID, err)
+		writeError(w, http.StatusInternalServerError, "could not save player")
+		return
+	}
+	writeJSON(w, http.StatusOK, player)
+}
+
+// load resolves the lobby after checking the sig query parameter.
+func (a *LobbiesAPI) load(w http.ResponseWriter, r *http.Request) (*store.Lobby, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || !a.signer.Verify(lobby.SigKind, id, r.URL.Query().Get("sig")) {
+		writeError(w, http.StatusNotFound, "lobby not found")
+		return nil, false
+	}
+
+	l, err := a.lobbies.GetLobby(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "lobby not found")
+		return nil, false
+	}
+	if err != nil {
+		a.logger.Printf("lobbies: get %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "could not load lobby")
+		return nil, false
+	}
+	return l, true
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sft/internal/auth"
+	"sft/internal/store"
+)
+
+func newTestLobbiesMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+	db := openTestStore(t)
+
+	lobbies := NewLobbiesAPI(db, auth.NewLinkSigner("test"))
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/lobbies", lobbies.Create)
+	mux.HandleFunc("GET /api/v1/lobbies/{id}", lobbies.Get)
+	mux.HandleFunc("PUT /api/v1/lobbies/{id}/players/{slot}", lobbies.UpdatePlayer)
+	return mux
+}
+
+func TestLobbiesAPI(t *testing.T) {
+	mux := newTestLobbiesMux(t)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/lobbies",
+		strings.NewReader(`{"name": "Finals", "players": ["Milk", "Setsuko"]}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created lobbyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(created.Players) != store.LobbySize || created.Players[1].Name != "Setsuko" || created.Sig == "" {
+		t.Fatalf("unexpected lobby: %+v", created)
+	}
+
+	location := rec.Header().Get("Location")
+	signed := location + "?sig=" + created.Sig
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, location+"?sig=forged", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a bad signature, got %d", rec.Code)
+	}
+
+	tests := []struct {
+		name   string
+		slot   string
+		body   string
+		status int
+	}{
+		{"valid", "2", `{"name": "Setsuko", "notes": "8 cost", "comps": ["/?b=1~001ahri"]}`, http.StatusOK},
+		{"bad slot", "9", `{"name": "x"}`, http.StatusBadRequest},
+		{"bad comp", "2", `{"comps": ["nope"]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			target := location + "/players/" + tt.slot + "?sig=" + created.Sig
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, target, strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signed, nil))
+	var got lobbyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if seat := got.Players[1]; seat.Notes != "8 cost" || len(seat.Comps) != 1 || seat.Comps[0] != "1~001ahri" {
+		t.Errorf("unexpected seat: %+v", seat)
+	}
+}
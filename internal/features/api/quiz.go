@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+
+	"sft/internal/services"
+)
+
+const maxQuizBodySize = 1 << 10
+
+// QuizAPI serves the flashcard quiz under /api/quiz.
+// It is stateless: answers carry the seed of the question they respond to.
+type QuizAPI struct {
+	units  services.UnitsSource
+	logger *log.Logger
+}
+
+// NewQuizAPI wires the quiz endpoints to the dataset.
+func NewQuizAPI(units services.UnitsSource) *QuizAPI {
+	return &QuizAPI{units: units, logger: log.Default()}
+}
+
+type quizAnswerRequest struct {
+	Seed   uint64 `json:"seed"`
+	Answer string `json:"answer"`
+}
+
+type quizAnswerResponse struct {
+	Correct  bool   `json:"correct"`
+	Expected string `json:"expected"`
+}
+
+// Question handles GET /api/quiz?seed=. Without a seed a random one is picked.
+func (a *QuizAPI) Question(w http.ResponseWriter, r *http.Request) {
+	seed := rand.Uint64()
+	if v := r.URL.Query().Get("seed"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid seed")
+			return
+		}
+		seed = n
+	}
+
+	q, ok := a.question(w, r, seed)
+	if !ok {
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, q)
+}
+
+// Answer handles POST /api/quiz/answer.
+func (a *QuizAPI) Answer(w http.ResponseWriter, r *http.Request) {
+	var req quizAnswerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxQuizBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	q, ok := a.question(w, r, req.Seed)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, quizAnswerResponse{Correct: q.Check(req.Answer), Expected: q.Answer})
+}
+
+func (a *QuizAPI) question(w http.ResponseWriter, r *http.Request, seed uint64) (services.QuizQuestion, bool) {
+	data, err := a.units.LoadUnits(r.Context())
+	if err != nil {
+		a.logger.Printf("quiz: loading units: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+		return services.QuizQuestion{}, false
+	}
+
+	q, err := services.GenerateQuizQuestion(seed, data.Units)
+	if err != nil {
+		a.logger.Printf("quiz: generate: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "dataset cannot produce a quiz")
+		return services.QuizQuestion{}, false
+	}
+	return q, true
+}
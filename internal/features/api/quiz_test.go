@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+func TestQuizAPI(t *testing.T) {
+	var units []models.Unit
+	for i := 0; i < 6; i++ {
+		units = append(units, models.Unit{
+			Name:    fmt.Sprintf("Unit %d", i),
+			Slug:    fmt.Sprintf("unit%d", i),
+			Cost:    i%5 + 1,
+			Traits:  []models.Trait{{Name: fmt.Sprintf("Trait %d", i%3)}},
+			Ability: models.Ability{Name: fmt.Sprintf("Spell %d", i)},
+		})
+	}
+	quiz := NewQuizAPI(staticUnits{data: &models.UnitsData{Units: units}})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/quiz", quiz.Question)
+	mux.HandleFunc("POST /api/quiz/answer", quiz.Answer)
+
+	rec := do(mux, http.MethodGet, "/api/quiz?seed=5", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var q services.QuizQuestion
+	if err := json.Unmarshal(rec.Body.Bytes(), &q); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if q.Answer != "" {
+		t.Fatal("question must not leak the answer")
+	}
+
+	expected, _ := services.GenerateQuizQuestion(5, units)
+	tests := []struct {
+		name    string
+		answer  string
+		correct bool
+	}{
+		{"right", expected.Answer, true},
+		{"wrong", "definitely not", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(mux, http.MethodPost, "/api/quiz/answer", fmt.Sprintf(`{"seed": 5, "answer": %q}`, tt.answer))
+			var got quizAnswerResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if got.Correct != tt.correct || got.Expected != expected.Answer {
+				t.Errorf("got %+v, want correct=%v expected=%q", got, tt.correct, expected.Answer)
+			}
+		})
+	}
+
+	if rec := do(mux, http.MethodGet, "/api/quiz?seed=x", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad seed, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"sft/internal/services"
+)
+
+// NewShopOddsHandler serves GET /api/v1/shop-odds: roll odds per level and
+// champion pool sizes for the loaded set.
+func NewShopOddsHandler(units services.UnitsSource) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("shop odds: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+		if data.Shop == nil {
+			writeError(w, http.StatusNotFound, "shop odds are not configured")
+			return
+		}
+		writeJSON(w, http.StatusOK, data.Shop)
+	}
+}
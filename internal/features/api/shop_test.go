@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestShopOddsHandler(t *testing.T) {
+	shop := &models.ShopOdds{Levels: []models.ShopLevel{{Level: 1, Odds: []int{100}}}}
+
+	rec := do(NewShopOddsHandler(staticUnits{data: &models.UnitsData{Shop: shop}}), http.MethodGet, "/api/v1/shop-odds", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"levels"`) {
+		t.Errorf("expected odds, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(NewShopOddsHandler(staticUnits{data: &models.UnitsData{}}), http.MethodGet, "/api/v1/shop-odds", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without odds, got %d", rec.Code)
+	}
+}
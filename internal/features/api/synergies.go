@@ -0,0 +1,81 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"sft/internal/cache"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+const maxSynergiesBodySize = 8 << 10
+
+type synergiesRequest struct {
+	Board string `json:"board"` // encoded models.BoardState
+}
+
+type synergiesResponse struct {
+	Synergies []services.Synergy `json:"synergies"`
+}
+
+// synergiesTTL bounds how long computed synergies are kept. Keys include
+// the dataset revision, so this only frees space.
+const synergiesTTL = time.Hour
+
+// NewSynergiesHandler serves POST /api/v1/synergies, resolving the traits
+// and breakpoints active on a board code. Responses are kept in results
+// when it is not nil.
+func NewSynergiesHandler(units services.UnitsSource, results cache.Cache) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req synergiesRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSynergiesBodySize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		state, err := models.DecodeBoardState(req.Board)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("synergies: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, synergiesResponse{Synergies: boardSynergies(r, results, data, req.Board, state)})
+	}
+}
+
+// boardSynergies computes the synergies of the board code, through results
+// when it is not nil.
+func boardSynergies(r *http.Request, results cache.Cache, data *models.UnitsData, code string, state models.BoardState) []services.Synergy {
+	sum := sha256.Sum256([]byte(code))
+	key := "synergies:" + data.Revision() + ":" + hex.EncodeToString(sum[:])
+	if results != nil {
+		var cached synergiesResponse
+		if body, ok := results.Get(r.Context(), key); ok && json.Unmarshal(body, &cached) == nil {
+			return cached.Synergies
+		}
+	}
+
+	synergies := services.ComputeSynergies(state, data.Traits)
+	if synergies == nil {
+		synergies = []services.Synergy{}
+	}
+	if results != nil {
+		if body, err := json.Marshal(synergiesResponse{Synergies: synergies}); err == nil {
+			results.Set(r.Context(), key, body, synergiesTTL)
+		}
+	}
+	return synergies
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sft/internal/cache"
+	"sft/internal/models"
+)
+
+func TestSynergiesHandler(t *testing.T) {
+	results := cache.NewMemory(16)
+	h := NewSynergiesHandler(staticUnits{data: &models.UnitsData{Traits: []models.TraitInfo{
+		{Name: "Bruiser", Slug: "bruiser", Units: []string{"sion", "chogath"},
+			Breakpoints: []models.TraitBreakpoint{{MinUnits: 2, Style: 1}}},
+	}}}, results)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		active int
+	}{
+		{"active trait", `{"board": "1~001sion.011chogath"}`, http.StatusOK, 1},
+		{"empty board", `{"board": "1~"}`, http.StatusOK, 0},
+		{"invalid code", `{"board": "2~x"}`, http.StatusBadRequest, 0},
+		{"bad json", `[`, http.StatusBadRequest, 0},
+		{"cached", `{"board": "1~001sion.011chogath"}`, http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(h, http.MethodPost, "/api/v1/synergies", tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var got synergiesResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			active := 0
+			for _, s := range got.Synergies {
+				if s.Active {
+					active++
+				}
+			}
+			if active != tt.active || got.Synergies == nil {
+				t.Errorf("got %d active synergies (%+v), want %d", active, got.Synergies, tt.active)
+			}
+		})
+	}
+	if results.Len() != 2 {
+		t.Errorf("cached %d results, want one per valid board", results.Len())
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"sft/internal/services"
+)
+
+// uiConfigRevision is bumped whenever DefaultUIConfig changes so clients
+// holding the same dataset version still see the new values.
+const uiConfigRevision = 1
+
+// Shortcut binds a key combination to a client action.
+type Shortcut struct {
+	Action string `json:"action"`
+	Key    string `json:"key"`            // KeyboardEvent.key, compared case-insensitively
+	Mod    bool   `json:"mod,omitempty"`  // Ctrl on Windows/Linux, Cmd on macOS
+	Help   string `json:"help,omitempty"` // human-readable description
+}
+
+// TooltipTiming tunes the unit tooltip, in milliseconds and pixels.
+type TooltipTiming struct {
+	ShowDelayMS      int `json:"showDelayMs"`
+	LockDelayMS      int `json:"lockDelayMs"`
+	HideDelayMS      int `json:"hideDelayMs"`
+	HideTransitionMS int `json:"hideTransitionMs"`
+	OffsetPX         int `json:"offsetPx"`
+	ViewportPadPX    int `json:"viewportPaddingPx"`
+}
+
+// BoardBehavior tunes drag and drop on the board.
+type BoardBehavior struct {
+	DragThresholdPX int `json:"dragThresholdPx"` // pointer travel before a press becomes a drag
+}
+
+// UIConfig is the client behavior manifest served at /api/ui-config.
+type UIConfig struct {
+	Version   string        `json:"version"`
+	Revision  int           `json:"revision"`
+	Shortcuts []Shortcut    `json:"shortcuts"`
+	Tooltip   TooltipTiming `json:"tooltip"`
+	Board     BoardBehavior `json:"board"`
+}
+
+// DefaultUIConfig mirrors the values the client bundle falls back to.
+func DefaultUIConfig() UIConfig {
+	return UIConfig{
+		Revision: uiConfigRevision,
+		Shortcuts: []Shortcut{
+			{Action: "search.toggle", Key: "f", Mod: true, Help: "Focus or leave the unit search"},
+			{Action: "search.blur", Key: "Escape", Help: "Leave the unit search"},
+			{Action: "tooltip.prevTab", Key: "ArrowLeft", Help: "Previous tooltip tab"},
+			{Action: "tooltip.nextTab", Key: "ArrowRight", Help: "Next tooltip tab"},
+		},
+		Tooltip: TooltipTiming{
+			ShowDelayMS:      200,
+			LockDelayMS:      800,
+			HideDelayMS:      100,
+			HideTransitionMS: 150,
+			OffsetPX:         12,
+			ViewportPadPX:    12,
+		},
+		Board: BoardBehavior{DragThresholdPX: 5},
+	}
+}
+
+// NewUIConfigHandler serves GET /api/ui-config, stamped with the dataset
+// version. The ETag changes with either the dataset or the config revision.
+func NewUIConfigHandler(units services.UnitsSource, base UIConfig) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("ui config: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		cfg := base
+		cfg.Version = data.Version
+		etag := fmt.Sprintf(`"%s-%d"`, cfg.Version, cfg.Revision)
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writeJSON(w, http.StatusOK, cfg)
+	}
+}
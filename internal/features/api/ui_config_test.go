@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestUIConfigHandler(t *testing.T) {
+	h := NewUIConfigHandler(staticUnits{data: &models.UnitsData{Version: "abc123"}}, DefaultUIConfig())
+
+	rec := do(h, http.MethodGet, "/api/ui-config", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var cfg UIConfig
+	if err := json.Unmarshal(rec.Body.Bytes(), &cfg); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if cfg.Version != "abc123" || cfg.Revision != uiConfigRevision || len(cfg.Shortcuts) == 0 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag != `"abc123-1"` {
+		t.Errorf("ETag = %q", etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ui-config", nil)
+	req.Header.Set("If-None-Match", etag)
+	cached := httptest.NewRecorder()
+	h.ServeHTTP(cached, req)
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("expected empty 304, got %d with %d bytes", cached.Code, cached.Body.Len())
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+// VersionHistory serves archived dataset versions; see
+// services.LocalUnitsLoader.
+type VersionHistory interface {
+	Versions(ctx context.Context) ([]services.SetSnapshot, error)
+	LoadVersion(ctx context.Context, version string) (*models.UnitsData, error)
+}
+
+type unitsResponse struct {
+	Version string        `json:"version"`
+	Units   []models.Unit `json:"units"`
+}
+
+// NewUnitsHandler serves GET /api/v1/units, narrowed by the same ?cost=,
+// ?trait=, ?role= and ?q= parameters as the builder page. ?version= picks
+// an archived dataset version when units keeps a VersionHistory.
+func NewUnitsHandler(units services.UnitsSource) http.HandlerFunc {
+	logger := log.Default()
+	history, _ := units.(VersionHistory)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data *models.UnitsData
+		var err error
+		version := r.URL.Query().Get("version")
+		switch {
+		case version == "":
+			data, err = units.LoadUnits(r.Context())
+		case history != nil:
+			data, err = history.LoadVersion(r.Context(), version)
+		default:
+			err = services.ErrUnknownVersion
+		}
+		if errors.Is(err, services.ErrUnknownVersion) {
+			writeError(w, http.StatusNotFound, "unknown version")
+			return
+		}
+		if err != nil {
+			logger.Printf("units: load units: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+		filtered := services.ParseUnitFilter(r.URL.Query()).Apply(data.Units)
+		if filtered == nil {
+			filtered = []models.Unit{}
+		}
+		writeJSON(w, http.StatusOK, unitsResponse{Version: data.Version, Units: filtered})
+	}
+}
+
+// NewUnitVersionsHandler serves GET /api/v1/units/versions, the archived
+// dataset versions newest first.
+func NewUnitVersionsHandler(history VersionHistory) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		versions, err := history.Versions(r.Context())
+		if err != nil {
+			logger.Printf("units: list versions: %v", err)
+			writeError(w, http.StatusServiceUnavailable, "history unavailable")
+			return
+		}
+		writeJSON(w, http.StatusOK, versions)
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+func TestUnitsHandler(t *testing.T) {
+	h := NewUnitsHandler(staticUnits{data: &models.UnitsData{Units: []models.Unit{
+		{Name: "Garen", Slug: "garen", Cost: 1},
+		{Name: "Lux", Slug: "lux", Cost: 4},
+	}}})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/units", 2},
+		{"/api/v1/units?cost=4", 1},
+		{"/api/v1/units?q=gar", 1},
+		{"/api/v1/units?q=nobody", 0},
+	}
+	for _, tt := range tests {
+		rec := do(h, http.MethodGet, tt.path, "")
+		var got unitsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("%s: %d %v", tt.path, rec.Code, err)
+		}
+		if len(got.Units) != tt.want || got.Units == nil {
+			t.Errorf("%s: got %d units, want %d", tt.path, len(got.Units), tt.want)
+		}
+	}
+}
+
+type versionedUnits struct {
+	staticUnits
+	past map[string]*models.UnitsData
+}
+
+func (v versionedUnits) Versions(context.Context) ([]services.SetSnapshot, error) {
+	var list []services.SetSnapshot
+	for version := range v.past {
+		list = append(list, services.SetSnapshot{Version: version})
+	}
+	return list, nil
+}
+
+func (v versionedUnits) LoadVersion(_ context.Context, version string) (*models.UnitsData, error) {
+	if data, ok := v.past[version]; ok {
+		return data, nil
+	}
+	return nil, services.ErrUnknownVersion
+}
+
+func TestUnitsHandler_Version(t *testing.T) {
+	units := versionedUnits{
+		staticUnits: staticUnits{data: &models.UnitsData{Version: "new", Units: []models.Unit{{Name: "Garen", Cost: 1}}}},
+		past: map[string]*models.UnitsData{
+			"old": {Version: "old", Units: []models.Unit{{Name: "Garen", Cost: 2}, {Name: "Lux", Cost: 4}}},
+		},
+	}
+	h := NewUnitsHandler(units)
+
+	rec := do(h, http.MethodGet, "/api/v1/units?version=old&cost=4", "")
+	var got unitsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("got %d %v", rec.Code, err)
+	}
+	if got.Version != "old" || len(got.Units) != 1 || got.Units[0].Name != "Lux" {
+		t.Errorf("old version = %+v", got)
+	}
+
+	if rec := do(h, http.MethodGet, "/api/v1/units?version=nope", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown version: expected 404, got %d", rec.Code)
+	}
+	if rec := do(NewUnitsHandler(units.staticUnits), http.MethodGet, "/api/v1/units?version=old", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("without history: expected 404, got %d", rec.Code)
+	}
+}
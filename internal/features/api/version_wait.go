@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"sft/internal/models"
+)
+
+const (
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 60 * time.Second
+)
+
+// VersionSource loads the dataset and signals when its version changes.
+type VersionSource interface {
+	LoadUnits(ctx context.Context) (*models.UnitsData, error)
+	Changed() <-chan struct{}
+}
+
+type versionWaitResponse struct {
+	Version string `json:"version"`
+	Changed bool   `json:"changed"`
+}
+
+// NewVersionWaitHandler blocks until the dataset version differs from ?current=
+// or ?timeout= (default 30s, max 60s) elapses, then reports the latest version.
+func NewVersionWaitHandler(source VersionSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		timeout, err := parseWaitTimeout(r.URL.Query().Get("timeout"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid timeout")
+			return
+		}
+		current := r.URL.Query().Get("current")
+
+		// Grab the channel before reading the version so a reload in between isn't missed.
+		changed := source.Changed()
+		version, err := datasetVersion(r.Context(), source)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if current == "" || current != version {
+			writeJSON(w, http.StatusOK, versionWaitResponse{Version: version, Changed: current != version})
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-changed:
+			if version, err = datasetVersion(r.Context(), source); err != nil {
+				writeError(w, http.StatusServiceUnavailable, "dataset unavailable")
+				return
+			}
+			writeJSON(w, http.StatusOK, versionWaitResponse{Version: version, Changed: version != current})
+		case <-timer.C:
+			writeJSON(w, http.StatusOK, versionWaitResponse{Version: version})
+		case <-r.Context().Done():
+		}
+	}
+}
+
+func datasetVersion(ctx context.Context, source VersionSource) (string, error) {
+	data, err := source.LoadUnits(ctx)
+	if err != nil {
+		return "", err
+	}
+	return data.Version, nil
+}
+
+// parseWaitTimeout accepts Go durations ("30s") or plain seconds ("30").
+func parseWaitTimeout(raw string) (time.Duration, error) {
+	if raw == "" {
+		return defaultWaitTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		d, err = time.ParseDuration(raw + "s")
+		if err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 {
+		return defaultWaitTimeout, nil
+	}
+	if d > maxWaitTimeout {
+		return maxWaitTimeout, nil
+	}
+	return d, nil
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"sft/internal/models"
+)
+
+type fakeVersionSource struct {
+	mu      sync.Mutex
+	version string
+	changed chan struct{}
+}
+
+func newFakeVersionSource(version string) *fakeVersionSource {
+	return &fakeVersionSource{version: version, changed: make(chan struct{})}
+}
+
+func (f *fakeVersionSource) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &models.UnitsData{Version: f.version}, nil
+}
+
+func (f *fakeVersionSource) Changed() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.changed
+}
+
+func (f *fakeVersionSource) bump(version string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.version = version
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func decodeWait(t *testing.T, rec *httptest.ResponseRecorder) versionWaitResponse {
+	t.Helper()
+	var resp versionWaitResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return resp
+}
+
+func TestVersionWait_ReturnsImmediatelyWhenStale(t *testing.T) {
+	handler := NewVersionWaitHandler(newFakeVersionSource("v2"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version/wait?current=v1", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	resp := decodeWait(t, rec)
+	if resp.Version != "v2" || !resp.Changed {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestVersionWait_TimesOut(t *testing.T) {
+	handler := NewVersionWaitHandler(newFakeVersionSource("v1"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version/wait?current=v1&timeout=10ms", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	resp := decodeWait(t, rec)
+	if resp.Version != "v1" || resp.Changed {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestVersionWait_WakesOnChange(t *testing.T) {
+	source := newFakeVersionSource("v1")
+	handler := NewVersionWaitHandler(source)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		source.bump("v2")
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version/wait?current=v1&timeout=5s", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	resp := decodeWait(t, rec)
+	if resp.Version != "v2" || !resp.Changed {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestParseWaitTimeout(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"", defaultWaitTimeout},
+		{"10s", 10 * time.Second},
+		{"15", 15 * time.Second},
+		{"5m", maxWaitTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseWaitTimeout(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("parseWaitTimeout(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := parseWaitTimeout("soon"); err == nil {
+		t.Error("expected error for invalid timeout")
+	}
+}
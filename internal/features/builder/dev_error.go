@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// dumpItems is how many elements of each list the data dump shows.
+	dumpItems = 3
+	// dumpBytes caps the dump; the builder data holds every unit.
+	dumpBytes = 64 << 10
+)
+
+// sensitiveKey matches data fields whose values are never shown.
+var sensitiveKey = regexp.MustCompile(`(?i)token|secret|password|passwd|session|cookie|email|signature|key$`)
+
+// execErrorPos finds the position in an execution error, e.g.
+// `template: builder.gohtml:12:5: executing "content" at <.Foo>: ...`.
+var execErrorPos = regexp.MustCompile(`template: ([^:]+):(\d+):(\d+): executing "([^"]+)" at <([^>]*)>: `)
+
+// RenderError answers a request whose page template failed to execute. In
+// production it writes a plain 500; with Dev set it writes a page naming
+// the failing template and line, with the data it was given. Callers log
+// err themselves.
+func (p PageOptions) RenderError(w http.ResponseWriter, name string, data any, err error) {
+	if !p.Dev {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	report := devError{Page: name, Message: err.Error(), Data: dumpData(data)}
+	if m := execErrorPos.FindStringSubmatch(report.Message); m != nil {
+		report.File, report.Line, report.Column = m[1], m[2], m[3]
+		report.Template, report.Action = m[4], m[5]
+		report.Message = strings.TrimPrefix(report.Message, m[0])
+	}
+
+	h := w.Header()
+	h.Del("ETag")
+	h.Set("Cache-Control", "no-store")
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	if err := devErrorPage.Execute(w, report); err != nil {
+		fmt.Fprintf(w, "%s: %v", name, report.Message)
+	}
+}
+
+type devError struct {
+	Page     string
+	File     string
+	Line     string
+	Column   string
+	Template string
+	Action   string
+	Message  string
+	Data     string
+}
+
+var devErrorPage = template.Must(template.New("dev-error").Parse(`<!doctype html>
+<html lang="en">
+<head><meta charset="UTF-8"><title>Template error · {{.Page}}</title>
+<style>
+body { margin: 2rem; font: 14px/1.5 system-ui, sans-serif; background: #111; color: #eee; }
+h1 { color: #f87171; font-size: 1.25rem; }
+dt { color: #9ca3af; } dd { margin: 0 0 .5rem; font-family: monospace; }
+pre { padding: 1rem; overflow: auto; background: #1f2937; }
+</style></head>
+<body>
+<h1>Template error rendering {{.Page}}</h1>
+<dl>
+{{if .File}}<dt>Location</dt><dd>{{.File}}:{{.Line}}:{{.Column}} in {{printf "%q" .Template}}</dd>
+<dt>Action</dt><dd>{{.Action}}</dd>{{end}}
+<dt>Error</dt><dd>{{.Message}}</dd>
+</dl>
+<h2>Data</h2>
+<pre>{{.Data}}</pre>
+</body>
+</html>
+`))
+
+// dumpData renders data as indented JSON with sensitive fields redacted
+// and long lists cut to their first few elements.
+func dumpData(data any) string {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("(cannot dump %T: %v)", data, err)
+	}
+	var tree any
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return fmt.Sprintf("(cannot dump %T: %v)", data, err)
+	}
+	out, _ := json.MarshalIndent(redact(tree), "", "  ")
+	if len(out) > dumpBytes {
+		return string(out[:dumpBytes]) + "\n… truncated"
+	}
+	return string(out)
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if sensitiveKey.MatchString(k) {
+				v[k] = "[redacted]"
+			} else {
+				v[k] = redact(child)
+			}
+		}
+		return v
+	case []any:
+		if len(v) > dumpItems {
+			v = append(v[:dumpItems:dumpItems], fmt.Sprintf("… %d more", len(v)-dumpItems))
+		}
+		for i, child := range v {
+			v[i] = redact(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,45 @@
+package builder
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRenderError(t *testing.T) {
+	tmpl := template.Must(template.New("page.gohtml").Parse("line one\n{{.Missing.Field}}"))
+	data := struct {
+		Title       string
+		Units       []int
+		AdminToken  string
+		Description string
+	}{"Builder", []int{1, 2, 3, 4, 5}, "hunter2", "ok"}
+	execErr := tmpl.Execute(io.Discard, data)
+	if execErr == nil {
+		t.Fatal("expected an execution error")
+	}
+
+	rec := httptest.NewRecorder()
+	PageOptions{}.RenderError(rec, "page.gohtml", data, execErr)
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "Missing") {
+		t.Errorf("production: status %d, body %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	PageOptions{Dev: true}.RenderError(rec, "page.gohtml", data, execErr)
+	body := rec.Body.String()
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("dev: status %d", rec.Code)
+	}
+	for _, want := range []string{"page.gohtml:2:", ".Missing.Field", "Builder", "… 2 more", "[redacted]"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("dev page does not contain %q", want)
+		}
+	}
+	if strings.Contains(body, "hunter2") {
+		t.Error("dev page shows a redacted value")
+	}
+}
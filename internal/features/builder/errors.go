@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"errors"
+	"net/http"
+
+	"sft/internal/services"
+)
+
+// UnitsErrorStatus returns the status a page answers when loading or
+// looking up units fails: 404 for an unknown unit, 503 while the set data
+// is not installed and 500 for anything else, such as broken data.
+func UnitsErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUnitNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrSetFileNotFound):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WriteUnitsError answers a units error with the status UnitsErrorStatus
+// chooses.
+func WriteUnitsError(w http.ResponseWriter, err error) {
+	status := UnitsErrorStatus(err)
+	http.Error(w, http.StatusText(status), status)
+}
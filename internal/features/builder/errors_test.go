@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+func TestUnitsErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("%w: %q", services.ErrUnitNotFound, "ahri"), http.StatusNotFound},
+		{fmt.Errorf("%w: open data/set.json", services.ErrSetFileNotFound), http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: decode", services.ErrSetFileInvalid), http.StatusInternalServerError},
+		{errors.New("disk on fire"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := UnitsErrorStatus(tt.err); got != tt.want {
+			t.Errorf("UnitsErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewHandler_LoadErrors(t *testing.T) {
+	failing := func(err error) unitsFunc {
+		return func(context.Context) (*models.UnitsData, error) { return nil, err }
+	}
+	page := PageOptions{Assets: staticAssets{}}
+
+	// Invalid data fails loudly rather than render a board missing its units.
+	rec := httptest.NewRecorder()
+	h := NewHandler(failing(services.ErrSetFileInvalid), nil, page)
+	h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("invalid data: status %d, want 500", rec.Code)
+	}
+}
+
+type staticAssets struct{}
+
+func (staticAssets) Resolve() AssetPaths { return AssetPaths{} }
@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// TemplateHash fingerprints the parsed template set so that template-only
+// deploys change page ETags. It must run before the first execution, since
+// html/template rewrites the trees when escaping them.
+func TemplateHash(tmpl *template.Template) string {
+	if tmpl == nil {
+		return ""
+	}
+	set := tmpl.Templates()
+	sort.Slice(set, func(i, j int) bool { return set[i].Name() < set[j].Name() })
+
+	h := sha256.New()
+	for _, t := range set {
+		h.Write([]byte(t.Name()))
+		h.Write([]byte{0})
+		if t.Tree != nil && t.Tree.Root != nil {
+			h.Write([]byte(t.Tree.Root.String()))
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// ETag identifies a rendered page. It changes with the templates, the
+// dataset, the asset bundle and key, which should cover every request input
+// the page depends on (typically the path and query).
+func (p PageOptions) ETag(c Chrome, dataVersion, key string) string {
+	h := sha256.New()
+	for _, part := range []string{
+		p.TemplateHash, dataVersion, c.Canonical, c.StaticBase, c.Locale,
+		c.Assets.CSS, c.Assets.JS, c.Assets.ThemeCSS,
+		c.Assets.CSSIntegrity, c.Assets.JSIntegrity, key,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:12]) + `"`
+}
+
+// NotModified sets the ETag header and reports whether the request's
+// If-None-Match already matches it, in which case a 304 has been written.
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == etag || candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
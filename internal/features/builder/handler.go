@@ -2,55 +2,230 @@ package builder
 
 import (
 	"bytes"
-	"html/template"
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"sft/internal/cache"
+	"sft/internal/i18n"
 	"sft/internal/models"
 	"sft/internal/services"
 )
 
 // AssetPaths holds the versioned asset URLs used by templates.
 type AssetPaths struct {
-	CSS string
-	JS  string
+	CSS      string
+	JS       string
+	ThemeCSS string // optional per-site stylesheet loaded after CSS
+
+	// Subresource Integrity hashes of CSS and JS, e.g. "sha384-...".
+	// Empty omits the integrity attribute.
+	CSSIntegrity string
+	JSIntegrity  string
+}
+
+// AssetSource provides the current versioned asset URLs.
+type AssetSource interface {
+	Resolve() AssetPaths
+}
+
+// Templates executes named page templates. *template.Template implements
+// it; development servers substitute one that re-parses on every call.
+type Templates interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+// PageOptions holds the site-wide settings shared by rendered pages.
+type PageOptions struct {
+	SiteName   string
+	Theme      string
+	ThemeColor string // theme-color meta tag, matching the web app manifest
+	StaticBase string
+	Canonical  string
+	Assets     AssetSource
+	Preconnect []string // origins that get preconnect/dns-prefetch hints
+	// TemplateHash fingerprints the parsed templates; see TemplateHash.
+	TemplateHash string
+	// Cache holds rendered builder pages; nil renders every request.
+	Cache *RenderCache
+	// Fragments holds rendered HTML fragments such as tooltips, keyed by
+	// ETag; nil renders every request.
+	Fragments cache.Cache
+	// Dev shows template errors in the page instead of a plain 500.
+	Dev bool
+	// Favorites returns the apiNames of the units the request's user has
+	// starred, listed first in the unit picker; nil disables favorites.
+	Favorites func(r *http.Request) []string
+}
+
+// Chrome is the layout data every page passes to the "head" template.
+type Chrome struct {
+	SiteName   string
+	Theme      string
+	ThemeColor string
+	StaticBase string
+	Canonical  string
+	Path       string // page path relative to Canonical; empty for the builder
+	Assets     AssetPaths
+	Preconnect []string
+	OGImage    string // absolute preview image URL; empty omits the Open Graph tags
+	Locale     string // UI language for the "t" template func, see i18n.FromContext
+	// Description is the page's meta description; empty uses the site default.
+	Description string
+}
+
+// Chrome resolves the layout data for a single request.
+func (p PageOptions) Chrome(r *http.Request) Chrome {
+	return Chrome{
+		SiteName:   p.SiteName,
+		Theme:      p.Theme,
+		ThemeColor: p.ThemeColor,
+		StaticBase: p.StaticBase,
+		Canonical:  p.Canonical,
+		Assets:     p.Assets.Resolve(),
+		Preconnect: p.Preconnect,
+		Locale:     i18n.FromContext(r.Context()),
+	}
 }
 
 // NewHandler builds an http.HandlerFunc with injected dependencies.
-func NewHandler(loader services.UnitsSource, templates *template.Template, staticBase, canonical string, assets AssetPaths) http.HandlerFunc {
+func NewHandler(loader services.UnitsSource, templates Templates, page PageOptions) http.HandlerFunc {
 	logger := log.Default()
+	page.Cache.Watch(loader)
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
+		chrome := page.Chrome(r)
+		var favorites []string
+		if page.Favorites != nil {
+			favorites = page.Favorites(r)
+		}
+		// Pages with favorites are personal: neither shared caches nor the
+		// render cache may hand them to anyone else.
+		personal := len(favorites) > 0
+
+		// etag stays empty for the degraded page, which must be neither
+		// revalidated as current nor cached.
+		var etag string
 		unitsData, err := loader.LoadUnits(r.Context())
-		if err != nil {
-			logger.Printf("Error loading units: %v", err)
+		if errors.Is(err, services.ErrSetFileNotFound) {
+			// Without data yet the builder still works as a blank board.
+			logger.Printf("Serving an empty board: %v", err)
 			unitsData = &models.UnitsData{Units: []models.Unit{}}
+		} else if err != nil {
+			logger.Printf("Error loading units: %v", err)
+			WriteUnitsError(w, err)
+			return
+		} else {
+			key := r.URL.RequestURI()
+			if personal {
+				w.Header().Set("Cache-Control", "private, no-cache")
+				key += "\x00" + strings.Join(favorites, ",")
+			} else {
+				w.Header().Set("Cache-Control", "no-cache")
+			}
+			etag = page.ETag(chrome, unitsData.Revision(), key)
+			if NotModified(w, r, etag) {
+				return
+			}
+			// Anonymous pages are identical until the templates or data change.
+			if body, ok := page.Cache.Get(etag); ok && !personal {
+				_, _ = w.Write(body)
+				return
+			}
 		}
 
-		board := models.NewBoardView(4, 7)
+		board := models.NewBoardView(models.BoardRows, models.BoardCols).WithBench(models.BenchSlots)
+
+		var state models.BoardState
+		boardCode := r.URL.Query().Get("b")
+		if boardCode != "" {
+			state, err = models.DecodeBoardState(boardCode)
+			if err != nil {
+				logger.Printf("Ignoring board code %q: %v", boardCode, err)
+				boardCode = ""
+			} else {
+				board.Place(state, unitsData.Units)
+				board.ResolveItems(unitsData.Items)
+			}
+		}
+
+		if boardCode != "" && chrome.Canonical != "" {
+			chrome.OGImage = chrome.Canonical + "comps/" + url.PathEscape(boardCode) + "/image.png"
+		}
+
+		synergies := services.ComputeSynergies(state, unitsData.Traits)
+		chrome.Description = services.CompMetaDescription(state, unitsData.Units, synergies)
+
+		// Filters only narrow the unit picker; the board keeps every placed unit.
+		filter := services.ParseUnitFilter(r.URL.Query())
 
 		data := struct {
-			Board      models.BoardView
-			Units      []models.Unit
-			StaticBase string
-			Canonical  string
-			Assets     AssetPaths
+			Chrome
+			Revision  string // of the data rendered, for reload notifications
+			Board     models.BoardView
+			BoardCode string
+			Units     []models.Unit
+			Favorites map[string]bool // by apiName
+			Filter    services.UnitFilter
+			Traits    models.TraitIndex
+			Synergies []services.Synergy
+			Shop      *models.ShopOdds
 		}{
-			Board:      board,
-			Units:      unitsData.Units,
-			StaticBase: staticBase,
-			Canonical:  canonical,
-			Assets:     assets,
+			Chrome:    chrome,
+			Revision:  unitsData.Revision(),
+			Board:     board,
+			BoardCode: boardCode,
+			Units:     favoritesFirst(filter.Apply(unitsData.Units), favorites),
+			Favorites: favoriteSet(favorites),
+			Filter:    filter,
+			Traits:    models.NewTraitIndex(unitsData.Traits, unitsData.Units, board),
+			Synergies: synergies,
+			Shop:      unitsData.Shop,
 		}
 
 		var buf bytes.Buffer
 		if err := templates.ExecuteTemplate(&buf, "builder.gohtml", data); err != nil {
 			logger.Printf("Template error: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			page.RenderError(w, "builder.gohtml", data, err)
 			return
 		}
+		if etag != "" && !personal {
+			page.Cache.Put(etag, buf.Bytes())
+		}
 		_, _ = w.Write(buf.Bytes())
 	}
 }
+
+// favoritesFirst moves the favorite units to the front, keeping the order
+// of both parts. It copies units rather than reorder the shared dataset.
+func favoritesFirst(units []models.Unit, favorites []string) []models.Unit {
+	if len(favorites) == 0 {
+		return units
+	}
+	set := favoriteSet(favorites)
+	out := make([]models.Unit, 0, len(units))
+	for _, u := range units {
+		if set[u.APIName] {
+			out = append(out, u)
+		}
+	}
+	for _, u := range units {
+		if !set[u.APIName] {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func favoriteSet(favorites []string) map[string]bool {
+	set := make(map[string]bool, len(favorites))
+	for _, f := range favorites {
+		set[f] = true
+	}
+	return set
+}
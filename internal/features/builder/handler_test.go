@@ -0,0 +1,23 @@
+package builder
+
+import (
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestFavoritesFirst(t *testing.T) {
+	units := []models.Unit{{APIName: "a"}, {APIName: "b"}, {APIName: "c"}, {APIName: "d"}}
+
+	got := favoritesFirst(units, []string{"d", "b", "gone"})
+	var order string
+	for _, u := range got {
+		order += u.APIName
+	}
+	if order != "bdac" {
+		t.Errorf("order = %s, want bdac", order)
+	}
+	if units[0].APIName != "a" {
+		t.Error("favoritesFirst reordered its input")
+	}
+}
@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// Layouts executes pages within the shared layouts. Each page is parsed
+// into its own copy of the shared templates (layouts and components), so
+// every page can define the "title" and "content" blocks of "base" without
+// clashing with the others.
+type Layouts struct {
+	shared *template.Template
+	pages  map[string]*template.Template
+}
+
+// NewLayouts creates a set of pages on top of shared. shared must not have
+// been executed, since pages are parsed into clones of it.
+func NewLayouts(shared *template.Template) *Layouts {
+	return &Layouts{shared: shared, pages: make(map[string]*template.Template)}
+}
+
+// AddPage parses files into a copy of the shared templates and registers
+// the result under the base name of the first file, e.g. "unit.gohtml".
+func (l *Layouts) AddPage(files ...string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("add page: no files")
+	}
+	page, err := l.shared.Clone()
+	if err != nil {
+		return fmt.Errorf("add page %s: %w", files[0], err)
+	}
+	if page, err = page.ParseFiles(files...); err != nil {
+		return fmt.Errorf("add page %s: %w", files[0], err)
+	}
+	l.pages[filepath.Base(files[0])] = page
+	return nil
+}
+
+// ExecuteTemplate renders the page called name within its layout. Names
+// that are not pages, such as a component rendered on its own, run from
+// the shared templates.
+func (l *Layouts) ExecuteTemplate(w io.Writer, name string, data any) error {
+	if page, ok := l.pages[name]; ok {
+		return page.ExecuteTemplate(w, name, data)
+	}
+	return l.shared.ExecuteTemplate(w, name, data)
+}
+
+// Hash fingerprints the shared templates and every page; see TemplateHash.
+// Like TemplateHash, it must run before the first execution.
+func (l *Layouts) Hash() string {
+	names := make([]string, 0, len(l.pages))
+	for name := range l.pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(TemplateHash(l.shared)))
+	for _, name := range names {
+		h.Write([]byte{0})
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(TemplateHash(l.pages[name])))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
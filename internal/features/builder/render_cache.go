@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"container/list"
+	"sync"
+)
+
+// RenderCache keeps recently rendered pages in memory, least recently used
+// evicted first. Keys are page ETags, which already cover the route, query,
+// locale, templates and dataset version. A nil RenderCache never hits.
+type RenderCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	lru     *list.List               // front is most recently used; values are *renderEntry
+	entries map[string]*list.Element // key → element in lru
+	source  ChangeNotifier
+	changed <-chan struct{} // closed when the dataset reloads
+}
+
+type renderEntry struct {
+	key  string
+	body []byte
+}
+
+// ChangeNotifier is implemented by units sources that signal reloads, such
+// as services.LocalUnitsLoader.
+type ChangeNotifier interface {
+	Changed() <-chan struct{}
+}
+
+// NewRenderCache creates a cache of up to maxEntries pages. It returns nil,
+// disabling caching, when maxEntries is not positive.
+func NewRenderCache(maxEntries int) *RenderCache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &RenderCache{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Watch empties the cache whenever source reloads. Old entries would never
+// be hit once the version changes, but they would hold memory until evicted.
+func (c *RenderCache) Watch(source any) {
+	if c == nil {
+		return
+	}
+	if n, ok := source.(ChangeNotifier); ok {
+		c.mu.Lock()
+		c.source, c.changed = n, n.Changed()
+		c.mu.Unlock()
+	}
+}
+
+// Get returns the page stored under key and marks it recently used.
+func (c *RenderCache) Get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.purgeIfChangedLocked()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*renderEntry).body, true
+}
+
+// Put stores body under key. The cache keeps body, so callers must not
+// modify it afterwards.
+func (c *RenderCache) Put(key string, body []byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.purgeIfChangedLocked()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*renderEntry).body = body
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&renderEntry{key: key, body: body})
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*renderEntry).key)
+	}
+}
+
+// Len returns the number of cached pages.
+func (c *RenderCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *RenderCache) purgeIfChangedLocked() {
+	if c.changed == nil {
+		return
+	}
+	select {
+	case <-c.changed:
+	default:
+		return
+	}
+	c.lru.Init()
+	clear(c.entries)
+	// Each reload hands out a fresh channel for the next one.
+	c.changed = c.source.Changed()
+}
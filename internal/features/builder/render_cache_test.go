@@ -0,0 +1,53 @@
+package builder
+
+import "testing"
+
+type notifier struct{ ch chan struct{} }
+
+func (n *notifier) Changed() <-chan struct{} { return n.ch }
+
+func TestRenderCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewRenderCache(2)
+	c.Put("a", []byte("A"))
+	c.Put("b", []byte("B"))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	c.Put("c", []byte("C"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s missing", key)
+		}
+	}
+}
+
+func TestRenderCachePurgesOnReload(t *testing.T) {
+	n := &notifier{ch: make(chan struct{})}
+	c := NewRenderCache(4)
+	c.Watch(n)
+	c.Put("a", []byte("A"))
+
+	close(n.ch)
+	n.ch = make(chan struct{})
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("entry survived a reload")
+	}
+
+	c.Put("b", []byte("B"))
+	if _, ok := c.Get("b"); !ok {
+		t.Error("cache stopped storing after a reload")
+	}
+}
+
+func TestNilRenderCache(t *testing.T) {
+	c := NewRenderCache(0)
+	c.Watch(&notifier{})
+	c.Put("a", []byte("A"))
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Error("disabled cache stored a page")
+	}
+}
@@ -0,0 +1,36 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"sft/internal/services"
+)
+
+// Warm renders the empty builder page through h once, so the units are
+// loaded, every ability tooltip is formatted and a cache-enabled handler
+// holds the page before the first visitor asks for it. It returns the
+// first problem found: a dataset that fails to load or a page that does
+// not render.
+func Warm(ctx context.Context, loader services.UnitsSource, h http.Handler) (time.Duration, error) {
+	start := time.Now()
+	data, err := loader.LoadUnits(ctx)
+	if err != nil {
+		return time.Since(start), fmt.Errorf("load units: %w", err)
+	}
+	if len(data.Units) == 0 {
+		return time.Since(start), errors.New("load units: dataset has no units")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return time.Since(start), fmt.Errorf("render builder page: status %d", rec.Code)
+	}
+	return time.Since(start), nil
+}
@@ -0,0 +1,43 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"sft/internal/models"
+)
+
+type unitsFunc func(context.Context) (*models.UnitsData, error)
+
+func (f unitsFunc) LoadUnits(ctx context.Context) (*models.UnitsData, error) { return f(ctx) }
+
+func TestWarm(t *testing.T) {
+	units := unitsFunc(func(context.Context) (*models.UnitsData, error) {
+		return &models.UnitsData{Units: []models.Unit{{Name: "Ahri"}}}, nil
+	})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	broken := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	failing := unitsFunc(func(context.Context) (*models.UnitsData, error) {
+		return nil, errors.New("no data")
+	})
+
+	tests := []struct {
+		name    string
+		units   unitsFunc
+		h       http.Handler
+		wantErr bool
+	}{
+		{"renders", units, ok, false},
+		{"template error", units, broken, true},
+		{"data error", failing, ok, true},
+	}
+	for _, tt := range tests {
+		if _, err := Warm(context.Background(), tt.units, tt.h); (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package changelog
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sft/internal/store"
+)
+
+const sample = `# Changelog
+
+## 16.1 (2026-10-01) Launch
+First set.
+
+## 16.2 (2026-10-15) Balance changes
+- **Ahri** damage up
+- Jinx range down
+`
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	got := entries[0]
+	if got.Version != "16.2" || got.Title != "Balance changes" || !got.Published.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("newest entry = %+v", got)
+	}
+	if got.Body != "- **Ahri** damage up\n- Jinx range down" {
+		t.Errorf("body = %q", got.Body)
+	}
+	if entries[1].Body != "First set." {
+		t.Errorf("older body = %q", entries[1].Body)
+	}
+
+	for _, bad := range []string{"## 16.3 Missing date\n", "## 16.3 (2026-13-40)\n", "## bad version (2026-10-01)\n"} {
+		if _, err := Parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("Parse(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"paragraph", "one\ntwo", "<p>one two</p>\n"},
+		{"list", "intro\n- a\n- `b`", "<p>intro</p>\n<ul>\n<li>a</li>\n<li><code>b</code></li>\n</ul>\n"},
+		{"heading", "### Units\n**Ahri**", "<h3>Units</h3>\n<p><strong>Ahri</strong></p>\n"},
+		{"link", "[notes](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2">notes</a></p>` + "\n"},
+		{"escapes html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"no script links", "[x](javascript:alert(1)) [y](//evil.example)", "<p>[x](javascript:alert(1)) [y](//evil.example)</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Render(tt.md)); got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.md, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSource_RereadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	write := func(body string, at time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write(sample, start)
+
+	source := NewFileSource(path)
+	if entries, err := source.Entries(context.Background()); err != nil || len(entries) != 2 {
+		t.Fatalf("entries: %v (%d)", err, len(entries))
+	}
+	write(sample+"\n## 16.3 (2026-11-01)\nMore.\n", start.Add(time.Minute))
+	if entries, _ := source.Entries(context.Background()); len(entries) != 3 || entries[0].Version != "16.3" {
+		t.Errorf("after edit: %+v", entries)
+	}
+}
+
+type staticSource []store.ChangelogEntry
+
+func (s staticSource) Entries(context.Context) ([]store.ChangelogEntry, error) { return s, nil }
+
+func TestFeedHandler(t *testing.T) {
+	entries, _ := Parse(strings.NewReader(sample))
+	handler := NewFeedHandler(staticSource(entries), "SFT", "https://sft.example/")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FeedPath, nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/atom+xml") {
+		t.Fatalf("got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if feed.Updated != "2026-10-15T00:00:00Z" || len(feed.Entries) != 2 {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+	e := feed.Entries[0]
+	if e.ID != "https://sft.example/changelog#v16.2" || e.Title != "16.2: Balance changes" || !strings.Contains(e.Content.Body, "<strong>Ahri</strong>") {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, FeedPath, nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation: expected 304, got %d", rec.Code)
+	}
+}
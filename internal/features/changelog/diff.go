@@ -0,0 +1,54 @@
+package changelog
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+
+	"sft/internal/features/builder"
+	"sft/internal/services"
+)
+
+// DiffSource provides the diff of the current set data against the
+// previous version, nil when there is none.
+type DiffSource interface {
+	LatestDiff() *services.SetDiff
+}
+
+// NewDiffHandler renders GET /changelog/diff, the unit changes of the
+// latest data update.
+func NewDiffHandler(source DiffSource, templates builder.Templates, page builder.PageOptions) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		diff := source.LatestDiff()
+
+		chrome := page.Chrome(r)
+		chrome.Path = "changelog/diff"
+		w.Header().Set("Cache-Control", "no-cache")
+		revision := "none"
+		if diff != nil {
+			revision = diff.From + "-" + diff.To
+		}
+		if builder.NotModified(w, r, page.ETag(chrome, revision, chrome.Path)) {
+			return
+		}
+
+		data := struct {
+			builder.Chrome
+			Diff *services.SetDiff
+		}{
+			Chrome: chrome,
+			Diff:   diff,
+		}
+
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "changelog_diff.gohtml", data); err != nil {
+			logger.Printf("Template error: %v", err)
+			page.RenderError(w, "changelog_diff.gohtml", data, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
@@ -0,0 +1,184 @@
+package changelog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+
+	"sft/internal/features/builder"
+	"sft/internal/store"
+)
+
+// FeedPath is where the Atom feed is served; the page links to it.
+const FeedPath = "/changelog.atom"
+
+type entryView struct {
+	store.ChangelogEntry
+	Anchor string        // fragment id of the entry on the page
+	HTML   template.HTML // rendered Body
+}
+
+// NewPageHandler renders GET /changelog.
+func NewPageHandler(source Source, templates builder.Templates, page builder.PageOptions) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := source.Entries(r.Context())
+		if err != nil {
+			logger.Printf("Error loading changelog: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		chrome := page.Chrome(r)
+		chrome.Path = "changelog"
+		w.Header().Set("Cache-Control", "no-cache")
+		if builder.NotModified(w, r, page.ETag(chrome, revision(entries), chrome.Path)) {
+			return
+		}
+
+		views := make([]entryView, 0, len(entries))
+		for _, e := range entries {
+			views = append(views, entryView{ChangelogEntry: e, Anchor: anchor(e.Version), HTML: Render(e.Body)})
+		}
+		data := struct {
+			builder.Chrome
+			Entries  []entryView
+			FeedPath string
+		}{
+			Chrome:   chrome,
+			Entries:  views,
+			FeedPath: FeedPath,
+		}
+
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "changelog.gohtml", data); err != nil {
+			logger.Printf("Template error: %v", err)
+			page.RenderError(w, "changelog.gohtml", data, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// NewFeedHandler serves the changelog as an Atom feed at FeedPath. Links
+// are absolute, built on canonical (the site URL with a trailing slash),
+// or on the request's host when the site URL is not configured.
+func NewFeedHandler(source Source, siteName, canonical string) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := source.Entries(r.Context())
+		if err != nil {
+			logger.Printf("Error loading changelog: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		etag := `"` + revision(entries) + `"`
+		w.Header().Set("Cache-Control", "no-cache")
+		if builder.NotModified(w, r, etag) {
+			return
+		}
+
+		base := canonical
+		if base == "" {
+			scheme := "http"
+			if r.TLS != nil {
+				scheme = "https"
+			}
+			base = scheme + "://" + r.Host + "/"
+		}
+		pageURL := base + "changelog"
+
+		feed := atomFeed{
+			Title: siteName + " changelog",
+			ID:    pageURL,
+			Links: []atomLink{
+				{Href: base + FeedPath[1:], Rel: "self"},
+				{Href: pageURL, Rel: "alternate"},
+			},
+			Entries: []atomEntry{},
+		}
+		// An empty feed still needs an update time; the epoch never changes.
+		updated := time.Unix(0, 0)
+		for _, e := range entries {
+			if e.Published.After(updated) {
+				updated = e.Published
+			}
+			title := e.Version
+			if e.Title != "" {
+				title += ": " + e.Title
+			}
+			link := pageURL + "#" + anchor(e.Version)
+			feed.Entries = append(feed.Entries, atomEntry{
+				Title:   title,
+				ID:      link,
+				Updated: e.Published.UTC().Format(time.RFC3339),
+				Link:    atomLink{Href: link},
+				Content: atomContent{Type: "html", Body: string(Render(e.Body))},
+			})
+		}
+		feed.Updated = updated.UTC().Format(time.RFC3339)
+
+		body, err := xml.MarshalIndent(feed, "", "  ")
+		if err != nil {
+			logger.Printf("Error encoding changelog feed: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		_, _ = w.Write([]byte(xml.Header))
+		_, _ = w.Write(body)
+	}
+}
+
+// anchor is the fragment id of a version's entry, e.g. "v16.2".
+func anchor(version string) string {
+	return "v" + version
+}
+
+// revision fingerprints the entries for ETags.
+func revision(entries []store.ChangelogEntry) string {
+	h := sha256.New()
+	for _, e := range entries {
+		for _, part := range []string{e.Version, e.Title, e.Body, e.Published.UTC().Format(time.RFC3339)} {
+			h.Write([]byte(part))
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
@@ -0,0 +1,71 @@
+package changelog
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Inline markdown, matched on HTML-escaped text.
+var (
+	inlineCode = regexp.MustCompile("`([^`]+)`")
+	strong     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	link       = regexp.MustCompile(`\[([^\]]+)\]\(((?:https?://|/[^/)\s])[^)\s]*)\)`)
+)
+
+// Render converts the small markdown subset patch notes use to HTML:
+// paragraphs, "-" or "*" lists, "###" subheadings, **bold**, `code` and
+// [links](https://...). Everything else is escaped, so entries cannot
+// inject markup.
+func Render(md string) template.HTML {
+	var b strings.Builder
+	var para []string
+	inList := false
+
+	closeBlocks := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + inline(strings.Join(para, " ")) + "</p>\n")
+			para = para[:0]
+		}
+		if inList {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			closeBlocks()
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			if len(para) > 0 {
+				closeBlocks()
+			}
+			if !inList {
+				b.WriteString("<ul>\n")
+				inList = true
+			}
+			b.WriteString("<li>" + inline(line[2:]) + "</li>\n")
+		case strings.HasPrefix(line, "### "):
+			closeBlocks()
+			b.WriteString("<h3>" + inline(line[4:]) + "</h3>\n")
+		default:
+			if inList {
+				closeBlocks()
+			}
+			para = append(para, line)
+		}
+	}
+	closeBlocks()
+	return template.HTML(b.String())
+}
+
+// inline escapes s and applies the inline markup.
+func inline(s string) string {
+	s = html.EscapeString(s)
+	s = inlineCode.ReplaceAllString(s, "<code>$1</code>")
+	s = strong.ReplaceAllString(s, "<strong>$1</strong>")
+	return link.ReplaceAllString(s, `<a href="$2">$1</a>`)
+}
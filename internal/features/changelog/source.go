@@ -0,0 +1,149 @@
+// Package changelog renders the patch notes page and its Atom feed from a
+// markdown file or the store.
+package changelog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sft/internal/store"
+)
+
+// maxEntries bounds the entries shown on the page and in the feed.
+const maxEntries = 100
+
+// Source provides the changelog, newest entry first.
+type Source interface {
+	Entries(ctx context.Context) ([]store.ChangelogEntry, error)
+}
+
+type storeSource struct {
+	entries store.ChangelogStore
+}
+
+// NewStoreSource reads the changelog from the store, where the admin
+// endpoint edits it.
+func NewStoreSource(entries store.ChangelogStore) Source {
+	return storeSource{entries: entries}
+}
+
+func (s storeSource) Entries(ctx context.Context) ([]store.ChangelogEntry, error) {
+	return s.entries.ListChangelog(ctx, maxEntries)
+}
+
+// FileSource reads the changelog from a markdown file, re-reading it when
+// its modification time changes so edits show up without a restart.
+type FileSource struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	entries []store.ChangelogEntry
+}
+
+// NewFileSource reads the changelog from the markdown file at path.
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// Entries returns the file's entries, newest first.
+func (s *FileSource) Entries(_ context.Context) ([]store.ChangelogEntry, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read changelog: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries != nil && info.ModTime().Equal(s.modTime) {
+		return s.entries, nil
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read changelog: %w", err)
+	}
+	defer f.Close()
+	entries, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("read changelog %s: %w", s.path, err)
+	}
+	s.entries, s.modTime = entries, info.ModTime()
+	return entries, nil
+}
+
+// heading matches an entry heading: "## <version> (<YYYY-MM-DD>) <title>".
+var heading = regexp.MustCompile(`^##\s+([A-Za-z0-9._-]{1,32})\s+\((\d{4}-\d{2}-\d{2})\)\s*(.*)$`)
+
+// Parse reads changelog entries from markdown. Each entry starts with a
+// heading such as
+//
+//	## 16.2 (2026-10-15) Balance changes
+//
+// and runs until the next one; text before the first heading, like a
+// "# Changelog" title, is ignored. Entries are returned newest first.
+func Parse(r io.Reader) ([]store.ChangelogEntry, error) {
+	entries := []store.ChangelogEntry{}
+	var body []string
+	flush := func() {
+		if len(entries) > 0 {
+			entries[len(entries)-1].Body = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = body[:0]
+	}
+
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if !strings.HasPrefix(text, "## ") {
+			body = append(body, text)
+			continue
+		}
+		m := heading.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: heading %q is not \"## <version> (<YYYY-MM-DD>) <title>\"", line, text)
+		}
+		published, err := time.Parse(time.DateOnly, m[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		flush()
+		entries = append(entries, store.ChangelogEntry{Version: m[1], Title: strings.TrimSpace(m[3]), Published: published})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Published.After(entries[j].Published)
+	})
+	if len(entries) > maxEntries {
+		entries = entries[:maxEntries]
+	}
+	return entries, nil
+}
+
+// version limits patch labels to characters safe in URL fragments.
+var version = regexp.MustCompile(`^[A-Za-z0-9._-]{1,32}$`)
+
+// Validate checks an entry before it is stored, trimming its title.
+func Validate(e *store.ChangelogEntry) error {
+	if !version.MatchString(e.Version) {
+		return fmt.Errorf("version %q must be 1-32 letters, digits, '.', '_' or '-'", e.Version)
+	}
+	e.Title = strings.TrimSpace(e.Title)
+	if strings.TrimSpace(e.Body) == "" {
+		return errors.New("body is required")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package lobby
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sft/internal/auth"
+	"sft/internal/features/builder"
+	"sft/internal/store"
+)
+
+const maxFormSize = 16 << 10
+
+// Pages serves the HTML lobby planner under /lobbies.
+type Pages struct {
+	lobbies   store.LobbyStore
+	signer    *auth.LinkSigner
+	templates builder.Templates
+	page      builder.PageOptions
+	logger    *log.Logger
+}
+
+// NewPages wires the lobby pages to storage, the link signer and templates.
+func NewPages(lobbies store.LobbyStore, signer *auth.LinkSigner, templates builder.Templates, page builder.PageOptions) *Pages {
+	return &Pages{lobbies: lobbies, signer: signer, templates: templates, page: page, logger: log.Default()}
+}
+
+type pageData struct {
+	builder.Chrome
+	Lobby *store.Lobby
+	Link  string // signed share link
+	Sig   string // signature for the seat form actions
+	Error string
+}
+
+func (p *Pages) lobbyData(r *http.Request, lobby *store.Lobby) pageData {
+	return pageData{
+		Chrome: p.page.Chrome(r),
+		Lobby:  lobby,
+		Link:   Link(p.signer, lobby.ID),
+		Sig:    p.signer.Sign(SigKind, lobby.ID),
+	}
+}
+
+// New handles GET /lobbies with the lobby creation form.
+func (p *Pages) New(w http.ResponseWriter, r *http.Request) {
+	p.render(w, http.StatusOK, pageData{Chrome: p.page.Chrome(r)})
+}
+
+// Create handles POST /lobbies and redirects to the signed lobby link.
+func (p *Pages) Create(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	name, err := NormalizeName(r.PostFormValue("name"))
+	if err != nil {
+		p.render(w, http.StatusBadRequest, pageData{Chrome: p.page.Chrome(r), Error: err.Error()})
+		return
+	}
+
+	lobby := &store.Lobby{Name: name}
+	if user := auth.UserFrom(r.Context()); user != nil {
+		lobby.OwnerID = user.ID
+	}
+	if err := p.lobbies.CreateLobby(r.Context(), lobby); err != nil {
+		p.logger.Printf("lobbies: create: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, Link(p.signer, lobby.ID), http.StatusSeeOther)
+}
+
+// Show handles GET /lobbies/{id}?sig=.
+func (p *Pages) Show(w http.ResponseWriter, r *http.Request) {
+	lobby, ok := p.load(w, r)
+	if !ok {
+		return
+	}
+	p.render(w, http.StatusOK, p.lobbyData(r, lobby))
+}
+
+// UpdatePlayer handles the seat form POST /lobbies/{id}/players/{slot}?sig=.
+func (p *Pages) UpdatePlayer(w http.ResponseWriter, r *http.Request) {
+	lobby, ok := p.load(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	slot, _ := strconv.Atoi(r.PathValue("slot"))
+	player, err := NormalizePlayer(store.LobbyPlayer{
+		Slot:  slot,
+		Name:  r.PostFormValue("name"),
+		Notes: r.PostFormValue("notes"),
+		Comps: strings.Split(r.PostFormValue("comps"), "\n"),
+	})
+	if err != nil {
+		data := p.lobbyData(r, lobby)
+		data.Error = err.Error()
+		p.render(w, http.StatusBadRequest, data)
+		return
+	}
+
+	if err := p.lobbies.UpdateLobbyPlayer(r.Context(), lobby.ID, player); err != nil {
+		p.logger.Printf("lobbies: update %d: %v", lobby.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, Link(p.signer, lobby.ID)+"#seat-"+strconv.Itoa(slot), http.StatusSeeOther)
+}
+
+// load resolves the lobby from the path after checking the link signature.
+// Unknown lobbies and bad signatures both answer 404 so IDs cannot be probed.
+func (p *Pages) load(w http.ResponseWriter, r *http.Request) (*store.Lobby, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || !p.signer.Verify(SigKind, id, r.URL.Query().Get("sig")) {
+		http.NotFound(w, r)
+		return nil, false
+	}
+
+	lobby, err := p.lobbies.GetLobby(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	if err != nil {
+		p.logger.Printf("lobbies: get %d: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return lobby, true
+}
+
+func (p *Pages) render(w http.ResponseWriter, status int, data pageData) {
+	var buf bytes.Buffer
+	if err := p.templates.ExecuteTemplate(&buf, "lobby.gohtml", data); err != nil {
+		p.logger.Printf("Template error: %v", err)
+		p.page.RenderError(w, "lobby.gohtml", data, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// Signed links are capabilities; keep them out of caches and referrers.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
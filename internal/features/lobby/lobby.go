@@ -0,0 +1,86 @@
+// Package lobby implements the tournament lobby planner: eight seats with
+// scouting notes and comp links, shared through signed URLs.
+package lobby
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"sft/internal/auth"
+	"sft/internal/models"
+	"sft/internal/store"
+)
+
+// SigKind namespaces lobby link signatures.
+const SigKind = "lobby"
+
+const (
+	maxNameLen       = 100
+	maxPlayerNameLen = 32
+	maxNotesLen      = 2000
+	maxCompsPerSeat  = 5
+)
+
+// Link returns the shareable, signed path of a lobby.
+func Link(signer *auth.LinkSigner, id int64) string {
+	return "/lobbies/" + strconv.FormatInt(id, 10) + "?sig=" + signer.Sign(SigKind, id)
+}
+
+// NormalizeName trims and validates a lobby name.
+func NormalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	if len(name) > maxNameLen {
+		return "", fmt.Errorf("name exceeds %d characters", maxNameLen)
+	}
+	return name, nil
+}
+
+// NormalizePlayer trims a seat and validates its limits and comp codes.
+// Comp entries may be board codes or builder URLs carrying ?b=.
+func NormalizePlayer(p store.LobbyPlayer) (store.LobbyPlayer, error) {
+	if p.Slot < 1 || p.Slot > store.LobbySize {
+		return p, fmt.Errorf("slot must be between 1 and %d", store.LobbySize)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if len(p.Name) > maxPlayerNameLen {
+		return p, fmt.Errorf("player name exceeds %d characters", maxPlayerNameLen)
+	}
+	p.Notes = strings.TrimSpace(p.Notes)
+	if len(p.Notes) > maxNotesLen {
+		return p, fmt.Errorf("notes exceed %d characters", maxNotesLen)
+	}
+
+	comps := make([]string, 0, len(p.Comps))
+	for _, ref := range p.Comps {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		state, err := models.DecodeBoardState(compCode(ref))
+		if err != nil {
+			return p, fmt.Errorf("comp %q: %w", ref, err)
+		}
+		comps = append(comps, state.Encode())
+	}
+	if len(comps) > maxCompsPerSeat {
+		return p, fmt.Errorf("at most %d comps per player", maxCompsPerSeat)
+	}
+	p.Comps = comps
+	return p, nil
+}
+
+// compCode extracts the board code from a builder URL, or returns ref unchanged.
+func compCode(ref string) string {
+	if u, err := url.Parse(ref); err == nil {
+		if code := u.Query().Get("b"); code != "" {
+			return code
+		}
+	}
+	return ref
+}
@@ -0,0 +1,68 @@
+// Package share serves rendered board images for social link previews.
+package share
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"sft/internal/features/builder"
+	"sft/internal/imagecache"
+	"sft/internal/models"
+	"sft/internal/preview"
+	"sft/internal/services"
+)
+
+// imageCacheSeconds is safe to keep long since the code fully describes the image.
+const imageCacheSeconds = "86400"
+
+// NewImageHandler renders GET /comps/{code}/image.png. Renders are kept in
+// cache, when non-nil, keyed by the board and dataset version.
+func NewImageHandler(loader services.UnitsSource, renderer *preview.Renderer, siteName string, cache *imagecache.Cache) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		state, err := models.DecodeBoardState(r.PathValue("code"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrInvalidBoardCode) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		unitsData, err := loader.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("Error loading units: %v", err)
+			builder.WriteUnitsError(w, err)
+			return
+		}
+
+		key := imagecache.Key("preview", state.Encode(), unitsData.Version, siteName)
+		img, ok := cache.Get(key)
+		if !ok {
+			img, err = drawBoard(unitsData, renderer, state, siteName)
+			if err != nil {
+				logger.Printf("Render error: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if err := cache.Put(key, img); err != nil {
+				logger.Printf("Caching preview: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "public, max-age="+imageCacheSeconds)
+		_, _ = w.Write(img)
+	}
+}
+
+// drawBoard places state on a board resolved against unitsData and renders it.
+func drawBoard(unitsData *models.UnitsData, renderer *preview.Renderer, state models.BoardState, siteName string) ([]byte, error) {
+	board := models.NewBoardView(models.BoardRows, models.BoardCols)
+	board.Place(state, unitsData.Units)
+	board.ResolveItems(unitsData.Items)
+	return renderer.RenderPNG(board, siteName)
+}
@@ -0,0 +1,33 @@
+package share
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"sft/internal/store"
+)
+
+// NewShortLinkHandler serves GET /c/{code} by redirecting to the builder
+// with the linked board pre-filled.
+func NewShortLinkHandler(links store.ShortLinkStore) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		link, err := links.GetShortLink(r.Context(), r.PathValue("code"))
+		if errors.Is(err, store.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			logger.Printf("short link %q: %v", r.PathValue("code"), err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		// Codes never change what they point to, so the redirect can be cached.
+		w.Header().Set("Cache-Control", "public, max-age="+imageCacheSeconds)
+		http.Redirect(w, r, "/?b="+url.QueryEscape(link.Board), http.StatusFound)
+	}
+}
@@ -0,0 +1,173 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"sft/internal/features/builder"
+	"sft/internal/models"
+	"sft/internal/preview"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+// renderRetryAfter is how long clients wait before asking for a queued image again.
+const renderRetryAfter = "2"
+
+// failedRenderRetry is how long a failed render is reported before a
+// request queues it again, e.g. once a worker has reloaded the data.
+const failedRenderRetry = time.Minute
+
+// NewQueuedImageHandler serves GET /comps/{code}/image.png from renders
+// produced by worker processes, keyed by board, dataset version and site.
+// Missing images are queued and answered with 503 and Retry-After, so the
+// web process never composes PNGs itself.
+func NewQueuedImageHandler(queue store.RenderQueue, loader services.UnitsSource, siteName string) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		state, err := models.DecodeBoardState(r.PathValue("code"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		unitsData, err := loader.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("Error loading units: %v", err)
+			builder.WriteUnitsError(w, err)
+			return
+		}
+		key := store.RenderKey{Code: state.Encode(), Version: unitsData.Version, Site: siteName}
+
+		job, err := queue.GetRender(r.Context(), key)
+		retry := err == nil && job.Status == store.RenderFailed && time.Since(job.UpdatedAt) >= failedRenderRetry
+		switch {
+		case errors.Is(err, store.ErrNotFound) || retry:
+			if err := queue.EnqueueRender(r.Context(), key); err != nil {
+				logger.Printf("Enqueue render: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		case err != nil:
+			logger.Printf("Get render: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		case job.Status == store.RenderDone:
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Cache-Control", "public, max-age="+imageCacheSeconds)
+			_, _ = w.Write(job.Image)
+			return
+		case job.Status == store.RenderFailed:
+			w.Header().Set("Cache-Control", "no-store")
+			http.Error(w, "Preview unavailable", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Retry-After", renderRetryAfter)
+		http.Error(w, "Preview is being rendered", http.StatusServiceUnavailable)
+	}
+}
+
+// renderPruneInterval is how often a worker deletes old jobs.
+const renderPruneInterval = time.Hour
+
+// RenderWorker drains a RenderQueue, composing preview images out of the
+// web serving path. Several workers may share one queue. A job is drawn
+// with the site name it was queued for, and only from the dataset version
+// it names: a worker that has not loaded that version yet fails the job,
+// and the web process queues it again later.
+type RenderWorker struct {
+	Queue    store.RenderQueue
+	Units    services.UnitsSource
+	Renderer *preview.Renderer
+	Poll     time.Duration // wait between polls of an empty queue
+	Stale    time.Duration // running jobs older than this are retried
+	Keep     time.Duration // jobs not updated for this long are deleted
+	Logger   *log.Logger
+}
+
+// NewRenderWorker creates a worker with default polling intervals.
+func NewRenderWorker(queue store.RenderQueue, units services.UnitsSource, renderer *preview.Renderer) *RenderWorker {
+	return &RenderWorker{
+		Queue:    queue,
+		Units:    units,
+		Renderer: renderer,
+		Poll:     500 * time.Millisecond,
+		Stale:    2 * time.Minute,
+		Keep:     7 * 24 * time.Hour,
+		Logger:   log.Default(),
+	}
+}
+
+// Run processes jobs until ctx is cancelled, pruning old ones hourly.
+func (wk *RenderWorker) Run(ctx context.Context) error {
+	var pruned time.Time
+	for {
+		if time.Since(pruned) >= renderPruneInterval {
+			wk.prune(ctx)
+			pruned = time.Now()
+		}
+		worked, err := wk.RunOnce(ctx)
+		if err != nil {
+			wk.Logger.Printf("render worker: %v", err)
+		}
+		if worked && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wk.Poll):
+		}
+	}
+}
+
+// RunOnce claims and renders a single job. It reports whether a job was found.
+func (wk *RenderWorker) RunOnce(ctx context.Context) (bool, error) {
+	key, err := wk.Queue.ClaimRender(ctx, wk.Stale)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	img, err := wk.render(ctx, key)
+	errMsg := ""
+	if err != nil {
+		wk.Logger.Printf("render %q failed: %v", key.Code, err)
+		errMsg = err.Error()
+	}
+	return true, wk.Queue.CompleteRender(ctx, key, img, errMsg)
+}
+
+func (wk *RenderWorker) render(ctx context.Context, key store.RenderKey) ([]byte, error) {
+	state, err := models.DecodeBoardState(key.Code)
+	if err != nil {
+		return nil, err
+	}
+	unitsData, err := wk.Units.LoadUnits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load units: %w", err)
+	}
+	if unitsData.Version != key.Version {
+		return nil, fmt.Errorf("worker has dataset version %q, job needs %q", unitsData.Version, key.Version)
+	}
+	return drawBoard(unitsData, wk.Renderer, state, key.Site)
+}
+
+func (wk *RenderWorker) prune(ctx context.Context) {
+	n, err := wk.Queue.PruneRenders(ctx, time.Now().Add(-wk.Keep))
+	if err != nil {
+		wk.Logger.Printf("render worker: %v", err)
+		return
+	}
+	if n > 0 {
+		wk.Logger.Printf("render worker: pruned %d old jobs", n)
+	}
+}
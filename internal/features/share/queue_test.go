@@ -0,0 +1,80 @@
+package share
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"sft/internal/models"
+	"sft/internal/preview"
+	"sft/internal/store"
+)
+
+// swappableUnits serves whichever dataset was set last, like a loader
+// after a data reload.
+type swappableUnits struct {
+	data atomic.Pointer[models.UnitsData]
+}
+
+func (s *swappableUnits) LoadUnits(context.Context) (*models.UnitsData, error) {
+	return s.data.Load(), nil
+}
+
+func TestQueuedImageHandler_RenderedByWorker(t *testing.T) {
+	db, err := store.OpenSQLite(filepath.Join(t.TempDir(), "renders.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	units := &swappableUnits{}
+	units.data.Store(&models.UnitsData{Version: "v1", Units: []models.Unit{{Name: "Ahri", Slug: "ahri", Cost: 3}}})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /comps/{code}/image.png", NewQueuedImageHandler(db, units, "Test"))
+	get := func(code string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/comps/"+code+"/image.png", nil))
+		return rec
+	}
+
+	if rec := get("bogus"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid code: expected 400, got %d", rec.Code)
+	}
+	rec := get("1~001ahri")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("first request: expected 503 with Retry-After, got %d", rec.Code)
+	}
+
+	worker := NewRenderWorker(db, units, preview.NewRenderer(t.TempDir()))
+	if worked, err := worker.RunOnce(context.Background()); !worked || err != nil {
+		t.Fatalf("RunOnce = %v, %v", worked, err)
+	}
+	if worked, _ := worker.RunOnce(context.Background()); worked {
+		t.Error("queue should be drained")
+	}
+
+	rec = get("1~001ahri")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || rec.Body.Len() == 0 {
+		t.Errorf("after render: expected PNG, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	// After a data reload the old image is not served; a new job is queued.
+	units.data.Store(&models.UnitsData{Version: "v2", Units: units.data.Load().Units})
+	if rec := get("1~001ahri"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("after reload: expected 503, got %d", rec.Code)
+	}
+
+	// A worker still on the old data fails the job instead of drawing it.
+	stale := &swappableUnits{}
+	stale.data.Store(&models.UnitsData{Version: "v1"})
+	worker.Units = stale
+	if worked, err := worker.RunOnce(context.Background()); !worked || err != nil {
+		t.Fatalf("RunOnce on old data = %v, %v", worked, err)
+	}
+	if rec := get("1~001ahri"); rec.Code != http.StatusInternalServerError || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("failed render: expected uncached 500, got %d %q", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+}
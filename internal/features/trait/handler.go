@@ -0,0 +1,77 @@
+// Package trait renders the per-trait detail pages.
+package trait
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+
+	"sft/internal/features/builder"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+// NewHandler renders GET /traits/{slug}.
+func NewHandler(loader services.UnitsSource, templates builder.Templates, page builder.PageOptions) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		unitsData, err := loader.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("Error loading units: %v", err)
+			builder.WriteUnitsError(w, err)
+			return
+		}
+
+		slug := r.PathValue("slug")
+		var trait *models.TraitInfo
+		for i := range unitsData.Traits {
+			if unitsData.Traits[i].Slug == slug {
+				trait = &unitsData.Traits[i]
+				break
+			}
+		}
+		if trait == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		members := make(map[string]bool, len(trait.Units))
+		for _, s := range trait.Units {
+			members[s] = true
+		}
+		var units []models.Unit
+		for _, u := range unitsData.Units {
+			if members[u.Slug] {
+				units = append(units, u)
+			}
+		}
+
+		chrome := page.Chrome(r)
+		chrome.Path = "traits/" + trait.Slug
+		chrome.Description = services.TraitMetaDescription(*trait, units)
+		w.Header().Set("Cache-Control", "no-cache")
+		if builder.NotModified(w, r, page.ETag(chrome, unitsData.Revision(), chrome.Path)) {
+			return
+		}
+
+		data := struct {
+			builder.Chrome
+			Trait models.TraitInfo
+			Units []models.Unit
+		}{
+			Chrome: chrome,
+			Trait:  *trait,
+			Units:  units,
+		}
+
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "trait.gohtml", data); err != nil {
+			logger.Printf("Template error: %v", err)
+			page.RenderError(w, "trait.gohtml", data, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
@@ -0,0 +1,57 @@
+// Package unit renders the per-champion detail pages.
+package unit
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+
+	"sft/internal/features/builder"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+// NewHandler renders GET /units/{slug}.
+func NewHandler(loader services.UnitsSource, templates builder.Templates, page builder.PageOptions) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		unitsData, err := loader.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("Error loading units: %v", err)
+			builder.WriteUnitsError(w, err)
+			return
+		}
+
+		unit, err := services.FindUnit(unitsData, r.PathValue("slug"))
+		if err != nil {
+			builder.WriteUnitsError(w, err)
+			return
+		}
+
+		chrome := page.Chrome(r)
+		chrome.Path = "units/" + unit.Slug
+		chrome.Description = services.UnitMetaDescription(*unit)
+		w.Header().Set("Cache-Control", "no-cache")
+		if builder.NotModified(w, r, page.ETag(chrome, unitsData.Revision(), chrome.Path)) {
+			return
+		}
+
+		data := struct {
+			builder.Chrome
+			Unit models.Unit
+		}{
+			Chrome: chrome,
+			Unit:   *unit,
+		}
+
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "unit.gohtml", data); err != nil {
+			logger.Printf("Template error: %v", err)
+			page.RenderError(w, "unit.gohtml", data, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sft/internal/features/builder"
+	"sft/internal/models"
+	"sft/internal/services"
+)
+
+// fragmentTTL bounds how long a rendered tooltip is kept. The ETag key
+// changes with the data and templates, so this only frees memory.
+const fragmentTTL = time.Hour
+
+// NewTooltipHandler renders GET /units/{slug}/tooltip, the unit tooltip as
+// an HTML fragment for scripts to insert. An optional ?star=1-3 shows only
+// that star level's ability values and stats.
+func NewTooltipHandler(loader services.UnitsSource, templates builder.Templates, page builder.PageOptions) http.HandlerFunc {
+	logger := log.Default()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		star := 0
+		if v := r.URL.Query().Get("star"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > models.MaxStars {
+				http.Error(w, "star must be 1, 2 or 3", http.StatusBadRequest)
+				return
+			}
+			star = n
+		}
+
+		unitsData, err := loader.LoadUnits(r.Context())
+		if err != nil {
+			logger.Printf("Error loading units: %v", err)
+			builder.WriteUnitsError(w, err)
+			return
+		}
+		unit, err := services.FindUnit(unitsData, r.PathValue("slug"))
+		if err != nil {
+			builder.WriteUnitsError(w, err)
+			return
+		}
+
+		chrome := page.Chrome(r)
+		w.Header().Set("Cache-Control", "no-cache")
+		key := "units/" + unit.Slug + "/tooltip?star=" + strconv.Itoa(star)
+		etag := page.ETag(chrome, unitsData.Revision(), key)
+		if builder.NotModified(w, r, etag) {
+			return
+		}
+		if page.Fragments != nil {
+			if body, ok := page.Fragments.Get(r.Context(), "tooltip:"+etag); ok {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				_, _ = w.Write(body)
+				return
+			}
+		}
+
+		data := map[string]any{
+			"Unit":       *unit,
+			"StaticBase": chrome.StaticBase,
+			"Locale":     chrome.Locale,
+			"Star":       star,
+		}
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "unit-tooltip", data); err != nil {
+			logger.Printf("Template error: %v", err)
+			page.RenderError(w, "unit-tooltip", data, err)
+			return
+		}
+		if page.Fragments != nil {
+			page.Fragments.Set(r.Context(), "tooltip:"+etag, buf.Bytes(), fragmentTTL)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
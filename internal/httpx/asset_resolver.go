@@ -1,10 +1,18 @@
 package httpx
 
 import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"sft/internal/features/builder"
 )
@@ -17,10 +25,30 @@ func DefaultAssetPaths() builder.AssetPaths {
 	}
 }
 
+// DefaultManifestTTL is how long resolved paths are served before the
+// manifest's modification time is checked again.
+const DefaultManifestTTL = 2 * time.Second
+
 // ManifestAssetResolver resolves asset paths from a JSON manifest file.
+// The manifest is re-read when its modification time changes, so a
+// frontend deploy takes effect without restarting the server.
 type ManifestAssetResolver struct {
 	ManifestPath string
 	Defaults     builder.AssetPaths
+	// TTL is how long paths are reused before the manifest is stat'ed
+	// again. Zero reads the manifest once, until Reload is called.
+	TTL time.Duration
+	// Integrity adds SRI hashes: the manifest's "app.css.integrity" and
+	// "app.js.integrity" entries, else a sha384 of the file under StaticDir.
+	// Leave it off in development, where app.css is rebuilt in place.
+	Integrity bool
+	StaticDir string
+
+	mu      sync.RWMutex
+	cached  *builder.AssetPaths
+	modTime time.Time // of the manifest behind cached; zero when it was missing
+	checked time.Time // when modTime was last compared with the file
+	changed chan struct{}
 }
 
 // NewManifestAssetResolver creates a resolver with standard defaults.
@@ -28,29 +56,98 @@ func NewManifestAssetResolver(manifestPath string) *ManifestAssetResolver {
 	return &ManifestAssetResolver{
 		ManifestPath: manifestPath,
 		Defaults:     DefaultAssetPaths(),
+		TTL:          DefaultManifestTTL,
+		StaticDir:    "static",
 	}
 }
 
-// Resolve reads the manifest and returns versioned asset paths.
+// Resolve returns versioned asset paths from the manifest.
 // Falls back to defaults if the manifest is missing or invalid.
 func (r *ManifestAssetResolver) Resolve() builder.AssetPaths {
-	manifest := r.loadManifest()
-	return r.resolveFromManifest(manifest)
+	now := time.Now()
+	r.mu.RLock()
+	if r.cached != nil && (r.TTL <= 0 || now.Sub(r.checked) < r.TTL) {
+		defer r.mu.RUnlock()
+		return *r.cached
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime := r.manifestModTime()
+	if r.cached != nil && modTime.Equal(r.modTime) {
+		r.checked = now
+		return *r.cached
+	}
+
+	manifest, err := r.loadManifest()
+	if err != nil {
+		log.Printf("asset manifest unavailable: %v", err)
+	}
+	assets := r.resolveFromManifest(manifest)
+	r.update(assets, modTime, now)
+	return assets
 }
 
-func (r *ManifestAssetResolver) loadManifest() map[string]string {
+// manifestModTime returns the manifest's modification time, zero if it
+// cannot be stat'ed.
+func (r *ManifestAssetResolver) manifestModTime() time.Time {
+	info, err := os.Stat(r.ManifestPath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Reload re-reads the manifest from disk.
+// On failure the previously resolved paths are kept and the error is returned.
+func (r *ManifestAssetResolver) Reload(_ context.Context) error {
+	modTime := r.manifestModTime()
+	manifest, err := r.loadManifest()
+	if err != nil {
+		return err
+	}
+	assets := r.resolveFromManifest(manifest)
+
+	r.mu.Lock()
+	r.update(assets, modTime, time.Now())
+	r.mu.Unlock()
+	return nil
+}
+
+// Changed returns a channel that is closed the next time the resolved
+// paths change, whether through Reload or a manifest rewrite noticed by
+// Resolve.
+func (r *ManifestAssetResolver) Changed() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changed == nil {
+		r.changed = make(chan struct{})
+	}
+	return r.changed
+}
+
+// update caches assets and wakes Changed waiters if they differ from the
+// previous paths. r.mu must be held for writing.
+func (r *ManifestAssetResolver) update(assets builder.AssetPaths, modTime, checked time.Time) {
+	if r.cached != nil && *r.cached != assets && r.changed != nil {
+		close(r.changed)
+		r.changed = nil
+	}
+	r.cached, r.modTime, r.checked = &assets, modTime, checked
+}
+
+func (r *ManifestAssetResolver) loadManifest() (map[string]string, error) {
 	data, err := os.ReadFile(r.ManifestPath)
 	if err != nil {
-		log.Printf("asset manifest not found (%s): %v", r.ManifestPath, err)
-		return nil
+		return nil, fmt.Errorf("read %s: %w", r.ManifestPath, err)
 	}
 
 	var manifest map[string]string
 	if err := json.Unmarshal(data, &manifest); err != nil {
-		log.Printf("asset manifest parse error: %v", err)
-		return nil
+		return nil, fmt.Errorf("decode %s: %w", r.ManifestPath, err)
 	}
-	return manifest
+	return manifest, nil
 }
 
 func (r *ManifestAssetResolver) resolveFromManifest(manifest map[string]string) builder.AssetPaths {
@@ -67,9 +164,30 @@ func (r *ManifestAssetResolver) resolveFromManifest(manifest map[string]string)
 		assets.JS = v
 	}
 
+	if r.Integrity {
+		assets.CSSIntegrity = r.integrity(manifest["app.css.integrity"], assets.CSS)
+		assets.JSIntegrity = r.integrity(manifest["app.js.integrity"], assets.JS)
+	}
 	return assets
 }
 
+// integrity returns the manifest hash if given, else hashes the file at
+// url under StaticDir. Without either the attribute is omitted, which is
+// safer than a wrong hash that blocks the asset.
+func (r *ManifestAssetResolver) integrity(fromManifest, url string) string {
+	if v := strings.TrimSpace(fromManifest); v != "" {
+		return v
+	}
+	path, _, _ := strings.Cut(url, "?") // drop a ?v= cache buster
+	data, err := os.ReadFile(filepath.Join(r.StaticDir, filepath.FromSlash(strings.TrimPrefix(path, "/"))))
+	if err != nil {
+		log.Printf("no integrity hash for %s: %v", url, err)
+		return ""
+	}
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
 // StaticAssetResolver always returns fixed asset paths (useful for testing).
 type StaticAssetResolver struct {
 	Assets builder.AssetPaths
@@ -79,3 +197,44 @@ type StaticAssetResolver struct {
 func (r *StaticAssetResolver) Resolve() builder.AssetPaths {
 	return r.Assets
 }
+
+// themeStylesheet is the file name a static override directory can provide
+// to restyle a site on top of the shared bundle.
+const themeStylesheet = "theme.css"
+
+// OverrideAssetResolver layers per-site overrides on top of a shared resolver.
+type OverrideAssetResolver struct {
+	Base AssetResolver
+	Dir  string
+}
+
+// NewOverrideAssetResolver wraps base with the overrides found in dir.
+func NewOverrideAssetResolver(base AssetResolver, dir string) *OverrideAssetResolver {
+	return &OverrideAssetResolver{Base: base, Dir: dir}
+}
+
+// Resolve returns the shared asset paths plus the site's theme stylesheet, if present.
+func (r *OverrideAssetResolver) Resolve() builder.AssetPaths {
+	assets := r.Base.Resolve()
+	if isFile(http.Dir(r.Dir), themeStylesheet) {
+		assets.ThemeCSS = "/" + themeStylesheet
+	}
+	return assets
+}
+
+// Reload forwards to the shared resolver when it supports reloading.
+func (r *OverrideAssetResolver) Reload(ctx context.Context) error {
+	if reloader, ok := r.Base.(Reloader); ok {
+		return reloader.Reload(ctx)
+	}
+	return nil
+}
+
+// Changed forwards to the shared resolver. The channel is nil, and never
+// fires, when the shared paths cannot change.
+func (r *OverrideAssetResolver) Changed() <-chan struct{} {
+	if notifier, ok := r.Base.(builder.ChangeNotifier); ok {
+		return notifier.Changed()
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"sft/internal/cache"
+	"sft/internal/config"
+	"sft/internal/imagecache"
+	"sft/internal/services"
+	"sft/internal/store"
+)
+
+// Hook is a service's part in the process lifecycle. Any function may be nil.
+type Hook struct {
+	Name string
+	// Start runs once before serving, in registration order.
+	Start func(ctx context.Context) error
+	// Stop runs at shutdown in reverse registration order.
+	Stop func(ctx context.Context) error
+	// Health reports whether the service can currently do its job.
+	Health func(ctx context.Context) error
+}
+
+// Container builds the production services on first use and runs their
+// lifecycle hooks. Services register their hooks when they are built, so a
+// process that never touches the database neither opens nor closes it.
+type Container struct {
+	cfg config.Config
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started bool
+
+	icons    func()
+	sprite   func()
+	database func() (*store.SQLiteStore, error)
+	postgres func() (*store.PostgresStore, error)
+	units    func() *services.LocalUnitsLoader
+	planner  func() services.TeamPlannerCodes
+	cache    func() (cache.Cache, error)
+	images   func() *imagecache.Cache
+
+	refresher *services.DataRefresher // set by units when DataRefresh is on
+	process   *Process                // set by Process.NewContainer
+
+	sitesMu sync.Mutex
+	sites   map[string]siteUnits // tenant datasets other than the default, by set data path
+}
+
+// siteUnits is the loader of a tenant site's own dataset.
+type siteUnits struct {
+	loader    *services.LocalUnitsLoader
+	refresher *services.DataRefresher // nil unless DataRefresh is on
+}
+
+// NewContainer creates a container for cfg. Nothing is built until asked for.
+func NewContainer(cfg config.Config) *Container {
+	c := &Container{cfg: cfg}
+	c.icons = sync.OnceFunc(c.loadScalingIcons)
+	c.sprite = sync.OnceFunc(c.buildSprite)
+	c.database = sync.OnceValues(c.openDatabase)
+	c.postgres = sync.OnceValues(c.openPostgres)
+	c.units = sync.OnceValue(c.buildUnits)
+	c.planner = sync.OnceValue(c.loadPlanner)
+	c.cache = sync.OnceValues(c.buildCache)
+	c.images = sync.OnceValue(c.openImages)
+	return c
+}
+
+// Register adds h to the lifecycle. A hook registered after Start has its
+// Start run immediately.
+func (c *Container) Register(ctx context.Context, h Hook) error {
+	c.mu.Lock()
+	c.hooks = append(c.hooks, h)
+	started := c.started
+	c.mu.Unlock()
+
+	if started && h.Start != nil {
+		if err := h.Start(ctx); err != nil {
+			return fmt.Errorf("start %s: %w", h.Name, err)
+		}
+	}
+	return nil
+}
+
+// Start runs every Start hook in order, stopping at the first failure.
+func (c *Container) Start(ctx context.Context) error {
+	c.mu.Lock()
+	c.started = true
+	hooks := append([]Hook(nil), c.hooks...)
+	c.mu.Unlock()
+
+	for _, h := range hooks {
+		if h.Start == nil {
+			continue
+		}
+		if err := h.Start(ctx); err != nil {
+			return fmt.Errorf("start %s: %w", h.Name, err)
+		}
+	}
+	return nil
+}
+
+// Stop runs every Stop hook, last registered first, and returns the
+// failures joined.
+func (c *Container) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	hooks := append([]Hook(nil), c.hooks...)
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if h := hooks[i]; h.Stop != nil {
+			if err := h.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Health runs every Health hook and returns the result per service name,
+// nil meaning healthy.
+func (c *Container) Health(ctx context.Context) map[string]error {
+	c.mu.Lock()
+	hooks := append([]Hook(nil), c.hooks...)
+	c.mu.Unlock()
+
+	results := make(map[string]error, len(hooks))
+	for _, h := range hooks {
+		if h.Health != nil {
+			results[h.Name] = h.Health(ctx)
+		}
+	}
+	return results
+}
+
+// Deps returns the router dependencies, building the services cfg enables.
+func (c *Container) Deps() (Deps, error) {
+	c.icons()
+	c.sprite()
+	shared, err := c.cache()
+	if err != nil {
+		return Deps{}, err
+	}
+	templates := NewFileTemplateLoader()
+	templates.OverrideDir = c.cfg.TemplatesDir
+	deps := Deps{
+		Templates: templates,
+		Units:     c.unitsSource(shared),
+		Assets:    c.manifestAssets(),
+		Cache:     shared,
+		Images:    c.images(),
+		Health:    c,
+	}
+	if c.process != nil {
+		deps.Hub = c.process.hub
+	}
+	if c.refresher != nil {
+		deps.Refresh = c.refresher
+	}
+	if c.cfg.StaticOverride != "" {
+		deps.Assets = NewOverrideAssetResolver(deps.Assets, c.cfg.StaticOverride)
+	}
+
+	if c.cfg.DatabaseURL != "" {
+		db, err := c.postgres()
+		if err != nil {
+			return Deps{}, err
+		}
+		deps.Comps = db
+		deps.CompVotes = db
+		deps.Users = db
+		deps.Favorites = db
+		deps.Lobbies = db
+		deps.Drills = db
+		deps.Links = db
+		deps.Renders = db
+		deps.Redirects = db
+		deps.Changelog = db
+		deps.SessionData = db
+	} else if c.cfg.DatabasePath != "" {
+		db, err := c.database()
+		if err != nil {
+			return Deps{}, err
+		}
+		deps.Comps = db
+		deps.CompVotes = db
+		deps.Users = db
+		deps.Favorites = db
+		deps.Lobbies = db
+		deps.Drills = db
+		deps.Links = db
+		deps.Renders = db
+		deps.Redirects = db
+		deps.Changelog = db
+		deps.SessionData = db
+	} else if c.process != nil {
+		deps.SessionData = c.process.sessionData
+	} else {
+		deps.SessionData = store.NewMemorySessionData()
+	}
+
+	if codes := c.planner(); codes != nil {
+		deps.Planner = codes
+	}
+	return deps, nil
+}
+
+// manifestAssets resolves the bundle from the build manifest, with SRI
+// hashes when enabled outside development.
+func (c *Container) manifestAssets() *ManifestAssetResolver {
+	assets := NewManifestAssetResolver("static/dist/manifest.json")
+	assets.Integrity = c.cfg.AssetIntegrity && !c.cfg.Dev
+	return assets
+}
+
+// loadScalingIcons applies the configured scaling icons, keeping the
+// built-in ones when the file cannot be used.
+func (c *Container) loadScalingIcons() {
+	if c.cfg.ScalingIconsPath == "" {
+		return
+	}
+	if err := services.LoadScalingIcons(c.cfg.ScalingIconsPath); err != nil {
+		log.Printf("Using built-in scaling icons: %v", err)
+	}
+}
+
+// buildSprite combines the trait and stat icons into the page sprite. On
+// failure pages draw each icon from its own file.
+func (c *Container) buildSprite() {
+	sprite, err := services.BuildSprite(
+		services.SpriteDir{Dir: c.cfg.TraitAssetsDir, Prefix: "trait"},
+		services.SpriteDir{Dir: services.DefaultStatIconDir, Prefix: "stat"},
+	)
+	if err != nil {
+		log.Printf("Icon sprite disabled: %v", err)
+		return
+	}
+	services.SetIconSprite(sprite)
+}
+
+func (c *Container) openDatabase() (*store.SQLiteStore, error) {
+	db, err := store.OpenSQLite(c.cfg.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Register(context.Background(), Hook{
+		Name:   "database",
+		Stop:   func(context.Context) error { return db.Close() },
+		Health: db.Ping,
+	})
+	return db, nil
+}
+
+func (c *Container) openPostgres() (*store.PostgresStore, error) {
+	db, err := store.OpenPostgres(c.cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Register(context.Background(), Hook{
+		Name:   "database",
+		Stop:   func(context.Context) error { return db.Close() },
+		Health: db.Ping,
+	})
+	return db, nil
+}
+
+// openImages returns the preview image cache, shared with the containers
+// of later reloads when the process keeps one.
+func (c *Container) openImages() *imagecache.Cache {
+	if c.process != nil {
+		return c.process.imageCache(c.cfg)
+	}
+	return openImageCache(c.cfg)
+}
+
+// openImageCache opens the preview image cache, or returns nil when it is
+// not configured or cannot be opened.
+func openImageCache(cfg config.Config) *imagecache.Cache {
+	if cfg.ImageCacheDir == "" {
+		return nil
+	}
+	images, err := imagecache.Open(cfg.ImageCacheDir, cfg.ImageCacheMB<<20)
+	if err != nil {
+		log.Printf("Preview image cache disabled: %v", err)
+		return nil
+	}
+	return images
+}
+
+func (c *Container) buildUnits() *services.LocalUnitsLoader {
+	units, refresher := c.newUnits(c.cfg, "")
+	c.refresher = refresher
+	return units
+}
+
+// SiteUnits returns the units of a tenant site whose set data differs from
+// the default, and its refresh status when DataRefresh is on. Sites on the
+// same set data share one loader, which is watched, refreshed and stopped
+// with the container like the default one.
+func (c *Container) SiteUnits(siteCfg config.Config) (UnitsLoader, RefreshStatus) {
+	c.sitesMu.Lock()
+	defer c.sitesMu.Unlock()
+	site, ok := c.sites[siteCfg.SetDataPath]
+	if !ok {
+		site.loader, site.refresher = c.newUnits(siteCfg, " "+siteCfg.SetDataPath)
+		if c.sites == nil {
+			c.sites = make(map[string]siteUnits)
+		}
+		c.sites[siteCfg.SetDataPath] = site
+	}
+	if site.refresher == nil {
+		return site.loader, nil
+	}
+	return site.loader, site.refresher
+}
+
+// newUnits builds a units loader for cfg and registers its health check,
+// asset watcher and data refresher, suffixing the hook names with suffix.
+func (c *Container) newUnits(cfg config.Config, suffix string) (*services.LocalUnitsLoader, *services.DataRefresher) {
+	units := NewUnitsLoader(cfg)
+	_ = c.Register(context.Background(), Hook{
+		Name: "units" + suffix,
+		Health: func(ctx context.Context) error {
+			_, err := units.LoadUnits(ctx)
+			return err
+		},
+	})
+	if cfg.AssetWatch > 0 {
+		_ = c.Register(context.Background(), runHook("asset-watcher"+suffix, services.NewAssetWatcher(units, cfg.AssetWatch).Run))
+	}
+	var refresher *services.DataRefresher
+	if cfg.DataRefresh > 0 {
+		fetch := services.CDragonFetch{Set: cfg.RefreshSet, Client: NewHTTPClient(cfg)}
+		refresher = services.NewDataRefresher(units, fetch, cfg.DataRefresh)
+		_ = c.Register(context.Background(), runHook("data-refresher"+suffix, refresher.Run))
+	}
+	return units, refresher
+}
+
+// unitsSource is where pages get their units: the local files, or with a
+// bucket configured, the bucket falling back to the local files when it
+// cannot be read. The local-only features (reload events, data diffs and
+// version history) are off in the bucket setup.
+func (c *Container) unitsSource(shared cache.Cache) services.UnitsSource {
+	local := c.units()
+	if c.cfg.BucketName == "" {
+		return local
+	}
+	bucket := services.NewSharedUnitsSource(NewBucketUnitsSource(c.cfg), shared, c.cfg.BucketTTL)
+	return services.FallbackUnitsSource(services.NewCachedUnitsSource(bucket, c.cfg.BucketTTL), local)
+}
+
+// buildCache connects the cache instances share, or keeps one in memory
+// when no Redis server is configured.
+func (c *Container) buildCache() (cache.Cache, error) {
+	if c.cfg.RedisURL == "" {
+		return cache.NewMemory(c.cfg.CacheEntries), nil
+	}
+	redis, err := cache.NewRedis(c.cfg.RedisURL, "sft:")
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Register(context.Background(), Hook{
+		Name:   "redis",
+		Stop:   func(context.Context) error { return redis.Close() },
+		Health: redis.Ping,
+	})
+	return redis, nil
+}
+
+// runHook runs a background loop from Start until Stop.
+func runHook(name string, run func(ctx context.Context)) Hook {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	return Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			var ctx context.Context
+			// The loop outlives the start context, which may be short.
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			if cancel == nil {
+				return nil
+			}
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func (c *Container) loadPlanner() services.TeamPlannerCodes {
+	if c.cfg.PlannerPath == "" {
+		return nil
+	}
+	codes, err := services.LoadTeamPlannerCodes(c.cfg.PlannerPath, c.cfg.PlannerSet)
+	if err != nil {
+		log.Printf("Team planner import disabled: %v; `sft fetch` downloads the mapping", err)
+		return nil
+	}
+	return codes
+}
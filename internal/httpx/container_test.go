@@ -0,0 +1,167 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sft/internal/config"
+)
+
+func TestContainer_Lifecycle(t *testing.T) {
+	c := NewContainer(config.Config{})
+	ctx := context.Background()
+
+	var calls []string
+	hook := func(name string, stopErr error) Hook {
+		return Hook{
+			Name:   name,
+			Start:  func(context.Context) error { calls = append(calls, "start "+name); return nil },
+			Stop:   func(context.Context) error { calls = append(calls, "stop "+name); return stopErr },
+			Health: func(context.Context) error { return stopErr },
+		}
+	}
+	_ = c.Register(ctx, hook("a", nil))
+	_ = c.Register(ctx, hook("b", errors.New("stuck")))
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Register(ctx, hook("late", nil))
+
+	err := c.Stop(ctx)
+	want := []string{"start a", "start b", "start late", "stop late", "stop b", "stop a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if err == nil || err.Error() != "stop b: stuck" {
+		t.Errorf("Stop() = %v, want the b failure", err)
+	}
+
+	health := c.Health(ctx)
+	if health["a"] != nil || health["b"] == nil {
+		t.Errorf("Health() = %v", health)
+	}
+}
+
+func TestContainer_LazyDatabase(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default()
+	cfg.PlannerPath = ""
+	cfg.DatabasePath = filepath.Join(t.TempDir(), "sft.db")
+
+	c := NewContainer(cfg)
+	if _, ok := c.Health(ctx)["database"]; ok {
+		t.Fatal("database registered before Deps was called")
+	}
+
+	deps, err := c.Deps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deps.Comps == nil || deps.Health == nil {
+		t.Fatal("expected database-backed stores and a health reporter")
+	}
+	if err, ok := c.Health(ctx)["database"]; !ok || err != nil {
+		t.Errorf("database health = %v, registered %v", err, ok)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Health(ctx)["database"]; err == nil {
+		t.Error("database still healthy after Stop")
+	}
+}
+
+func TestContainer_SiteUnits(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Config{AssetWatch: time.Hour}
+	c := NewContainer(cfg)
+
+	pbe := cfg
+	pbe.SetDataPath = filepath.Join(t.TempDir(), "pbe.json")
+	first, refresh := c.SiteUnits(pbe)
+	if again, _ := c.SiteUnits(pbe); again != first || refresh != nil {
+		t.Errorf("sites on one dataset should share a loader without a refresher, got %p and %p, %v", first, again, refresh)
+	}
+	health := c.Health(ctx)
+	if err, ok := health["units "+pbe.SetDataPath]; !ok || err == nil {
+		t.Errorf("site units health = %v, registered %v; want the missing file reported", err, ok)
+	}
+
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Errorf("site asset watcher did not stop: %v", err)
+	}
+}
+
+type fakeHealth map[string]error
+
+func (f fakeHealth) Health(context.Context) map[string]error { return f }
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		reporter HealthReporter
+		status   int
+	}{
+		{"no reporter", nil, http.StatusOK},
+		{"healthy", fakeHealth{"units": nil}, http.StatusOK},
+		{"failing", fakeHealth{"units": nil, "database": errors.New("closed")}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			healthHandler(tt.reporter)(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestProcess_StateOutlivesContainers(t *testing.T) {
+	p := NewProcess()
+	first, second := p.Config(config.Config{}), p.Config(config.Config{})
+	if first.LobbySecret == "" || first.SessionSecret == "" || first.LobbySecret == first.SessionSecret {
+		t.Fatalf("expected distinct fallback keys, got %+v", first)
+	}
+	if first.LobbySecret != second.LobbySecret || first.SessionSecret != second.SessionSecret {
+		t.Error("fallback keys changed between configs")
+	}
+	if cfg := p.Config(config.Config{LobbySecret: "set"}); cfg.LobbySecret != "set" {
+		t.Errorf("configured secret replaced: %q", cfg.LobbySecret)
+	}
+	if first.VoterSecret == "" || first.VoterSecret == first.SessionSecret || first.VoterSecret != second.VoterSecret {
+		t.Errorf("voter key should be derived from, not equal to, the session key: %+v", first)
+	}
+	if cfg := p.Config(config.Config{VoterSecret: "votes"}); cfg.VoterSecret != "votes" {
+		t.Errorf("configured voter secret replaced: %q", cfg.VoterSecret)
+	}
+
+	images := t.TempDir()
+	first.ImageCacheDir, second.ImageCacheDir = images, images
+	a, err := p.NewContainer(first).Deps()
+	if err != nil {
+		t.Fatalf("deps: %v", err)
+	}
+	b, err := p.NewContainer(second).Deps()
+	if err != nil {
+		t.Fatalf("deps: %v", err)
+	}
+	if a.Hub == nil || a.Hub != b.Hub {
+		t.Error("containers do not share the hub")
+	}
+	if a.SessionData != b.SessionData {
+		t.Error("containers do not share the in-memory session data")
+	}
+	if a.Images == nil || a.Images != b.Images {
+		t.Error("containers do not share the image cache")
+	}
+}
@@ -3,15 +3,20 @@ package httpx
 
 import (
 	"context"
-	"html/template"
 
+	"sft/internal/cache"
 	"sft/internal/features/builder"
+	"sft/internal/imagecache"
+	"sft/internal/middleware"
 	"sft/internal/models"
+	"sft/internal/realtime"
+	"sft/internal/services"
+	"sft/internal/store"
 )
 
 // TemplateLoader loads and parses HTML templates.
 type TemplateLoader interface {
-	Load() (*template.Template, error)
+	Load() (*builder.Layouts, error)
 }
 
 // UnitsLoader provides access to unit data.
@@ -24,10 +29,49 @@ type AssetResolver interface {
 	Resolve() builder.AssetPaths
 }
 
+// HealthReporter reports per-service health; nil errors mean healthy.
+type HealthReporter interface {
+	Health(ctx context.Context) map[string]error
+}
+
+// Reloader is implemented by dependencies that can refresh cached state from disk.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// RefreshStatus reports the scheduled data refreshes.
+type RefreshStatus interface {
+	Stats() services.RefreshStats
+}
+
 // Deps holds all dependencies required by the router.
 // This enables dependency injection and easier testing.
 type Deps struct {
 	Templates TemplateLoader
 	Units     UnitsLoader
 	Assets    AssetResolver
+	Comps     store.CompStore           // optional; comps API is disabled when nil
+	CompVotes store.CompVoteStore       // optional; needs Comps, comp upvotes are disabled when nil
+	Planner   services.TeamPlannerCodes // optional; comp import is unavailable when nil
+	Lobbies   store.LobbyStore          // optional; lobby planner is disabled when nil
+	Drills    store.DrillStore          // optional; practice drills are disabled when nil
+	Links     store.ShortLinkStore      // optional; short permalinks are disabled when nil
+	Renders   store.RenderQueue         // optional; required for the queue render mode
+	Redirects store.RedirectStore       // optional; legacy URL redirects are disabled when nil
+	Changelog store.ChangelogStore      // optional; patch notes come from ChangelogPath or are disabled when nil
+	Users     store.UserStore           // optional; accounts are disabled when nil
+	Favorites store.FavoriteStore       // optional; needs Users, favorites are disabled when nil
+	Cache     cache.Cache               // optional; tooltips and API results are recomputed when nil
+	Health    HealthReporter            // optional; /healthz only reports liveness when nil
+	Refresh   RefreshStatus             // optional; set when data refreshing is on
+	Hub       *realtime.Hub             // optional; the router creates its own when nil
+	Images    *imagecache.Cache         // optional; preview images are rendered on every request when nil
+
+	// SessionData backs the cookie sessions of pages and API routes; see
+	// middleware.SessionFrom. Sessions are disabled when nil.
+	SessionData store.SessionDataStore
+
+	// Middleware adds chains per route group, e.g. rate limiting on
+	// GroupAPI, run in order inside the router-wide middleware.
+	Middleware map[RouteGroup][]middleware.Middleware
 }
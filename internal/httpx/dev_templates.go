@@ -0,0 +1,32 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+)
+
+// devTemplates re-parses the templates on every execution so template
+// edits show up on the next page load. Parsing the whole set takes a few
+// milliseconds, which is fine for one developer but not for production.
+type devTemplates struct {
+	loader TemplateLoader
+}
+
+func (d devTemplates) ExecuteTemplate(w io.Writer, name string, data any) error {
+	tmpl, err := d.loader.Load()
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+// noRevalidate drops conditional request headers. Page ETags include the
+// template hash taken at startup, so in development a browser would
+// otherwise keep getting 304s for pages whose templates were edited.
+func noRevalidate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del("If-None-Match")
+		r.Header.Del("If-Modified-Since")
+		next.ServeHTTP(w, r)
+	})
+}
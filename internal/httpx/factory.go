@@ -1,20 +1,87 @@
 package httpx
 
 import (
+	"net/http"
+	"strings"
+
 	"sft/internal/config"
+	"sft/internal/i18n"
 	"sft/internal/services"
 )
 
 // NewDefaultDeps creates the standard production dependencies from config.
-func NewDefaultDeps(cfg config.Config) Deps {
-	return Deps{
-		Templates: NewFileTemplateLoader(),
-		Units: services.NewUnitsLoader(services.LoadUnitsConfig{
-			SetDataPath: cfg.SetDataPath,
-			TraitDir:    cfg.TraitAssetsDir,
-			UnitDir:     cfg.UnitAssetsDir,
-			SpellDir:    cfg.SpellAssetsDir,
-		}),
-		Assets: NewManifestAssetResolver("static/dist/manifest.json"),
+// Callers that need to shut them down should use a Container instead.
+func NewDefaultDeps(cfg config.Config) (Deps, error) {
+	return NewContainer(cfg).Deps()
+}
+
+// NewUnitsLoader creates the file-based units loader for cfg.
+func NewUnitsLoader(cfg config.Config) *services.LocalUnitsLoader {
+	return services.NewUnitsLoader(services.LoadUnitsConfig{
+		SetDataPath: cfg.SetDataPath,
+		TraitDir:    cfg.TraitAssetsDir,
+		UnitDir:     cfg.UnitAssetsDir,
+		SpellDir:    cfg.SpellAssetsDir,
+		ItemsPath:   cfg.ItemsDataPath,
+		ItemDir:     cfg.ItemAssetsDir,
+		ShopPath:    cfg.ShopOddsPath,
+
+		RecommendedItemsPath: cfg.RecommendedItemsPath,
+		PreviousSetDataPath:  cfg.PrevSetPath,
+		HistoryDir:           cfg.HistoryDir,
+		Locales:              translatedLocales(),
+	})
+}
+
+// NewBucketUnitsSource creates the units source reading cfg's bucket. Set
+// data and assets keep their local layout there, with the asset
+// directories relative to static/: the trait icons of
+// static/assets/Traits/SET16 are listed from assets/Traits/SET16/.
+func NewBucketUnitsSource(cfg config.Config) *services.BucketUnitsSource {
+	bucket := &services.Bucket{
+		Endpoint:  cfg.BucketEndpoint,
+		Name:      cfg.BucketName,
+		Region:    cfg.BucketRegion,
+		AccessKey: cfg.BucketKey,
+		SecretKey: cfg.BucketSecret,
+		Client:    NewHTTPClient(cfg),
+	}
+	return services.NewBucketUnitsSource(bucket, services.BucketUnitsConfig{
+		SetDataKey:   cfg.SetDataPath,
+		TraitPrefix:  bucketPrefix(cfg.TraitAssetsDir),
+		UnitPrefix:   bucketPrefix(cfg.UnitAssetsDir),
+		SpellPrefix:  bucketPrefix(cfg.SpellAssetsDir),
+		AssetBaseURL: cfg.BucketAssetURL,
+	})
+}
+
+// bucketPrefix turns a local asset directory into its bucket key prefix.
+func bucketPrefix(dir string) string {
+	dir = strings.Trim(strings.TrimPrefix(dir, "static/"), "/")
+	if dir == "" {
+		return ""
 	}
+	return dir + "/"
+}
+
+// translatedLocales lists the UI locales whose set data may be translated.
+func translatedLocales() []string {
+	var locales []string
+	for _, l := range i18n.Default().Locales() {
+		if l != i18n.Fallback {
+			locales = append(locales, l)
+		}
+	}
+	return locales
+}
+
+// NewHTTPClient creates the client outbound fetchers share, following the
+// timeout, retry and per-host limits of cfg.
+func NewHTTPClient(cfg config.Config) *http.Client {
+	return services.NewHTTPClient(services.HTTPClientOptions{
+		Timeout: cfg.HTTPTimeout,
+		Retries: cfg.HTTPRetries,
+		Backoff: cfg.HTTPBackoff,
+		PerHost: cfg.HTTPPerHost,
+	})
 }
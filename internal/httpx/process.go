@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"sync"
+
+	"sft/internal/config"
+	"sft/internal/imagecache"
+	"sft/internal/realtime"
+	"sft/internal/store"
+)
+
+// Process holds the state that must outlive the router and container a
+// config reload replaces: live co-edit rooms, in-memory session data,
+// the preview image cache and the keys standing in for unset secrets.
+// Regenerating those keys would break lobby links and session cookies on
+// every reload.
+type Process struct {
+	hub         *realtime.Hub
+	sessionData *store.MemorySessionData
+	lobbyKey    string
+	sessionKey  string
+
+	imagesMu  sync.Mutex
+	images    *imagecache.Cache
+	imagesDir string // ImageCacheDir and ImageCacheMB images was opened with
+	imagesMB  int64
+}
+
+// NewProcess creates the per-process state, generating the fallback keys.
+func NewProcess() *Process {
+	return &Process{
+		hub:         realtime.NewHub(),
+		sessionData: store.NewMemorySessionData(),
+		lobbyKey:    randomKey(),
+		sessionKey:  randomKey(),
+	}
+}
+
+// Config returns cfg with its unset signing secrets filled in with the
+// process's fallback keys. An unset VoterSecret is derived from the
+// session secret rather than reusing it, so neither key can stand in for
+// the other.
+func (p *Process) Config(cfg config.Config) config.Config {
+	if cfg.LobbySecret == "" {
+		cfg.LobbySecret = p.lobbyKey
+	}
+	if cfg.SessionSecret == "" {
+		cfg.SessionSecret = p.sessionKey
+	}
+	if cfg.VoterSecret == "" {
+		cfg.VoterSecret = deriveKey(cfg.SessionSecret, "comp-votes")
+	}
+	return cfg
+}
+
+// NewContainer creates a container for cfg that shares the process's
+// hub and in-memory session data. Pass it cfg from Config.
+func (p *Process) NewContainer(cfg config.Config) *Container {
+	c := NewContainer(cfg)
+	c.process = p
+	return c
+}
+
+// imageCache returns the preview image cache for cfg, opening it only on
+// first use or when a reload changed its directory or size.
+func (p *Process) imageCache(cfg config.Config) *imagecache.Cache {
+	p.imagesMu.Lock()
+	defer p.imagesMu.Unlock()
+	if cfg.ImageCacheDir != p.imagesDir || cfg.ImageCacheMB != p.imagesMB {
+		p.images = openImageCache(cfg)
+		p.imagesDir, p.imagesMB = cfg.ImageCacheDir, cfg.ImageCacheMB
+	}
+	return p.images
+}
+
+// deriveKey returns HMAC-SHA256(secret, purpose), a key for purpose that
+// reveals nothing about secret.
+func deriveKey(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func randomKey() string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("httpx: generating fallback key: " + err.Error())
+	}
+	return base64.RawStdEncoding.EncodeToString(key)
+}
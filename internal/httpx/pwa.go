@@ -0,0 +1,196 @@
+package httpx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png" // app icon sizes
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"text/template"
+
+	_ "golang.org/x/image/webp"
+
+	"sft/internal/buildinfo"
+	"sft/internal/config"
+	"sft/internal/features/builder"
+)
+
+// Web app endpoints. The service worker is served from the root so its
+// scope covers every page.
+const (
+	webManifestPath   = "/manifest.webmanifest"
+	serviceWorkerPath = "/sw.js"
+)
+
+type webManifest struct {
+	Name            string         `json:"name"`
+	ShortName       string         `json:"short_name"`
+	StartURL        string         `json:"start_url"`
+	Scope           string         `json:"scope"`
+	Display         string         `json:"display"`
+	BackgroundColor string         `json:"background_color,omitempty"`
+	ThemeColor      string         `json:"theme_color,omitempty"`
+	Icons           []manifestIcon `json:"icons"`
+}
+
+type manifestIcon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes"`
+	Type    string `json:"type"`
+	Purpose string `json:"purpose"`
+}
+
+// buildWebManifest renders the web app manifest for cfg. Icons that cannot
+// be read are left out with a warning rather than advertised at a wrong size.
+func buildWebManifest(cfg config.Config) ([]byte, error) {
+	m := webManifest{
+		Name:            cfg.SiteName,
+		ShortName:       cfg.SiteName,
+		StartURL:        "/",
+		Scope:           "/",
+		Display:         "standalone",
+		BackgroundColor: cfg.ThemeColor,
+		ThemeColor:      cfg.ThemeColor,
+		Icons:           []manifestIcon{},
+	}
+
+	dirs := []http.Dir{http.Dir("./static")}
+	if cfg.StaticOverride != "" {
+		dirs = append([]http.Dir{http.Dir(cfg.StaticOverride)}, dirs...)
+	}
+	for _, icon := range cfg.AppIcons {
+		size, err := iconSize(dirs, icon)
+		if err != nil {
+			log.Printf("Leaving app icon %s out of the manifest: %v", icon, err)
+			continue
+		}
+		m.Icons = append(m.Icons, manifestIcon{
+			Src:     publicStaticBase(cfg) + "/" + strings.TrimLeft(icon, "/"),
+			Sizes:   fmt.Sprintf("%dx%d", size.X, size.Y),
+			Type:    mime.TypeByExtension(path.Ext(icon)),
+			Purpose: "any maskable",
+		})
+	}
+
+	body, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode web manifest: %w", err)
+	}
+	return body, nil
+}
+
+// iconSize reads the dimensions of the first dirs entry holding name.
+func iconSize(dirs []http.Dir, name string) (image.Point, error) {
+	for _, dir := range dirs {
+		if !isFile(dir, name) {
+			continue
+		}
+		f, err := dir.Open("/" + strings.TrimPrefix(name, "/"))
+		if err != nil {
+			return image.Point{}, err
+		}
+		defer f.Close()
+		cfg, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return image.Point{}, fmt.Errorf("decode %s: %w", name, err)
+		}
+		return image.Pt(cfg.Width, cfg.Height), nil
+	}
+	return image.Point{}, fmt.Errorf("%s not found", name)
+}
+
+// webManifestHandler serves the prebuilt manifest, revalidated by ETag.
+func webManifestHandler(body []byte) http.HandlerFunc {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/manifest+json")
+		w.Header().Set("Cache-Control", "no-cache")
+		if builder.NotModified(w, r, etag) {
+			return
+		}
+		_, _ = w.Write(body)
+	}
+}
+
+// serviceWorker caches the app shell so an installed builder opens
+// offline. Navigations go to the network first, falling back to the cached
+// page; the versioned bundle is served from the cache. The cache name
+// changes with the build and the bundle, dropping stale copies on update.
+var serviceWorker = template.Must(template.New("sw").Parse(`// Generated by the server; see internal/httpx/pwa.go.
+const CACHE = {{.Cache}};
+const SHELL = {{.Shell}};
+
+self.addEventListener('install', (event) => {
+  event.waitUntil(
+    caches.open(CACHE)
+      .then((cache) => cache.addAll(SHELL.map((url) => new Request(url, { mode: 'cors' }))))
+      .then(() => self.skipWaiting()),
+  );
+});
+
+self.addEventListener('activate', (event) => {
+  event.waitUntil(
+    caches.keys()
+      .then((keys) => Promise.all(keys.filter((key) => key !== CACHE).map((key) => caches.delete(key))))
+      .then(() => self.clients.claim()),
+  );
+});
+
+self.addEventListener('fetch', (event) => {
+  const { request } = event;
+  if (request.method !== 'GET') return;
+
+  if (request.mode === 'navigate') {
+    event.respondWith(
+      fetch(request).catch(() => caches.match(request).then((hit) => hit || caches.match('/'))),
+    );
+    return;
+  }
+  if (SHELL.includes(request.url) || SHELL.includes(new URL(request.url).pathname)) {
+    event.respondWith(caches.match(request).then((hit) => hit || fetch(request)));
+  }
+});
+`))
+
+// serviceWorkerHandler renders the worker for the current asset bundle.
+// Service-Worker-Allowed lets it control the whole origin.
+func serviceWorkerHandler(cfg config.Config, build buildinfo.Info, assets AssetResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		paths := assets.Resolve()
+		shell := []string{"/"}
+		for _, p := range []string{paths.CSS, paths.JS, paths.ThemeCSS} {
+			if p != "" {
+				shell = append(shell, assetURL(cfg, p))
+			}
+		}
+
+		sum := sha256.Sum256([]byte(build.String() + "\x00" + strings.Join(shell, "\x00")))
+		cache, _ := json.Marshal("sft-" + hex.EncodeToString(sum[:6]))
+		list, _ := json.Marshal(shell)
+
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Service-Worker-Allowed", "/")
+		err := serviceWorker.Execute(w, struct{ Cache, Shell string }{string(cache), string(list)})
+		if err != nil {
+			log.Printf("Service worker render failed: %v", err)
+		}
+	}
+}
+
+// assetURL is the URL pages load a bundle path from, as the "static"
+// template func builds it.
+func assetURL(cfg config.Config, p string) string {
+	if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+		return p
+	}
+	return publicStaticBase(cfg) + "/" + strings.TrimLeft(p, "/")
+}
@@ -0,0 +1,47 @@
+package httpx
+
+import (
+	"net/http"
+
+	"sft/internal/middleware"
+)
+
+// RouteGroup names a set of routes that share a middleware chain.
+type RouteGroup string
+
+// Route groups. Health, version and robots.txt belong to none, so probes
+// and crawlers reach them whatever the groups add.
+const (
+	GroupPages  RouteGroup = "pages"  // HTML pages, share links and preview images
+	GroupAPI    RouteGroup = "api"    // /api/...
+	GroupStatic RouteGroup = "static" // files under StaticBaseURL
+	GroupAdmin  RouteGroup = "admin"  // /admin/..., behind the admin token
+)
+
+// routeGroups registers handlers on a mux wrapped in their group's chain.
+// The chains run inside the router-wide middleware, after routing.
+type routeGroups struct {
+	mux    *http.ServeMux
+	chains map[RouteGroup]middleware.Middleware
+}
+
+func newRouteGroups(mux *http.ServeMux, chains map[RouteGroup][]middleware.Middleware) routeGroups {
+	g := routeGroups{mux: mux, chains: make(map[RouteGroup]middleware.Middleware, len(chains))}
+	for group, chain := range chains {
+		if len(chain) > 0 {
+			g.chains[group] = middleware.Chain(chain...)
+		}
+	}
+	return g
+}
+
+func (g routeGroups) handle(group RouteGroup, pattern string, h http.Handler) {
+	if chain, ok := g.chains[group]; ok {
+		h = chain(h)
+	}
+	g.mux.Handle(pattern, h)
+}
+
+func (g routeGroups) handleFunc(group RouteGroup, pattern string, h http.HandlerFunc) {
+	g.handle(group, pattern, h)
+}
@@ -1,19 +1,63 @@
 package httpx
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"sft/internal/assetmiss"
+	"sft/internal/auth"
+	"sft/internal/buildinfo"
 	"sft/internal/config"
+	"sft/internal/features/admin"
+	"sft/internal/features/api"
 	"sft/internal/features/builder"
+	"sft/internal/features/changelog"
+	"sft/internal/features/lobby"
+	"sft/internal/features/share"
+	"sft/internal/features/trait"
+	"sft/internal/features/unit"
+	"sft/internal/i18n"
 	"sft/internal/middleware"
+	"sft/internal/preview"
+	"sft/internal/realtime"
+	"sft/internal/redirects"
+	"sft/internal/services"
+	"sft/internal/store"
+	"sft/internal/tenant"
 )
 
 // NewRouter creates a router with default production dependencies.
+// When cfg.SitesConfig is set, each site profile gets its own router.
 // For testing or custom setups, use NewRouterWithDeps.
 func NewRouter(cfg config.Config) (http.Handler, error) {
-	return NewRouterWithDeps(cfg, NewDefaultDeps(cfg))
+	return NewRouterWithContainer(cfg, NewContainer(cfg))
+}
+
+// NewRouterWithContainer is NewRouter with services built by c, so the
+// caller can start and stop them.
+func NewRouterWithContainer(cfg config.Config, c *Container) (http.Handler, error) {
+	deps, err := c.Deps()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SitesConfig == "" {
+		return NewRouterWithDeps(cfg, deps)
+	}
+
+	profiles, err := tenant.LoadProfiles(cfg.SitesConfig)
+	if err != nil {
+		return nil, err
+	}
+	return NewTenantRouter(cfg, c, deps, profiles)
 }
 
 // NewRouterWithDeps wires the provided dependencies into an http.Handler.
@@ -25,14 +69,315 @@ func NewRouterWithDeps(cfg config.Config, deps Deps) (http.Handler, error) {
 	}
 
 	canonical := buildCanonicalURL(cfg.SiteURL)
-	assets := deps.Assets.Resolve()
+	build := buildinfo.Get()
+
+	page := builder.PageOptions{
+		SiteName:   cfg.SiteName,
+		Theme:      cfg.Theme,
+		ThemeColor: cfg.ThemeColor,
+		StaticBase: publicStaticBase(cfg),
+		Canonical:  canonical,
+		Assets:     deps.Assets,
+		Preconnect: preconnectOrigins(cfg),
+		Dev:        cfg.Dev,
+		Fragments:  deps.Cache,
+		// Hashed before any handler runs: execution rewrites the parsed trees.
+		TemplateHash: tmpl.Hash(),
+	}
+	// Only the builder caches whole pages; the other pages share page without it.
+	builderPage := page
+	builderPage.Cache = builder.NewRenderCache(cfg.RenderCache)
+	accounts := deps.Users != nil && deps.SessionData != nil
+	if accounts && deps.Favorites != nil {
+		builderPage.Favorites = userFavorites(deps.Favorites)
+	}
+
+	var pages builder.Templates = tmpl
+	if cfg.Dev {
+		pages = devTemplates{loader: deps.Templates}
+		builderPage.Cache = nil
+		page.Fragments = nil
+	}
+
+	// Only HTML pages are translated, so only they vary by language.
+	localized := i18n.Default().Middleware
 
+	builderHandler := localized(builder.NewHandler(deps.Units, pages, builderPage))
+	if err := warmBuilder(cfg, deps.Units, builderHandler); err != nil {
+		return nil, err
+	}
+
+	// Patterns name their methods, so the mux answers 405 with an Allow
+	// header for a known path and 404 for an unknown one; the builder only
+	// serves the root.
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", builder.NewHandler(deps.Units, tmpl, cfg.StaticBaseURL, canonical, assets))
-	mux.HandleFunc("/robots.txt", serveRobots)
-	mux.Handle(cfg.StaticBaseURL+"/", staticFileHandler(cfg))
+	routes := newRouteGroups(mux, groupMiddleware(cfg, deps))
+	routes.handle(GroupPages, "GET /{$}", builderHandler)
+	routes.handle(GroupPages, "GET /builder", builderHandler)
+	// Nav links to pages not built yet go to the builder until they are.
+	for _, path := range []string{"/simulator", "/statistics", "/standings"} {
+		routes.handle(GroupPages, "GET "+path, http.RedirectHandler("/", http.StatusFound))
+	}
+	routes.handle(GroupPages, "GET /units/{slug}", localized(unit.NewHandler(deps.Units, pages, page)))
+	routes.handle(GroupPages, "GET /units/{slug}/tooltip", localized(unit.NewTooltipHandler(deps.Units, pages, page)))
+	routes.handle(GroupPages, "GET /traits/{slug}", localized(trait.NewHandler(deps.Units, pages, page)))
+	if cfg.RenderMode == config.RenderQueue && deps.Renders != nil {
+		routes.handleFunc(GroupPages, "GET /comps/{code}/image.png", share.NewQueuedImageHandler(deps.Renders, deps.Units, cfg.SiteName))
+	} else {
+		routes.handleFunc(GroupPages, "GET /comps/{code}/image.png", share.NewImageHandler(deps.Units, preview.NewRenderer("."), cfg.SiteName, deps.Images))
+	}
+	mux.HandleFunc("GET /robots.txt", robotsHandler(canonical, cfg.Indexing))
+	if canonical != "" && cfg.Indexing {
+		// buildRobots advertises the sitemap under the same conditions.
+		mux.HandleFunc("GET /sitemap.xml", sitemapHandler(canonical, deps.Units))
+	}
+	mux.HandleFunc("GET /version", versionHandler(build))
+	manifest, err := buildWebManifest(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mux.HandleFunc("GET "+webManifestPath, webManifestHandler(manifest))
+	mux.HandleFunc("GET "+serviceWorkerPath, serviceWorkerHandler(cfg, build, deps.Assets))
+	mux.HandleFunc("GET /healthz", healthHandler(deps.Health))
+	if source, ok := deps.Units.(api.VersionSource); ok {
+		routes.handleFunc(GroupAPI, "GET /api/version/wait", api.NewVersionWaitHandler(source))
+		assets, _ := deps.Assets.(api.ChangeSignal)
+		routes.handleFunc(GroupAPI, "GET /api/events", api.NewReloadEventsHandler(source, assets))
+	}
+	routes.handleFunc(GroupAPI, "GET /api/ui-config", api.NewUIConfigHandler(deps.Units, api.DefaultUIConfig()))
+	routes.handleFunc(GroupAPI, "POST /api/v1/synergies", api.NewSynergiesHandler(deps.Units, deps.Cache))
+	routes.handleFunc(GroupAPI, "POST /api/v1/comps/score", api.NewCompScoreHandler(deps.Units))
+	batchBody := cfg.BatchBodyKB << 10
+	routes.handle(GroupAPI, "POST /api/v1/synergies/batch", middleware.DecompressBody(batchBody)(api.NewSynergiesBatchHandler(deps.Units, deps.Cache, batchBody)))
+	routes.handle(GroupAPI, "POST /api/v1/units/batch", middleware.DecompressBody(batchBody)(api.NewUnitsBatchHandler(deps.Units, batchBody)))
+	routes.handle(GroupAPI, "POST /api/v1/comps/score/batch", middleware.DecompressBody(batchBody)(api.NewCompScoreBatchHandler(deps.Units, batchBody)))
+	routes.handleFunc(GroupAPI, "GET /api/v1/units", api.NewUnitsHandler(deps.Units))
+	if history, ok := deps.Units.(api.VersionHistory); ok {
+		routes.handleFunc(GroupAPI, "GET /api/v1/units/versions", api.NewUnitVersionsHandler(history))
+	}
+	if delta, ok := deps.Units.(api.DeltaSource); ok {
+		routes.handleFunc(GroupAPI, "GET /api/export/delta", api.NewExportDeltaHandler(delta))
+	}
+	routes.handleFunc(GroupAPI, "GET /api/v1/shop-odds", api.NewShopOddsHandler(deps.Units))
+	routes.handleFunc(GroupAPI, "GET /api/v1/bundle", api.NewBundleHandler(deps.Units))
+	quiz := api.NewQuizAPI(deps.Units)
+	routes.handleFunc(GroupAPI, "GET /api/quiz", quiz.Question)
+	routes.handleFunc(GroupAPI, "POST /api/quiz/answer", quiz.Answer)
+	// Signs lobby links and the delete tokens of anonymous comps.
+	signer := auth.NewLinkSigner(cfg.LobbySecret)
+	if deps.Comps != nil {
+		comps := api.NewCompsAPI(deps.Comps, deps.Units).WithPlanner(deps.Planner).WithDeleteTokens(signer).WithVoterSecret(cfg.VoterSecret)
+		routes.handleFunc(GroupAPI, "POST /api/v1/comps", comps.Create)
+		routes.handleFunc(GroupAPI, "POST /api/v1/comps/import", comps.Import)
+		routes.handleFunc(GroupAPI, "GET /api/v1/comps", comps.List)
+		routes.handleFunc(GroupAPI, "GET /api/v1/comps/{id}", comps.Get)
+		routes.handleFunc(GroupAPI, "DELETE /api/v1/comps/{id}", comps.Delete)
+		if deps.CompVotes != nil {
+			comps.WithVotes(deps.CompVotes)
+			routes.handleFunc(GroupAPI, "PUT /api/v1/comps/{id}/vote", comps.Vote)
+			routes.handleFunc(GroupAPI, "DELETE /api/v1/comps/{id}/vote", comps.Unvote)
+		}
+	}
+
+	if accounts {
+		account := api.NewAccountAPI(deps.Users, auth.NewSessions(deps.Users))
+		routes.handleFunc(GroupAPI, "POST /api/v1/account/signup", account.Signup)
+		routes.handleFunc(GroupAPI, "POST /api/v1/account/login", account.Login)
+		routes.handleFunc(GroupAPI, "POST /api/v1/account/logout", account.Logout)
+		routes.handleFunc(GroupAPI, "GET /api/v1/account/me", account.Me)
+		if deps.Favorites != nil {
+			favorites := api.NewFavoritesAPI(deps.Favorites, deps.Units)
+			routes.handleFunc(GroupAPI, "GET /api/v1/favorites", favorites.List)
+			routes.handleFunc(GroupAPI, "PUT /api/v1/favorites/{unit}", favorites.Add)
+			routes.handleFunc(GroupAPI, "DELETE /api/v1/favorites/{unit}", favorites.Remove)
+		}
+	}
+	if deps.Drills != nil {
+		drills := api.NewDrillsAPI(deps.Drills, deps.Units)
+		routes.handleFunc(GroupAPI, "GET /api/v1/drills/answers", drills.History)
+		routes.handleFunc(GroupAPI, "GET /api/v1/drills/{kind}", drills.Generate)
+		routes.handleFunc(GroupAPI, "POST /api/v1/drills/{kind}/{seed}/answers", drills.Answer)
+	}
+	var patchNotes changelog.Source
+	switch {
+	case cfg.ChangelogPath != "":
+		patchNotes = changelog.NewFileSource(cfg.ChangelogPath)
+	case deps.Changelog != nil:
+		patchNotes = changelog.NewStoreSource(deps.Changelog)
+	}
+	if patchNotes != nil {
+		routes.handle(GroupPages, "GET /changelog", localized(changelog.NewPageHandler(patchNotes, pages, page)))
+		routes.handleFunc(GroupPages, "GET "+changelog.FeedPath, changelog.NewFeedHandler(patchNotes, cfg.SiteName, canonical))
+	}
+	if source, ok := deps.Units.(changelog.DiffSource); ok {
+		routes.handle(GroupPages, "GET /changelog/diff", localized(changelog.NewDiffHandler(source, pages, page)))
+	}
+	// Live co-editing: peers in the same room see each other's placements.
+	hub := deps.Hub
+	if hub == nil {
+		hub = realtime.NewHub()
+	}
+	routes.handleFunc(GroupPages, "GET /ws/board/{code}", hub.ServeBoard)
+	if deps.Links != nil {
+		routes.handleFunc(GroupPages, "GET /c/{code}", share.NewShortLinkHandler(deps.Links))
+		routes.handleFunc(GroupAPI, "POST /api/v1/links", api.NewLinksAPI(deps.Links).Create)
+	}
+	if deps.Lobbies != nil {
+		lobbyPages := lobby.NewPages(deps.Lobbies, signer, pages, page)
+		routes.handle(GroupPages, "GET /lobbies", localized(http.HandlerFunc(lobbyPages.New)))
+		routes.handle(GroupPages, "POST /lobbies", localized(http.HandlerFunc(lobbyPages.Create)))
+		routes.handle(GroupPages, "GET /lobbies/{id}", localized(http.HandlerFunc(lobbyPages.Show)))
+		routes.handle(GroupPages, "POST /lobbies/{id}/players/{slot}", localized(http.HandlerFunc(lobbyPages.UpdatePlayer)))
+
+		lobbies := api.NewLobbiesAPI(deps.Lobbies, signer)
+		routes.handleFunc(GroupAPI, "POST /api/v1/lobbies", lobbies.Create)
+		routes.handleFunc(GroupAPI, "GET /api/v1/lobbies/{id}", lobbies.Get)
+		routes.handleFunc(GroupAPI, "PUT /api/v1/lobbies/{id}/players/{slot}", lobbies.UpdatePlayer)
+	}
+	missingAssets := assetmiss.New()
+	routes.handle(GroupStatic, "GET "+cfg.StaticBaseURL+"/", missingAssets.Middleware(staticFileHandler(cfg)))
+
+	var redirectTable *redirects.Table
+	targets := reloadTargets(deps)
+	if deps.Redirects != nil || cfg.RedirectsPath != "" {
+		redirectTable = redirects.NewTable(deps.Redirects).WithFile(cfg.RedirectsPath)
+		if err := redirectTable.Reload(context.Background()); err != nil {
+			log.Printf("Redirects unavailable until reloaded: %v", err)
+		}
+		targets = append(targets, admin.Target{Name: "redirects", Reload: redirectTable.Reload})
+	}
 
-	return middleware.Gzip(mux), nil
+	iconIssues := checkIconAssets(deps.Assets)
+	if cfg.AdminToken != "" {
+		routes.handleFunc(GroupAdmin, "POST /admin/reload", admin.NewReloadHandler(cfg.AdminToken, targets))
+		routes.handleFunc(GroupAdmin, "GET /admin/report", admin.NewReportHandler(cfg.AdminToken, deps.Units, iconIssues))
+		missing := admin.NewMissingAssetsHandler(cfg.AdminToken, missingAssets)
+		routes.handleFunc(GroupAdmin, "GET /admin/missing-assets", missing)
+		routes.handleFunc(GroupAdmin, "DELETE /admin/missing-assets", missing)
+		if deps.Refresh != nil {
+			routes.handleFunc(GroupAdmin, "GET /admin/data-refresh", admin.NewRefreshStatsHandler(cfg.AdminToken, deps.Refresh.Stats))
+		}
+		if deps.Changelog != nil && cfg.ChangelogPath == "" {
+			notes := admin.NewChangelogHandler(cfg.AdminToken, deps.Changelog)
+			routes.handleFunc(GroupAdmin, "GET /admin/changelog", notes)
+			routes.handleFunc(GroupAdmin, "PUT /admin/changelog", notes)
+			routes.handleFunc(GroupAdmin, "DELETE /admin/changelog", notes)
+		}
+		if deps.Redirects != nil {
+			rules := admin.NewRedirectsHandler(cfg.AdminToken, deps.Redirects, redirectTable)
+			routes.handleFunc(GroupAdmin, "GET /admin/redirects", rules)
+			routes.handleFunc(GroupAdmin, "PUT /admin/redirects", rules)
+			routes.handleFunc(GroupAdmin, "DELETE /admin/redirects", rules)
+		}
+	}
+
+	proxies, err := middleware.ParseProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	middlewares := []middleware.Middleware{
+		middleware.RealIP(proxies),
+		buildHeader(build),
+	}
+	if cfg.Dev {
+		middlewares = append(middlewares, noRevalidate)
+	}
+	if redirectTable != nil {
+		middlewares = append(middlewares, redirectTable.Middleware)
+	}
+	middlewares = append(middlewares, middleware.Gzip)
+	return middleware.Chain(middlewares...)(mux), nil
+}
+
+// userFavorites returns the favorites of the request's logged-in user, or
+// nil for anonymous requests and lookup failures, which get the shared page.
+func userFavorites(favorites store.FavoriteStore) func(*http.Request) []string {
+	return func(r *http.Request) []string {
+		user := auth.UserFrom(r.Context())
+		if user == nil {
+			return nil
+		}
+		list, err := favorites.ListFavorites(r.Context(), user.ID)
+		if err != nil {
+			log.Printf("Favorites of user %d unavailable: %v", user.ID, err)
+			return nil
+		}
+		return list
+	}
+}
+
+// groupMiddleware returns the built-in chain of each route group followed
+// by the one deps adds.
+func groupMiddleware(cfg config.Config, deps Deps) map[RouteGroup][]middleware.Middleware {
+	chains := make(map[RouteGroup][]middleware.Middleware)
+	if deps.SessionData != nil {
+		session := middleware.Session(deps.SessionData, middleware.SessionOptions{
+			Secret: cfg.SessionSecret,
+			Secure: strings.HasPrefix(cfg.SiteURL, "https://"),
+		})
+		chains[GroupPages] = []middleware.Middleware{session}
+		chains[GroupAPI] = []middleware.Middleware{session}
+		if deps.Users != nil {
+			// Logins live in the session; see auth.Sessions.
+			login := auth.NewSessions(deps.Users).Middleware
+			chains[GroupPages] = append(chains[GroupPages], login)
+			chains[GroupAPI] = append(chains[GroupAPI], login)
+		}
+	}
+	for group, chain := range deps.Middleware {
+		chains[group] = append(chains[group], chain...)
+	}
+	return chains
+}
+
+// warmBuilder renders the builder page once per cfg.Warmup. Only strict
+// mode turns a failure into an error.
+func warmBuilder(cfg config.Config, units services.UnitsSource, h http.Handler) error {
+	if cfg.Warmup == config.WarmupOff {
+		return nil
+	}
+	took, err := builder.Warm(context.Background(), units, h)
+	switch {
+	case err == nil:
+		log.Printf("Warmup: builder page rendered in %s", took.Round(time.Millisecond))
+	case cfg.Warmup == config.WarmupStrict:
+		return fmt.Errorf("warmup: %w", err)
+	default:
+		log.Printf("WARNING: warmup failed, the first visitors may see errors: %v", err)
+	}
+	return nil
+}
+
+// reloadTargets collects the dependencies that support reloading from disk.
+func reloadTargets(deps Deps) []admin.Target {
+	var targets []admin.Target
+	if r, ok := deps.Units.(Reloader); ok {
+		targets = append(targets, admin.Target{Name: "units", Reload: r.Reload})
+	}
+	if r, ok := deps.Assets.(Reloader); ok {
+		targets = append(targets, admin.Target{Name: "assets", Reload: r.Reload})
+	}
+	return targets
+}
+
+// preconnectOrigins returns the configured hint origins plus the static
+// origin when assets are served from another host.
+func preconnectOrigins(cfg config.Config) []string {
+	origins := append([]string(nil), cfg.PreconnectOrigins...)
+	if base := publicStaticBase(cfg); strings.HasPrefix(base, "http://") || strings.HasPrefix(base, "https://") {
+		origins = append(origins, base)
+	}
+	return origins
+}
+
+// publicStaticBase is the static URL prefix pages link to. With a CDN it is
+// the CDN origin plus the path this server serves static files under, so
+// the CDN can pull misses from here unchanged.
+func publicStaticBase(cfg config.Config) string {
+	if cfg.CDNBaseURL == "" {
+		return cfg.StaticBaseURL
+	}
+	return cfg.CDNBaseURL + "/" + strings.Trim(cfg.StaticBaseURL, "/")
 }
 
 // buildCanonicalURL normalizes the site URL for use in templates.
@@ -44,16 +389,61 @@ func buildCanonicalURL(siteURL string) string {
 	return canonical
 }
 
+// checkIconAssets verifies the scaling icons against the built stylesheet
+// and logs each icon that would render invisibly.
+func checkIconAssets(assets AssetResolver) []services.IconIssue {
+	cssPath := filepath.Join("static", filepath.FromSlash(assets.Resolve().CSS))
+	css, err := os.ReadFile(cssPath)
+	if err != nil {
+		log.Printf("Skipping scaling icon check: %v", err)
+		return nil
+	}
+	issues := services.CheckScalingIcons(string(css), "static")
+	for _, issue := range issues {
+		log.Printf("Scaling icon %s (.%s): %s %s", issue.Scaling, issue.Class, issue.Problem, issue.Asset)
+	}
+	return issues
+}
+
 // staticFileHandler creates a handler for serving static files with caching.
+// Files present in cfg.StaticOverride take precedence over the shared ./static tree.
 func staticFileHandler(cfg config.Config) http.Handler {
 	fs := http.FileServer(http.Dir("./static"))
 
+	var overrideDir http.Dir
+	var override http.Handler
+	if cfg.StaticOverride != "" {
+		overrideDir = http.Dir(cfg.StaticOverride)
+		override = http.FileServer(overrideDir)
+	}
+
 	return http.StripPrefix(cfg.StaticBaseURL+"/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		setCacheHeaders(w, cfg.StaticCacheSec)
+		if cfg.CDNBaseURL != "" {
+			// Pages on the app origin load module scripts, fonts and SRI-checked
+			// files from the CDN, which needs CORS. A wildcard rather than the
+			// request's Origin keeps one cached copy per file: no Vary: Origin.
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+		if override != nil && isFile(overrideDir, r.URL.Path) {
+			override.ServeHTTP(w, r)
+			return
+		}
 		fs.ServeHTTP(w, r)
 	}))
 }
 
+// isFile reports whether name exists as a regular file inside dir.
+func isFile(dir http.Dir, name string) bool {
+	f, err := dir.Open("/" + strings.TrimPrefix(name, "/"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	return err == nil && !info.IsDir()
+}
+
 // setCacheHeaders sets appropriate cache headers based on configuration.
 func setCacheHeaders(w http.ResponseWriter, cacheSec int) {
 	if cacheSec <= 0 {
@@ -65,8 +455,113 @@ func setCacheHeaders(w http.ResponseWriter, cacheSec int) {
 	}
 }
 
-// serveRobots exposes a root-level robots.txt.
-func serveRobots(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	http.ServeFile(w, r, "static/robots.txt")
+// robotsHandler serves a generated robots.txt for the configured environment.
+func robotsHandler(canonical string, indexing bool) http.HandlerFunc {
+	body := buildRobots(canonical, indexing)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// buildRobots renders robots.txt, blocking all crawlers when indexing is disabled.
+func buildRobots(canonical string, indexing bool) string {
+	var b strings.Builder
+	b.WriteString("# robots.txt for SFT\n")
+	b.WriteString("User-agent: *\n")
+
+	if !indexing {
+		b.WriteString("Disallow: /\n")
+		return b.String()
+	}
+
+	b.WriteString("Allow: /\n")
+	if canonical != "" {
+		fmt.Fprintf(&b, "\nSitemap: %ssitemap.xml\n", canonical)
+	}
+	return b.String()
+}
+
+// sitemapURLSet is the <urlset> of a sitemap.xml.
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// sitemapHandler lists the builder and every unit and trait page under
+// canonical, from the set data served now.
+func sitemapHandler(canonical string, units UnitsLoader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := units.LoadUnits(r.Context())
+		if err != nil {
+			builder.WriteUnitsError(w, err)
+			return
+		}
+		set := sitemapURLSet{URLs: []sitemapURL{{Loc: canonical}}}
+		for _, u := range data.Units {
+			set.URLs = append(set.URLs, sitemapURL{Loc: canonical + "units/" + url.PathEscape(u.Slug)})
+		}
+		for _, t := range data.Traits {
+			set.URLs = append(set.URLs, sitemapURL{Loc: canonical + "traits/" + url.PathEscape(t.Slug)})
+		}
+
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		_, _ = w.Write([]byte(xml.Header))
+		_ = xml.NewEncoder(w).Encode(set)
+	}
+}
+
+// versionHandler reports the running build as JSON.
+func versionHandler(info buildinfo.Info) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(info)
+	}
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// healthHandler serves GET /healthz: 200 when every service reports
+// healthy, 503 with the failing checks otherwise.
+func healthHandler(reporter HealthReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := healthResponse{Status: "ok"}
+		status := http.StatusOK
+		if reporter != nil {
+			resp.Checks = make(map[string]string)
+			for name, err := range reporter.Health(r.Context()) {
+				if err != nil {
+					resp.Checks[name] = err.Error()
+					resp.Status = "degraded"
+					status = http.StatusServiceUnavailable
+					continue
+				}
+				resp.Checks[name] = "ok"
+			}
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// buildHeader tags every response with the running build.
+func buildHeader(info buildinfo.Info) middleware.Middleware {
+	label := info.String()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-SFT-Version", label)
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -1,15 +1,24 @@
 package httpx
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"html/template"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"sft/internal/config"
 	"sft/internal/features/builder"
+	"sft/internal/middleware"
 	"sft/internal/models"
 )
 
@@ -20,15 +29,19 @@ type mockTemplateLoader struct {
 	err  error
 }
 
-func (m *mockTemplateLoader) Load() (*template.Template, error) {
+func (m *mockTemplateLoader) Load() (*builder.Layouts, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	if m.tmpl != nil {
-		return m.tmpl, nil
+		return builder.NewLayouts(m.tmpl), nil
 	}
 	// Return a minimal working template
-	return template.New("builder.gohtml").Parse(`<!DOCTYPE html><html><body>Test</body></html>`)
+	tmpl, err := template.New("builder.gohtml").Parse(`<!DOCTYPE html><html><body>Test</body></html>`)
+	if err != nil {
+		return nil, err
+	}
+	return builder.NewLayouts(tmpl), nil
 }
 
 type mockUnitsLoader struct {
@@ -90,6 +103,51 @@ func TestNewRouterWithDeps_TemplateError(t *testing.T) {
 	}
 }
 
+// countingTemplateLoader parses a template that reports how often it was loaded.
+type countingTemplateLoader struct{ loads int }
+
+func (c *countingTemplateLoader) Load() (*builder.Layouts, error) {
+	c.loads++
+	tmpl, err := template.New("builder.gohtml").Parse(fmt.Sprintf("load %d", c.loads))
+	if err != nil {
+		return nil, err
+	}
+	return builder.NewLayouts(tmpl), nil
+}
+
+func TestNewRouterWithDeps_DevReloadsTemplates(t *testing.T) {
+	for _, dev := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.Dev = dev
+		handler, err := NewRouterWithDeps(cfg, Deps{
+			Templates: &countingTemplateLoader{},
+			Units:     &mockUnitsLoader{data: &models.UnitsData{Version: "1"}},
+			Assets:    &mockAssetResolver{},
+		})
+		if err != nil {
+			t.Fatalf("dev=%v: %v", dev, err)
+		}
+
+		var bodies []string
+		var etag string
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("If-None-Match", etag)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			etag = rec.Header().Get("ETag")
+			bodies = append(bodies, rec.Body.String())
+		}
+
+		if dev && (bodies[1] == bodies[0] || bodies[1] == "") {
+			t.Errorf("dev: second request served %q after %q, want a fresh parse", bodies[1], bodies[0])
+		}
+		if !dev && bodies[1] != "" {
+			t.Errorf("prod: second request served %q, want a 304", bodies[1])
+		}
+	}
+}
+
 func TestNewRouterWithDeps_ServesRobotsTxt(t *testing.T) {
 	cfg := config.Default()
 	deps := Deps{
@@ -105,11 +163,111 @@ func TestNewRouterWithDeps_ServesRobotsTxt(t *testing.T) {
 
 	handler.ServeHTTP(rec, req)
 
-	// Will 404 since file doesn't exist in test, but route should be registered
-	// In real scenario, would return the file
-	if rec.Code == http.StatusInternalServerError {
-		t.Error("route should be registered even if file missing")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Sitemap: http://localhost:8080/sitemap.xml") {
+		t.Errorf("expected sitemap line, got %q", rec.Body.String())
+	}
+}
+
+func TestNewRouterWithDeps_ServesSitemap(t *testing.T) {
+	cfg := config.Default()
+	deps := Deps{
+		Templates: &mockTemplateLoader{},
+		Units: &mockUnitsLoader{data: &models.UnitsData{
+			Units:  []models.Unit{{Name: "Ahri", Slug: "ahri"}},
+			Traits: []models.TraitInfo{{Name: "Arcanist", Slug: "arcanist"}},
+		}},
+		Assets: &mockAssetResolver{},
+	}
+
+	handler, _ := NewRouterWithDeps(cfg, deps)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	for _, loc := range []string{
+		"<loc>http://localhost:8080/</loc>",
+		"<loc>http://localhost:8080/units/ahri</loc>",
+		"<loc>http://localhost:8080/traits/arcanist</loc>",
+	} {
+		if !strings.Contains(rec.Body.String(), loc) {
+			t.Errorf("sitemap lacks %s: %s", loc, rec.Body.String())
+		}
+	}
+
+	cfg.Indexing = false
+	handler, _ = NewRouterWithDeps(cfg, deps)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	if rec.Code == http.StatusOK {
+		t.Error("sitemap served with indexing disabled")
+	}
+}
+
+func TestNewRouterWithDeps_ServesVersion(t *testing.T) {
+	cfg := config.Default()
+	deps := Deps{
+		Templates: &mockTemplateLoader{},
+		Units:     &mockUnitsLoader{},
+		Assets:    &mockAssetResolver{},
+	}
+
+	handler, _ := NewRouterWithDeps(cfg, deps)
+
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
 	}
+	if rec.Header().Get("X-SFT-Version") == "" {
+		t.Error("expected X-SFT-Version header")
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body["version"] == "" || body["version"] == nil {
+		t.Error("expected a version field")
+	}
+}
+
+func TestBuildRobots(t *testing.T) {
+	t.Run("indexing enabled", func(t *testing.T) {
+		got := buildRobots("https://example.com/", true)
+
+		if !strings.Contains(got, "Allow: /") {
+			t.Error("expected Allow: /")
+		}
+		if !strings.Contains(got, "Sitemap: https://example.com/sitemap.xml") {
+			t.Errorf("expected sitemap URL, got %q", got)
+		}
+	})
+
+	t.Run("indexing disabled", func(t *testing.T) {
+		got := buildRobots("https://staging.example.com/", false)
+
+		if !strings.Contains(got, "Disallow: /") {
+			t.Error("expected Disallow: /")
+		}
+		if strings.Contains(got, "Sitemap:") {
+			t.Error("staging robots.txt should not advertise a sitemap")
+		}
+	})
+
+	t.Run("no site URL", func(t *testing.T) {
+		got := buildRobots("", true)
+
+		if strings.Contains(got, "Sitemap:") {
+			t.Error("expected no sitemap without a site URL")
+		}
+	})
 }
 
 func TestBuildCanonicalURL(t *testing.T) {
@@ -157,3 +315,449 @@ func TestSetCacheHeaders_WithCache(t *testing.T) {
 		t.Error("expected max-age=3600")
 	}
 }
+
+type reloadableUnitsLoader struct {
+	mockUnitsLoader
+	reloads int
+}
+
+func (m *reloadableUnitsLoader) Reload(ctx context.Context) error {
+	m.reloads++
+	return nil
+}
+
+func TestNewRouterWithDeps_AdminReload(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdminToken = "secret"
+	units := &reloadableUnitsLoader{}
+	deps := Deps{
+		Templates: &mockTemplateLoader{},
+		Units:     units,
+		Assets:    &mockAssetResolver{},
+	}
+
+	handler, _ := NewRouterWithDeps(cfg, deps)
+
+	t.Run("rejects missing token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/reload", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/reload", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", rec.Code)
+		}
+	})
+
+	t.Run("reloads with valid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/reload", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if units.reloads != 1 {
+			t.Errorf("expected 1 reload, got %d", units.reloads)
+		}
+	})
+}
+
+func TestStaticFileHandler_Override(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "theme.css"), []byte("body{color:red}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.StaticOverride = dir
+	handler := staticFileHandler(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/static/theme.css", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "color:red") {
+		t.Errorf("expected override file, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/static/../router.go", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if strings.Contains(rec.Body.String(), "package httpx") {
+		t.Error("override lookup must not escape its directory")
+	}
+}
+
+func TestOverrideAssetResolver(t *testing.T) {
+	dir := t.TempDir()
+	base := &StaticAssetResolver{Assets: DefaultAssetPaths()}
+
+	resolver := NewOverrideAssetResolver(base, dir)
+	if got := resolver.Resolve(); got.ThemeCSS != "" {
+		t.Errorf("expected no theme without theme.css, got %q", got.ThemeCSS)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "theme.css"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := resolver.Resolve()
+	if got.ThemeCSS != "/theme.css" {
+		t.Errorf("expected /theme.css, got %q", got.ThemeCSS)
+	}
+	if got.CSS != DefaultAssetPaths().CSS {
+		t.Error("shared asset paths should be preserved")
+	}
+}
+
+func TestStaticFileHandler_CDN(t *testing.T) {
+	cfg := config.Default()
+	for _, cdn := range []string{"", "https://cdn.example.com"} {
+		cfg.CDNBaseURL = cdn
+		rec := httptest.NewRecorder()
+		staticFileHandler(cfg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/missing.css", nil))
+
+		want := ""
+		if cdn != "" {
+			want = "*"
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("CDN %q: Access-Control-Allow-Origin = %q, want %q", cdn, got, want)
+		}
+	}
+
+	if got := publicStaticBase(cfg); got != "https://cdn.example.com/static" {
+		t.Errorf("publicStaticBase = %q", got)
+	}
+	if got := preconnectOrigins(cfg); len(got) != 1 || got[0] != "https://cdn.example.com/static" {
+		t.Errorf("preconnectOrigins = %v", got)
+	}
+}
+
+func TestUnitPage(t *testing.T) {
+	tmpl := template.Must(template.New("builder.gohtml").Parse(`builder`))
+	template.Must(tmpl.New("unit.gohtml").Parse(`{{.Unit.Name}} {{.Canonical}}{{.Path}}`))
+	template.Must(tmpl.New("unit-tooltip").Parse(`{{.Unit.Name}} {{.Star}}`))
+
+	deps := Deps{
+		Templates: &mockTemplateLoader{tmpl: tmpl},
+		Units: &mockUnitsLoader{data: &models.UnitsData{
+			Units: []models.Unit{{Name: "Ahri", Slug: "ahri"}},
+		}},
+		Assets: &mockAssetResolver{},
+	}
+	handler, err := NewRouterWithDeps(config.Default(), deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/units/ahri", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "Ahri http://localhost:8080/units/ahri" {
+		t.Errorf("got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/units/nobody", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown unit, got %d", rec.Code)
+	}
+
+	for target, want := range map[string]string{
+		"/units/ahri/tooltip":        "Ahri 0",
+		"/units/ahri/tooltip?star=2": "Ahri 2",
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("%s: got %d %q, want %q", target, rec.Code, rec.Body.String(), want)
+		}
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/units/ahri/tooltip?star=4", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for star=4, got %d", rec.Code)
+	}
+}
+
+func TestTraitPage(t *testing.T) {
+	tmpl := template.Must(template.New("builder.gohtml").Parse(`builder`))
+	template.Must(tmpl.New("trait.gohtml").Parse(`{{.Trait.Name}}:{{range .Units}} {{.Name}}{{end}}`))
+
+	deps := Deps{
+		Templates: &mockTemplateLoader{tmpl: tmpl},
+		Units: &mockUnitsLoader{data: &models.UnitsData{
+			Units:  []models.Unit{{Name: "Ahri", Slug: "ahri"}, {Name: "Garen", Slug: "garen"}, {Name: "Yasuo", Slug: "yasuo"}},
+			Traits: []models.TraitInfo{{Name: "Ionia", Slug: "ionia", Units: []string{"ahri", "yasuo"}}},
+		}},
+		Assets: &mockAssetResolver{},
+	}
+	handler, err := NewRouterWithDeps(config.Default(), deps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/traits/ionia", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "Ionia: Ahri Yasuo" {
+		t.Errorf("got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/traits/void", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown trait, got %d", rec.Code)
+	}
+}
+
+func TestBuilderPage_ETag(t *testing.T) {
+	newRouter := func(body string) http.Handler {
+		t.Helper()
+		tmpl := template.Must(template.New("builder.gohtml").Parse(body))
+		router, err := NewRouterWithDeps(config.Default(), Deps{
+			Templates: &mockTemplateLoader{tmpl: tmpl},
+			Units:     &mockUnitsLoader{data: &models.UnitsData{Version: "v1"}},
+			Assets:    &mockAssetResolver{},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return router
+	}
+
+	router := newRouter(`builder`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", rec.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	newRouter(`builder v2`).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("ETag"); got == etag {
+		t.Error("template change should change the ETag")
+	}
+}
+
+func TestManifestAssetResolver_Integrity(t *testing.T) {
+	static := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(static, "dist"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := filepath.Join(static, "dist", "manifest.json")
+	css := filepath.Join(static, "dist", "app.css")
+	if err := os.WriteFile(manifest, []byte(`{"app.js":"/dist/app-1.js","app.css":"/dist/app.css","app.js.integrity":"sha384-fromManifest"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(css, []byte("body{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewManifestAssetResolver(manifest)
+	r.StaticDir = static
+	if got := r.Resolve(); got.CSSIntegrity != "" || got.JSIntegrity != "" {
+		t.Errorf("integrity disabled but got %+v", got)
+	}
+
+	r.Integrity = true
+	if err := r.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := r.Resolve()
+	// echo -n 'body{}' | openssl dgst -sha384 -binary | base64
+	if want := "sha384-myyg/hQ74aSgjBBvVME/QXAXEkT4Y9dHbVQ5C0lIyGpldvNLJV2IWc5ElXbqLi06"; got.CSSIntegrity != want {
+		t.Errorf("CSSIntegrity = %q, want %q", got.CSSIntegrity, want)
+	}
+	if got.JSIntegrity != "sha384-fromManifest" {
+		t.Errorf("JSIntegrity = %q, want the manifest entry", got.JSIntegrity)
+	}
+}
+
+func TestManifestAssetResolver_HotReload(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	deploy := func(js string, at time.Time) {
+		t.Helper()
+		if err := os.WriteFile(manifest, []byte(`{"app.js":"`+js+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(manifest, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	deploy("/dist/app-1.js", start)
+
+	r := NewManifestAssetResolver(manifest)
+	r.TTL = time.Hour
+	if got := r.Resolve().JS; got != "/dist/app-1.js" {
+		t.Fatalf("JS = %q", got)
+	}
+
+	changed := r.Changed()
+
+	deploy("/dist/app-2.js", start.Add(time.Minute))
+	if got := r.Resolve().JS; got != "/dist/app-1.js" {
+		t.Errorf("JS = %q before the TTL expired, want the cached bundle", got)
+	}
+
+	r.TTL = time.Nanosecond
+	if got := r.Resolve().JS; got != "/dist/app-2.js" {
+		t.Errorf("JS = %q after deploy, want /dist/app-2.js", got)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Error("Changed did not fire after the deploy")
+	}
+}
+
+func TestNewRouterWithDeps_GroupMiddleware(t *testing.T) {
+	tag := func(name string) middleware.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Chain", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handler, err := NewRouterWithDeps(config.Default(), Deps{
+		Templates: &mockTemplateLoader{},
+		Units:     &mockUnitsLoader{},
+		Assets:    &mockAssetResolver{},
+		Middleware: map[RouteGroup][]middleware.Middleware{
+			GroupAPI:   {tag("api-1"), tag("api-2")},
+			GroupPages: {tag("pages")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/units", "api-1,api-2"},
+		{"/", "pages"},
+		{"/version", ""},
+		{"/static/css/app.css", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if got := strings.Join(rec.Header().Values("X-Chain"), ","); got != tt.want {
+			t.Errorf("%s: chain %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNewRouterWithDeps_NotFoundAndMethods(t *testing.T) {
+	handler, err := NewRouterWithDeps(config.Default(), Deps{
+		Templates: &mockTemplateLoader{},
+		Units:     &mockUnitsLoader{},
+		Assets:    &mockAssetResolver{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, path string
+		want         int
+		allow        string
+	}{
+		{http.MethodGet, "/", http.StatusOK, ""},
+		{http.MethodHead, "/", http.StatusOK, ""},
+		{http.MethodGet, "/builder", http.StatusOK, ""},
+		{http.MethodGet, "/wp-login.php", http.StatusNotFound, ""},
+		{http.MethodGet, "/units/ahri/extra", http.StatusNotFound, ""},
+		{http.MethodPost, "/", http.StatusMethodNotAllowed, "GET, HEAD"},
+		{http.MethodDelete, "/version", http.StatusMethodNotAllowed, "GET, HEAD"},
+		{http.MethodGet, "/api/v1/synergies", http.StatusMethodNotAllowed, "POST"},
+		{http.MethodGet, "/simulator", http.StatusFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+		if got := rec.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: Allow %q, want %q", tt.method, tt.path, got, tt.allow)
+		}
+	}
+}
+
+func TestNewRouterWithDeps_PWA(t *testing.T) {
+	dir := t.TempDir()
+	var icon bytes.Buffer
+	if err := png.Encode(&icon, image.NewRGBA(image.Rect(0, 0, 48, 48))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "icon.png"), icon.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.StaticOverride = dir
+	cfg.ThemeColor = "#112233"
+	cfg.AppIcons = []string{"icon.png", "missing.png"}
+	handler, err := NewRouterWithDeps(cfg, Deps{
+		Templates: &mockTemplateLoader{},
+		Units:     &mockUnitsLoader{},
+		Assets:    &mockAssetResolver{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil))
+	if ct := rec.Header().Get("Content-Type"); rec.Code != http.StatusOK || ct != "application/manifest+json" {
+		t.Fatalf("manifest: got %d %q", rec.Code, ct)
+	}
+	var manifest webManifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &manifest); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if manifest.Name != cfg.SiteName || manifest.ThemeColor != "#112233" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	want := manifestIcon{Src: "/static/icon.png", Sizes: "48x48", Type: "image/png", Purpose: "any maskable"}
+	if len(manifest.Icons) != 1 || manifest.Icons[0] != want {
+		t.Errorf("icons = %+v, want only %+v", manifest.Icons, want)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sw.js", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Service-Worker-Allowed") != "/" {
+		t.Fatalf("service worker: got %d, headers %v", rec.Code, rec.Header())
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/javascript") {
+		t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `["/","/static/dist/app.css","/static/dist/app.js"]`) {
+		t.Errorf("service worker does not precache the bundle:\n%s", rec.Body.String())
+	}
+}
@@ -3,27 +3,101 @@ package httpx
 import (
 	"fmt"
 	"html/template"
+	"io/fs"
+	"path/filepath"
 
+	"sft/internal/features/builder"
 	tmplhelpers "sft/internal/httpx/templates"
 )
 
-// FileTemplateLoader loads templates from the filesystem.
+// FileTemplateLoader loads templates from the filesystem. Files in the
+// layouts and components subdirectories of Dir are shared by every page;
+// each file in pages is a page parsed on top of them, see builder.Layouts.
 type FileTemplateLoader struct {
-	Pattern string // Glob pattern, e.g. "templates/**/*.gohtml"
+	Dir string // e.g. "templates"
+	// OverrideDir holds .gohtml files parsed after Dir, at any depth. A file
+	// named like a page (e.g. builder.gohtml) replaces that page; any other
+	// file is shared, and a {{define}} in it replaces the built-in template
+	// of the same name.
+	OverrideDir string
 }
 
-// NewFileTemplateLoader creates a loader with the default pattern.
+// NewFileTemplateLoader creates a loader for the templates directory.
 func NewFileTemplateLoader() *FileTemplateLoader {
 	return &FileTemplateLoader{
-		Pattern: "templates/**/*.gohtml",
+		Dir: "templates",
 	}
 }
 
-// Load parses all templates matching the pattern.
-func (l *FileTemplateLoader) Load() (*template.Template, error) {
-	tmpl, err := template.New("").Funcs(tmplhelpers.Funcs()).ParseGlob(l.Pattern)
+// Load parses the shared templates, the overrides, then every page.
+func (l *FileTemplateLoader) Load() (*builder.Layouts, error) {
+	shared := template.New("").Funcs(tmplhelpers.Funcs())
+	var sharedFiles []string
+	for _, sub := range []string{"layouts", "components"} {
+		files, err := filepath.Glob(filepath.Join(l.Dir, sub, "*.gohtml"))
+		if err != nil {
+			return nil, fmt.Errorf("template loading failed: %w", err)
+		}
+		sharedFiles = append(sharedFiles, files...)
+	}
+	pageFiles, err := filepath.Glob(filepath.Join(l.Dir, "pages", "*.gohtml"))
 	if err != nil {
 		return nil, fmt.Errorf("template loading failed: %w", err)
 	}
-	return tmpl, nil
+	if len(pageFiles) == 0 {
+		return nil, fmt.Errorf("template loading failed: no pages in %s", filepath.Join(l.Dir, "pages"))
+	}
+
+	if l.OverrideDir != "" {
+		overrides, err := templateFiles(l.OverrideDir)
+		if err != nil {
+			return nil, fmt.Errorf("template overrides: %w", err)
+		}
+		for _, file := range overrides {
+			if i := indexByBase(pageFiles, file); i >= 0 {
+				pageFiles[i] = file
+			} else {
+				sharedFiles = append(sharedFiles, file)
+			}
+		}
+	}
+
+	if len(sharedFiles) > 0 {
+		if _, err := shared.ParseFiles(sharedFiles...); err != nil {
+			return nil, fmt.Errorf("template loading failed: %w", err)
+		}
+	}
+	layouts := builder.NewLayouts(shared)
+	for _, file := range pageFiles {
+		if err := layouts.AddPage(file); err != nil {
+			return nil, fmt.Errorf("template loading failed: %w", err)
+		}
+	}
+	return layouts, nil
+}
+
+// templateFiles lists the .gohtml files under dir in lexical order.
+func templateFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".gohtml" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// indexByBase returns the index of the file in files with the same base
+// name as file, or -1.
+func indexByBase(files []string, file string) int {
+	for i, f := range files {
+		if filepath.Base(f) == filepath.Base(file) {
+			return i
+		}
+	}
+	return -1
 }
@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func render(t *testing.T, loader *FileTemplateLoader, name string) string {
+	t.Helper()
+	layouts, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := layouts.ExecuteTemplate(&out, name, nil); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func baseTemplates(t *testing.T) string {
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "layouts", "base.gohtml"),
+		`{{define "base"}}<title>{{block "title" .}}site{{end}}</title>{{template "header"}}|{{template "content" .}}|{{template "footer"}}{{end}}`)
+	writeTemplate(t, filepath.Join(dir, "components", "parts.gohtml"),
+		`{{define "header"}}base header{{end}}{{define "footer"}}base footer{{end}}`)
+	writeTemplate(t, filepath.Join(dir, "pages", "home.gohtml"),
+		`{{define "content"}}home{{end}}{{template "base" .}}`)
+	writeTemplate(t, filepath.Join(dir, "pages", "about.gohtml"),
+		`{{define "title"}}about{{end}}{{define "content"}}about us{{end}}{{template "base" .}}`)
+	return dir
+}
+
+func TestFileTemplateLoader_PagesShareLayout(t *testing.T) {
+	loader := &FileTemplateLoader{Dir: baseTemplates(t)}
+
+	tests := []struct{ name, want string }{
+		{"home.gohtml", "<title>site</title>base header|home|base footer"},
+		{"about.gohtml", "<title>about</title>base header|about us|base footer"},
+		{"footer", "base footer"},
+	}
+	for _, tt := range tests {
+		if got := render(t, loader, tt.name); got != tt.want {
+			t.Errorf("%s rendered %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFileTemplateLoader_Overrides(t *testing.T) {
+	override := t.TempDir()
+	writeTemplate(t, filepath.Join(override, "brand", "header.gohtml"), `{{define "header"}}custom header{{end}}`)
+	writeTemplate(t, filepath.Join(override, "about.gohtml"), `{{define "content"}}custom about{{end}}{{template "base" .}}`)
+
+	loader := &FileTemplateLoader{Dir: baseTemplates(t), OverrideDir: override}
+	if got, want := render(t, loader, "home.gohtml"), "<title>site</title>custom header|home|base footer"; got != want {
+		t.Errorf("home rendered %q, want %q", got, want)
+	}
+	if got, want := render(t, loader, "about.gohtml"), "<title>site</title>custom header|custom about|base footer"; got != want {
+		t.Errorf("about rendered %q, want %q", got, want)
+	}
+
+	loader.OverrideDir = filepath.Join(override, "missing")
+	if _, err := loader.Load(); err == nil {
+		t.Error("expected an error for a missing override directory")
+	}
+}
@@ -3,9 +3,11 @@ package templates
 import (
 	"fmt"
 	"html/template"
+	"net/url"
 	"path/filepath"
 	"strings"
 
+	"sft/internal/i18n"
 	"sft/internal/services"
 )
 
@@ -14,9 +16,11 @@ func Funcs() template.FuncMap {
 	return template.FuncMap{
 		"mod":               func(a, b int) int { return a % b },
 		"formatAbility":     services.FormatAbilityDescription,
+		"formatAbilityAt":   services.FormatAbilityDescriptionAt,
 		"formatPercent":     services.FormatPercent,
 		"formatAttackSpeed": services.FormatAttackSpeed,
 		"formatIntList":     services.FormatIntList,
+		"formatIntListAt":   services.FormatIntListAt,
 		"formatMana":        services.FormatMana,
 		"dict": func(values ...any) (map[string]any, error) {
 			if len(values)%2 != 0 {
@@ -32,8 +36,12 @@ func Funcs() template.FuncMap {
 			}
 			return dict, nil
 		},
+		"t":              i18n.Default().T,
 		"static":         staticPath,
 		"unitWebpSrcset": buildUnitWebpSrcset,
+		"resourceHints":  resourceHints,
+		"iconSprite":     func() template.HTML { return services.IconSprite().HTML() },
+		"spriteIcon":     spriteIcon,
 		// slice creates a slice from variadic arguments - useful for range in templates
 		"slice": func(items ...any) []any {
 			return items
@@ -41,9 +49,10 @@ func Funcs() template.FuncMap {
 	}
 }
 
-// staticPath builds the full static asset URL.
+// staticPath builds the full static asset URL. base is a path on this
+// server or, when assets are served through a CDN, an absolute URL.
 func staticPath(base, path string) string {
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+	if isAbsoluteURL(path) {
 		return path
 	}
 
@@ -51,7 +60,11 @@ func staticPath(base, path string) string {
 	if b == "" {
 		b = "/static"
 	}
-	b = "/" + strings.Trim(b, "/")
+	if isAbsoluteURL(b) {
+		b = strings.TrimRight(b, "/")
+	} else {
+		b = "/" + strings.Trim(b, "/")
+	}
 
 	p := "/" + strings.TrimLeft(path, "/")
 	p = strings.TrimPrefix(p, "/static")
@@ -64,7 +77,7 @@ func buildUnitWebpSrcset(base, path string, widths ...int) string {
 	if path == "" {
 		return ""
 	}
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+	if isAbsoluteURL(path) {
 		return ""
 	}
 
@@ -91,3 +104,44 @@ func buildUnitWebpSrcset(base, path string, widths ...int) string {
 
 	return strings.Join(parts, ", ")
 }
+
+// spriteIcon draws the icon at path from the page's icon sprite, falling
+// back to an <img> of the file for icons the sprite does not have.
+func spriteIcon(base, path, class string) template.HTML {
+	if id, ok := services.IconSprite().ID(path); ok {
+		return template.HTML(fmt.Sprintf(
+			`<svg class="%s" fill="currentColor" aria-hidden="true" focusable="false"><use href="#%s"></use></svg>`,
+			template.HTMLEscapeString(class), id))
+	}
+	return template.HTML(fmt.Sprintf(`<img src="%s" alt="" class="%s" aria-hidden="true">`,
+		template.HTMLEscapeString(staticPath(base, path)), template.HTMLEscapeString(class)))
+}
+
+// resourceHints renders preconnect and dns-prefetch links for each unique origin.
+// Entries that are not absolute http(s) URLs are ignored; paths are dropped.
+func resourceHints(origins []string) template.HTML {
+	seen := make(map[string]bool, len(origins))
+	var b strings.Builder
+
+	for _, raw := range origins {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if seen[origin] {
+			continue
+		}
+		seen[origin] = true
+
+		escaped := template.HTMLEscapeString(origin)
+		fmt.Fprintf(&b, `<link rel="preconnect" href="%s" crossorigin>`+"\n", escaped)
+		fmt.Fprintf(&b, `<link rel="dns-prefetch" href="%s">`+"\n", escaped)
+	}
+
+	return template.HTML(b.String())
+}
+
+func isAbsoluteURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceHints(t *testing.T) {
+	got := string(resourceHints([]string{
+		"https://cdn.example.com/static/",
+		"https://cdn.example.com",
+		"https://fonts.gstatic.com",
+		"/relative/path",
+		"ftp://files.example.com",
+	}))
+
+	if strings.Count(got, `rel="preconnect"`) != 2 {
+		t.Errorf("expected 2 unique preconnect hints, got:\n%s", got)
+	}
+	if !strings.Contains(got, `<link rel="preconnect" href="https://cdn.example.com" crossorigin>`) {
+		t.Errorf("expected CDN origin without path, got:\n%s", got)
+	}
+	if !strings.Contains(got, `<link rel="dns-prefetch" href="https://fonts.gstatic.com">`) {
+		t.Errorf("expected dns-prefetch hint, got:\n%s", got)
+	}
+	if strings.Contains(got, "relative") || strings.Contains(got, "ftp") {
+		t.Errorf("non-http origins should be skipped, got:\n%s", got)
+	}
+}
+
+func TestResourceHints_Empty(t *testing.T) {
+	if got := resourceHints(nil); got != "" {
+		t.Errorf("expected empty output, got %q", got)
+	}
+}
+
+func TestStaticPath(t *testing.T) {
+	tests := []struct {
+		base, path, want string
+	}{
+		{"/static", "/dist/app.js", "/static/dist/app.js"},
+		{"", "static/assets/a.png", "/static/assets/a.png"},
+		{"static/", "assets/a.png", "/static/assets/a.png"},
+		{"https://cdn.example.com/static/", "/dist/app.js", "https://cdn.example.com/static/dist/app.js"},
+		{"https://cdn.example.com/static", "static/assets/a.png", "https://cdn.example.com/static/assets/a.png"},
+		{"https://cdn.example.com/static", "https://img.example.com/a.png", "https://img.example.com/a.png"},
+	}
+	for _, tt := range tests {
+		if got := staticPath(tt.base, tt.path); got != tt.want {
+			t.Errorf("staticPath(%q, %q) = %q, want %q", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
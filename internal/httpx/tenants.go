@@ -0,0 +1,53 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+
+	"sft/internal/config"
+	"sft/internal/tenant"
+)
+
+// NewTenantRouter builds one router per site profile on top of shared
+// dependencies and dispatches requests between them by Host header.
+// Sites with their own dataset get its units from c.
+func NewTenantRouter(cfg config.Config, c *Container, deps Deps, profiles []tenant.Profile) (http.Handler, error) {
+	sites := make([]tenant.Site, 0, len(profiles))
+
+	for _, p := range profiles {
+		siteCfg := p.Apply(cfg)
+		siteDeps := deps
+
+		if siteCfg.SetDataPath != cfg.SetDataPath {
+			siteDeps.Units, siteDeps.Refresh = c.SiteUnits(siteCfg)
+		}
+		if siteCfg.StaticOverride != "" {
+			siteDeps.Assets = NewOverrideAssetResolver(deps.Assets, siteCfg.StaticOverride)
+		}
+		if !p.Enabled(tenant.FeatureComps) {
+			siteDeps.Comps = nil
+			siteDeps.CompVotes = nil
+		}
+		if !p.Enabled(tenant.FeatureLobbies) {
+			siteDeps.Lobbies = nil
+		}
+		if !p.Enabled(tenant.FeatureAccounts) {
+			siteDeps.Users = nil
+			siteDeps.Favorites = nil
+		}
+		if !p.Enabled(tenant.FeatureDrills) {
+			siteDeps.Drills = nil
+		}
+		if !p.Enabled(tenant.FeatureLinks) {
+			siteDeps.Links = nil
+		}
+
+		handler, err := NewRouterWithDeps(siteCfg, siteDeps)
+		if err != nil {
+			return nil, fmt.Errorf("site %q: %w", p.ID, err)
+		}
+		sites = append(sites, tenant.Site{Profile: p, Handler: handler})
+	}
+
+	return tenant.NewRouter(sites), nil
+}
@@ -0,0 +1,171 @@
+// Package i18n translates UI strings. Message catalogs are JSON files in
+// locales/, one per locale, embedded in the binary.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Fallback is the locale used when nothing better matches. Its catalog
+// must hold every key.
+const Fallback = "en"
+
+// CookieName holds the visitor's chosen locale. The ?lang= query
+// parameter sets it.
+const CookieName = "lang"
+
+//go:embed locales/*.json
+var catalogFS embed.FS
+
+// Bundle holds the message catalogs keyed by locale.
+type Bundle struct {
+	catalogs map[string]map[string]string
+}
+
+var defaultBundle = sync.OnceValue(func() *Bundle {
+	b, err := load()
+	if err != nil {
+		// The catalogs are compiled in, so this only fails on a bad edit.
+		panic(err)
+	}
+	return b
+})
+
+// Default returns the bundle built from the embedded catalogs.
+func Default() *Bundle {
+	return defaultBundle()
+}
+
+func load() (*Bundle, error) {
+	files, err := catalogFS.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{catalogs: make(map[string]map[string]string, len(files))}
+	for _, f := range files {
+		data, err := catalogFS.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("decode catalog %s: %w", f.Name(), err)
+		}
+		b.catalogs[strings.TrimSuffix(f.Name(), ".json")] = messages
+	}
+	if _, ok := b.catalogs[Fallback]; !ok {
+		return nil, fmt.Errorf("missing %s catalog", Fallback)
+	}
+	return b, nil
+}
+
+// Locales returns the supported locales in sorted order.
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.catalogs))
+	for l := range b.catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supports reports whether locale has a catalog.
+func (b *Bundle) Supports(locale string) bool {
+	_, ok := b.catalogs[locale]
+	return ok
+}
+
+// T returns the message for key in locale, falling back to the Fallback
+// catalog and then to the key itself. Args are applied with fmt.Sprintf.
+func (b *Bundle) T(locale, key string, args ...any) string {
+	msg, ok := b.catalogs[locale][key]
+	if !ok {
+		if msg, ok = b.catalogs[Fallback][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Match picks the supported locale that best fits an Accept-Language
+// header, honouring q-values and falling back from "fr-CA" to "fr".
+func (b *Bundle) Match(acceptLanguage string) string {
+	best, bestQ := Fallback, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= bestQ {
+			continue
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if b.Supports(base) {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// Resolve returns the locale for r: a valid ?lang= parameter, then the
+// CookieName cookie, then Accept-Language.
+func (b *Bundle) Resolve(r *http.Request) string {
+	if l := r.URL.Query().Get("lang"); b.Supports(l) {
+		return l
+	}
+	if c, err := r.Cookie(CookieName); err == nil && b.Supports(c.Value) {
+		return c.Value
+	}
+	return b.Match(r.Header.Get("Accept-Language"))
+}
+
+// Middleware resolves each request's locale into its context. A ?lang=
+// parameter is remembered in a cookie for later visits.
+func (b *Bundle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := b.Resolve(r)
+		if l := r.URL.Query().Get("lang"); l == locale {
+			http.SetCookie(w, &http.Cookie{
+				Name:     CookieName,
+				Value:    locale,
+				Path:     "/",
+				MaxAge:   365 * 24 * 60 * 60,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		w.Header().Add("Vary", "Accept-Language, Cookie")
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+	})
+}
+
+type contextKey struct{}
+
+// WithLocale returns a copy of ctx carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the request locale, or Fallback when none was set.
+func FromContext(ctx context.Context) string {
+	if l, ok := ctx.Value(contextKey{}).(string); ok {
+		return l
+	}
+	return Fallback
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBundle_Match(t *testing.T) {
+	b := Default()
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"fr-CA,fr;q=0.9,en;q=0.8", "fr"},
+		{"en;q=0.5,fr;q=0.9", "fr"},
+		{"de-DE,de;q=0.9", "en"},
+		{"de,fr;q=0.3", "fr"},
+		{"fr;q=bogus,en", "en"},
+	}
+	for _, tt := range tests {
+		if got := b.Match(tt.header); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestBundle_T(t *testing.T) {
+	b := Default()
+	if got := b.T("fr", "unit.gold", 3); got != "3 or" {
+		t.Errorf("fr unit.gold = %q", got)
+	}
+	if got := b.T("de", "nav.back"); got != "Back to the builder" {
+		t.Errorf("unsupported locale should fall back to en, got %q", got)
+	}
+	if got := b.T("en", "no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key should render as itself, got %q", got)
+	}
+}
+
+// Every catalog must translate the same keys as the fallback.
+func TestCatalogsComplete(t *testing.T) {
+	b := Default()
+	for _, locale := range b.Locales() {
+		for key := range b.catalogs[Fallback] {
+			if _, ok := b.catalogs[locale][key]; !ok {
+				t.Errorf("%s catalog is missing %q", locale, key)
+			}
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	h := Default().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		target string
+		cookie string
+		accept string
+		want   string
+		sets   bool
+	}{
+		{"accept-language", "/", "", "fr-FR", "fr", false},
+		{"cookie beats header", "/", "en", "fr", "en", false},
+		{"query beats cookie and is remembered", "/?lang=fr", "en", "", "fr", true},
+		{"unsupported query ignored", "/?lang=xx", "", "", "en", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CookieName, Value: tt.cookie})
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if got != tt.want {
+				t.Errorf("locale = %q, want %q", got, tt.want)
+			}
+			if sets := rec.Header().Get("Set-Cookie") != ""; sets != tt.sets {
+				t.Errorf("Set-Cookie present = %v, want %v", sets, tt.sets)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Package imagecache stores generated images on disk under content-addressed
+// paths, with a persistent key index and least-recently-used eviction.
+package imagecache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const indexFile = "index.json"
+
+// Key derives a cache key from the inputs that determine an image.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// entry maps a key to the content hash of its image.
+type entry struct {
+	Key      string    `json:"key"`
+	Hash     string    `json:"hash"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// Cache is safe for concurrent use. Identical images stored under different
+// keys share one blob; blobs are deleted once no key references them.
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	lru   *list.List               // front is most recently used; values are *entry
+	keys  map[string]*list.Element // key → element in lru
+	refs  map[string]int           // content hash → number of keys
+	bytes int64                    // total size of distinct blobs
+}
+
+// Open loads or creates a cache in dir, evicting down to maxBytes.
+// A corrupt index is discarded rather than failing startup.
+func Open(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create %s: %w", dir, err)
+	}
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		keys:     make(map[string]*list.Element),
+		refs:     make(map[string]int),
+	}
+
+	var entries []entry
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("read %s index: %w", dir, err)
+	default:
+		if err := json.Unmarshal(data, &entries); err != nil {
+			log.Printf("imagecache: discarding corrupt index in %s: %v", dir, err)
+			entries = nil
+		}
+	}
+
+	// The index is saved most recently used first.
+	for i := range entries {
+		e := entries[i]
+		if _, err := os.Stat(c.blobPath(e.Hash)); err != nil {
+			continue
+		}
+		c.add(&e, false)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictLocked()
+	return c, c.saveLocked()
+}
+
+// Get returns the image stored under key and marks it recently used. A nil
+// Cache always misses.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	el, ok := c.keys[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	e.LastUsed = time.Now().UTC()
+	c.lru.MoveToFront(el)
+	path := c.blobPath(e.Hash)
+	c.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.mu.Lock()
+		c.removeLocked(key)
+		c.mu.Unlock()
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores data under key, evicting old entries past the size limit. It
+// is a no-op on a nil Cache.
+func (c *Cache) Put(key string, data []byte) error {
+	if c == nil {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	path := c.blobPath(hash)
+	if _, err := os.Stat(path); err != nil {
+		if err := writeFileAtomic(path, data); err != nil {
+			return fmt.Errorf("store image %s: %w", hash, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.keys[key]; ok && el.Value.(*entry).Hash == hash {
+		// Same image again: removing the key first would drop the blob's
+		// last reference and delete the file just checked.
+		el.Value.(*entry).LastUsed = time.Now().UTC()
+		c.lru.MoveToFront(el)
+		return c.saveLocked()
+	}
+	c.removeLocked(key)
+	c.addLocked(&entry{Key: key, Hash: hash, Size: int64(len(data)), LastUsed: time.Now().UTC()}, true)
+	c.evictLocked()
+	return c.saveLocked()
+}
+
+// Size returns the total bytes of stored images.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+func (c *Cache) add(e *entry, front bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addLocked(e, front)
+}
+
+func (c *Cache) addLocked(e *entry, front bool) {
+	if _, dup := c.keys[e.Key]; dup {
+		return
+	}
+	if front {
+		c.keys[e.Key] = c.lru.PushFront(e)
+	} else {
+		c.keys[e.Key] = c.lru.PushBack(e)
+	}
+	if c.refs[e.Hash] == 0 {
+		c.bytes += e.Size
+	}
+	c.refs[e.Hash]++
+}
+
+func (c *Cache) removeLocked(key string) {
+	el, ok := c.keys[key]
+	if !ok {
+		return
+	}
+	e := el.Value.(*entry)
+	c.lru.Remove(el)
+	delete(c.keys, key)
+
+	c.refs[e.Hash]--
+	if c.refs[e.Hash] > 0 {
+		return
+	}
+	delete(c.refs, e.Hash)
+	c.bytes -= e.Size
+	if err := os.Remove(c.blobPath(e.Hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("imagecache: remove %s: %v", e.Hash, err)
+	}
+}
+
+func (c *Cache) evictLocked() {
+	for c.maxBytes > 0 && c.bytes > c.maxBytes && c.lru.Len() > 1 {
+		c.removeLocked(c.lru.Back().Value.(*entry).Key)
+	}
+}
+
+func (c *Cache) saveLocked() error {
+	entries := make([]entry, 0, c.lru.Len())
+	for el := c.lru.Front(); el != nil; el = el.Next() {
+		entries = append(entries, *el.Value.(*entry))
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(c.dir, indexFile), data); err != nil {
+		return fmt.Errorf("save %s index: %w", c.dir, err)
+	}
+	return nil
+}
+
+// blobPath fans blobs out over 256 directories by their first hash byte.
+func (c *Cache) blobPath(hash string) string {
+	return filepath.Join(c.dir, hash[:2], hash+".bin")
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,105 @@
+package imagecache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache_PutGetDedup(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir, 0)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	img := []byte("same image")
+	if err := c.Put("a", img); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("b", img); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.Get("b"); !ok || !bytes.Equal(got, img) {
+		t.Errorf("Get(b) = %q, %v", got, ok)
+	}
+	if c.Size() != int64(len(img)) {
+		t.Errorf("identical images should be stored once, size = %d", c.Size())
+	}
+	blobs, _ := filepath.Glob(filepath.Join(dir, "*", "*.bin"))
+	if len(blobs) != 1 {
+		t.Errorf("expected 1 blob on disk, got %d", len(blobs))
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("unexpected hit for missing key")
+	}
+}
+
+func TestCache_PutSameImageTwice(t *testing.T) {
+	c, err := Open(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	img := []byte("same image")
+	for i := 0; i < 2; i++ {
+		if err := c.Put("k", img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, ok := c.Get("k"); !ok || !bytes.Equal(got, img) {
+		t.Errorf("Get(k) after a repeated Put = %q, %v", got, ok)
+	}
+	if c.Size() != int64(len(img)) {
+		t.Errorf("size = %d, want %d", c.Size(), len(img))
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir, 10)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	_ = c.Put("old", []byte("aaaa"))
+	_ = c.Put("mid", []byte("bbbb"))
+	c.Get("old") // refresh: "mid" is now least recently used
+	_ = c.Put("new", []byte("cccc"))
+
+	if _, ok := c.Get("mid"); ok {
+		t.Error("least recently used entry should be evicted")
+	}
+	for _, key := range []string{"old", "new"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s should still be cached", key)
+		}
+	}
+	if c.Size() != 8 {
+		t.Errorf("size = %d, want 8", c.Size())
+	}
+
+	// The index survives a restart.
+	reopened, err := Open(dir, 10)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, ok := reopened.Get("new"); !ok || string(got) != "cccc" {
+		t.Errorf("reopened Get(new) = %q, %v", got, ok)
+	}
+}
+
+func TestOpen_CorruptIndex(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, indexFile), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Open(dir, 0)
+	if err != nil {
+		t.Fatalf("corrupt index should not fail open: %v", err)
+	}
+	if c.Size() != 0 {
+		t.Errorf("size = %d, want 0", c.Size())
+	}
+}
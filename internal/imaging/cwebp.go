@@ -0,0 +1,61 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// CWebP encodes with the cwebp tool from libwebp, since neither the
+// standard library nor x/image can write WebP.
+type CWebP struct {
+	Path    string // executable; empty looks up "cwebp" in PATH
+	Quality int    // 0-100; 0 uses 80
+}
+
+// Check reports whether the encoder can run.
+func (c CWebP) Check() error {
+	if _, err := exec.LookPath(c.path()); err != nil {
+		return fmt.Errorf("cwebp not found (install libwebp tools): %w", err)
+	}
+	return nil
+}
+
+// EncodeFile writes img to path through a temporary PNG.
+func (c CWebP) EncodeFile(path string, img image.Image) error {
+	tmp, err := os.CreateTemp("", "sft-imaging-*.png")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := png.Encode(tmp, img); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	quality := c.Quality
+	if quality <= 0 {
+		quality = 80
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(c.path(), "-quiet", "-q", strconv.Itoa(quality), tmp.Name(), "-o", path)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cwebp: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+func (c CWebP) path() string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return "cwebp"
+}
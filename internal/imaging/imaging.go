@@ -0,0 +1,132 @@
+// Package imaging produces the resized WebP variants of unit and spell art
+// that pages reference through srcset, e.g. Units/SET16/webp-256/Ahri.webp
+// next to Units/SET16/Ahri.jpg.
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	// Register decoders for the source formats found in the asset dirs.
+	_ "golang.org/x/image/webp"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Job describes one directory of source images and the widths to produce.
+type Job struct {
+	Dir    string // e.g. static/assets/Units/SET16
+	Widths []int  // e.g. 64, 256, 600
+}
+
+// Encoder writes img to path as WebP.
+type Encoder interface {
+	EncodeFile(path string, img image.Image) error
+}
+
+// Result lists the variants of a run. Generated were written; Current were
+// already newer than their source and left alone.
+type Result struct {
+	Generated []string
+	Current   []string
+}
+
+// VariantPath returns where the width variant of a source image lives,
+// matching the srcset built by the templates.
+func VariantPath(source string, width int) string {
+	dir, file := filepath.Split(source)
+	name := strings.TrimSuffix(file, filepath.Ext(file))
+	return filepath.Join(dir, fmt.Sprintf("webp-%d", width), name+".webp")
+}
+
+// Generate writes every missing or stale variant for the sources in job.Dir.
+// Subdirectories, including existing webp-* outputs, are not descended.
+// With force set, variants are rewritten even when current.
+func Generate(ctx context.Context, job Job, enc Encoder, force bool) (Result, error) {
+	var res Result
+	entries, err := os.ReadDir(job.Dir)
+	if err != nil {
+		return res, fmt.Errorf("read %s: %w", job.Dir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !isSource(e.Name()) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		source := filepath.Join(job.Dir, e.Name())
+		info, err := e.Info()
+		if err != nil {
+			return res, fmt.Errorf("stat %s: %w", source, err)
+		}
+
+		var img image.Image
+		for _, width := range job.Widths {
+			dst := VariantPath(source, width)
+			if !force && newerThan(dst, info.ModTime().UnixNano()) {
+				res.Current = append(res.Current, dst)
+				continue
+			}
+			if img == nil {
+				if img, err = decode(source); err != nil {
+					return res, err
+				}
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+				return res, fmt.Errorf("create %s: %w", filepath.Dir(dst), err)
+			}
+			if err := enc.EncodeFile(dst, Resize(img, width)); err != nil {
+				return res, fmt.Errorf("encode %s: %w", dst, err)
+			}
+			res.Generated = append(res.Generated, dst)
+		}
+	}
+	return res, nil
+}
+
+// Resize scales img to width, keeping its aspect ratio. Images already
+// narrower than width are returned unchanged rather than upscaled.
+func Resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if width <= 0 || b.Dx() <= width {
+		return img
+	}
+	height := max(1, b.Dy()*width/b.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func decode(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+func isSource(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".webp":
+		return true
+	}
+	return false
+}
+
+// newerThan reports whether path exists and was modified after modNano.
+func newerThan(path string, modNano int64) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.ModTime().UnixNano() >= modNano
+}
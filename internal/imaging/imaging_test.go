@@ -0,0 +1,97 @@
+package imaging
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// sizeEncoder records the width of each encoded image instead of writing WebP.
+type sizeEncoder map[string]int
+
+func (e sizeEncoder) EncodeFile(path string, img image.Image) error {
+	e[path] = img.Bounds().Dx()
+	return os.WriteFile(path, []byte("webp"), 0o644)
+}
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	writePNG(t, filepath.Join(dir, "Ahri.png"), 400, 200)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	job := Job{Dir: dir, Widths: []int{64, 600}}
+
+	enc := sizeEncoder{}
+	res, err := Generate(context.Background(), job, enc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, large := filepath.Join(dir, "webp-64", "Ahri.webp"), filepath.Join(dir, "webp-600", "Ahri.webp")
+	if len(res.Generated) != 2 || enc[small] != 64 || enc[large] != 400 {
+		t.Fatalf("generated %v with widths %v, want 64 and an unscaled 400", res.Generated, enc)
+	}
+
+	// A second run leaves current variants alone.
+	enc = sizeEncoder{}
+	if res, err = Generate(context.Background(), job, enc, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Generated) != 0 || len(res.Current) != 2 {
+		t.Errorf("second run generated %v, current %v", res.Generated, res.Current)
+	}
+
+	// Touching the source makes its variants stale.
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "Ahri.png"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if res, err = Generate(context.Background(), job, enc, false); err != nil || len(res.Generated) != 2 {
+		t.Errorf("after touching the source: generated %v, err %v", res.Generated, err)
+	}
+}
+
+func TestRecordManifest(t *testing.T) {
+	static := t.TempDir()
+	manifest := filepath.Join(static, "dist", "manifest.json")
+	if err := os.MkdirAll(filepath.Dir(manifest), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifest, []byte(`{"app.js":"/dist/app-1.js"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	variant := VariantPath(filepath.Join(static, "assets", "Units", "Ahri.jpg"), 256)
+	if err := RecordManifest(manifest, static, []string{variant}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["app.js"] != "/dist/app-1.js" || got["assets/Units/webp-256/Ahri.webp"] != "/assets/Units/webp-256/Ahri.webp" {
+		t.Errorf("manifest = %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package imaging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RecordManifest adds each variant to the asset manifest, keyed by its path
+// relative to staticDir, e.g. "assets/Units/SET16/webp-256/Ahri.webp" maps
+// to "/assets/Units/SET16/webp-256/Ahri.webp". Existing entries such as
+// app.js are kept. A missing manifest is created.
+func RecordManifest(manifestPath, staticDir string, variants []string) error {
+	manifest := map[string]string{}
+	data, err := os.ReadFile(manifestPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("read manifest: %w", err)
+	default:
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return fmt.Errorf("parse manifest %s: %w", manifestPath, err)
+		}
+	}
+
+	for _, v := range variants {
+		rel, err := filepath.Rel(staticDir, v)
+		if err != nil {
+			return fmt.Errorf("manifest entry for %s: %w", v, err)
+		}
+		rel = filepath.ToSlash(rel)
+		manifest[rel] = "/" + rel
+	}
+
+	out, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath, append(out, '\n'), 0o644)
+}
@@ -0,0 +1,103 @@
+// Package logfile provides a log file that rotates itself by size.
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is an append-only log file. Once a write would take it past
+// MaxBytes it is renamed to <path>.1, older rotations shift up to
+// <path>.<Backups>, and a new file is started. It is safe for concurrent
+// use.
+type File struct {
+	path     string
+	maxBytes int64 // 0 never rotates
+	backups  int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// Open opens path for appending, creating it and its directory if needed.
+// maxBytes 0 disables rotation; backups is how many rotated files to keep.
+func Open(path string, maxBytes int64, backups int) (*File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("log file: %w", err)
+	}
+	l := &File{path: path, maxBytes: maxBytes, backups: backups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *File) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("log file: %w", err)
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would not fit. A single write
+// larger than the limit still goes into one file.
+func (l *File) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return 0, os.ErrClosed
+	}
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(p)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+func (l *File) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return fmt.Errorf("log file: %w", err)
+	}
+	l.f = nil
+	if l.backups <= 0 {
+		_ = os.Remove(l.path)
+	} else {
+		_ = os.Remove(l.backup(l.backups))
+		for i := l.backups - 1; i >= 1; i-- {
+			_ = os.Rename(l.backup(i), l.backup(i+1))
+		}
+		if err := os.Rename(l.path, l.backup(1)); err != nil {
+			return fmt.Errorf("log file: %w", err)
+		}
+	}
+	return l.open()
+}
+
+func (l *File) backup(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Close closes the file; later writes fail.
+func (l *File) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
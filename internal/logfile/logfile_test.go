@@ -0,0 +1,43 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "access.log")
+	f, err := Open(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		path:        "four\nfive\n",
+		path + ".1": "three\n",
+		path + ".2": "one\ntwo\n",
+	} {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept a third backup: %v", err)
+	}
+	if _, err := f.Write([]byte("late\n")); err == nil {
+		t.Error("write after Close succeeded")
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Access log formats accepted by AccessLog.
+const (
+	LogFormatJSON     = "json"     // one JSON object per request
+	LogFormatCombined = "combined" // Apache/NCSA combined log format
+)
+
+// AccessLog writes a line per request to out in format, once the handler
+// returns. Lines are written whole, so out may be shared with other
+// loggers. It returns an error for an unknown format.
+func AccessLog(format string, out io.Writer) (Middleware, error) {
+	var line func(e accessEntry) []byte
+	switch format {
+	case LogFormatJSON:
+		line = accessEntry.json
+	case LogFormatCombined:
+		line = accessEntry.combined
+	default:
+		return nil, fmt.Errorf("unknown log format %q, want %s or %s", format, LogFormatJSON, LogFormatCombined)
+	}
+
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				if rec.status == 0 {
+					// Hijacked connections and handlers that never wrote.
+					rec.status = http.StatusOK
+				}
+				b := line(accessEntry{r: r, start: start, took: time.Since(start), status: rec.status, bytes: rec.bytes})
+				mu.Lock()
+				defer mu.Unlock()
+				_, _ = out.Write(b)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}, nil
+}
+
+type accessEntry struct {
+	r      *http.Request
+	start  time.Time
+	took   time.Duration
+	status int
+	bytes  int64
+}
+
+func (e accessEntry) json() []byte {
+	b, _ := json.Marshal(struct {
+		Time       string  `json:"time"`
+		Remote     string  `json:"remote"`
+		Host       string  `json:"host"`
+		Method     string  `json:"method"`
+		URI        string  `json:"uri"`
+		Proto      string  `json:"proto"`
+		Status     int     `json:"status"`
+		Bytes      int64   `json:"bytes"`
+		DurationMS float64 `json:"duration_ms"`
+		Referer    string  `json:"referer,omitempty"`
+		UserAgent  string  `json:"user_agent,omitempty"`
+	}{
+		Time:       e.start.UTC().Format(time.RFC3339Nano),
+		Remote:     remoteHost(e.r.RemoteAddr),
+		Host:       e.r.Host,
+		Method:     e.r.Method,
+		URI:        e.r.RequestURI,
+		Proto:      e.r.Proto,
+		Status:     e.status,
+		Bytes:      e.bytes,
+		DurationMS: float64(e.took.Microseconds()) / 1000,
+		Referer:    e.r.Referer(),
+		UserAgent:  e.r.UserAgent(),
+	})
+	return append(b, '\n')
+}
+
+// combined renders e as
+//
+//	host - - [02/Jan/2006:15:04:05 -0700] "GET / HTTP/1.1" 200 1234 "referer" "agent"
+func (e accessEntry) combined() []byte {
+	size := "-"
+	if e.bytes > 0 {
+		size = strconv.FormatInt(e.bytes, 10)
+	}
+	return []byte(fmt.Sprintf("%s - - [%s] %s %d %s %s %s\n",
+		remoteHost(e.r.RemoteAddr),
+		e.start.Format("02/Jan/2006:15:04:05 -0700"),
+		strconv.Quote(e.r.Method+" "+e.r.RequestURI+" "+e.r.Proto),
+		e.status,
+		size,
+		quoteOrDash(e.r.Referer()),
+		quoteOrDash(e.r.UserAgent()),
+	))
+}
+
+// quoteOrDash quotes a header value, writing an absent one as "-".
+func quoteOrDash(v string) string {
+	if v == "" {
+		return `"-"`
+	}
+	return strconv.Quote(v)
+}
+
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// statusRecorder notes the status and body size a handler sends.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush passes through to the underlying writer, for streamed responses.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack passes through to the underlying writer, for protocol upgrades.
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		if w.status == 0 {
+			w.status = http.StatusSwitchingProtocols
+		}
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
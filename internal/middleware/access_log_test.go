@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	})
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/builder?set=16", nil)
+		r.RemoteAddr = "203.0.113.7:5123"
+		r.Header.Set("Referer", "https://example.com/")
+		r.Header.Set("User-Agent", `curl "8"`)
+		return r
+	}
+
+	t.Run("combined", func(t *testing.T) {
+		var out bytes.Buffer
+		mw, err := AccessLog(LogFormatCombined, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mw(handler).ServeHTTP(httptest.NewRecorder(), newRequest())
+		re := regexp.MustCompile(`^203\.0\.113\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /builder\?set=16 HTTP/1\.1" 418 15 "https://example.com/" "curl \\"8\\""\n$`)
+		if !re.MatchString(out.String()) {
+			t.Errorf("line = %q", out.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		mw, err := AccessLog(LogFormatJSON, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mw(handler).ServeHTTP(httptest.NewRecorder(), newRequest())
+		var entry map[string]any
+		if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q: %v", out.String(), err)
+		}
+		for key, want := range map[string]any{
+			"remote": "203.0.113.7", "method": "GET", "uri": "/builder?set=16",
+			"status": float64(418), "bytes": float64(15), "user_agent": `curl "8"`,
+		} {
+			if entry[key] != want {
+				t.Errorf("%s = %v, want %v", key, entry[key], want)
+			}
+		}
+	})
+
+	if _, err := AccessLog("xml", &bytes.Buffer{}); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DecompressBody accepts request bodies sent with Content-Encoding: gzip,
+// replacing them with the decoded stream so handlers read plain JSON. The
+// decoded body is capped at maxBytes, which bounds what a small compressed
+// upload can expand to. Uncompressed bodies get the same cap; any other
+// encoding is refused with 415.
+func DecompressBody(maxBytes int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+			case "", "identity":
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			case "gzip", "x-gzip":
+				zr, err := gzip.NewReader(http.MaxBytesReader(w, r.Body, maxBytes))
+				if err != nil {
+					http.Error(w, "Invalid gzip body", http.StatusBadRequest)
+					return
+				}
+				defer zr.Close()
+				r.Body = http.MaxBytesReader(w, readCloser{zr, r.Body}, maxBytes)
+				r.Header.Del("Content-Encoding")
+				r.Header.Del("Content-Length")
+				r.ContentLength = -1
+			default:
+				w.Header().Set("Accept-Encoding", "gzip")
+				http.Error(w, "Unsupported Content-Encoding", http.StatusUnsupportedMediaType)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// readCloser reads the decoded stream and closes the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressBody(t *testing.T) {
+	handler := DecompressBody(64)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Write(body)
+	}))
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		status   int
+		want     string
+	}{
+		{"plain", "", []byte(`{"boards":[]}`), http.StatusOK, `{"boards":[]}`},
+		{"gzip", "gzip", gzipped(t, `{"boards":["1~"]}`), http.StatusOK, `{"boards":["1~"]}`},
+		{"gzip bomb", "gzip", gzipped(t, strings.Repeat("a", 10000)), http.StatusRequestEntityTooLarge, ""},
+		{"plain too large", "", []byte(strings.Repeat("a", 100)), http.StatusRequestEntityTooLarge, ""},
+		{"corrupt gzip", "gzip", []byte("not gzip"), http.StatusBadRequest, ""},
+		{"unsupported", "br", []byte("x"), http.StatusUnsupportedMediaType, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comps/score/batch", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.want != "" && rec.Body.String() != tt.want {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
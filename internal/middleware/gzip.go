@@ -3,54 +3,173 @@ package middleware
 
 import (
 	"compress/gzip"
-	"io"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 )
 
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
 // Gzip wraps an http.Handler with gzip compression for text-based responses.
 // It skips compression for already compressed formats and HEAD requests.
+//
+// It also keeps conditional requests working across encodings. A compressed
+// body is not byte-for-byte the representation its handler tagged, so its
+// ETag is sent weak (W/"..."), as is the ETag of a 304 for that request.
+// If-None-Match is compared weakly per RFC 9110, so W/ prefixes are dropped
+// before handlers see the header, letting them match against their own
+// strong tags.
 func Gzip(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !shouldCompress(r) {
+		if !isCompressiblePath(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
-
-		w.Header().Set("Content-Encoding", "gzip")
+		// The body depends on Accept-Encoding even when this request gets
+		// the identity encoding.
 		w.Header().Add("Vary", "Accept-Encoding")
+		if !shouldCompress(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-		gzw := gzip.NewWriter(w)
-		defer gzw.Close()
-
-		wrapped := &gzipResponseWriter{
-			ResponseWriter: w,
-			writer:         gzw,
+		if inm := r.Header.Get("If-None-Match"); inm != "" {
+			r.Header.Set("If-None-Match", stripWeak(inm))
 		}
-		next.ServeHTTP(wrapped, r)
+
+		cw := &gzipResponseWriter{ResponseWriter: w}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
 	})
 }
 
-// gzipResponseWriter proxies writes through the gzip writer while preserving headers.
+// gzipResponseWriter compresses the body once the status is known, leaving
+// bodiless responses and already encoded ones untouched.
 type gzipResponseWriter struct {
 	http.ResponseWriter
-	writer io.Writer
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	switch {
+	case code == http.StatusNotModified:
+		// Validators must match those of the 200 this request would get.
+		weakenETag(h)
+	case bodyless(code) || h.Get("Content-Encoding") != "":
+	default:
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		weakenETag(h)
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(code)
 }
 
 func (w *gzipResponseWriter) Write(p []byte) (int, error) {
-	return w.writer.Write(p)
+	if !w.wroteHeader {
+		// Sniff before compressing, or net/http sniffs the gzip bytes.
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.gz.Write(p)
+}
+
+// Flush sends buffered compressed data to the client.
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the gzip stream and returns the writer to the pool.
+func (w *gzipResponseWriter) Close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(nil)
+	gzipWriters.Put(w.gz)
+	w.gz = nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// bodyless reports whether a response with code carries no body to encode.
+// Partial content is left alone too: its byte ranges refer to the identity
+// encoding.
+func bodyless(code int) bool {
+	return code < 200 || code == http.StatusNoContent || code == http.StatusPartialContent
+}
+
+// weakenETag marks a strong ETag as weak.
+func weakenETag(h http.Header) {
+	if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		h.Set("ETag", "W/"+etag)
+	}
+}
+
+// stripWeak removes W/ prefixes from an If-None-Match list.
+func stripWeak(inm string) string {
+	tags := strings.Split(inm, ",")
+	for i, tag := range tags {
+		tags[i] = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	}
+	return strings.Join(tags, ", ")
 }
 
 // shouldCompress determines if the request should receive a gzipped response.
 func shouldCompress(r *http.Request) bool {
-	if r.Method == http.MethodHead {
+	if r.Method == http.MethodHead || r.Header.Get("Range") != "" {
 		return false
 	}
-	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
-		return false
+	return AcceptsGzip(r.Header.Get("Accept-Encoding"))
+}
+
+// AcceptsGzip reports whether an Accept-Encoding header allows gzip,
+// honouring q=0 exclusions and the * wildcard.
+func AcceptsGzip(header string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "x-gzip" && coding != "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if coding == "*" {
+			// An explicit gzip entry wins over the wildcard.
+			if !strings.Contains(strings.ToLower(header), "gzip") {
+				accepted = q > 0
+			}
+			continue
+		}
+		accepted = q > 0
 	}
-	return isCompressiblePath(r.URL.Path)
+	return accepted
 }
 
 // isCompressiblePath returns true for text-like payloads where gzip provides real savings.
@@ -120,3 +120,85 @@ func TestIsCompressiblePath(t *testing.T) {
 		})
 	}
 }
+
+func TestGzip_ConditionalRequests(t *testing.T) {
+	const etag = `"abc123"`
+	handler := Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Length", "11")
+		w.Write([]byte("Hello World"))
+	}))
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		ifNoneMatch    string
+		status         int
+		etag           string
+		encoding       string
+	}{
+		{"compressed", "gzip", "", http.StatusOK, `W/"abc123"`, "gzip"},
+		{"identity", "", "", http.StatusOK, etag, ""},
+		{"compressed revalidation", "gzip, br", `W/"abc123"`, http.StatusNotModified, `W/"abc123"`, ""},
+		{"identity revalidation", "", etag, http.StatusNotModified, etag, ""},
+		{"stale", "gzip", `W/"old"`, http.StatusOK, `W/"abc123"`, "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.etag {
+				t.Errorf("ETag = %q, want %q", got, tt.etag)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.encoding)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if tt.encoding != "" && rec.Header().Get("Content-Length") != "" {
+				t.Error("Content-Length of the identity body leaked into the compressed response")
+			}
+			if tt.status == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a %d byte body", rec.Body.Len())
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"gzip", true},
+		{"gzip, deflate, br", true},
+		{"br;q=1.0, gzip;q=0.8", true},
+		{"gzip;q=0", false},
+		{"*", true},
+		{"*, gzip;q=0", false},
+		{"identity", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := AcceptsGzip(tt.header); got != tt.want {
+			t.Errorf("AcceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseProxies parses trusted proxy addresses, each a CIDR range such as
+// "10.0.0.0/8" or a single IP.
+func ParseProxies(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		if addr, err := netip.ParseAddr(s); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: not an IP or CIDR range", s)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// RealIP sets r.RemoteAddr to the client address that trusted reverse
+// proxies report in X-Forwarded-For. Only requests arriving from a
+// trusted proxy are rewritten, and the header is read from the right,
+// skipping trusted hops, so a client cannot pass off an address of its
+// choosing. With no trusted proxies it does nothing.
+func RealIP(trusted []netip.Prefix) Middleware {
+	isTrusted := func(s string) bool {
+		addr, err := netip.ParseAddr(strings.TrimSpace(s))
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		for _, p := range trusted {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, port, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil || !isTrusted(host) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+			for i := len(hops) - 1; i >= 0; i-- {
+				hop := strings.TrimSpace(hops[i])
+				if isTrusted(hop) {
+					continue
+				}
+				if addr, err := netip.ParseAddr(hop); err == nil {
+					r = r.Clone(r.Context())
+					r.RemoteAddr = net.JoinHostPort(addr.Unmap().String(), port)
+				}
+				break
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
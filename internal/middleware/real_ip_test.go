@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted, err := ParseProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	handler := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name, remote, forwarded, want string
+	}{
+		{"direct client", "203.0.113.7:5123", "", "203.0.113.7:5123"},
+		{"untrusted peer cannot forward", "203.0.113.7:5123", "198.51.100.1", "203.0.113.7:5123"},
+		{"trusted proxy", "10.1.2.3:443", "198.51.100.1", "198.51.100.1:443"},
+		{"proxy chain", "10.1.2.3:443", "198.51.100.1, 192.0.2.1", "198.51.100.1:443"},
+		{"spoofed hop ignored", "10.1.2.3:443", "1.1.1.1, 198.51.100.1", "198.51.100.1:443"},
+		{"garbage keeps proxy", "10.1.2.3:443", "unknown", "10.1.2.3:443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			if got != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an error for an invalid range")
+	}
+}
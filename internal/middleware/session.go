@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"sft/internal/store"
+)
+
+// DefaultSessionCookie names the session cookie unless SessionOptions
+// says otherwise. Logins live in the same session; see auth.Sessions.
+const DefaultSessionCookie = "sft_sid"
+
+// DefaultSessionTTL is how long an unchanged session lives.
+const DefaultSessionTTL = 180 * 24 * time.Hour
+
+// sessionSigLen truncates cookie signatures; 128 bits is plenty.
+const sessionSigLen = 16
+
+// SessionOptions configures Session.
+type SessionOptions struct {
+	Secret string        // HMAC key signing the cookie; empty uses a per-process key
+	Cookie string        // defaults to DefaultSessionCookie
+	TTL    time.Duration // defaults to DefaultSessionTTL; each change extends it
+	Secure bool          // set on HTTPS sites
+}
+
+type sessionKey struct{}
+
+// SessionData is the session of one request. It is loaded from the store
+// on first use and saved, with the cookie, before the response header is
+// sent, only if it changed. A nil *SessionData reads as empty and ignores
+// writes, so handlers need not check for the middleware.
+type SessionData struct {
+	load func() (string, map[string]string)
+
+	once      sync.Once
+	mu        sync.Mutex
+	id        string
+	stale     string // ID Renew replaced, deleted on save
+	values    map[string]string
+	changed   bool
+	destroyed bool
+}
+
+// SessionFrom returns the request's session, or nil outside Session.
+func SessionFrom(ctx context.Context) *SessionData {
+	s, _ := ctx.Value(sessionKey{}).(*SessionData)
+	return s
+}
+
+func (s *SessionData) init() {
+	s.once.Do(func() {
+		s.id, s.values = s.load()
+		if s.values == nil {
+			s.values = make(map[string]string)
+		}
+	})
+}
+
+// Get returns the value stored under key, or "".
+func (s *SessionData) Get(key string) string {
+	if s == nil {
+		return ""
+	}
+	s.init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// Set stores value under key; an empty value deletes the key.
+func (s *SessionData) Set(key, value string) {
+	if s == nil {
+		return
+	}
+	s.init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values[key] == value {
+		return
+	}
+	if value == "" {
+		delete(s.values, key)
+	} else {
+		s.values[key] = value
+	}
+	s.changed = true
+}
+
+// Renew keeps the session's values under a new ID, dropping the old one,
+// as a login should to defeat session fixation.
+func (s *SessionData) Renew() {
+	if s == nil {
+		return
+	}
+	s.init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.id != "" {
+		s.stale, s.id = s.id, ""
+	}
+	s.changed = true
+}
+
+// Destroy empties the session and removes it and its cookie.
+func (s *SessionData) Destroy() {
+	if s == nil {
+		return
+	}
+	s.init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]string)
+	s.destroyed = true
+}
+
+// Session gives each request a SessionData, see SessionFrom, kept in
+// sessions under a random ID that a signed, HttpOnly cookie carries.
+// Requests that never touch their session cost no store access, and
+// visitors get no cookie until something is stored for them.
+func Session(sessions store.SessionDataStore, opts SessionOptions) Middleware {
+	if opts.Cookie == "" {
+		opts.Cookie = DefaultSessionCookie
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultSessionTTL
+	}
+	key := []byte(opts.Secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic("middleware: generating session key: " + err.Error())
+		}
+	}
+	sign := func(id string) string {
+		m := hmac.New(sha256.New, key)
+		m.Write([]byte(id))
+		return base64.RawURLEncoding.EncodeToString(m.Sum(nil)[:sessionSigLen])
+	}
+	logger := log.Default()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			sess := &SessionData{load: func() (string, map[string]string) {
+				cookie, err := r.Cookie(opts.Cookie)
+				if err != nil {
+					return "", nil
+				}
+				id, sig, ok := strings.Cut(cookie.Value, ".")
+				if !ok || !hmac.Equal([]byte(sig), []byte(sign(id))) {
+					return "", nil
+				}
+				values, err := sessions.GetSessionData(ctx, id)
+				if err != nil {
+					if !errors.Is(err, store.ErrNotFound) {
+						logger.Printf("session load failed: %v", err)
+					}
+					// Keep the ID: a save recreates the expired session.
+					return id, nil
+				}
+				return id, values
+			}}
+
+			save := func() {
+				sess.mu.Lock()
+				defer sess.mu.Unlock()
+				cookie := &http.Cookie{
+					Name:     opts.Cookie,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   opts.Secure,
+					SameSite: http.SameSiteLaxMode,
+				}
+				if sess.stale != "" {
+					if err := sessions.DeleteSessionData(ctx, sess.stale); err != nil {
+						logger.Printf("session delete failed: %v", err)
+					}
+				}
+				switch {
+				case sess.destroyed:
+					if sess.id != "" {
+						if err := sessions.DeleteSessionData(ctx, sess.id); err != nil {
+							logger.Printf("session delete failed: %v", err)
+						}
+					}
+					cookie.MaxAge = -1
+				case sess.changed:
+					if sess.id == "" {
+						sess.id = newSessionID()
+					}
+					expires := time.Now().Add(opts.TTL)
+					if err := sessions.PutSessionData(ctx, sess.id, sess.values, expires); err != nil {
+						logger.Printf("session save failed: %v", err)
+						return
+					}
+					cookie.Value = sess.id + "." + sign(sess.id)
+					cookie.Expires = expires
+				default:
+					return
+				}
+				http.SetCookie(w, cookie)
+			}
+
+			sw := &sessionWriter{ResponseWriter: w, save: save}
+			next.ServeHTTP(sw, r.WithContext(context.WithValue(ctx, sessionKey{}, sess)))
+			sw.commit()
+		})
+	}
+}
+
+func newSessionID() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic("middleware: generating session ID: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sessionWriter saves the session before the header goes out, the last
+// moment its cookie can still be set.
+type sessionWriter struct {
+	http.ResponseWriter
+	save      func()
+	committed bool
+}
+
+func (w *sessionWriter) commit() {
+	if !w.committed {
+		w.committed = true
+		w.save()
+	}
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	w.commit()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(p []byte) (int, error) {
+	w.commit()
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends the header, and with it the session, then flushes.
+func (w *sessionWriter) Flush() {
+	w.commit()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *sessionWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
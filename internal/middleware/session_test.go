@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sft/internal/store"
+)
+
+func TestSession(t *testing.T) {
+	sessions := store.NewMemorySessionData()
+	mw := Session(sessions, SessionOptions{Secret: "test"})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := SessionFrom(r.Context())
+		switch r.URL.Path {
+		case "/set":
+			sess.Set("locale", r.URL.Query().Get("locale"))
+		case "/renew":
+			sess.Renew()
+		case "/destroy":
+			sess.Destroy()
+		}
+		w.Write([]byte(sess.Get("locale")))
+	}))
+	do := func(path string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	cookieOf := func(rec *httptest.ResponseRecorder) *http.Cookie {
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == DefaultSessionCookie {
+				return c
+			}
+		}
+		return nil
+	}
+
+	if rec := do("/", nil); cookieOf(rec) != nil {
+		t.Error("untouched session set a cookie")
+	}
+
+	rec := do("/set?locale=fr", nil)
+	cookie := cookieOf(rec)
+	if cookie == nil || !cookie.HttpOnly || !strings.Contains(cookie.Value, ".") {
+		t.Fatalf("cookie = %+v, want a signed HttpOnly cookie", cookie)
+	}
+
+	rec = do("/", cookie)
+	if rec.Body.String() != "fr" {
+		t.Errorf("next request read %q, want fr", rec.Body.String())
+	}
+	if cookieOf(rec) != nil {
+		t.Error("unchanged session rewrote its cookie")
+	}
+
+	forged := *cookie
+	forged.Value = strings.Split(cookie.Value, ".")[0] + ".forged"
+	if rec := do("/", &forged); rec.Body.String() != "" {
+		t.Errorf("forged cookie read %q", rec.Body.String())
+	}
+
+	rec = do("/renew", cookie)
+	renewed := cookieOf(rec)
+	if renewed == nil || renewed.Value == cookie.Value || rec.Body.String() != "fr" {
+		t.Fatalf("renew = %+v reading %q, want a new cookie keeping the values", renewed, rec.Body.String())
+	}
+	if rec := do("/", cookie); rec.Body.String() != "" {
+		t.Errorf("replaced session ID still reads %q", rec.Body.String())
+	}
+	cookie = renewed
+
+	rec = do("/destroy", cookie)
+	if c := cookieOf(rec); c == nil || c.MaxAge >= 0 {
+		t.Errorf("destroy cookie = %+v, want it expired", c)
+	}
+	if rec := do("/", cookie); rec.Body.String() != "" {
+		t.Errorf("destroyed session read %q", rec.Body.String())
+	}
+
+	var none *SessionData
+	none.Set("locale", "fr")
+	if none.Get("locale") != "" {
+		t.Error("nil session kept a value")
+	}
+}
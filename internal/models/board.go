@@ -6,10 +6,32 @@ type BoardLayout struct {
 	Cols int
 }
 
+// Board dimensions in the game. The bench sits on row BoardRows.
+const (
+	BoardRows  = 4
+	BoardCols  = 7
+	BenchSlots = 9
+)
+
 // BoardRow stores metadata for a single row.
 type BoardRow struct {
 	Index  int
 	Offset bool
+	Bench  bool // bench slots render as a flat row with their own styling
+	Hexes  []BoardHex
+}
+
+// BoardHex is a single cell of a row, optionally occupied by a unit.
+type BoardHex struct {
+	Col  int
+	Unit *PlacedUnit
+}
+
+// PlacedUnit is a unit resolved from a Placement for rendering.
+type PlacedUnit struct {
+	Unit  Unit
+	Stars int
+	Items []Item // slug-only until ResolveItems fills in the catalog entries
 }
 
 // BoardView is the shape passed to templates to render the board.
@@ -17,6 +39,7 @@ type BoardView struct {
 	Layout BoardLayout
 	Rows   []BoardRow
 	Cols   []int
+	Bench  *BoardRow // nil when the board has no bench
 }
 
 // NewBoardView builds a board description with computed offsets.
@@ -32,9 +55,14 @@ func NewBoardView(rows, cols int) BoardView {
 
 	boardRows := make([]BoardRow, rows)
 	for i := 0; i < rows; i++ {
+		hexes := make([]BoardHex, cols)
+		for c := range hexes {
+			hexes[c].Col = c
+		}
 		boardRows[i] = BoardRow{
 			Index:  i,
 			Offset: i%2 == 1,
+			Hexes:  hexes,
 		}
 	}
 
@@ -44,3 +72,88 @@ func NewBoardView(rows, cols int) BoardView {
 		Cols:   MakeRange(0, cols),
 	}
 }
+
+// WithBench returns a copy of the board with a bench row of the given size.
+// The bench is addressed as the row just below the last board row, so a
+// placement on row Layout.Rows lands on the bench.
+func (b BoardView) WithBench(slots int) BoardView {
+	if slots < 0 {
+		slots = 0
+	}
+	hexes := make([]BoardHex, slots)
+	for c := range hexes {
+		hexes[c].Col = c
+	}
+	b.Bench = &BoardRow{
+		Index: b.Layout.Rows,
+		Bench: true,
+		Hexes: hexes,
+	}
+	return b
+}
+
+// Place fills the board hexes from a BoardState, resolving unit slugs against units.
+// Placements outside the grid (and bench, if any) or referencing unknown units are skipped.
+func (b *BoardView) Place(state BoardState, units []Unit) {
+	bySlug := make(map[string]Unit, len(units))
+	for _, u := range units {
+		bySlug[u.Slug] = u
+	}
+
+	for _, p := range state.Placements {
+		var row *BoardRow
+		switch {
+		case p.Row >= 0 && p.Row < len(b.Rows):
+			row = &b.Rows[p.Row]
+		case b.Bench != nil && p.Row == b.Bench.Index:
+			row = b.Bench
+		default:
+			continue
+		}
+		if p.Col < 0 || p.Col >= len(row.Hexes) {
+			continue
+		}
+		unit, ok := bySlug[p.Unit]
+		if !ok {
+			continue
+		}
+		placed := &PlacedUnit{Unit: unit, Stars: clampStars(p.Stars)}
+		for _, slug := range p.Items {
+			if len(placed.Items) == MaxItems {
+				break
+			}
+			placed.Items = append(placed.Items, Item{Slug: slug})
+		}
+		row.Hexes[p.Col].Unit = placed
+	}
+}
+
+// ResolveItems replaces the placed units' item slugs with catalog entries.
+// Items missing from the catalog are dropped.
+func (b *BoardView) ResolveItems(catalog []Item) {
+	bySlug := make(map[string]Item, len(catalog))
+	for _, item := range catalog {
+		bySlug[item.Slug] = item
+	}
+
+	resolve := func(row *BoardRow) {
+		for _, hex := range row.Hexes {
+			if hex.Unit == nil {
+				continue
+			}
+			items := hex.Unit.Items[:0]
+			for _, item := range hex.Unit.Items {
+				if full, ok := bySlug[item.Slug]; ok {
+					items = append(items, full)
+				}
+			}
+			hex.Unit.Items = items
+		}
+	}
+	for i := range b.Rows {
+		resolve(&b.Rows[i])
+	}
+	if b.Bench != nil {
+		resolve(b.Bench)
+	}
+}
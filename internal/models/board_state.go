@@ -0,0 +1,160 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// boardStateVersion prefixes encoded board codes so the format can evolve.
+const boardStateVersion = "1"
+
+// Board code separators. All are URL-safe unreserved characters.
+const (
+	boardVersionSep   = "~"
+	boardPlacementSep = "."
+	boardItemSep      = "-"
+)
+
+// ErrInvalidBoardCode is returned when a board code cannot be decoded.
+var ErrInvalidBoardCode = errors.New("invalid board code")
+
+// Placement is a unit placed on a hex of the board.
+type Placement struct {
+	Row   int      `json:"row"`
+	Col   int      `json:"col"`
+	Unit  string   `json:"unit"`            // unit slug
+	Stars int      `json:"stars"`           // 1-3
+	Items []string `json:"items,omitempty"` // item slugs, at most MaxItems
+}
+
+// OnBoard reports whether p is on a hex of the board or a bench slot.
+func (p Placement) OnBoard() bool {
+	if p.Row == BoardRows {
+		return p.Col >= 0 && p.Col < BenchSlots
+	}
+	return p.Row >= 0 && p.Row < BoardRows && p.Col >= 0 && p.Col < BoardCols
+}
+
+// BoardState is the shareable description of a comp on the board.
+type BoardState struct {
+	Placements []Placement `json:"placements"`
+}
+
+// Encode serializes the board into a compact URL-safe string,
+// e.g. "1~032ahri-infinityedge.101garen".
+// Each placement is <row><col><stars><unit>[-<item>...].
+func (s BoardState) Encode() string {
+	parts := make([]string, 0, len(s.Placements))
+	for _, p := range s.Placements {
+		var b strings.Builder
+		b.WriteString(strconv.Itoa(p.Row))
+		b.WriteString(strconv.Itoa(p.Col))
+		b.WriteString(strconv.Itoa(clampStars(p.Stars)))
+		b.WriteString(p.Unit)
+		for i, item := range p.Items {
+			if i == MaxItems {
+				break
+			}
+			b.WriteString(boardItemSep)
+			b.WriteString(item)
+		}
+		parts = append(parts, b.String())
+	}
+	return boardStateVersion + boardVersionSep + strings.Join(parts, boardPlacementSep)
+}
+
+// DecodeBoardState parses a code produced by Encode.
+// Later placements on an already occupied hex replace earlier ones.
+func DecodeBoardState(code string) (BoardState, error) {
+	version, body, ok := strings.Cut(strings.TrimSpace(code), boardVersionSep)
+	if !ok || version != boardStateVersion {
+		return BoardState{}, fmt.Errorf("%w: unsupported version", ErrInvalidBoardCode)
+	}
+
+	var state BoardState
+	if body == "" {
+		return state, nil
+	}
+
+	occupied := make(map[[2]int]int)
+	for _, raw := range strings.Split(body, boardPlacementSep) {
+		p, err := decodePlacement(raw)
+		if err != nil {
+			return BoardState{}, err
+		}
+		key := [2]int{p.Row, p.Col}
+		if i, ok := occupied[key]; ok {
+			state.Placements[i] = p
+			continue
+		}
+		occupied[key] = len(state.Placements)
+		state.Placements = append(state.Placements, p)
+	}
+
+	return state, nil
+}
+
+func decodePlacement(raw string) (Placement, error) {
+	if len(raw) < 4 {
+		return Placement{}, fmt.Errorf("%w: placement %q too short", ErrInvalidBoardCode, raw)
+	}
+
+	digits := make([]int, 3)
+	for i := range digits {
+		c := raw[i]
+		if c < '0' || c > '9' {
+			return Placement{}, fmt.Errorf("%w: placement %q", ErrInvalidBoardCode, raw)
+		}
+		digits[i] = int(c - '0')
+	}
+	if digits[2] < 1 || digits[2] > 3 {
+		return Placement{}, fmt.Errorf("%w: star level %d", ErrInvalidBoardCode, digits[2])
+	}
+
+	fields := strings.Split(raw[3:], boardItemSep)
+	if len(fields)-1 > MaxItems {
+		return Placement{}, fmt.Errorf("%w: placement %q has more than %d items", ErrInvalidBoardCode, raw, MaxItems)
+	}
+	if !isSlug(fields[0]) {
+		return Placement{}, fmt.Errorf("%w: unit %q", ErrInvalidBoardCode, fields[0])
+	}
+
+	p := Placement{
+		Row:   digits[0],
+		Col:   digits[1],
+		Stars: digits[2],
+		Unit:  fields[0],
+	}
+	for _, item := range fields[1:] {
+		if !isSlug(item) {
+			return Placement{}, fmt.Errorf("%w: item %q", ErrInvalidBoardCode, item)
+		}
+		p.Items = append(p.Items, item)
+	}
+	return p, nil
+}
+
+// isSlug reports whether s is a non-empty lowercase alphanumeric slug.
+func isSlug(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func clampStars(stars int) int {
+	if stars < 1 {
+		return 1
+	}
+	if stars > MaxStars {
+		return MaxStars
+	}
+	return stars
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBoardState_EncodeDecodeRoundTrip(t *testing.T) {
+	state := BoardState{Placements: []Placement{
+		{Row: 0, Col: 3, Unit: "ahri", Stars: 2, Items: []string{"infinityedge", "bloodthirster"}},
+		{Row: 1, Col: 0, Unit: "garen", Stars: 1},
+	}}
+
+	code := state.Encode()
+	if code != "1~032ahri-infinityedge-bloodthirster.101garen" {
+		t.Errorf("unexpected code %q", code)
+	}
+
+	decoded, err := DecodeBoardState(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded.Placements) != 2 {
+		t.Fatalf("expected 2 placements, got %d", len(decoded.Placements))
+	}
+	first := decoded.Placements[0]
+	if first.Row != 0 || first.Col != 3 || first.Unit != "ahri" || first.Stars != 2 || len(first.Items) != 2 {
+		t.Errorf("unexpected first placement: %+v", first)
+	}
+}
+
+func TestDecodeBoardState_Empty(t *testing.T) {
+	state, err := DecodeBoardState("1~")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Placements) != 0 {
+		t.Error("expected no placements")
+	}
+}
+
+func TestDecodeBoardState_LastPlacementWins(t *testing.T) {
+	state, err := DecodeBoardState("1~001ahri.003garen")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Placements) != 1 || state.Placements[0].Unit != "garen" {
+		t.Errorf("expected garen to replace ahri, got %+v", state.Placements)
+	}
+}
+
+func TestDecodeBoardState_Invalid(t *testing.T) {
+	codes := []string{
+		"",
+		"2~001ahri",
+		"1~00",
+		"1~x01ahri",
+		"1~004ahri",
+		"1~001Ahri",
+		"1~001ahri-",
+		"1~001ahri-a-b-c-d",
+	}
+
+	for _, code := range codes {
+		t.Run(code, func(t *testing.T) {
+			_, err := DecodeBoardState(code)
+			if !errors.Is(err, ErrInvalidBoardCode) {
+				t.Errorf("expected ErrInvalidBoardCode, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPlacement_OnBoard(t *testing.T) {
+	tests := []struct {
+		row, col int
+		want     bool
+	}{
+		{0, 0, true},
+		{3, 6, true},
+		{0, 7, false},
+		{4, 8, true}, // last bench slot
+		{4, 9, false},
+		{5, 0, false},
+	}
+	for _, tt := range tests {
+		if got := (Placement{Row: tt.row, Col: tt.col}).OnBoard(); got != tt.want {
+			t.Errorf("(%d,%d).OnBoard() = %v, want %v", tt.row, tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestBoardView_Place(t *testing.T) {
+	board := NewBoardView(4, 7)
+	units := []Unit{{Name: "Ahri", Slug: "ahri"}}
+	state := BoardState{Placements: []Placement{
+		{Row: 2, Col: 5, Unit: "ahri", Stars: 3},
+		{Row: 9, Col: 0, Unit: "ahri", Stars: 1},
+		{Row: 0, Col: 0, Unit: "unknown", Stars: 1},
+	}}
+
+	board.Place(state, units)
+
+	placed := board.Rows[2].Hexes[5].Unit
+	if placed == nil || placed.Unit.Name != "Ahri" || placed.Stars != 3 {
+		t.Errorf("expected 3-star Ahri at (2,5), got %+v", placed)
+	}
+	if board.Rows[0].Hexes[0].Unit != nil {
+		t.Error("unknown units should be skipped")
+	}
+}
+
+func TestBoardView_PlaceBench(t *testing.T) {
+	units := []Unit{{Name: "Ahri", Slug: "ahri"}}
+	state := BoardState{Placements: []Placement{
+		{Row: 4, Col: 8, Unit: "ahri", Stars: 2},
+		{Row: 4, Col: 9, Unit: "ahri", Stars: 1},
+	}}
+
+	board := NewBoardView(4, 7).WithBench(BenchSlots)
+	board.Place(state, units)
+
+	if !board.Bench.Bench || board.Bench.Offset || len(board.Bench.Hexes) != BenchSlots {
+		t.Fatalf("unexpected bench row %+v", board.Bench)
+	}
+	if placed := board.Bench.Hexes[8].Unit; placed == nil || placed.Stars != 2 {
+		t.Errorf("expected 2-star Ahri on bench slot 8, got %+v", placed)
+	}
+
+	plain := NewBoardView(4, 7)
+	plain.Place(state, units)
+	if plain.Bench != nil {
+		t.Error("board without bench should stay without bench")
+	}
+}
+
+func TestBoardView_ResolveItems(t *testing.T) {
+	board := NewBoardView(4, 7)
+	board.Place(BoardState{Placements: []Placement{
+		{Row: 0, Col: 0, Unit: "ahri", Stars: 1, Items: []string{"infinityedge", "unknown", "bloodthirster"}},
+	}}, []Unit{{Name: "Ahri", Slug: "ahri"}})
+
+	board.ResolveItems([]Item{
+		{Name: "Infinity Edge", Slug: "infinityedge"},
+		{Name: "Bloodthirster", Slug: "bloodthirster"},
+	})
+
+	items := board.Rows[0].Hexes[0].Unit.Items
+	if len(items) != 2 || items[0].Name != "Infinity Edge" || items[1].Name != "Bloodthirster" {
+		t.Errorf("unexpected resolved items %+v", items)
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+// BoardTableRow is one occupied board row in the no-JavaScript view of a
+// board: the units listed left to right.
+type BoardTableRow struct {
+	Row   int // board row index, or Layout.Rows for the bench
+	Bench bool
+	Units []BoardTableUnit
+}
+
+// Number is the 1-based row number shown to readers.
+func (r BoardTableRow) Number() int {
+	return r.Row + 1
+}
+
+// BoardTableUnit is a placed unit as plain data.
+type BoardTableUnit struct {
+	Col   int
+	Name  string
+	Slug  string
+	Cost  int
+	Stars int
+	Items []string // item names, or slugs before ResolveItems
+}
+
+// Table lists the placed units row by row, bench last, skipping empty rows.
+// It backs the <noscript> board that crawlers and script-less visitors see.
+func (b BoardView) Table() []BoardTableRow {
+	var rows []BoardTableRow
+	add := func(r BoardRow) {
+		out := BoardTableRow{Row: r.Index, Bench: r.Bench}
+		for _, hex := range r.Hexes {
+			if hex.Unit == nil {
+				continue
+			}
+			u := BoardTableUnit{
+				Col:   hex.Col,
+				Name:  hex.Unit.Unit.Name,
+				Slug:  hex.Unit.Unit.Slug,
+				Cost:  hex.Unit.Unit.Cost,
+				Stars: hex.Unit.Stars,
+			}
+			for _, item := range hex.Unit.Items {
+				name := item.Name
+				if name == "" {
+					name = item.Slug
+				}
+				u.Items = append(u.Items, name)
+			}
+			out.Units = append(out.Units, u)
+		}
+		if len(out.Units) > 0 {
+			rows = append(rows, out)
+		}
+	}
+	for _, r := range b.Rows {
+		add(r)
+	}
+	if b.Bench != nil {
+		add(*b.Bench)
+	}
+	return rows
+}
@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+func TestBoardView_Table(t *testing.T) {
+	units := []Unit{
+		{Name: "Sion", Slug: "sion", Cost: 4},
+		{Name: "Lux", Slug: "lux", Cost: 2},
+	}
+	board := NewBoardView(BoardRows, BoardCols).WithBench(BenchSlots)
+	board.Place(BoardState{Placements: []Placement{
+		{Row: 3, Col: 5, Unit: "lux", Stars: 2, Items: []string{"bluebuff"}},
+		{Row: 0, Col: 2, Unit: "sion", Stars: 1},
+		{Row: 3, Col: 1, Unit: "sion", Stars: 3},
+		{Row: BoardRows, Col: 0, Unit: "lux", Stars: 1},
+	}}, units)
+	board.ResolveItems([]Item{{Name: "Blue Buff", Slug: "bluebuff"}})
+
+	rows := board.Table()
+
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3 (empty rows skipped): %+v", len(rows), rows)
+	}
+	if rows[0].Row != 0 || len(rows[0].Units) != 1 || rows[0].Units[0].Name != "Sion" {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	back := rows[1]
+	if back.Row != 3 || len(back.Units) != 2 || back.Units[0].Col != 1 || back.Units[1].Col != 5 {
+		t.Errorf("units should be listed left to right: %+v", back)
+	}
+	if lux := back.Units[1]; lux.Stars != 2 || len(lux.Items) != 1 || lux.Items[0] != "Blue Buff" {
+		t.Errorf("unexpected lux entry %+v", lux)
+	}
+	if bench := rows[2]; !bench.Bench || bench.Units[0].Slug != "lux" {
+		t.Errorf("bench should come last: %+v", bench)
+	}
+}
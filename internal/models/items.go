@@ -0,0 +1,25 @@
+package models
+
+// MaxItems is the number of item slots a unit has.
+const MaxItems = 3
+
+// Item is an equippable item.
+type Item struct {
+	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
+	APIName     string   `json:"apiName"`
+	Icon        string   `json:"icon,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Components  []string `json:"components,omitempty"` // component item API names
+}
+
+// Initial returns the first letter of the item name, used when no icon exists.
+func (i Item) Initial() string {
+	for _, r := range i.Name {
+		return string(r)
+	}
+	for _, r := range i.Slug {
+		return string(r)
+	}
+	return "?"
+}
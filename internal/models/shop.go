@@ -0,0 +1,31 @@
+package models
+
+// ShopLevel is the chance, in percent, of each cost appearing in a shop slot
+// at a player level. Odds[i] is the chance for cost i+1.
+type ShopLevel struct {
+	Level int   `json:"level"`
+	Odds  []int `json:"odds"`
+}
+
+// CostPool is the shared champion pool for one cost.
+type CostPool struct {
+	Cost      int `json:"cost"`
+	Copies    int `json:"copies"`    // copies of each champion
+	Champions int `json:"champions"` // distinct champions of this cost in the pool
+	Total     int `json:"total"`     // Copies * Champions
+}
+
+// ShopOdds holds roll odds per level and the champion pool sizes.
+type ShopOdds struct {
+	Levels []ShopLevel `json:"levels"`
+	Pools  []CostPool  `json:"pools"`
+}
+
+// Costs returns the shop costs in order, e.g. [1 2 3 4 5].
+func (s ShopOdds) Costs() []int {
+	n := 0
+	for _, l := range s.Levels {
+		n = max(n, len(l.Odds))
+	}
+	return MakeRange(1, n+1)
+}
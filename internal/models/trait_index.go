@@ -0,0 +1,98 @@
+package models
+
+import "sort"
+
+// TraitGroup is one trait of the synergy sidebar with its resolved members.
+type TraitGroup struct {
+	TraitInfo
+	Members []Unit           // units carrying the trait, cheapest first
+	Count   int              // unique units with the trait on the board
+	Tier    *TraitBreakpoint // highest reached breakpoint; nil when inactive
+	Next    int              // unit count of the next breakpoint; 0 when none is left
+}
+
+// Active reports whether the board reaches at least the first breakpoint.
+func (g TraitGroup) Active() bool { return g.Tier != nil }
+
+// Style returns the style of the reached tier, StyleNone when inactive.
+func (g TraitGroup) Style() TraitStyle {
+	if g.Tier == nil {
+		return StyleNone
+	}
+	return g.Tier.Style
+}
+
+// TraitIndex is the server-rendered payload of the synergy sidebar.
+type TraitIndex struct {
+	Groups []TraitGroup
+}
+
+// Fielded returns the groups with at least one unit on the board.
+func (idx TraitIndex) Fielded() []TraitGroup {
+	var out []TraitGroup
+	for _, g := range idx.Groups {
+		if g.Count > 0 {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// NewTraitIndex groups units under their traits and counts the board's synergies.
+// Groups on the board come first (active, then by count), the rest by name.
+func NewTraitIndex(traits []TraitInfo, units []Unit, board BoardView) TraitIndex {
+	bySlug := make(map[string]Unit, len(units))
+	for _, u := range units {
+		bySlug[u.Slug] = u
+	}
+	counts := board.TraitCounts()
+
+	groups := make([]TraitGroup, 0, len(traits))
+	for _, t := range traits {
+		g := TraitGroup{TraitInfo: t, Count: counts[t.Slug]}
+		for _, slug := range t.Units {
+			if u, ok := bySlug[slug]; ok {
+				g.Members = append(g.Members, u)
+			}
+		}
+		sort.SliceStable(g.Members, func(i, j int) bool {
+			if g.Members[i].Cost != g.Members[j].Cost {
+				return g.Members[i].Cost < g.Members[j].Cost
+			}
+			return g.Members[i].Name < g.Members[j].Name
+		})
+		g.Tier, g.Next = ActiveBreakpoint(t.Breakpoints, g.Count)
+		groups = append(groups, g)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Active() != b.Active() {
+			return a.Active()
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	return TraitIndex{Groups: groups}
+}
+
+// TraitCounts returns the number of unique units per trait slug on the board.
+// Bench units do not count toward synergies.
+func (b BoardView) TraitCounts() map[string]int {
+	seen := make(map[string]bool)
+	counts := make(map[string]int)
+	for _, row := range b.Rows {
+		for _, hex := range row.Hexes {
+			if hex.Unit == nil || seen[hex.Unit.Unit.Slug] {
+				continue
+			}
+			seen[hex.Unit.Unit.Slug] = true
+			for _, t := range hex.Unit.Unit.Traits {
+				counts[t.Slug]++
+			}
+		}
+	}
+	return counts
+}
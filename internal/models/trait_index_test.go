@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestNewTraitIndex(t *testing.T) {
+	bruiser := Trait{Name: "Bruiser", Slug: "bruiser"}
+	sorc := Trait{Name: "Sorcerer", Slug: "sorcerer"}
+	units := []Unit{
+		{Name: "Sion", Slug: "sion", Cost: 4, Traits: []Trait{bruiser}},
+		{Name: "Cho'Gath", Slug: "chogath", Cost: 1, Traits: []Trait{bruiser}},
+		{Name: "Lux", Slug: "lux", Cost: 2, Traits: []Trait{sorc}},
+	}
+	traits := []TraitInfo{
+		{Name: "Bruiser", Slug: "bruiser", Units: []string{"sion", "chogath"},
+			Breakpoints: []TraitBreakpoint{{MinUnits: 2, Style: StyleBronze}, {MinUnits: 4, Style: StyleGold}}},
+		{Name: "Sorcerer", Slug: "sorcerer", Units: []string{"lux"},
+			Breakpoints: []TraitBreakpoint{{MinUnits: 2}}},
+	}
+	board := NewBoardView(4, 7).WithBench(BenchSlots)
+	board.Place(BoardState{Placements: []Placement{
+		{Row: 0, Col: 0, Unit: "sion", Stars: 1},
+		{Row: 0, Col: 1, Unit: "chogath", Stars: 1},
+		{Row: 1, Col: 0, Unit: "chogath", Stars: 1}, // duplicates count once
+		{Row: 4, Col: 0, Unit: "lux", Stars: 1},     // bench does not count
+	}}, units)
+
+	idx := NewTraitIndex(traits, units, board)
+
+	if len(idx.Groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(idx.Groups))
+	}
+	first := idx.Groups[0]
+	if first.Slug != "bruiser" || first.Count != 2 || !first.Active() || first.Next != 4 {
+		t.Errorf("unexpected bruiser group %+v", first)
+	}
+	if got := first.Style().String(); got != "bronze" {
+		t.Errorf("bruiser style = %q, want bronze", got)
+	}
+	if first.Members[0].Slug != "chogath" || first.Members[1].Slug != "sion" {
+		t.Errorf("members not sorted by cost: %v, %v", first.Members[0].Slug, first.Members[1].Slug)
+	}
+	if second := idx.Groups[1]; second.Count != 0 || second.Active() || second.Next != 2 || second.Style() != StyleNone {
+		t.Errorf("unexpected sorcerer group %+v", second)
+	}
+	if fielded := idx.Fielded(); len(fielded) != 1 {
+		t.Errorf("Fielded() = %d groups, want 1", len(fielded))
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+// TraitStyle is the in-game color of an active trait tier, using the
+// set data's numeric style codes.
+type TraitStyle int
+
+// Trait styles as numbered in the set data.
+const (
+	StyleNone      TraitStyle = 0
+	StyleBronze    TraitStyle = 1
+	StyleSilver    TraitStyle = 3
+	StyleUnique    TraitStyle = 4 // single-unit traits
+	StyleGold      TraitStyle = 5
+	StylePrismatic TraitStyle = 6
+)
+
+// String returns the style name used by templates and CSS, e.g. "gold".
+// Unknown codes render as "none".
+func (s TraitStyle) String() string {
+	switch s {
+	case StyleBronze:
+		return "bronze"
+	case StyleSilver:
+		return "silver"
+	case StyleUnique:
+		return "unique"
+	case StyleGold:
+		return "gold"
+	case StylePrismatic:
+		return "prismatic"
+	}
+	return "none"
+}
+
+// TraitBreakpoint is a unit count at which a trait activates a new tier.
+type TraitBreakpoint struct {
+	MinUnits int        `json:"minUnits"`
+	MaxUnits int        `json:"maxUnits,omitempty"` // 0 when the tier has no upper bound
+	Style    TraitStyle `json:"style"`
+	Effect   string     `json:"effect,omitempty"` // what the tier grants, as plain text
+}
+
+// TraitInfo describes a trait and the units that carry it.
+type TraitInfo struct {
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Icon        string            `json:"icon"`
+	Description string            `json:"description,omitempty"`
+	Breakpoints []TraitBreakpoint `json:"breakpoints,omitempty"`
+	Units       []string          `json:"units"` // unit slugs, in dataset order
+}
+
+// ActiveBreakpoint returns the highest breakpoint reached by count and the
+// unit count of the next one (0 when none is left). Breakpoints are
+// expected in ascending MinUnits order, as the set data lists them.
+func ActiveBreakpoint(bps []TraitBreakpoint, count int) (tier *TraitBreakpoint, next int) {
+	for i := range bps {
+		if count >= bps[i].MinUnits {
+			tier = &bps[i]
+		} else if next == 0 {
+			next = bps[i].MinUnits
+		}
+	}
+	return tier, next
+}
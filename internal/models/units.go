@@ -2,6 +2,16 @@ package models
 
 type VariableType string
 
+// Variable types the formatter colors when the source gives no CSS class.
+// Types are matched case-insensitively.
+const (
+	VariableMagicDamage    VariableType = "Magic Damage"
+	VariablePhysicalDamage VariableType = "Physical Damage"
+	VariableTrueDamage     VariableType = "True Damage"
+	VariableHeal           VariableType = "Heal"
+	VariableShield         VariableType = "Shield"
+)
+
 // AbilityVariable represents a variable in ability description
 type AbilityVariable struct {
 	Name          string       `json:"name"`
@@ -25,10 +35,15 @@ type Ability struct {
 // Trait represents a TFT trait/synergy
 type Trait struct {
 	Name string `json:"name"`
+	Slug string `json:"slug"`
 	Icon string `json:"icon"`
 }
 
-// UnitStats holds the base stats shown in the tooltip.
+// MaxStars is the highest star level a unit can reach.
+const MaxStars = 3
+
+// UnitStats holds the base stats shown in the tooltip. HP and Damage hold
+// one value per star level, 1★ first.
 type UnitStats struct {
 	HP             []int   `json:"hp"`
 	Damage         []int   `json:"damage"`
@@ -46,6 +61,8 @@ type UnitStats struct {
 // Unit represents a TFT unit/champion
 type Unit struct {
 	Name              string    `json:"name"`
+	Slug              string    `json:"slug"`
+	APIName           string    `json:"apiName"`
 	Cost              int       `json:"cost"`
 	URL               string    `json:"url"`
 	Traits            []Trait   `json:"traits"`
@@ -54,11 +71,28 @@ type Unit struct {
 	UnlockDescription string    `json:"unlockDescription"`
 	Role              string    `json:"role"`
 	Stats             UnitStats `json:"stats"`
+	RecommendedItems  []Item    `json:"recommendedItems,omitempty"` // best-in-slot items, best first
 }
 
 // UnitsData contains the complete list of units
 type UnitsData struct {
-	Units []Unit `json:"units"`
+	Version string      `json:"version"` // content hash of the source dataset
+	Units   []Unit      `json:"units"`
+	Traits  []TraitInfo `json:"traits"`
+	Items   []Item      `json:"items,omitempty"`
+	Shop    *ShopOdds   `json:"shop,omitempty"`
+
+	// AssetsVersion fingerprints the icon and portrait paths found on disk.
+	// It changes when asset files are added or renamed without a new dataset.
+	AssetsVersion string `json:"-"`
+}
+
+// Revision identifies everything pages render from the data, for ETags.
+func (d *UnitsData) Revision() string {
+	if d.AssetsVersion == "" {
+		return d.Version
+	}
+	return d.Version + "+" + d.AssetsVersion
 }
 
 // MakeRange generates a slice of integers from min to max (exclusive)
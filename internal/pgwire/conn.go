@@ -0,0 +1,351 @@
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// conn is one server connection. database/sql uses it from one goroutine
+// at a time. After an I/O error or a cancelled exchange the protocol
+// state is unknown, so the connection is marked bad and the pool drops it.
+type conn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	bad     bool
+}
+
+var (
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+)
+
+type column struct {
+	name string
+	oid  int
+}
+
+// result is everything the server sent back for one exchange.
+type result struct {
+	columns []column
+	rows    [][]driver.Value
+	tag     string // CommandComplete tag, e.g. "DELETE 3"
+}
+
+// watch interrupts the connection's I/O when ctx is done. stop reports
+// whether that happened.
+func (c *conn) watch(ctx context.Context) (stop func() bool) {
+	if ctx.Done() == nil {
+		return func() bool { return false }
+	}
+	nc := c.netConn // startTLS may replace c.netConn; deadlines pass through
+	done := make(chan struct{})
+	fired := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			nc.SetDeadline(time.Unix(1, 0))
+			fired <- true
+		case <-done:
+			fired <- false
+		}
+	}()
+	return func() bool {
+		close(done)
+		if <-fired {
+			nc.SetDeadline(time.Time{})
+			return true
+		}
+		return false
+	}
+}
+
+// exchange sends msgs, which end in Sync or are a simple Query, and reads
+// up to ReadyForQuery.
+func (c *conn) exchange(ctx context.Context, msgs []byte) (*result, error) {
+	if c.bad {
+		return nil, driver.ErrBadConn
+	}
+	stop := c.watch(ctx)
+	res, err := c.roundTrip(msgs)
+	if stop() && err != nil {
+		c.bad = true
+		return nil, ctx.Err()
+	}
+	return res, err
+}
+
+func (c *conn) roundTrip(msgs []byte) (*result, error) {
+	if _, err := c.netConn.Write(msgs); err != nil {
+		c.bad = true
+		return nil, err
+	}
+	res := &result{}
+	var firstErr error
+	for {
+		typ, body, err := readMessage(c.r)
+		if err != nil {
+			c.bad = true
+			return nil, err
+		}
+		r := &reader{b: body}
+		switch typ {
+		case msgRowDescription:
+			res.columns = make([]column, r.int16())
+			for i := range res.columns {
+				res.columns[i].name = r.string()
+				r.next(6) // table OID and column number
+				res.columns[i].oid = r.int32()
+				r.next(8) // type size, modifier and format
+			}
+		case msgDataRow:
+			row := make([]driver.Value, r.int16())
+			for i := range row {
+				n := r.int32()
+				if n < 0 || i >= len(res.columns) {
+					continue
+				}
+				v, err := decodeValue(res.columns[i].oid, r.next(n))
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				row[i] = v
+			}
+			res.rows = append(res.rows, row)
+		case msgCommandComplete:
+			res.tag = r.string()
+		case msgError:
+			if firstErr == nil {
+				firstErr = parseError(body)
+			}
+		case msgReady:
+			return res, firstErr
+		case msgParseComplete, msgBindComplete, msgNoData, msgEmptyQuery,
+			msgNotice, msgParameterStatus, msgNotification:
+		default:
+			c.bad = true
+			return nil, fmt.Errorf("pgwire: unexpected message %q", typ)
+		}
+		if r.err != nil {
+			c.bad = true
+			return nil, r.err
+		}
+	}
+}
+
+// simpleQuery runs query, which may hold several statements, without
+// parameters.
+func (c *conn) simpleQuery(ctx context.Context, query string) (*result, error) {
+	return c.exchange(ctx, newMessage(msgQuery).string(query).done())
+}
+
+// extendedQuery runs query with args as $1, $2, ... through the unnamed
+// statement and portal, letting the server infer the parameter types.
+func (c *conn) extendedQuery(ctx context.Context, query string, args []driver.NamedValue) (*result, error) {
+	bind := newMessage(msgBind).string("").string("").int16(len(args))
+	values := make([][]byte, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, fmt.Errorf("pgwire: named parameter %q; use $%d", a.Name, a.Ordinal)
+		}
+		v, format, err := encodeParam(a.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+		bind = bind.int16(format)
+	}
+	bind = bind.int16(len(args))
+	for _, v := range values {
+		if v == nil {
+			bind = bind.int32(-1)
+			continue
+		}
+		bind = bind.int32(len(v)).bytes(v)
+	}
+	bind = bind.int16(0) // all results in text format
+
+	var msgs []byte
+	msgs = append(msgs, newMessage(msgParse).string("").string(query).int16(0).done()...)
+	msgs = append(msgs, bind.done()...)
+	msgs = append(msgs, append(newMessage(msgDescribe), 'P').string("").done()...)
+	msgs = append(msgs, newMessage(msgExecute).string("").int32(0).done()...)
+	msgs = append(msgs, newMessage(msgSync).done()...)
+	return c.exchange(ctx, msgs)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var res *result
+	var err error
+	if len(args) == 0 {
+		res, err = c.simpleQuery(ctx, query)
+	} else {
+		res, err = c.extendedQuery(ctx, query, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowsAffected(res.tag), nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := c.extendedQuery(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{result: res}, nil
+}
+
+// rowsAffected reads the count at the end of tags such as "INSERT 0 1"
+// and "UPDATE 3".
+func rowsAffected(tag string) driver.Result {
+	n, _ := strconv.ParseInt(tag[strings.LastIndexByte(tag, ' ')+1:], 10, 64)
+	return driver.RowsAffected(n)
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	_, err := c.simpleQuery(ctx, "")
+	return err
+}
+
+func (c *conn) ResetSession(context.Context) error {
+	if c.bad {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool { return !c.bad }
+
+// Close says goodbye to the server and closes the connection.
+func (c *conn) Close() error {
+	if !c.bad {
+		c.netConn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.netConn.Write(newMessage(msgTerminate).done())
+	}
+	return c.netConn.Close()
+}
+
+var errTxRolledBack = errors.New("pgwire: transaction was rolled back after an error")
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	begin := "BEGIN"
+	switch level := sql.IsolationLevel(opts.Isolation); level {
+	case sql.LevelDefault:
+	case sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+		begin += " ISOLATION LEVEL " + strings.ToUpper(level.String())
+	default:
+		return nil, fmt.Errorf("pgwire: unsupported isolation level %v", level)
+	}
+	if opts.ReadOnly {
+		begin += " READ ONLY"
+	}
+	if _, err := c.simpleQuery(ctx, begin); err != nil {
+		return nil, err
+	}
+	return tx{c}, nil
+}
+
+type tx struct{ c *conn }
+
+// Commit ends the transaction. The server answers COMMIT in a failed
+// transaction with a rollback, which is reported as an error.
+func (t tx) Commit() error {
+	res, err := t.c.simpleQuery(context.Background(), "COMMIT")
+	if err != nil {
+		return err
+	}
+	if res.tag == "ROLLBACK" {
+		return errTxRolledBack
+	}
+	return nil
+}
+
+func (t tx) Rollback() error {
+	_, err := t.c.simpleQuery(context.Background(), "ROLLBACK")
+	return err
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return c.Prepare(query)
+}
+
+// stmt only remembers its query: every execution parses it again through
+// the unnamed statement.
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), named(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.c.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.c.QueryContext(ctx, s.query, args)
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
+	}
+	return nv
+}
+
+// rows iterates over a result already read in full.
+type rows struct {
+	*result
+	next int
+}
+
+func (r *rows) Columns() []string {
+	names := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
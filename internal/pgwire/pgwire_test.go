@@ -0,0 +1,349 @@
+package pgwire
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// fakePostgres declines TLS, authenticates "sft" with SCRAM-SHA-256 and
+// password "hunter2", and answers a few canned queries:
+//
+//   - SELECT with $n::type casts echoes its parameters with those types;
+//   - DELETE reports 3 rows;
+//   - anything containing "dup" fails with a unique violation;
+//   - anything containing "pg_sleep" never answers.
+//
+// It records every query it is sent.
+func fakePostgres(t *testing.T) (addr string, queries func() []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	var seen []string
+	record := func(q string) {
+		mu.Lock()
+		seen = append(seen, q)
+		mu.Unlock()
+	}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				serveFake(c, record)
+			}()
+		}
+	}()
+	return ln.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+var castPattern = regexp.MustCompile(`\$(\d+)::(\w+)`)
+
+var castOIDs = map[string]int{
+	"int8": oidInt8, "bool": oidBool, "timestamptz": oidTimestamptz,
+	"bytea": oidBytea, "text": 25, "float8": oidFloat8,
+}
+
+func serveFake(c net.Conn, record func(string)) {
+	r := bufio.NewReader(c)
+	readUntyped := func() []byte {
+		var n uint32
+		if binary.Read(r, binary.BigEndian, &n) != nil {
+			return nil
+		}
+		body := make([]byte, n-4)
+		io.ReadFull(r, body)
+		return body
+	}
+	send := func(m message) { c.Write(m.done()) }
+
+	startup := readUntyped()
+	if binary.BigEndian.Uint32(startup) == sslRequestCode {
+		c.Write([]byte{'N'})
+		startup = readUntyped()
+	}
+	if !bytes.Contains(startup, []byte("user\x00sft\x00")) {
+		send(newMessage(msgError).bytes([]byte("SFATAL\x00C28000\x00Mno such role\x00\x00")))
+		return
+	}
+	if !fakeSCRAM(r, send) {
+		return
+	}
+	send(newMessage(msgParameterStatus).string("server_version").string("16.0"))
+	send(append(newMessage(msgReady), 'I'))
+
+	var query string
+	var params [][]byte
+	for {
+		typ, body, err := readMessage(r)
+		if err != nil {
+			return
+		}
+		m := &reader{b: body}
+		switch typ {
+		case msgQuery:
+			query = m.string()
+			record(query)
+			switch {
+			case query == "":
+				send(newMessage(msgEmptyQuery))
+			case strings.Contains(query, "dup"):
+				send(uniqueViolation())
+			default:
+				send(newMessage(msgCommandComplete).string(strings.Fields(query)[0]))
+			}
+			send(append(newMessage(msgReady), 'I'))
+		case msgParse:
+			m.string()
+			query = m.string()
+			record(query)
+		case msgBind:
+			m.string()
+			m.string()
+			formats := make([]int, m.int16())
+			for i := range formats {
+				formats[i] = m.int16()
+			}
+			params = make([][]byte, m.int16())
+			for i := range params {
+				if n := m.int32(); n >= 0 {
+					params[i] = m.next(n)
+					if formats[i] == formatBinary {
+						params[i] = []byte(`\x` + hex.EncodeToString(params[i]))
+					}
+				}
+			}
+		case msgSync:
+			if strings.Contains(query, "pg_sleep") {
+				continue
+			}
+			send(newMessage(msgParseComplete))
+			send(newMessage(msgBindComplete))
+			switch {
+			case strings.Contains(query, "dup"):
+				send(uniqueViolation())
+			case strings.HasPrefix(query, "DELETE"):
+				send(newMessage(msgNoData))
+				send(newMessage(msgCommandComplete).string("DELETE 3"))
+			default:
+				casts := castPattern.FindAllStringSubmatch(query, -1)
+				desc := newMessage(msgRowDescription).int16(len(casts))
+				row := newMessage(msgDataRow).int16(len(casts))
+				for i, cast := range casts {
+					desc = desc.string("c" + cast[1]).int32(0).int16(0).int32(castOIDs[cast[2]]).int16(-1).int32(-1).int16(0)
+					if p := params[i]; p == nil {
+						row = row.int32(-1)
+					} else {
+						row = row.int32(len(p)).bytes(p)
+					}
+				}
+				send(desc)
+				send(row)
+				send(newMessage(msgCommandComplete).string("SELECT 1"))
+			}
+			send(append(newMessage(msgReady), 'I'))
+		case msgTerminate:
+			return
+		}
+	}
+}
+
+func uniqueViolation() message {
+	return newMessage(msgError).bytes([]byte("SERROR\x00VERROR\x00C23505\x00Mduplicate key value\x00\x00"))
+}
+
+// fakeSCRAM runs the server side of SCRAM-SHA-256 for password hunter2.
+func fakeSCRAM(r *bufio.Reader, send func(message)) bool {
+	salt := []byte("0123456789abcdef")
+	salted := pbkdf2.Key([]byte("hunter2"), salt, 4096, sha256.Size, sha256.New)
+
+	send(newMessage(msgAuth).int32(10).string("SCRAM-SHA-256").bytes([]byte{0}))
+	typ, body, err := readMessage(r)
+	if err != nil || typ != msgPassword {
+		return false
+	}
+	m := &reader{b: body}
+	m.string()
+	clientFirst := string(m.next(m.int32()))
+	clientFirstBare := strings.TrimPrefix(clientFirst, "n,,")
+	clientNonce := clientFirstBare[strings.Index(clientFirstBare, "r=")+2:]
+	serverFirst := "r=" + clientNonce + "server,s=" + base64.StdEncoding.EncodeToString(salt) + ",i=4096"
+	send(newMessage(msgAuth).int32(11).bytes([]byte(serverFirst)))
+
+	if typ, body, err = readMessage(r); err != nil || typ != msgPassword {
+		return false
+	}
+	clientFinal := string(body)
+	withoutProof, proof64, _ := strings.Cut(clientFinal, ",p=")
+	authMessage := clientFirstBare + "," + serverFirst + "," + withoutProof
+	proof, _ := base64.StdEncoding.DecodeString(proof64)
+	clientKey := hmacSHA256(salted, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	sig := hmacSHA256(storedKey[:], authMessage)
+	for i := range proof {
+		proof[i] ^= sig[i]
+	}
+	if got := sha256.Sum256(proof); !hmac.Equal(got[:], storedKey[:]) {
+		send(newMessage(msgError).bytes([]byte("SFATAL\x00C28P01\x00Mpassword authentication failed\x00\x00")))
+		return false
+	}
+	serverSig := hmacSHA256(hmacSHA256(salted, "Server Key"), authMessage)
+	send(newMessage(msgAuth).int32(12).bytes([]byte("v=" + base64.StdEncoding.EncodeToString(serverSig))))
+	send(newMessage(msgAuth).int32(0))
+	return true
+}
+
+func TestDriver(t *testing.T) {
+	addr, queries := fakePostgres(t)
+	db, err := sql.Open("pgwire", "postgres://sft:hunter2@"+addr+"/sft?search_path=test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if err := db.PingContext(ctx); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	when := time.Date(2026, 10, 15, 12, 30, 0, 123456000, time.UTC)
+	var (
+		n     int64
+		ok    bool
+		at    time.Time
+		blob  []byte
+		text  string
+		empty sql.NullString
+	)
+	err = db.QueryRowContext(ctx,
+		"SELECT $1::int8, $2::bool, $3::timestamptz, $4::bytea, $5::text, $6::text",
+		42, true, when, []byte{0, 1, 0xff}, "it's", nil,
+	).Scan(&n, &ok, &at, &blob, &text, &empty)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if n != 42 || !ok || !at.Equal(when) || !bytes.Equal(blob, []byte{0, 1, 0xff}) || text != "it's" || empty.Valid {
+		t.Errorf("scanned %v %v %v %x %q %v", n, ok, at, blob, text, empty)
+	}
+
+	res, err := db.ExecContext(ctx, "DELETE FROM comps WHERE owner = $1", 7)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if rows, _ := res.RowsAffected(); rows != 3 {
+		t.Errorf("rows affected = %d, want 3", rows)
+	}
+
+	// A server error keeps the connection usable and exposes its SQLSTATE.
+	_, err = db.ExecContext(ctx, "INSERT INTO dup VALUES ($1)", 1)
+	var state interface{ SQLState() string }
+	if !errors.As(err, &state) || state.SQLState() != "23505" {
+		t.Errorf("duplicate insert error = %v, want SQLSTATE 23505", err)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE a (id int); CREATE TABLE b (id int)"); err != nil {
+		t.Fatalf("exec in tx: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	want := []string{
+		"",
+		"SELECT $1::int8, $2::bool, $3::timestamptz, $4::bytea, $5::text, $6::text",
+		"DELETE FROM comps WHERE owner = $1",
+		"INSERT INTO dup VALUES ($1)",
+		"BEGIN ISOLATION LEVEL SERIALIZABLE",
+		"CREATE TABLE a (id int); CREATE TABLE b (id int)",
+		"COMMIT",
+	}
+	if got := queries(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("queries = %q, want %q", got, want)
+	}
+
+	// A cancelled query abandons its connection; the pool dials a new one.
+	cctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := db.ExecContext(cctx, "SELECT pg_sleep($1)", 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow query error = %v, want deadline exceeded", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		t.Errorf("ping after cancel: %v", err)
+	}
+
+	bad, _ := sql.Open("pgwire", "postgres://sft:wrong@"+addr+"/sft?sslmode=prefer")
+	defer bad.Close()
+	if err := bad.PingContext(ctx); err == nil || !strings.Contains(err.Error(), "password authentication failed") {
+		t.Errorf("ping with a wrong password = %v", err)
+	}
+	if _, err := (Driver{}).OpenConnector("mysql://sft@" + addr); err == nil {
+		t.Error("accepted a non-postgres URL")
+	}
+	if _, err := (Driver{}).OpenConnector("postgres://sft@" + addr + "?sslmode=sometimes"); err == nil {
+		t.Error("accepted an unknown sslmode")
+	}
+}
+
+// TestSCRAM checks the client against the SCRAM-SHA-256 example in
+// RFC 7677, section 3.
+func TestSCRAM(t *testing.T) {
+	s := &scramClient{user: "user", password: "pencil", nonce: "rOprNGfwEbeRWgbNEkqO"}
+	if got := string(s.first()); got != "n,,n=user,r=rOprNGfwEbeRWgbNEkqO" {
+		t.Errorf("client-first = %q", got)
+	}
+	final, err := s.final([]byte("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="; string(final) != want {
+		t.Errorf("client-final = %q, want %q", final, want)
+	}
+	if err := s.verify([]byte("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=")); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+	if err := s.verify([]byte("v=AAAA")); err == nil {
+		t.Error("verified a wrong server signature")
+	}
+	if _, err := s.final([]byte("r=someoneelse,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")); err == nil {
+		t.Error("accepted a server nonce that does not extend ours")
+	}
+}
+
+func TestMD5Password(t *testing.T) {
+	if got, want := md5Password("sft", "secret", []byte("salt")), "md59c671284e2ad470bb6f6283b77f28a6f"; got != want {
+		t.Errorf("md5Password = %q, want %q", got, want)
+	}
+}
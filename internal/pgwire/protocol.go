@@ -0,0 +1,196 @@
+package pgwire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Frontend and backend message types of protocol version 3.0 used here.
+const (
+	msgAuth            = 'R'
+	msgBackendKey      = 'K'
+	msgBind            = 'B'
+	msgBindComplete    = '2'
+	msgCommandComplete = 'C'
+	msgDataRow         = 'D'
+	msgDescribe        = 'D' // frontend
+	msgEmptyQuery      = 'I'
+	msgError           = 'E'
+	msgExecute         = 'E' // frontend
+	msgNoData          = 'n'
+	msgNotice          = 'N'
+	msgNotification    = 'A'
+	msgParameterStatus = 'S'
+	msgParse           = 'P'
+	msgParseComplete   = '1'
+	msgPassword        = 'p' // password, SASLInitialResponse and SASLResponse
+	msgQuery           = 'Q'
+	msgReady           = 'Z'
+	msgRowDescription  = 'T'
+	msgSync            = 'S' // frontend
+	msgTerminate       = 'X'
+)
+
+const (
+	protocolVersion = 196608   // 3.0
+	sslRequestCode  = 80877103 // asks the server to switch to TLS
+	maxMessageSize  = 1 << 30
+)
+
+// message builds one frontend message.
+type message []byte
+
+func newMessage(typ byte) message {
+	return message{typ, 0, 0, 0, 0}
+}
+
+func (m message) int16(v int) message {
+	return binary.BigEndian.AppendUint16(m, uint16(v))
+}
+
+func (m message) int32(v int) message {
+	return binary.BigEndian.AppendUint32(m, uint32(v))
+}
+
+// string appends s NUL-terminated, as the protocol's String type.
+func (m message) string(s string) message {
+	return append(append(m, s...), 0)
+}
+
+func (m message) bytes(b []byte) message {
+	return append(m, b...)
+}
+
+// done fills in the length, which counts itself but not the type byte.
+func (m message) done() []byte {
+	binary.BigEndian.PutUint32(m[1:5], uint32(len(m)-1))
+	return m
+}
+
+// startupMessage builds the untyped StartupMessage carrying params.
+func startupMessage(params [][2]string) []byte {
+	m := message{0, 0, 0, 0}.int32(protocolVersion)
+	for _, p := range params {
+		m = m.string(p[0]).string(p[1])
+	}
+	m = append(m, 0)
+	binary.BigEndian.PutUint32(m, uint32(len(m)))
+	return m
+}
+
+// readMessage reads one backend message.
+func readMessage(r io.Reader) (typ byte, body []byte, err error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	n := int(binary.BigEndian.Uint32(header[1:])) - 4
+	if n < 0 || n > maxMessageSize {
+		return 0, nil, fmt.Errorf("pgwire: invalid message length %d", n)
+	}
+	body = make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header[0], body, nil
+}
+
+// reader parses a backend message body.
+type reader struct {
+	b   []byte
+	err error
+}
+
+var errShortMessage = errors.New("pgwire: message too short")
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil || n < 0 || len(r.b) < n {
+		r.err = errShortMessage
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) int16() int {
+	if b := r.next(2); b != nil {
+		return int(int16(binary.BigEndian.Uint16(b)))
+	}
+	return 0
+}
+
+func (r *reader) int32() int {
+	if b := r.next(4); b != nil {
+		return int(int32(binary.BigEndian.Uint32(b)))
+	}
+	return 0
+}
+
+func (r *reader) string() string {
+	i := strings.IndexByte(string(r.b), 0)
+	if r.err != nil || i < 0 {
+		r.err = errShortMessage
+		return ""
+	}
+	s := string(r.b[:i])
+	r.b = r.b[i+1:]
+	return s
+}
+
+// Error is an error the server reported. Its SQLState method lets
+// callers check the code without importing this package.
+type Error struct {
+	Severity string
+	Code     string // SQLSTATE, e.g. "23505" for a unique violation
+	Message  string
+	Detail   string
+}
+
+func (e *Error) Error() string {
+	s := "pgwire: " + e.Severity + ": " + e.Message + " (SQLSTATE " + e.Code + ")"
+	if e.Detail != "" {
+		s += ": " + e.Detail
+	}
+	return s
+}
+
+// SQLState returns the error's SQLSTATE code.
+func (e *Error) SQLState() string { return e.Code }
+
+// parseError decodes an ErrorResponse body.
+func parseError(body []byte) *Error {
+	e := &Error{}
+	r := &reader{b: body}
+	for {
+		field := r.byte()
+		if field == 0 || r.err != nil {
+			return e
+		}
+		value := r.string()
+		switch field {
+		case 'V': // non-localized severity, preferred over 'S'
+			e.Severity = value
+		case 'S':
+			if e.Severity == "" {
+				e.Severity = value
+			}
+		case 'C':
+			e.Code = value
+		case 'M':
+			e.Message = value
+		case 'D':
+			e.Detail = value
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package pgwire
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// scramClient runs the client side of SCRAM-SHA-256 (RFC 7677), the
+// default password method since Postgres 14. Postgres takes the user
+// from the startup message, so the SCRAM user name is left empty.
+type scramClient struct {
+	user, password, nonce string
+
+	clientFirstBare string
+	serverSignature []byte
+}
+
+func newScramClient(password string) (*scramClient, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return &scramClient{password: password, nonce: base64.RawStdEncoding.EncodeToString(b)}, nil
+}
+
+// first returns the client-first-message.
+func (s *scramClient) first() []byte {
+	s.clientFirstBare = "n=" + s.user + ",r=" + s.nonce
+	return []byte("n,," + s.clientFirstBare)
+}
+
+// final answers the server-first-message with the client-final-message.
+func (s *scramClient) final(serverFirst []byte) ([]byte, error) {
+	var nonce, salt string
+	iterations := 0
+	for _, attr := range strings.Split(string(serverFirst), ",") {
+		k, v, _ := strings.Cut(attr, "=")
+		switch k {
+		case "r":
+			nonce = v
+		case "s":
+			salt = v
+		case "i":
+			iterations, _ = strconv.Atoi(v)
+		}
+	}
+	if !strings.HasPrefix(nonce, s.nonce) || len(nonce) == len(s.nonce) {
+		return nil, errors.New("pgwire: SCRAM server nonce does not extend ours")
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil || iterations < 1 {
+		return nil, errors.New("pgwire: malformed SCRAM server-first-message")
+	}
+
+	salted := pbkdf2.Key([]byte(s.password), saltBytes, iterations, sha256.Size, sha256.New)
+	clientKey := hmacSHA256(salted, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	withoutProof := "c=biws,r=" + nonce // biws is base64("n,,")
+	authMessage := s.clientFirstBare + "," + string(serverFirst) + "," + withoutProof
+
+	proof := hmacSHA256(storedKey[:], authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	s.serverSignature = hmacSHA256(hmacSHA256(salted, "Server Key"), authMessage)
+	return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof)), nil
+}
+
+// verify checks the server-final-message, which proves the server knew
+// the password too.
+func (s *scramClient) verify(serverFinal []byte) error {
+	v, ok := strings.CutPrefix(string(serverFinal), "v=")
+	if !ok {
+		return errors.New("pgwire: SCRAM authentication failed: " + string(serverFinal))
+	}
+	sig, err := base64.StdEncoding.DecodeString(v)
+	if err != nil || !hmac.Equal(sig, s.serverSignature) {
+		return errors.New("pgwire: SCRAM server signature does not match")
+	}
+	return nil
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package pgwire
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type OIDs decoded into something other than a string.
+const (
+	oidBool        = 16
+	oidBytea       = 17
+	oidInt8        = 20
+	oidInt2        = 21
+	oidInt4        = 23
+	oidOID         = 26
+	oidFloat4      = 700
+	oidFloat8      = 701
+	oidDate        = 1082
+	oidTimestamp   = 1114
+	oidTimestamptz = 1184
+)
+
+// Parameters are sent in text format, except []byte, which is sent in
+// binary so a bytea column gets the bytes as they are.
+const (
+	formatText   = 0
+	formatBinary = 1
+)
+
+const timeLayout = "2006-01-02 15:04:05.999999-07:00"
+
+// encodeParam returns v's wire form and format; a nil slice is NULL.
+func encodeParam(v driver.Value) ([]byte, int, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, formatText, nil
+	case int64:
+		return strconv.AppendInt(nil, v, 10), formatText, nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'g', -1, 64), formatText, nil
+	case bool:
+		if v {
+			return []byte("t"), formatText, nil
+		}
+		return []byte("f"), formatText, nil
+	case string:
+		return []byte(v), formatText, nil
+	case []byte:
+		if v == nil {
+			v = []byte{}
+		}
+		return v, formatBinary, nil
+	case time.Time:
+		return []byte(v.UTC().Format(timeLayout)), formatText, nil
+	default:
+		return nil, 0, fmt.Errorf("pgwire: unsupported parameter type %T", v)
+	}
+}
+
+// decodeValue turns a text-format column value into a driver.Value.
+func decodeValue(oid int, b []byte) (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+	s := string(b)
+	switch oid {
+	case oidBool:
+		return s == "t", nil
+	case oidInt2, oidInt4, oidInt8, oidOID:
+		return strconv.ParseInt(s, 10, 64)
+	case oidFloat4, oidFloat8:
+		return strconv.ParseFloat(s, 64)
+	case oidBytea:
+		if rest, ok := strings.CutPrefix(s, `\x`); ok {
+			return hex.DecodeString(rest)
+		}
+		return nil, fmt.Errorf("pgwire: bytea value not in hex format")
+	case oidDate:
+		return time.Parse("2006-01-02", s)
+	case oidTimestamp:
+		return time.Parse("2006-01-02 15:04:05.999999999", s)
+	case oidTimestamptz:
+		return parseTimestamptz(s)
+	default:
+		return s, nil
+	}
+}
+
+// parseTimestamptz parses the ISO output format, whose zone offset is
+// "+00", "+05:30" or, for historic zones, "+00:53:28".
+func parseTimestamptz(s string) (time.Time, error) {
+	var err error
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07",
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999-07:00:00",
+	} {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
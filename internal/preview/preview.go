@@ -0,0 +1,337 @@
+// Package preview renders shareable PNG images of a board for link previews.
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	// Register decoders for the portrait formats found in the asset dirs.
+	_ "golang.org/x/image/webp"
+	_ "image/jpeg"
+
+	"sft/internal/models"
+)
+
+// Output dimensions follow the common Open Graph card size.
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	hexRatio    = 1.15
+	hexBorder   = 4
+	boardMargin = 40
+	panelWidth  = 260
+)
+
+var (
+	backgroundColor = color.RGBA{0x11, 0x18, 0x27, 0xff}
+	emptyHexColor   = color.RGBA{0x1f, 0x29, 0x37, 0xff}
+	textColor       = color.RGBA{0xf3, 0xf4, 0xf6, 0xff}
+	mutedTextColor  = color.RGBA{0x9c, 0xa3, 0xaf, 0xff}
+	starColor       = color.RGBA{0xf4, 0xc4, 0x52, 0xff}
+
+	// costColors are sRGB approximations of the --cost-border-* OKLCH tokens.
+	costColors = map[int]color.RGBA{
+		1: {0xa0, 0x9a, 0x8f, 0xff},
+		2: {0x33, 0xa9, 0x68, 0xff},
+		3: {0x25, 0x7e, 0xc5, 0xff},
+		4: {0xab, 0x43, 0xdd, 0xff},
+		5: {0xcf, 0x9a, 0x3e, 0xff},
+		7: {0xfe, 0xa4, 0xed, 0xff},
+	}
+)
+
+// Renderer composes board images from unit portraits on disk.
+type Renderer struct {
+	// Root is prepended to relative unit image paths (usually ".").
+	Root string
+
+	fontOnce  sync.Once
+	titleFace font.Face
+	textFace  font.Face
+	fontErr   error
+
+	mu        sync.Mutex
+	portraits map[string]image.Image
+}
+
+// NewRenderer creates a renderer resolving image paths relative to root.
+func NewRenderer(root string) *Renderer {
+	return &Renderer{Root: root, portraits: make(map[string]image.Image)}
+}
+
+// RenderPNG draws the board and its active trait counts and encodes it as PNG.
+func (r *Renderer) RenderPNG(board models.BoardView, title string) ([]byte, error) {
+	img, err := r.Render(board, title)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Render draws the board onto a Width x Height canvas.
+func (r *Renderer) Render(board models.BoardView, title string) (image.Image, error) {
+	r.fontOnce.Do(r.loadFonts)
+	if r.fontErr != nil {
+		return nil, r.fontErr
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	r.drawBoard(canvas, board)
+	r.drawTraitPanel(canvas, board, title)
+	return canvas, nil
+}
+
+func (r *Renderer) drawBoard(canvas *image.RGBA, board models.BoardView) {
+	rows, cols := board.Layout.Rows, board.Layout.Cols
+	if rows == 0 || cols == 0 {
+		return
+	}
+
+	areaW := float64(Width - panelWidth - 2*boardMargin)
+	areaH := float64(Height - 2*boardMargin)
+	// Offset rows shift by half a hex; rows overlap by a quarter hex height.
+	hexW := areaW / (float64(cols) + 0.5)
+	if maxW := areaH / ((float64(rows)*0.75 + 0.25) * hexRatio); maxW < hexW {
+		hexW = maxW
+	}
+	hexH := hexW * hexRatio
+	top := (float64(Height) - (float64(rows)*0.75+0.25)*hexH) / 2
+
+	for _, row := range board.Rows {
+		y := top + float64(row.Index)*hexH*0.75
+		for _, hex := range row.Hexes {
+			x := boardMargin + float64(hex.Col)*hexW
+			if row.Offset {
+				x += hexW / 2
+			}
+			rect := image.Rect(int(x), int(y), int(x+hexW)-2, int(y+hexH))
+			r.drawHex(canvas, rect, hex.Unit)
+		}
+	}
+}
+
+func (r *Renderer) drawHex(canvas *image.RGBA, rect image.Rectangle, unit *models.PlacedUnit) {
+	if unit == nil {
+		fillHex(canvas, rect, emptyHexColor)
+		return
+	}
+
+	fillHex(canvas, rect, costColor(unit.Unit.Cost))
+	inner := rect.Inset(hexBorder)
+
+	if portrait := r.portrait(unit.Unit.URL); portrait != nil {
+		scaled := image.NewRGBA(image.Rect(0, 0, inner.Dx(), inner.Dy()))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), portrait, coverRect(portrait.Bounds(), inner.Dx(), inner.Dy()), draw.Src, nil)
+		draw.DrawMask(canvas, inner, scaled, image.Point{}, hexMask{rect: inner}, inner.Min, draw.Over)
+	} else {
+		fillHex(canvas, inner, emptyHexColor)
+	}
+
+	drawStars(canvas, rect, unit.Stars)
+}
+
+func (r *Renderer) drawTraitPanel(canvas *image.RGBA, board models.BoardView, title string) {
+	x := Width - panelWidth
+	y := boardMargin + 24
+
+	if title != "" {
+		drawText(canvas, r.titleFace, x, y, textColor, truncate(title, 18))
+		y += 40
+	}
+
+	for _, tc := range countTraits(board) {
+		drawText(canvas, r.textFace, x, y, textColor, fmt.Sprintf("%d", tc.count))
+		drawText(canvas, r.textFace, x+32, y, mutedTextColor, truncate(tc.name, 16))
+		y += 30
+		if y > Height-boardMargin {
+			break
+		}
+	}
+}
+
+func (r *Renderer) loadFonts() {
+	parsed, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		r.fontErr = fmt.Errorf("parse font: %w", err)
+		return
+	}
+	if r.titleFace, err = opentype.NewFace(parsed, &opentype.FaceOptions{Size: 28, DPI: 72, Hinting: font.HintingFull}); err != nil {
+		r.fontErr = fmt.Errorf("title face: %w", err)
+		return
+	}
+	if r.textFace, err = opentype.NewFace(parsed, &opentype.FaceOptions{Size: 20, DPI: 72, Hinting: font.HintingFull}); err != nil {
+		r.fontErr = fmt.Errorf("text face: %w", err)
+	}
+}
+
+// portrait loads and memoizes a local unit image. Remote URLs are not fetched.
+func (r *Renderer) portrait(path string) image.Image {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if img, ok := r.portraits[path]; ok {
+		return img
+	}
+
+	var img image.Image
+	if f, err := os.Open(filepath.Join(r.Root, filepath.FromSlash(path))); err == nil {
+		img, _, _ = image.Decode(f)
+		f.Close()
+	}
+	r.portraits[path] = img
+	return img
+}
+
+type traitCount struct {
+	name  string
+	count int
+}
+
+// countTraits counts unique units per trait, highest counts first.
+func countTraits(board models.BoardView) []traitCount {
+	seen := make(map[string]bool)
+	counts := make(map[string]int)
+	for _, row := range board.Rows {
+		for _, hex := range row.Hexes {
+			if hex.Unit == nil || seen[hex.Unit.Unit.Slug] {
+				continue
+			}
+			seen[hex.Unit.Unit.Slug] = true
+			for _, t := range hex.Unit.Unit.Traits {
+				counts[t.Name]++
+			}
+		}
+	}
+
+	out := make([]traitCount, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, traitCount{name: name, count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].count != out[j].count {
+			return out[i].count > out[j].count
+		}
+		return out[i].name < out[j].name
+	})
+	return out
+}
+
+func costColor(cost int) color.RGBA {
+	if c, ok := costColors[cost]; ok {
+		return c
+	}
+	return costColors[1]
+}
+
+// coverRect returns the centered source rectangle that fills w x h without distortion.
+func coverRect(src image.Rectangle, w, h int) image.Rectangle {
+	sw, sh := src.Dx(), src.Dy()
+	if sw*h > sh*w {
+		cw := sh * w / h
+		x0 := src.Min.X + (sw-cw)/2
+		return image.Rect(x0, src.Min.Y, x0+cw, src.Max.Y)
+	}
+	ch := sw * h / w
+	// Portraits frame the face near the top; bias the crop upwards.
+	y0 := src.Min.Y + (sh-ch)/4
+	return image.Rect(src.Min.X, y0, src.Max.X, y0+ch)
+}
+
+func fillHex(canvas *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.DrawMask(canvas, rect, image.NewUniform(c), image.Point{}, hexMask{rect: rect}, rect.Min, draw.Over)
+}
+
+func drawStars(canvas *image.RGBA, rect image.Rectangle, stars int) {
+	const size, gap = 10, 4
+	total := stars*size + (stars-1)*gap
+	x := rect.Min.X + (rect.Dx()-total)/2
+	y := rect.Max.Y - rect.Dy()/4
+	for i := 0; i < stars; i++ {
+		star := image.Rect(x, y, x+size, y+size)
+		draw.Draw(canvas, star, image.NewUniform(starColor), image.Point{}, draw.Src)
+		x += size + gap
+	}
+}
+
+func drawText(canvas *image.RGBA, face font.Face, x, y int, c color.Color, s string) {
+	d := font.Drawer{
+		Dst:  canvas,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// hexMask is an alpha mask for a pointy-top hexagon filling rect,
+// matching the clip-path used by the .hex CSS class.
+type hexMask struct {
+	rect image.Rectangle
+}
+
+func (m hexMask) ColorModel() color.Model { return color.AlphaModel }
+
+func (m hexMask) Bounds() image.Rectangle { return m.rect }
+
+func (m hexMask) At(x, y int) color.Color {
+	w, h := float64(m.rect.Dx()), float64(m.rect.Dy())
+	if w == 0 || h == 0 {
+		return color.Transparent
+	}
+	px := (float64(x-m.rect.Min.X) + 0.5) / w
+	py := (float64(y-m.rect.Min.Y) + 0.5) / h
+	dx := px - 0.5
+	if dx < 0 {
+		dx = -dx
+	}
+
+	var limit float64
+	switch {
+	case py < 0 || py > 1:
+		return color.Transparent
+	case py < 0.25:
+		limit = 2 * py
+	case py > 0.75:
+		limit = 2 * (1 - py)
+	default:
+		limit = 0.5
+	}
+	if dx <= limit {
+		return color.Opaque
+	}
+	return color.Transparent
+}
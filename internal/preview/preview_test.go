@@ -0,0 +1,65 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestRenderPNG(t *testing.T) {
+	board := models.NewBoardView(4, 7)
+	board.Place(models.BoardState{Placements: []models.Placement{
+		{Row: 0, Col: 0, Unit: "ahri", Stars: 2},
+		{Row: 1, Col: 3, Unit: "garen", Stars: 1},
+	}}, []models.Unit{
+		{Name: "Ahri", Slug: "ahri", Cost: 4, URL: "missing.jpg", Traits: []models.Trait{{Name: "Arcanist"}}},
+		{Name: "Garen", Slug: "garen", Cost: 1, Traits: []models.Trait{{Name: "Arcanist"}, {Name: "Warden"}}},
+	})
+
+	data, err := NewRenderer(t.TempDir()).RenderPNG(board, "TFT Builder")
+	if err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), Width, Height)
+	}
+}
+
+func TestCountTraits(t *testing.T) {
+	board := models.NewBoardView(1, 3)
+	ahri := models.Unit{Slug: "ahri", Traits: []models.Trait{{Name: "Arcanist"}}}
+	garen := models.Unit{Slug: "garen", Traits: []models.Trait{{Name: "Arcanist"}, {Name: "Warden"}}}
+	board.Place(models.BoardState{Placements: []models.Placement{
+		{Row: 0, Col: 0, Unit: "ahri", Stars: 1},
+		{Row: 0, Col: 1, Unit: "ahri", Stars: 1}, // duplicates count once
+		{Row: 0, Col: 2, Unit: "garen", Stars: 1},
+	}}, []models.Unit{ahri, garen})
+
+	got := countTraits(board)
+	want := []traitCount{{"Arcanist", 2}, {"Warden", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("countTraits = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("countTraits[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHexMask(t *testing.T) {
+	m := hexMask{rect: image.Rect(0, 0, 100, 115)}
+	if _, _, _, a := m.At(50, 57).RGBA(); a == 0 {
+		t.Error("center should be opaque")
+	}
+	if _, _, _, a := m.At(0, 0).RGBA(); a != 0 {
+		t.Error("corner should be transparent")
+	}
+}
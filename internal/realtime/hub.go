@@ -0,0 +1,320 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"sft/internal/models"
+)
+
+// Connection timings: pings keep idle sockets alive through proxies and
+// detect peers that vanished without a close frame.
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+const (
+	maxRoomPeers  = 8  // coach plus a full lobby is plenty
+	maxRoomCode   = 64 // characters
+	sendQueueSize = 32 // messages buffered per peer before it is dropped
+)
+
+// Message types exchanged with browsers.
+const (
+	TypeState = "state" // server → client: the whole board, on join
+	TypePlace = "place" // both ways: a unit put on a hex
+	TypeClear = "clear" // both ways: a hex emptied
+	TypePeers = "peers" // server → client: how many editors are connected
+	TypeError = "error" // server → client: the last message was rejected
+)
+
+// Message is the JSON payload of every WebSocket message. Place carries a
+// full placement; clear only needs its row and col.
+type Message struct {
+	Type      string            `json:"type"`
+	Board     string            `json:"board,omitempty"`     // state
+	Placement *models.Placement `json:"placement,omitempty"` // place, clear
+	Peers     int               `json:"peers,omitempty"`     // peers
+	Error     string            `json:"error,omitempty"`     // error
+}
+
+// Hub holds the board rooms. Rooms exist while at least one peer is
+// connected; the first peer may seed the board with ?b=<board code>.
+type Hub struct {
+	mu     sync.Mutex
+	rooms  map[string]*room
+	logger *log.Logger
+}
+
+type room struct {
+	code  string
+	board map[[2]int]models.Placement
+	peers map[*peer]struct{}
+}
+
+type peer struct {
+	conn *Conn
+	send chan []byte
+}
+
+// NewHub returns a hub with no rooms.
+func NewHub() *Hub {
+	return &Hub{rooms: make(map[string]*room), logger: log.Default()}
+}
+
+// ServeBoard handles GET /ws/board/{code}, joining the caller to the room
+// for code and relaying placement updates between its peers.
+func (h *Hub) ServeBoard(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if !validRoomCode(code) {
+		http.Error(w, "invalid room code", http.StatusBadRequest)
+		return
+	}
+	var seed models.BoardState
+	if b := r.URL.Query().Get("b"); b != "" {
+		state, err := models.DecodeBoardState(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		seed = state
+	}
+	if h.Peers(code) >= maxRoomPeers {
+		http.Error(w, "room is full", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	p := &peer{conn: conn, send: make(chan []byte, sendQueueSize)}
+	rm, ok := h.join(code, p, seed)
+	if !ok {
+		conn.Close()
+		return
+	}
+	defer h.leave(rm, p)
+
+	go p.writeLoop()
+	h.serve(rm, p)
+}
+
+// Peers returns the number of peers connected to the room for code.
+func (h *Hub) Peers(code string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if rm := h.rooms[code]; rm != nil {
+		return len(rm.peers)
+	}
+	return 0
+}
+
+// join adds p to the room for code, creating it from seed if needed, and
+// sends p the current board. It fails if the room filled up meanwhile.
+func (h *Hub) join(code string, p *peer, seed models.BoardState) (*room, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rm := h.rooms[code]
+	if rm == nil {
+		rm = &room{code: code, board: make(map[[2]int]models.Placement), peers: make(map[*peer]struct{})}
+		for _, pl := range seed.Placements {
+			if onBoard(pl.Row, pl.Col) {
+				rm.board[[2]int{pl.Row, pl.Col}] = pl
+			}
+		}
+		h.rooms[code] = rm
+	}
+	if len(rm.peers) >= maxRoomPeers {
+		return nil, false
+	}
+	rm.peers[p] = struct{}{}
+
+	h.deliver(p, Message{Type: TypeState, Board: rm.state().Encode()})
+	h.broadcast(rm, nil, Message{Type: TypePeers, Peers: len(rm.peers)})
+	return rm, true
+}
+
+// leave removes p from rm, dropping the room once it is empty.
+func (h *Hub) leave(rm *room, p *peer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := rm.peers[p]; !ok {
+		return
+	}
+	delete(rm.peers, p)
+	close(p.send)
+	if len(rm.peers) == 0 {
+		delete(h.rooms, rm.code)
+		return
+	}
+	h.broadcast(rm, nil, Message{Type: TypePeers, Peers: len(rm.peers)})
+}
+
+// serve reads p's updates until the connection ends, applying each to the
+// room and relaying it to the other peers.
+func (h *Hub) serve(rm *room, p *peer) {
+	for {
+		data, err := p.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			h.reject(p, "invalid JSON message")
+			continue
+		}
+		if err := h.apply(rm, p, msg); err != nil {
+			h.reject(p, err.Error())
+		}
+	}
+}
+
+// apply validates msg, updates the room's board and relays the update.
+func (h *Hub) apply(rm *room, from *peer, msg Message) error {
+	if msg.Type != TypePlace && msg.Type != TypeClear {
+		return fmt.Errorf("unsupported message type %q", msg.Type)
+	}
+	if msg.Placement == nil {
+		return fmt.Errorf("%s message without placement", msg.Type)
+	}
+	pl := *msg.Placement
+	if !onBoard(pl.Row, pl.Col) {
+		return fmt.Errorf("hex %d,%d is off the board", pl.Row, pl.Col)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hex := [2]int{pl.Row, pl.Col}
+	if msg.Type == TypeClear {
+		delete(rm.board, hex)
+		h.broadcast(rm, from, Message{Type: TypeClear, Placement: &models.Placement{Row: pl.Row, Col: pl.Col}})
+		return nil
+	}
+
+	clean, err := normalizePlacement(pl)
+	if err != nil {
+		return err
+	}
+	rm.board[hex] = clean
+	h.broadcast(rm, from, Message{Type: TypePlace, Placement: &clean})
+	return nil
+}
+
+// reject tells p why its last message was ignored.
+func (h *Hub) reject(p *peer, reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deliver(p, Message{Type: TypeError, Error: reason})
+}
+
+// broadcast sends msg to every peer in rm except skip. h.mu must be held.
+func (h *Hub) broadcast(rm *room, skip *peer, msg Message) {
+	for p := range rm.peers {
+		if p != skip {
+			h.deliver(p, msg)
+		}
+	}
+}
+
+// deliver queues msg for p. A peer too slow to drain its queue is
+// disconnected rather than allowed to stall the room. h.mu must be held.
+func (h *Hub) deliver(p *peer, msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Printf("realtime: encoding %s message: %v", msg.Type, err)
+		return
+	}
+	select {
+	case p.send <- data:
+	default:
+		// Closing may wait on the peer's stuck write; don't hold the hub.
+		go p.conn.Close()
+	}
+}
+
+// writeLoop sends queued messages and periodic pings until the queue closes.
+func (p *peer) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	defer p.conn.Close()
+
+	for {
+		select {
+		case data, ok := <-p.send:
+			if !ok {
+				return
+			}
+			if err := p.conn.WriteMessage(data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := p.conn.Ping(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// state returns the room's board in row, then column order.
+func (rm *room) state() models.BoardState {
+	var s models.BoardState
+	for row := 0; row <= models.BoardRows; row++ {
+		for col := 0; col < models.BenchSlots; col++ {
+			if pl, ok := rm.board[[2]int{row, col}]; ok {
+				s.Placements = append(s.Placements, pl)
+			}
+		}
+	}
+	return s
+}
+
+// onBoard reports whether row, col is a board hex or a bench slot.
+func onBoard(row, col int) bool {
+	switch {
+	case row < 0 || row > models.BoardRows || col < 0:
+		return false
+	case row == models.BoardRows:
+		return col < models.BenchSlots
+	default:
+		return col < models.BoardCols
+	}
+}
+
+// normalizePlacement round-trips pl through the board code so a relayed
+// placement obeys the same slug, star and item rules as a shared link.
+func normalizePlacement(pl models.Placement) (models.Placement, error) {
+	if pl.Stars < 1 || pl.Stars > models.MaxStars {
+		return models.Placement{}, fmt.Errorf("star level %d out of range", pl.Stars)
+	}
+	if len(pl.Items) > models.MaxItems {
+		return models.Placement{}, fmt.Errorf("more than %d items", models.MaxItems)
+	}
+	state, err := models.DecodeBoardState(models.BoardState{Placements: []models.Placement{pl}}.Encode())
+	if err != nil {
+		return models.Placement{}, err
+	}
+	return state.Placements[0], nil
+}
+
+// validRoomCode accepts short codes of letters, digits, '-' and '_'.
+func validRoomCode(code string) bool {
+	if code == "" || len(code) > maxRoomCode {
+		return false
+	}
+	for _, r := range code {
+		ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
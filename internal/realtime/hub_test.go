@@ -0,0 +1,202 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sft/internal/models"
+)
+
+// testClient is a bare WebSocket client speaking just enough of the
+// protocol to exercise the hub.
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, srv *httptest.Server, path string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: " + srv.Listener.Addr().String() + "\r\n" +
+		"Connection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q", got)
+	}
+	return &testClient{t: t, conn: conn, r: r}
+}
+
+func (c *testClient) send(msg Message) {
+	c.t.Helper()
+	payload, _ := json.Marshal(msg)
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("send: %v", err)
+	}
+}
+
+func (c *testClient) receive() Message {
+	c.t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		c.t.Fatalf("receive: %v", err)
+	}
+	size := int(head[1] & 0x7F)
+	if size == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			c.t.Fatalf("receive: %v", err)
+		}
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		c.t.Fatalf("receive: %v", err)
+	}
+	var msg Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.t.Fatalf("receive: %v (%q)", err, payload)
+	}
+	return msg
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *Hub) {
+	hub := NewHub()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/board/{code}", hub.ServeBoard)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, hub
+}
+
+func TestHub_RelaysPlacements(t *testing.T) {
+	srv, hub := newTestServer(t)
+
+	alice := dial(t, srv, "/ws/board/coaching?b=1~001ahri")
+	if msg := alice.receive(); msg.Type != TypeState || msg.Board != "1~001ahri" {
+		t.Fatalf("first message = %+v, want seeded state", msg)
+	}
+	alice.receive() // peers: 1
+
+	bob := dial(t, srv, "/ws/board/coaching?b=1~001jinx")
+	if msg := bob.receive(); msg.Type != TypeState || msg.Board != "1~001ahri" {
+		t.Fatalf("joining peer got %+v, want the existing board", msg)
+	}
+	if msg := alice.receive(); msg.Type != TypePeers || msg.Peers != 2 {
+		t.Fatalf("alice got %+v, want 2 peers", msg)
+	}
+	bob.receive() // peers: 2
+
+	bob.send(Message{Type: TypePlace, Placement: &models.Placement{Row: 4, Col: 8, Unit: "jinx", Stars: 2}})
+	if msg := alice.receive(); msg.Type != TypePlace || msg.Placement.Unit != "jinx" || msg.Placement.Col != 8 {
+		t.Fatalf("alice got %+v, want bob's placement", msg)
+	}
+
+	alice.send(Message{Type: TypeClear, Placement: &models.Placement{Row: 0, Col: 0}})
+	if msg := bob.receive(); msg.Type != TypeClear {
+		t.Fatalf("bob got %+v, want clear", msg)
+	}
+
+	carol := dial(t, srv, "/ws/board/coaching")
+	if msg := carol.receive(); msg.Board != "1~482jinx" {
+		t.Fatalf("late joiner got board %q, want 1~482jinx", msg.Board)
+	}
+	if n := hub.Peers("coaching"); n != 3 {
+		t.Errorf("Peers = %d, want 3", n)
+	}
+}
+
+func TestHub_RejectsInvalidUpdates(t *testing.T) {
+	srv, _ := newTestServer(t)
+	c := dial(t, srv, "/ws/board/room1")
+	c.receive() // state
+	c.receive() // peers
+
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{"unknown type", Message{Type: "shuffle"}},
+		{"missing placement", Message{Type: TypePlace}},
+		{"off the board", Message{Type: TypePlace, Placement: &models.Placement{Row: 0, Col: 7, Unit: "ahri", Stars: 1}}},
+		{"bad stars", Message{Type: TypePlace, Placement: &models.Placement{Row: 0, Col: 0, Unit: "ahri", Stars: 4}}},
+		{"bad slug", Message{Type: TypePlace, Placement: &models.Placement{Row: 0, Col: 0, Unit: "Ahri!", Stars: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.t = t
+			c.send(tt.msg)
+			if msg := c.receive(); msg.Type != TypeError || msg.Error == "" {
+				t.Errorf("got %+v, want an error message", msg)
+			}
+		})
+	}
+}
+
+func TestHub_ServeBoardRejectsBadRequests(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		header map[string]string
+		status int
+	}{
+		{"not an upgrade", "/ws/board/room1", nil, http.StatusUpgradeRequired},
+		{"bad room code", "/ws/board/" + strings.Repeat("x", 65), nil, http.StatusBadRequest},
+		{"bad seed", "/ws/board/room1?b=nope", nil, http.StatusBadRequest},
+		{"cross origin", "/ws/board/room1", map[string]string{
+			"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13",
+			"Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ==", "Origin": "https://evil.example",
+		}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+tt.path, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Package realtime lets several browsers edit the same board at once over
+// WebSockets. It implements the small part of RFC 6455 the board rooms need:
+// the server handshake and unfragmented text messages.
+package realtime
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to the client key in the handshake (RFC 6455 §1.3).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Frame opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close status codes sent to the peer.
+const (
+	closeNormal      = 1000
+	closeGoingAway   = 1001
+	closeProtocol    = 1002
+	closeUnsupported = 1003
+	closeTooLarge    = 1009
+)
+
+// maxMessageSize bounds a single client message; board updates are tiny.
+const maxMessageSize = 4 << 10
+
+// ErrClosed is returned by ReadMessage once the peer closed the connection.
+var ErrClosed = errors.New("websocket closed")
+
+// Conn is a server-side WebSocket connection. ReadMessage must be called
+// from one goroutine; WriteMessage and Close are safe for concurrent use.
+// A peer that sends nothing, not even a pong, for pongWait is dropped.
+type Conn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+
+	mu     sync.Mutex // guards writes
+	closed bool
+}
+
+// Upgrade completes the WebSocket handshake for r and takes over its
+// connection. On failure it has already answered the request.
+// Cross-origin requests are refused: browsers let any page open sockets.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "expected a WebSocket upgrade", http.StatusUpgradeRequired)
+		return nil, errors.New("upgrade: not a websocket request")
+	}
+	if r.Header.Get("Sec-Websocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusBadRequest)
+		return nil, errors.New("upgrade: unsupported version")
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("upgrade: invalid key")
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin WebSocket refused", http.StatusForbidden)
+		return nil, errors.New("upgrade: cross-origin request")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "WebSocket unsupported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("upgrade: %w", err)
+	}
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("upgrade: %w", err)
+	}
+	// The server's deadlines no longer apply to a hijacked connection.
+	_ = conn.SetDeadline(time.Time{})
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	return &Conn{conn: conn, rw: rw}, nil
+}
+
+// acceptKey derives Sec-WebSocket-Accept from the client key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains reports whether a comma-separated header lists token.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin accepts requests without an Origin (non-browser clients) and
+// those whose Origin host matches the requested host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// ReadMessage returns the next text message, answering pings on the way.
+// Binary and fragmented messages close the connection as unsupported.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opText:
+			return payload, nil
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			c.closeWith(closeNormal)
+			return nil, ErrClosed
+		default:
+			c.closeWith(closeUnsupported)
+			return nil, fmt.Errorf("read message: unsupported opcode %#x", op)
+		}
+	}
+}
+
+func (c *Conn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	fin, op := head[0]&0x80 != 0, head[0]&0x0F
+	masked, size := head[1]&0x80 != 0, uint64(head[1]&0x7F)
+
+	if head[0]&0x70 != 0 || !masked {
+		c.closeWith(closeProtocol)
+		return 0, nil, errors.New("read frame: reserved bits set or unmasked client frame")
+	}
+	if !fin || op == opContinuation {
+		c.closeWith(closeUnsupported)
+		return 0, nil, errors.New("read frame: fragmented messages are not supported")
+	}
+
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > maxMessageSize {
+		c.closeWith(closeTooLarge)
+		return 0, nil, fmt.Errorf("read frame: %d bytes exceeds the %d byte limit", size, maxMessageSize)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return op, payload, nil
+}
+
+// WriteMessage sends data as a single text frame.
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// writeFrame sends an unmasked frame, as servers must.
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	head := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		head = append(head, byte(n))
+	case n <= 0xFFFF:
+		head = append(head, 126)
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head = append(head, 127)
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if _, err := c.rw.Write(head); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// Ping sends a ping frame; the browser answers with a pong.
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// Close sends a going-away close frame and closes the connection.
+func (c *Conn) Close() error {
+	return c.closeWith(closeGoingAway)
+}
+
+func (c *Conn) closeWith(code uint16) error {
+	_ = c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, code))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
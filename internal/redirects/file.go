@@ -0,0 +1,90 @@
+package redirects
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sft/internal/store"
+	"sft/internal/yamlite"
+)
+
+// LoadFile reads redirect rules from a JSON or YAML file holding a list of
+// from/to/status entries, e.g.
+//
+//	[{"from": "/set15/units/*", "to": "/units/*"}, {"from": "/help", "to": "/?tab=help", "status": 302}]
+//
+// with status defaulting to 301. Each rule is checked with Validate; the
+// first bad one fails the load.
+func LoadFile(path string) ([]store.Redirect, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load redirects: %w", err)
+	}
+
+	var rules []store.Redirect
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &rules)
+	case ".yaml", ".yml":
+		rules, err = parseYAML(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("load redirects: %s: unsupported format, want .json or .yaml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load redirects: %s: %w", path, err)
+	}
+
+	for i := range rules {
+		if err := Validate(&rules[i]); err != nil {
+			return nil, fmt.Errorf("load redirects: %s: rule %d (%s): %w", path, i+1, rules[i].From, err)
+		}
+	}
+	return rules, nil
+}
+
+// parseYAML reads a YAML list of flat from/to/status mappings.
+func parseYAML(r io.Reader) ([]store.Redirect, error) {
+	doc, err := yamlite.Parse(r)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	if doc.Kind != yamlite.List {
+		return nil, fmt.Errorf("line %d: want a list of from/to entries", doc.Line)
+	}
+
+	rules := make([]store.Redirect, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		if item.Kind != yamlite.Map {
+			return nil, fmt.Errorf("line %d: want a list of from/to entries", item.Line)
+		}
+		var rule store.Redirect
+		for _, p := range item.Keys {
+			value, n := p.Value.Value, p.Value.Line
+			if p.Value.Kind != yamlite.Scalar {
+				return nil, fmt.Errorf("line %d: %s wants a single value", n, p.Key)
+			}
+			switch p.Key {
+			case "from":
+				rule.From = value
+			case "to":
+				rule.To = value
+			case "status":
+				status, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: bad status %q", n, value)
+				}
+				rule.Status = status
+			default:
+				return nil, fmt.Errorf("line %d: unknown key %q", n, p.Key)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,88 @@
+package redirects
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sft/internal/store"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"rules.yaml": `# moved in set 16
+- from: /set15/units/*
+  to: /units/*
+- from: "/help"
+  to: /?tab=help   # temporary
+  status: 302
+`,
+		"rules.json": `[{"from": "/set15/units/*", "to": "/units/*"}, {"from": "/help", "to": "/?tab=help", "status": 302}]`,
+	}
+	want := []store.Redirect{
+		{From: "/set15/units/*", To: "/units/*", Status: http.StatusMovedPermanently},
+		{From: "/help", To: "/?tab=help", Status: http.StatusFound},
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d rules, want %d", name, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: rule %d = %+v, want %+v", name, i, got[i], want[i])
+			}
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	os.WriteFile(bad, []byte("- from: help\n  to: /\n"), 0o644)
+	if _, err := LoadFile(bad); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("bad rule: err = %v, want ErrInvalidRule", err)
+	}
+}
+
+func TestTable_WithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redirects.json")
+	os.WriteFile(path, []byte(`[{"from": "/a", "to": "/file"}, {"from": "/b", "to": "/file"}]`), 0o644)
+	s := &memStore{hits: map[string]int{}, rules: []store.Redirect{
+		{From: "/b", To: "/stored", Status: http.StatusFound},
+	}}
+
+	table := NewTable(s).WithFile(path)
+	if err := table.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, to, _ := table.Match("/a"); to != "/file" {
+		t.Errorf("/a -> %q, want /file", to)
+	}
+	if _, to, _ := table.Match("/b"); to != "/stored" {
+		t.Errorf("/b -> %q, want the stored rule", to)
+	}
+
+	h := table.Middleware(http.NotFoundHandler())
+	for _, p := range []string{"/a", "/b"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+	if s.hits["/a"] != 0 || s.hits["/b"] != 1 {
+		t.Errorf("hits = %v, want only the stored rule counted", s.hits)
+	}
+
+	fileOnly := NewTable(nil).WithFile(path)
+	if err := fileOnly.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	fileOnly.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/b", nil))
+}
@@ -0,0 +1,173 @@
+// Package redirects keeps old URLs working as unit names and routes change
+// between sets. Rules live in a store.RedirectStore, a file shipped with the
+// deployment, or both, and are applied by middleware ahead of routing.
+package redirects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"sft/internal/store"
+)
+
+// ErrInvalidRule is returned by Validate for malformed redirects.
+var ErrInvalidRule = errors.New("invalid redirect")
+
+// wildcard marks a prefix rule when it ends From, and where the matched
+// suffix goes when it ends To.
+const wildcard = "*"
+
+// Table is an in-memory copy of the stored redirects.
+type Table struct {
+	store store.RedirectStore // nil when rules only come from the file
+	file  string              // optional rules file; see LoadFile
+
+	mu       sync.RWMutex
+	exact    map[string]store.Redirect
+	prefixes []store.Redirect // longest prefix first
+	stored   map[string]bool  // From of the rules whose hits the store counts
+}
+
+// NewTable creates an empty table backed by s, which may be nil when
+// WithFile supplies the rules. Call Reload to load the rules.
+func NewTable(s store.RedirectStore) *Table {
+	return &Table{store: s, exact: map[string]store.Redirect{}}
+}
+
+// WithFile adds the rules in path, read on each Reload. Stored rules win
+// over file rules for the same path, so the admin API can override the
+// file. An empty path is ignored.
+func (t *Table) WithFile(path string) *Table {
+	t.file = path
+	return t
+}
+
+// Reload replaces the rules with the file's and the store's current
+// contents. On error the current rules stay in place.
+func (t *Table) Reload(ctx context.Context) error {
+	byFrom := make(map[string]store.Redirect)
+	stored := make(map[string]bool)
+	if t.file != "" {
+		rules, err := LoadFile(t.file)
+		if err != nil {
+			return err
+		}
+		for _, r := range rules {
+			byFrom[r.From] = r
+		}
+	}
+	if t.store != nil {
+		rules, err := t.store.ListRedirects(ctx)
+		if err != nil {
+			return fmt.Errorf("load redirects: %w", err)
+		}
+		for _, r := range rules {
+			byFrom[r.From] = r
+			stored[r.From] = true
+		}
+	}
+
+	exact := make(map[string]store.Redirect, len(byFrom))
+	var prefixes []store.Redirect
+	for _, r := range byFrom {
+		if strings.HasSuffix(r.From, wildcard) {
+			prefixes = append(prefixes, r)
+		} else {
+			exact[r.From] = r
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i].From) > len(prefixes[j].From) })
+
+	t.mu.Lock()
+	t.exact, t.prefixes, t.stored = exact, prefixes, stored
+	t.mu.Unlock()
+	return nil
+}
+
+// Match returns the redirect for path and the resolved target. Exact rules
+// win over prefix rules, and longer prefixes over shorter ones.
+func (t *Table) Match(path string) (store.Redirect, string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if r, ok := t.exact[path]; ok {
+		return r, r.To, true
+	}
+	for _, r := range t.prefixes {
+		prefix := strings.TrimSuffix(r.From, wildcard)
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			if to, ok := strings.CutSuffix(r.To, wildcard); ok {
+				return r, to + rest, true
+			}
+			return r, r.To, true
+		}
+	}
+	return store.Redirect{}, "", false
+}
+
+// Middleware redirects GET and HEAD requests that match a rule, carrying
+// over the query string unless the target sets its own, and counts the hit.
+func (t *Table) Middleware(next http.Handler) http.Handler {
+	logger := log.Default()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rule, target, ok := t.Match(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, rule.Status)
+
+		if !t.isStored(rule.From) {
+			return
+		}
+		if err := t.store.RecordRedirectHit(r.Context(), rule.From); err != nil {
+			logger.Printf("redirect %s: %v", rule.From, err)
+		}
+	})
+}
+
+// isStored reports whether the rule for from came from the store.
+func (t *Table) isStored(from string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.stored[from]
+}
+
+// Validate checks r and defaults its status to a permanent redirect.
+func Validate(r *store.Redirect) error {
+	r.From = strings.TrimSpace(r.From)
+	r.To = strings.TrimSpace(r.To)
+	switch {
+	case !strings.HasPrefix(r.From, "/"):
+		return fmt.Errorf("%w: from must be a path starting with /", ErrInvalidRule)
+	case strings.Contains(strings.TrimSuffix(r.From, wildcard), wildcard):
+		return fmt.Errorf("%w: * is only allowed at the end of from", ErrInvalidRule)
+	case r.To == "" || r.To == r.From:
+		return fmt.Errorf("%w: to must name a different target", ErrInvalidRule)
+	case strings.HasSuffix(r.To, wildcard) && !strings.HasSuffix(r.From, wildcard):
+		return fmt.Errorf("%w: a wildcard target needs a wildcard source", ErrInvalidRule)
+	}
+	switch r.Status {
+	case 0:
+		r.Status = http.StatusMovedPermanently
+	case http.StatusMovedPermanently, http.StatusFound:
+	default:
+		return fmt.Errorf("%w: status must be 301 or 302", ErrInvalidRule)
+	}
+	return nil
+}
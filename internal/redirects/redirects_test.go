@@ -0,0 +1,93 @@
+package redirects
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sft/internal/store"
+)
+
+type memStore struct {
+	rules []store.Redirect
+	hits  map[string]int
+}
+
+func (m *memStore) ListRedirects(context.Context) ([]store.Redirect, error) { return m.rules, nil }
+func (m *memStore) PutRedirect(context.Context, *store.Redirect) error      { return nil }
+func (m *memStore) DeleteRedirect(context.Context, string) error            { return nil }
+func (m *memStore) RecordRedirectHit(_ context.Context, from string) error {
+	m.hits[from]++
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	s := &memStore{hits: map[string]int{}, rules: []store.Redirect{
+		{From: "/units/old-name", To: "/units/new-name", Status: http.StatusMovedPermanently},
+		{From: "/set15/*", To: "/", Status: http.StatusFound},
+		{From: "/set15/units/*", To: "/units/*", Status: http.StatusMovedPermanently},
+		{From: "/help", To: "/?tab=help", Status: http.StatusFound},
+	}}
+	table := NewTable(s)
+	if err := table.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	h := table.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		{http.MethodGet, "/units/old-name", http.StatusMovedPermanently, "/units/new-name"},
+		{http.MethodGet, "/units/old-name?b=1~", http.StatusMovedPermanently, "/units/new-name?b=1~"},
+		{http.MethodGet, "/set15/units/ahri", http.StatusMovedPermanently, "/units/ahri"},
+		{http.MethodGet, "/set15/traits/arcana", http.StatusFound, "/"},
+		{http.MethodGet, "/help?x=1", http.StatusFound, "/?tab=help"},
+		{http.MethodPost, "/units/old-name", http.StatusTeapot, ""},
+		{http.MethodGet, "/units/ahri", http.StatusTeapot, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+			if rec.Code != tt.status || rec.Header().Get("Location") != tt.location {
+				t.Errorf("got %d %q, want %d %q", rec.Code, rec.Header().Get("Location"), tt.status, tt.location)
+			}
+		})
+	}
+	if s.hits["/units/old-name"] != 2 || s.hits["/set15/units/*"] != 1 {
+		t.Errorf("unexpected hits %v", s.hits)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		rule   store.Redirect
+		valid  bool
+		status int
+	}{
+		{store.Redirect{From: "/a", To: "/b"}, true, http.StatusMovedPermanently},
+		{store.Redirect{From: "/a/*", To: "/b/*", Status: http.StatusFound}, true, http.StatusFound},
+		{store.Redirect{From: "a", To: "/b"}, false, 0},
+		{store.Redirect{From: "/a/*/c", To: "/b"}, false, 0},
+		{store.Redirect{From: "/a", To: "/a"}, false, 0},
+		{store.Redirect{From: "/a", To: "/b/*"}, false, 0},
+		{store.Redirect{From: "/a", To: "/b", Status: http.StatusTemporaryRedirect}, false, 0},
+	}
+	for _, tt := range tests {
+		r := tt.rule
+		err := Validate(&r)
+		if tt.valid != (err == nil) || (err != nil && !errors.Is(err, ErrInvalidRule)) {
+			t.Errorf("Validate(%+v) = %v, want valid=%v", tt.rule, err, tt.valid)
+		}
+		if tt.valid && r.Status != tt.status {
+			t.Errorf("Validate(%+v) status = %d, want %d", tt.rule, r.Status, tt.status)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// Ability consistency issue kinds.
+const (
+	// IssueUnbackedNumber is a number in the ability text that no variable holds.
+	IssueUnbackedNumber = "unbacked-number"
+	// IssueMissingValue is a numeric variable whose values never appear in the text.
+	IssueMissingValue = "missing-value"
+)
+
+// AbilityIssue flags a disagreement between an ability's literal text and its variables,
+// typically left behind when a patch updates one but not the other.
+type AbilityIssue struct {
+	Unit     string `json:"unit"`
+	Ability  string `json:"ability"`
+	Kind     string `json:"kind"`
+	Value    string `json:"value"`
+	Variable string `json:"variable,omitempty"`
+}
+
+// numberGroup matches "280/420/635", "2", "1.5" or "25%" style values.
+var numberGroup = regexp.MustCompile(`\d+(?:\.\d+)?%?(?:/\d+(?:\.\d+)?%?)*`)
+
+// CheckAbilityConsistency cross-references the numbers written in each
+// ability's raw description with its variable values.
+func CheckAbilityConsistency(units []models.Unit) []AbilityIssue {
+	issues := []AbilityIssue{}
+	for _, u := range units {
+		issues = append(issues, checkAbility(u)...)
+	}
+	return issues
+}
+
+func checkAbility(u models.Unit) []AbilityIssue {
+	text := u.Ability.DescriptionRaw
+	if text == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(u.Ability.Variables))
+	for name, v := range u.Ability.Variables {
+		if len(v.Values) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	tokens := textNumbers(text)
+	used := make(map[string]bool)
+	var issues []AbilityIssue
+
+	for _, tok := range tokens {
+		backed := false
+		for _, name := range names {
+			if valuesMatch(tok.values, u.Ability.Variables[name].Values) {
+				backed = true
+				used[name] = true
+			}
+		}
+		if !backed {
+			issues = append(issues, AbilityIssue{
+				Unit: u.Name, Ability: u.Ability.Name, Kind: IssueUnbackedNumber, Value: tok.text,
+			})
+		}
+	}
+
+	for _, name := range names {
+		if !used[name] {
+			issues = append(issues, AbilityIssue{
+				Unit:     u.Name,
+				Ability:  u.Ability.Name,
+				Kind:     IssueMissingValue,
+				Value:    joinDisplayValues(u.Ability.Variables[name].DisplayValues),
+				Variable: name,
+			})
+		}
+	}
+	return issues
+}
+
+type textNumber struct {
+	text   string
+	values []float64
+}
+
+// textNumbers extracts standalone number groups, skipping ordinals like "3rd".
+func textNumbers(text string) []textNumber {
+	var out []textNumber
+	for _, loc := range numberGroup.FindAllStringIndex(text, -1) {
+		if loc[1] < len(text) && isLetter(text[loc[1]]) {
+			continue
+		}
+		if loc[0] > 0 && isLetter(text[loc[0]-1]) {
+			continue
+		}
+		tok := text[loc[0]:loc[1]]
+		var values []float64
+		for _, part := range strings.Split(tok, "/") {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(part, "%"), 64)
+			if err != nil {
+				values = nil
+				break
+			}
+			values = append(values, f)
+		}
+		if values != nil {
+			out = append(out, textNumber{text: tok, values: values})
+		}
+	}
+	return out
+}
+
+// valuesMatch compares text values with variable values. Percentages may be
+// stored as ratios (0.25 for "25%"), and star-invariant values may be written once.
+func valuesMatch(text, vars []float64) bool {
+	vars = collapseUniform(vars)
+	text = collapseUniform(text)
+	if len(text) != len(vars) {
+		return false
+	}
+	for _, scale := range []float64{1, 100} {
+		ok := true
+		for i := range text {
+			if !displayedAs(text[i], vars[i]*scale) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func collapseUniform(values []float64) []float64 {
+	for _, v := range values[1:] {
+		if math.Abs(v-values[0]) > 1e-6 {
+			return values
+		}
+	}
+	return values[:1]
+}
+
+// displayedAs reports whether value, rounded to at most one decimal, reads as shown.
+func displayedAs(shown, value float64) bool {
+	return math.Abs(shown-value) < 1e-6 || shown == math.Round(value) || shown == math.Round(value*10)/10
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestCheckAbilityConsistency(t *testing.T) {
+	unit := func(text string, vars map[string]models.AbilityVariable) models.Unit {
+		return models.Unit{Name: "Ahri", Ability: models.Ability{Name: "Fox-Fire", DescriptionRaw: text, Variables: vars}}
+	}
+
+	tests := []struct {
+		name string
+		unit models.Unit
+		want []AbilityIssue
+	}{
+		{
+			name: "consistent",
+			unit: unit("Deal 85/125/195 damage for 2 seconds, gaining 25% Attack Speed every 3rd cast.", map[string]models.AbilityVariable{
+				"Damage":   {Values: []float64{85, 125, 195}},
+				"Duration": {Values: []float64{2, 2, 2}},
+				"AS":       {Values: []float64{0.25}},
+				"Target":   {Type: "Target"},
+			}),
+			want: []AbilityIssue{},
+		},
+		{
+			name: "patched variable",
+			unit: unit("Deal 85/130/200 damage.", map[string]models.AbilityVariable{
+				"Damage": {Values: []float64{85, 125, 195}, DisplayValues: []string{"85", "125", "195"}},
+			}),
+			want: []AbilityIssue{
+				{Unit: "Ahri", Ability: "Fox-Fire", Kind: IssueUnbackedNumber, Value: "85/130/200"},
+				{Unit: "Ahri", Ability: "Fox-Fire", Kind: IssueMissingValue, Value: "85/125/195", Variable: "Damage"},
+			},
+		},
+		{
+			name: "rounded values",
+			unit: unit("Heal 33/50 Health.", map[string]models.AbilityVariable{
+				"Heal": {Values: []float64{33.33, 50}},
+			}),
+			want: []AbilityIssue{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckAbilityConsistency([]models.Unit{tt.unit})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
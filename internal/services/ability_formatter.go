@@ -21,8 +21,70 @@ var (
 	abilityParenTokenRe = regexp.MustCompile(`\(\s*([^()]*@[^@()]+@[^()]*)\s*\)`)
 )
 
-// FormatAbilityDescription renders the ability description by interpolating variables into HTML.
+// Token is an ability description placeholder such as @Damage.values@.
+type Token struct {
+	Raw      string                  // the placeholder as written, HTML-escaped
+	Name     string                  // variable name, e.g. "Damage"
+	Field    string                  // part after the dot, e.g. "values"; may be empty
+	Variable *models.AbilityVariable // nil when the ability defines no such variable
+	Star     int                     // star level being rendered; 0 for all
+}
+
+// TokenResolver renders a placeholder as HTML. It returns false to leave
+// the token to the next resolver and finally the built-in variable lookup.
+type TokenResolver func(tok Token) (template.HTML, bool)
+
+// PostProcessor rewrites a rendered description, e.g. to link keywords.
+type PostProcessor func(desc template.HTML, ability models.Ability) template.HTML
+
+// Formatter renders ability descriptions. Resolvers and post-processors
+// run in the order they were added; register them before first use, since
+// a Formatter is not safe for concurrent modification.
+type Formatter struct {
+	resolvers []TokenResolver
+	post      []PostProcessor
+}
+
+// NewFormatter returns a Formatter with only the built-in rendering.
+func NewFormatter() *Formatter {
+	return &Formatter{}
+}
+
+// DefaultFormatter backs FormatAbilityDescription and the templates.
+var DefaultFormatter = NewFormatter()
+
+// AddResolver registers r ahead of the built-in variable lookup.
+func (f *Formatter) AddResolver(r TokenResolver) *Formatter {
+	f.resolvers = append(f.resolvers, r)
+	return f
+}
+
+// AddPostProcessor registers p to run on every rendered description.
+func (f *Formatter) AddPostProcessor(p PostProcessor) *Formatter {
+	f.post = append(f.post, p)
+	return f
+}
+
+// FormatAbilityDescription renders the ability description with DefaultFormatter.
+// Per-star values are shown together, e.g. 70/105/160.
 func FormatAbilityDescription(ability models.Ability) template.HTML {
+	return DefaultFormatter.FormatAt(ability, 0)
+}
+
+// FormatAbilityDescriptionAt is FormatAbilityDescription showing only the
+// values for starLevel (1-3). Values that do not vary by star are shown as
+// they are, and a starLevel outside 1-3 shows every level.
+func FormatAbilityDescriptionAt(ability models.Ability, starLevel int) template.HTML {
+	return DefaultFormatter.FormatAt(ability, starLevel)
+}
+
+// Format renders the ability description by interpolating variables into HTML.
+func (f *Formatter) Format(ability models.Ability) template.HTML {
+	return f.FormatAt(ability, 0)
+}
+
+// FormatAt is Format for one star level, as FormatAbilityDescriptionAt.
+func (f *Formatter) FormatAt(ability models.Ability, starLevel int) template.HTML {
 	desc := strings.TrimSpace(ability.Description)
 	if desc == "" {
 		desc = strings.TrimSpace(ability.DescriptionRaw)
@@ -33,16 +95,20 @@ func FormatAbilityDescription(ability models.Ability) template.HTML {
 
 	// Escape any unexpected HTML before injecting our spans.
 	escaped := html.EscapeString(desc)
-	withParen := replaceParenthesizedTokens(escaped, ability.Variables)
-	withAtTokens := replaceAbilityTokens(withParen, ability.Variables, abilityAtTokenRe)
-	withBraceTokens := replaceAbilityTokens(withAtTokens, ability.Variables, abilityBraceTokenRe)
+	withParen := f.replaceParenthesizedTokens(escaped, ability.Variables, starLevel)
+	withAtTokens := f.replaceTokens(withParen, ability.Variables, abilityAtTokenRe, starLevel)
+	withBraceTokens := f.replaceTokens(withAtTokens, ability.Variables, abilityBraceTokenRe, starLevel)
 	withLineBreaks := strings.ReplaceAll(withBraceTokens, "\n", "<br />")
 
-	return template.HTML(strings.TrimSpace(withLineBreaks))
+	out := template.HTML(strings.TrimSpace(withLineBreaks))
+	for _, p := range f.post {
+		out = p(out, ability)
+	}
+	return out
 }
 
-func replaceParenthesizedTokens(desc string, vars map[string]models.AbilityVariable) string {
-	if len(vars) == 0 {
+func (f *Formatter) replaceParenthesizedTokens(desc string, vars map[string]models.AbilityVariable, star int) string {
+	if len(vars) == 0 && len(f.resolvers) == 0 {
 		return desc
 	}
 	return abilityParenTokenRe.ReplaceAllStringFunc(desc, func(match string) string {
@@ -52,8 +118,8 @@ func replaceParenthesizedTokens(desc string, vars map[string]models.AbilityVaria
 		}
 
 		inner := strings.TrimSpace(parts[1])
-		rendered := replaceAbilityTokens(inner, vars, abilityAtTokenRe)
-		rendered = replaceAbilityTokens(rendered, vars, abilityBraceTokenRe)
+		rendered := f.replaceTokens(inner, vars, abilityAtTokenRe, star)
+		rendered = f.replaceTokens(rendered, vars, abilityBraceTokenRe, star)
 		if rendered == "" || rendered == inner {
 			return match
 		}
@@ -62,8 +128,8 @@ func replaceParenthesizedTokens(desc string, vars map[string]models.AbilityVaria
 	})
 }
 
-func replaceAbilityTokens(desc string, vars map[string]models.AbilityVariable, re *regexp.Regexp) string {
-	if len(vars) == 0 {
+func (f *Formatter) replaceTokens(desc string, vars map[string]models.AbilityVariable, re *regexp.Regexp, star int) string {
+	if len(vars) == 0 && len(f.resolvers) == 0 {
 		return desc
 	}
 
@@ -73,15 +139,21 @@ func replaceAbilityTokens(desc string, vars map[string]models.AbilityVariable, r
 			return match
 		}
 
-		token := parts[1]
-		name, field := splitToken(token)
+		tok := Token{Raw: match, Star: star}
+		tok.Name, tok.Field = splitToken(parts[1])
+		if v, ok := vars[tok.Name]; ok {
+			tok.Variable = &v
+		}
+		for _, resolve := range f.resolvers {
+			if rendered, ok := resolve(tok); ok {
+				return string(rendered)
+			}
+		}
 
-		v, ok := vars[name]
-		if !ok {
+		if tok.Variable == nil {
 			return match
 		}
-
-		rendered := renderAbilityValue(v, field)
+		rendered := renderAbilityValue(*tok.Variable, tok.Field, star)
 		if rendered == "" {
 			return match
 		}
@@ -89,8 +161,8 @@ func replaceAbilityTokens(desc string, vars map[string]models.AbilityVariable, r
 	})
 }
 
-func renderAbilityValue(v models.AbilityVariable, field string) string {
-	content := selectAbilityContent(v, field)
+func renderAbilityValue(v models.AbilityVariable, field string, star int) string {
+	content := selectAbilityContent(v, field, star)
 	if content == "" {
 		return ""
 	}
@@ -104,6 +176,8 @@ func renderAbilityValue(v models.AbilityVariable, field string) string {
 	classes := []string{"ability-token"}
 	if css := strings.TrimSpace(v.CSSClass); css != "" {
 		classes = append(classes, css)
+	} else if css := variableTypeClass(v.Type); css != "" {
+		classes = append(classes, css)
 	}
 
 	return fmt.Sprintf(
@@ -113,13 +187,13 @@ func renderAbilityValue(v models.AbilityVariable, field string) string {
 	)
 }
 
-func selectAbilityContent(v models.AbilityVariable, field string) string {
+func selectAbilityContent(v models.AbilityVariable, field string, star int) string {
 	switch field {
 	case "values", "":
-		if joined := joinDisplayValues(v.DisplayValues); joined != "" {
+		if joined := joinDisplayValues(atStar(v.DisplayValues, star)); joined != "" {
 			return joined
 		}
-		if joined := joinAbilityValues(v.Values); joined != "" {
+		if joined := joinAbilityValues(atStar(v.Values, star)); joined != "" {
 			return joined
 		}
 	case "scaling":
@@ -136,10 +210,10 @@ func selectAbilityContent(v models.AbilityVariable, field string) string {
 	}
 
 	// Fallbacks in case the requested field was missing.
-	if joined := joinDisplayValues(v.DisplayValues); joined != "" {
+	if joined := joinDisplayValues(atStar(v.DisplayValues, star)); joined != "" {
 		return joined
 	}
-	if joined := joinAbilityValues(v.Values); joined != "" {
+	if joined := joinAbilityValues(atStar(v.Values, star)); joined != "" {
 		return joined
 	}
 	if v.Type != "" {
@@ -152,6 +226,15 @@ func selectAbilityContent(v models.AbilityVariable, field string) string {
 	return field
 }
 
+// atStar narrows per-star values to the one for star. Lists that hold a
+// single shared value, and stars outside 1-3, keep every value.
+func atStar[T any](values []T, star int) []T {
+	if star < 1 || star > models.MaxStars || len(values) < star || len(values) == 1 {
+		return values
+	}
+	return values[star-1 : star]
+}
+
 func joinAbilityValues(values []float64) string {
 	if len(values) == 0 {
 		return ""
@@ -196,7 +279,7 @@ func scalingIconClass(raw string) string {
 		return ""
 	}
 
-	if cls, ok := scalingIconMap[key]; ok {
+	if cls, ok := scalingIcons()[key]; ok {
 		return cls
 	}
 	return ""
@@ -261,16 +344,22 @@ func normalizeScalingKey(s string) string {
 	return b.String()
 }
 
-var scalingIconMap = map[string]string{
-	"AP":    "ability-token ability-icon ability-icon-ap",
-	"AD":    "ability-token ability-icon ability-icon-ad",
-	"AS":    "ability-token ability-icon ability-icon-as",
-	"ARMOR": "ability-token ability-icon ability-icon-armor",
-	"MR":    "ability-token ability-icon ability-icon-mr",
-	"CC":    "ability-token ability-icon ability-icon-crit-chance",
-	"CD":    "ability-token ability-icon ability-icon-crit-damage",
-	"HP":    "ability-token ability-icon ability-icon-health",
-	"MANA":  "ability-token ability-icon ability-icon-mana",
-	"RANGE": "ability-token ability-icon ability-icon-range",
-	"SOULS": "ability-token ability-icon ability-icon-souls",
+// variableTypeClasses colors untagged variables by type, using the classes
+// the source data puts on tagged ones.
+var variableTypeClasses = map[models.VariableType]string{
+	models.VariableMagicDamage:    "tft-magic-damage",
+	models.VariablePhysicalDamage: "tft-physical-damage",
+	models.VariableTrueDamage:     "tft-true-damage",
+	models.VariableHeal:           "tft-heal",
+	"Healing":                     "tft-heal",
+	models.VariableShield:         "tft-shield",
+}
+
+func variableTypeClass(t models.VariableType) string {
+	for typ, cls := range variableTypeClasses {
+		if strings.EqualFold(strings.TrimSpace(string(t)), string(typ)) {
+			return cls
+		}
+	}
+	return ""
 }
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestFormatAbilityDescriptionAt(t *testing.T) {
+	ability := models.Ability{
+		Description: "Deal @Damage.values@ damage to @Targets.values@ enemies.",
+		Variables: map[string]models.AbilityVariable{
+			"Damage":  {Values: []float64{70, 105, 160}},
+			"Targets": {DisplayValues: []string{"3"}},
+		},
+	}
+
+	tests := []struct {
+		star int
+		want string
+	}{
+		{0, "70/105/160"},
+		{1, ">70<"},
+		{3, ">160<"},
+		{4, "70/105/160"},
+	}
+	for _, tt := range tests {
+		got := string(FormatAbilityDescriptionAt(ability, tt.star))
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("star %d: %s does not contain %q", tt.star, got, tt.want)
+		}
+		if !strings.Contains(got, ">3<") {
+			t.Errorf("star %d: shared value missing from %s", tt.star, got)
+		}
+	}
+	if got := FormatIntListAt([]int{550, 990, 1782}, 2); got != "990" {
+		t.Errorf("FormatIntListAt = %q, want 990", got)
+	}
+}
+
+func TestFormatAbilityDescription_TypeClasses(t *testing.T) {
+	ability := models.Ability{
+		Description: "@A@ @B@ @C@ @D@",
+		Variables: map[string]models.AbilityVariable{
+			"A": {Type: "magic damage", Values: []float64{1}},
+			"B": {Type: models.VariableShield, Values: []float64{2}},
+			"C": {Type: models.VariableMagicDamage, CSSClass: "tft-ressource", Values: []float64{3}},
+			"D": {Type: "Seconds", Values: []float64{4}},
+		},
+	}
+	got := string(FormatAbilityDescription(ability))
+	for _, want := range []string{
+		`<span class="ability-token tft-magic-damage">1</span>`,
+		`<span class="ability-token tft-shield">2</span>`,
+		`<span class="ability-token tft-ressource">3</span>`,
+		`<span class="ability-token">4</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s does not contain %s", got, want)
+		}
+	}
+}
+
+func TestFormatterHooks(t *testing.T) {
+	ability := models.Ability{
+		Description: "Grant @Shield.values@ and @Keyword@ to allies.",
+		Variables: map[string]models.AbilityVariable{
+			"Shield": {Values: []float64{200, 250, 300}},
+		},
+	}
+
+	f := NewFormatter().
+		AddResolver(func(tok Token) (template.HTML, bool) {
+			if tok.Variable != nil {
+				return "", false
+			}
+			return template.HTML(`<abbr>` + tok.Name + `</abbr>`), true
+		}).
+		AddPostProcessor(func(desc template.HTML, _ models.Ability) template.HTML {
+			return template.HTML(strings.ReplaceAll(string(desc), "allies", `<a href="/glossary#allies">allies</a>`))
+		})
+
+	got := string(f.FormatAt(ability, 2))
+	for _, want := range []string{">250<", "<abbr>Keyword</abbr>", `<a href="/glossary#allies">`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s does not contain %q", got, want)
+		}
+	}
+	if got := string(NewFormatter().Format(ability)); !strings.Contains(got, "@Keyword@") {
+		t.Errorf("default formatter rendered an unknown token: %s", got)
+	}
+}
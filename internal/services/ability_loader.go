@@ -46,6 +46,17 @@ func adaptAbility(a setAbility, icon string) models.Ability {
 		}
 	}
 
+	// Curated scalings win; markers only fill the gaps.
+	for name, scalings := range inferScalings(a.Description) {
+		v, ok := vars[name]
+		if !ok || len(v.Scalings) > 0 || v.Scaling != "" {
+			continue
+		}
+		v.Scaling = scalings[0]
+		v.Scalings = scalings
+		vars[name] = v
+	}
+
 	return models.Ability{
 		Name:           strings.TrimSpace(a.Name),
 		Description:    desc,
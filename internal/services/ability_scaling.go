@@ -0,0 +1,68 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	descVarRef      = regexp.MustCompile(`@([A-Za-z0-9_]+)(?:\*100)?@`)
+	descScaleMarker = regexp.MustCompile(`%i:scale([A-Za-z]+)%`)
+)
+
+// scaleMarkerNames maps %i:scaleX% suffixes to the scaling names used in the dataset.
+// Unlisted suffixes are kept as written.
+var scaleMarkerNames = map[string]string{
+	"AP":         "AP",
+	"AD":         "AD",
+	"AS":         "AS",
+	"Armor":      "Armor",
+	"MR":         "MR",
+	"Health":     "HP",
+	"Mana":       "Mana",
+	"Crit":       "CC",
+	"CritChance": "CC",
+	"CritMult":   "CD",
+	"Range":      "Range",
+}
+
+// inferScalings reads scaling icon markers from a source description before
+// they are stripped, attributing each marker to the variable referenced just
+// before it: "@Damage@ (%i:scaleAD%%i:scaleAP%)" gives Damage → [AD AP].
+func inferScalings(desc string) map[string][]string {
+	refs := descVarRef.FindAllStringSubmatchIndex(desc, -1)
+	if len(refs) == 0 || !strings.Contains(desc, "%i:scale") {
+		return nil
+	}
+
+	out := make(map[string][]string)
+	for i, ref := range refs {
+		end := len(desc)
+		if i+1 < len(refs) {
+			end = refs[i+1][0]
+		}
+		name := desc[ref[2]:ref[3]]
+		for _, m := range descScaleMarker.FindAllStringSubmatch(desc[ref[1]:end], -1) {
+			scaling := m[1]
+			if mapped, ok := scaleMarkerNames[scaling]; ok {
+				scaling = mapped
+			}
+			if !containsString(out[name], scaling) {
+				out[name] = append(out[name], scaling)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferScalings(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want map[string][]string
+	}{
+		{
+			name: "markers after variables",
+			desc: "Deal @ModifiedDamage@&nbsp;(%i:scaleAD%%i:scaleAP%) physical damage, then heal @Heal*100@% (%i:scaleHealth%).",
+			want: map[string][]string{"ModifiedDamage": {"AD", "AP"}, "Heal": {"HP"}},
+		},
+		{
+			name: "unknown marker kept",
+			desc: "Gain @Stacks@ (%i:scaleSouls%) souls.",
+			want: map[string][]string{"Stacks": {"Souls"}},
+		},
+		{name: "no markers", desc: "Deal @Damage@ damage.", want: nil},
+		{name: "marker before any variable", desc: "%i:scaleAP% Deal damage.", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferScalings(tt.desc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inferScalings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptAbility_InfersMissingScaling(t *testing.T) {
+	a := setAbility{
+		Description: "Deal @Damage@ (%i:scaleAP%) and @Bonus@ (%i:scaleAD%).",
+		Variables: rawAbilityVariables{Map: map[string]detailedAbilityVariable{
+			"Damage": {},
+			"Bonus":  {Scaling: scalingList{"AP"}},
+		}},
+	}
+	got := adaptAbility(a, "")
+
+	if d := got.Variables["Damage"]; d.Scaling != "AP" || !reflect.DeepEqual(d.Scalings, []string{"AP"}) {
+		t.Errorf("Damage scaling not inferred: %+v", d)
+	}
+	if b := got.Variables["Bonus"]; !reflect.DeepEqual(b.Scalings, []string{"AP"}) {
+		t.Errorf("curated Bonus scaling overwritten: %+v", b)
+	}
+}
@@ -20,12 +20,29 @@ type AssetIndexer struct {
 
 // Index scans the directory and returns a map of slug → relative file path.
 func (idx AssetIndexer) Index(dir string) map[string]string {
-	m := make(map[string]string)
-
 	files, err := os.ReadDir(dir)
 	if err != nil {
-		return m
+		return make(map[string]string)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+
+	m := idx.IndexNames(names)
+	for key, name := range m {
+		m[key] = filepath.ToSlash(filepath.Join(dir, name))
 	}
+	return m
+}
+
+// IndexNames returns a map of slug → file name for a listing of file names,
+// such as the objects under a bucket prefix.
+func (idx AssetIndexer) IndexNames(names []string) map[string]string {
+	m := make(map[string]string)
 
 	slugFn := idx.SlugFunc
 	if slugFn == nil {
@@ -34,24 +51,19 @@ func (idx AssetIndexer) Index(dir string) map[string]string {
 
 	filterSet := idx.buildFilterSet()
 
-	for _, f := range files {
-		if f.IsDir() {
-			continue
-		}
-
-		ext := strings.ToLower(filepath.Ext(f.Name()))
+	for _, name := range names {
+		ext := strings.ToLower(filepath.Ext(name))
 		if len(filterSet) > 0 && !filterSet[ext] {
 			continue
 		}
 
-		base := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		base := strings.TrimSuffix(name, filepath.Ext(name))
 		// Handle filenames with dots (e.g., "Ahri.CjTbL0xA.jpg")
 		if dotIdx := strings.Index(base, "."); dotIdx > 0 {
 			base = base[:dotIdx]
 		}
 
-		key := slugFn(base)
-		m[key] = filepath.ToSlash(filepath.Join(dir, f.Name()))
+		m[slugFn(base)] = name
 	}
 
 	return m
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// AssetWatcher polls asset directories and calls Reload when their files
+// change, so icons dropped in for a new patch are linked without a
+// restart. Polling keeps it dependency-free; a scan only stats the few
+// hundred files in the directories themselves, not subdirectories.
+type AssetWatcher struct {
+	Dirs     []string
+	Interval time.Duration
+	Reload   func(ctx context.Context) error
+
+	logger *log.Logger
+}
+
+// NewAssetWatcher watches the asset directories of loader.
+func NewAssetWatcher(loader *LocalUnitsLoader, interval time.Duration) *AssetWatcher {
+	return &AssetWatcher{
+		Dirs:     loader.AssetDirs(),
+		Interval: interval,
+		Reload:   loader.Reload,
+		logger:   log.Default(),
+	}
+}
+
+// Run scans every Interval until ctx is done. The first scan only records
+// the current state.
+func (w *AssetWatcher) Run(ctx context.Context) {
+	logger := w.logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	last := w.Snapshot()
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current := w.Snapshot()
+		if current == last {
+			continue
+		}
+		if err := w.Reload(ctx); err != nil {
+			// Keep the old snapshot so the next scan retries, e.g. after
+			// a half-copied file is complete.
+			logger.Printf("Asset change detected, reload failed: %v", err)
+			continue
+		}
+		last = current
+		logger.Printf("Asset change detected, units reloaded")
+	}
+}
+
+// Snapshot fingerprints the names, sizes and modification times of the
+// files in Dirs. Missing directories count as empty.
+func (w *AssetWatcher) Snapshot() string {
+	h := sha256.New()
+	for _, dir := range w.Dirs {
+		fmt.Fprintf(h, "%s\x00", dir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || info.IsDir() {
+				continue
+			}
+			fmt.Fprintf(h, "%s %d %d\x00", e.Name(), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAssetWatcherReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	reloads := make(chan struct{}, 4)
+	w := &AssetWatcher{
+		Dirs:     []string{dir, filepath.Join(dir, "missing")},
+		Interval: 5 * time.Millisecond,
+		Reload: func(context.Context) error {
+			reloads <- struct{}{}
+			return nil
+		},
+		logger: log.New(io.Discard, "", 0),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	select {
+	case <-reloads:
+		t.Fatal("reloaded without a change")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Ahri.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		t.Fatal("no reload after adding a file")
+	}
+}
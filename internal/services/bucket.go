@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, which every GET signs.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Bucket reads objects from an S3-compatible object store: Amazon S3, or
+// Google Cloud Storage through its XML API with HMAC keys. Requests are
+// signed with AWS Signature Version 4 when AccessKey is set and sent
+// anonymously otherwise, for public buckets. Objects are addressed
+// path-style, <Endpoint>/<Name>/<key>.
+type Bucket struct {
+	Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or https://storage.googleapis.com
+	Name      string
+	Region    string // signing region, e.g. "eu-west-1"; GCS takes "auto"
+	AccessKey string
+	SecretKey string
+	Client    *http.Client // defaults to http.DefaultClient
+
+	now func() time.Time
+}
+
+// URL returns the address of the object key.
+func (b *Bucket) URL(key string) string {
+	return strings.TrimRight(b.Endpoint, "/") + "/" + b.Name + "/" + escapeKey(key)
+}
+
+// Get reads the object key. A missing object is an error wrapping
+// os.ErrNotExist.
+func (b *Bucket) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := b.do(ctx, b.URL(key))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// listResult is the part of a ListObjectsV2 response List reads.
+type listResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the keys directly under prefix, like a directory listing:
+// keys in deeper "subdirectories" are left out.
+func (b *Bucket) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := b.do(ctx, strings.TrimRight(b.Endpoint, "/")+"/"+b.Name+"?"+query.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("list %s/%s: %w", b.Name, prefix, err)
+		}
+		for _, c := range page.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (b *Bucket) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if b.AccessKey != "" {
+		b.sign(req)
+	}
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("get %s: %w", req.URL.Path, os.ErrNotExist)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("get %s: %s", req.URL.Path, resp.Status)
+	}
+}
+
+// sign adds AWS Signature Version 4 headers to a body-less request.
+func (b *Bucket) sign(req *http.Request) {
+	now := time.Now
+	if b.now != nil {
+		now = b.now
+	}
+	t := now().UTC()
+	stamp, day := t.Format("20060102T150405Z"), t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + emptyPayloadHash,
+		"x-amz-date:" + stamp,
+		"",
+		"host;x-amz-content-sha256;x-amz-date",
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := day + "/" + b.Region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+b.SecretKey), day)
+	key = hmacSHA256(key, b.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+b.AccessKey+"/"+scope+
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and strictly escapes query parameters as SigV4
+// requires.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapeKey escapes each segment of an object key, keeping the slashes.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = awsEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsEscape percent-encodes everything but unreserved characters.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
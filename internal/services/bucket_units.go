@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// BucketUnitsConfig locates the set data and asset listings in a bucket.
+type BucketUnitsConfig struct {
+	SetDataKey  string // object key of the set JSON, e.g. "data/set16_champions.json"
+	TraitPrefix string // key prefix of trait icons, e.g. "assets/Traits/SET16/"
+	UnitPrefix  string
+	SpellPrefix string
+	// AssetBaseURL is where browsers load the assets from, e.g. a CDN in
+	// front of the bucket. Empty links to the bucket itself.
+	AssetBaseURL string
+}
+
+// BucketUnitsSource loads units and traits from set JSON and asset
+// listings kept in an object storage bucket. Every call goes to the
+// bucket; wrap it in a CachedUnitsSource. Icons and portraits resolve to
+// absolute URLs, which templates use as they are.
+type BucketUnitsSource struct {
+	bucket *Bucket
+	cfg    BucketUnitsConfig
+}
+
+// NewBucketUnitsSource returns a source reading cfg's objects from bucket.
+func NewBucketUnitsSource(bucket *Bucket, cfg BucketUnitsConfig) *BucketUnitsSource {
+	return &BucketUnitsSource{bucket: bucket, cfg: cfg}
+}
+
+// LoadUnits reads the set JSON and indexes the asset prefixes.
+func (s *BucketUnitsSource) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	raw, err := s.bucket.Get(ctx, s.cfg.SetDataKey)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w", ErrSetFileNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	set, err := decodeSetFile(s.cfg.SetDataKey, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.assetMaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]models.Unit, 0, len(set.Champions))
+	for _, ch := range set.Champions {
+		if unit, ok := adaptChampion(ch, assets.traits, assets.units, assets.spells); ok {
+			units = append(units, unit)
+		}
+	}
+	sortUnitsByCostAndName(units)
+
+	return &models.UnitsData{
+		Version: set.version,
+		Units:   units,
+		Traits:  buildTraitInfos(set.Traits, units),
+
+		AssetsVersion: assets.fingerprint(),
+	}, nil
+}
+
+// SourceName names the source by its bucket, for FallbackSource.
+func (s *BucketUnitsSource) SourceName() string {
+	return "bucket " + s.bucket.Name
+}
+
+func (s *BucketUnitsSource) assetMaps(ctx context.Context) (assetMaps, error) {
+	traits, err := s.index(ctx, TraitIndexer, s.cfg.TraitPrefix)
+	if err != nil {
+		return assetMaps{}, err
+	}
+	units, err := s.index(ctx, UnitIndexer, s.cfg.UnitPrefix)
+	if err != nil {
+		return assetMaps{}, err
+	}
+	spells, err := s.index(ctx, SpellIndexer, s.cfg.SpellPrefix)
+	if err != nil {
+		return assetMaps{}, err
+	}
+	return assetMaps{traits: traits, units: units, spells: spells}, nil
+}
+
+// index lists the objects under prefix and maps their slugs to URLs. An
+// empty prefix indexes nothing.
+func (s *BucketUnitsSource) index(ctx context.Context, idx AssetIndexer, prefix string) (map[string]string, error) {
+	if prefix == "" {
+		return map[string]string{}, nil
+	}
+	keys, err := s.bucket.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list assets: %w", err)
+	}
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		// Skip the "directory" placeholder some consoles create.
+		if name := strings.TrimPrefix(key, prefix); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	m := idx.IndexNames(names)
+	for slug, name := range m {
+		m[slug] = s.assetURL(prefix + name)
+	}
+	return m, nil
+}
+
+func (s *BucketUnitsSource) assetURL(key string) string {
+	if s.cfg.AssetBaseURL == "" {
+		return s.bucket.URL(key)
+	}
+	return strings.TrimRight(s.cfg.AssetBaseURL, "/") + "/" + escapeKey(key)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeBucket serves objects path-style and lists them one key per page.
+func fakeBucket(t *testing.T, objects map[string][]byte) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			http.Error(w, "unsigned", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/sets" {
+			prefix := r.URL.Query().Get("prefix")
+			var keys []string
+			for key := range objects {
+				if rest, ok := strings.CutPrefix(key, prefix); ok && !strings.Contains(rest, "/") {
+					keys = append(keys, key)
+				}
+			}
+			// One key per page exercises continuation tokens.
+			var after string
+			fmt.Sscan(r.URL.Query().Get("continuation-token"), &after)
+			var next []string
+			for _, k := range keys {
+				if k > after && (len(next) == 0 || k < next[0]) {
+					next = []string{k}
+				}
+			}
+			fmt.Fprint(w, "<ListBucketResult>")
+			for _, k := range next {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents><IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>", k, k)
+			}
+			fmt.Fprint(w, "</ListBucketResult>")
+			return
+		}
+		body, ok := objects[strings.TrimPrefix(r.URL.Path, "/sets/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	}))
+}
+
+func TestBucketUnitsSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "set.json")
+	set := &FetchedSet{Set: 16, Champions: []fetchedChampion{
+		{APIName: "TFT16_Ahri", Name: "Ahri", Cost: 3},
+		{APIName: "TFT16_Zed", Name: "Zed", Cost: 1},
+	}}
+	if err := WriteSetFile(path, set); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := fakeBucket(t, map[string][]byte{
+		"data/set.json":                    raw,
+		"assets/Units/SET16/Ahri.png":      {},
+		"assets/Units/SET16/Zed.png":       {},
+		"assets/Units/SET16/webp-64/x.png": {},
+	})
+	defer srv.Close()
+
+	bucket := &Bucket{Endpoint: srv.URL, Name: "sets", Region: "auto", AccessKey: "AKID", SecretKey: "secret"}
+	source := NewBucketUnitsSource(bucket, BucketUnitsConfig{
+		SetDataKey:   "data/set.json",
+		UnitPrefix:   "assets/Units/SET16/",
+		AssetBaseURL: "https://cdn.example.com/",
+	})
+	data, err := source.LoadUnits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Version != datasetVersion(raw) {
+		t.Errorf("version = %q, want %q", data.Version, datasetVersion(raw))
+	}
+	if len(data.Units) != 2 || data.Units[0].Name != "Zed" {
+		t.Fatalf("units = %+v, want Zed then Ahri", data.Units)
+	}
+	if want := "https://cdn.example.com/assets/Units/SET16/Zed.png"; data.Units[0].URL != want {
+		t.Errorf("portrait = %q, want %q", data.Units[0].URL, want)
+	}
+
+	missing := NewBucketUnitsSource(bucket, BucketUnitsConfig{SetDataKey: "data/gone.json"})
+	if _, err := missing.LoadUnits(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing set data: %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"sft/internal/i18n"
+	"sft/internal/models"
+)
+
+// CachedUnitsSource serves the last good data of a slow UnitsSource at
+// once and refreshes it in the background when it is older than the TTL
+// (stale-while-revalidate). Only the first load of each locale waits for
+// the source; a failed refresh keeps serving the stale data and is retried
+// a TTL later.
+type CachedUnitsSource struct {
+	source UnitsSource
+	ttl    time.Duration
+	now    func() time.Time
+	logger *log.Logger
+
+	mu      sync.Mutex
+	entries map[string]*cachedUnits // keyed by locale
+}
+
+type cachedUnits struct {
+	data       *models.UnitsData
+	checked    time.Time // last load attempt, successful or not
+	refreshing bool
+}
+
+// NewCachedUnitsSource wraps source with a cache whose entries go stale
+// after ttl.
+func NewCachedUnitsSource(source UnitsSource, ttl time.Duration) *CachedUnitsSource {
+	return &CachedUnitsSource{
+		source:  source,
+		ttl:     ttl,
+		now:     time.Now,
+		logger:  log.Default(),
+		entries: make(map[string]*cachedUnits),
+	}
+}
+
+// LoadUnits returns the cached data for the locale of ctx, starting a
+// background refresh when it is stale.
+func (c *CachedUnitsSource) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	locale := i18n.FromContext(ctx)
+
+	c.mu.Lock()
+	if e, ok := c.entries[locale]; ok {
+		if !e.refreshing && c.now().Sub(e.checked) >= c.ttl {
+			e.refreshing = true
+			// The refresh outlives the request but keeps its locale.
+			go c.refresh(context.WithoutCancel(ctx), locale)
+		}
+		data := e.data
+		c.mu.Unlock()
+		return data, nil
+	}
+	c.mu.Unlock()
+
+	data, err := c.source.LoadUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if _, ok := c.entries[locale]; !ok {
+		c.entries[locale] = &cachedUnits{data: data, checked: c.now()}
+	}
+	c.mu.Unlock()
+	return data, nil
+}
+
+func (c *CachedUnitsSource) refresh(ctx context.Context, locale string) {
+	data, err := c.source.LoadUnits(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entries[locale]
+	e.refreshing = false
+	e.checked = c.now()
+	if err != nil {
+		c.logger.Printf("Units refresh failed, serving stale data: %v", err)
+		return
+	}
+	e.data = data
+}
+
+// SourceName names the wrapped source, for FallbackSource.
+func (c *CachedUnitsSource) SourceName() string {
+	return sourceName(c.source)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"sft/internal/i18n"
+	"sft/internal/models"
+)
+
+// slowSource returns its next result once release is signalled.
+type slowSource struct {
+	mu      sync.Mutex
+	version string
+	err     error
+	calls   int
+	release chan struct{}
+}
+
+func (s *slowSource) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &models.UnitsData{Version: s.version + "/" + i18n.FromContext(ctx)}, nil
+}
+
+func (s *slowSource) set(version string, err error) {
+	s.mu.Lock()
+	s.version, s.err = version, err
+	s.mu.Unlock()
+}
+
+func TestCachedUnitsSource(t *testing.T) {
+	source := &slowSource{version: "v1", release: make(chan struct{}, 10)}
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	var clock sync.Mutex
+	cache := NewCachedUnitsSource(source, time.Minute)
+	cache.now = func() time.Time { clock.Lock(); defer clock.Unlock(); return now }
+	advance := func(d time.Duration) { clock.Lock(); now = now.Add(d); clock.Unlock() }
+	ctx := i18n.WithLocale(context.Background(), "fr")
+
+	load := func() string {
+		t.Helper()
+		data, err := cache.LoadUnits(ctx)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		return data.Version
+	}
+	waitCalls := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			source.mu.Lock()
+			calls := source.calls
+			source.mu.Unlock()
+			cache.mu.Lock()
+			refreshing := cache.entries["fr"].refreshing
+			cache.mu.Unlock()
+			if calls >= n && !refreshing {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("source called %d times, want %d", calls, n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	source.release <- struct{}{}
+	if got := load(); got != "v1/fr" {
+		t.Fatalf("first load = %q", got)
+	}
+
+	// Stale data is served at once while the source is still loading.
+	source.set("v2", nil)
+	advance(time.Minute)
+	if got := load(); got != "v1/fr" {
+		t.Errorf("stale load = %q, want the cached v1", got)
+	}
+	if got := load(); got != "v1/fr" {
+		t.Errorf("load during refresh = %q", got)
+	}
+	source.release <- struct{}{}
+	waitCalls(2)
+	if got := load(); got != "v2/fr" {
+		t.Errorf("after refresh = %q, want v2", got)
+	}
+
+	// A failed refresh keeps the stale data.
+	source.set("", errors.New("offline"))
+	advance(time.Minute)
+	load()
+	source.release <- struct{}{}
+	waitCalls(3)
+	if got := load(); got != "v2/fr" {
+		t.Errorf("after failed refresh = %q, want v2", got)
+	}
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// CDragonRoot serves every CommunityDragon patch, e.g. <root>/latest/game.
+const CDragonRoot = "https://raw.communitydragon.org"
+
+// CDragonFetch downloads a TFT set's data and art from CommunityDragon.
+type CDragonFetch struct {
+	Root    string // defaults to CDragonRoot
+	Version string // patch directory, e.g. "latest" (default) or "15.1"
+	Locale  string // defaults to "en_us"
+	Set     int    // set number; 0 picks the newest
+	Client  *http.Client
+}
+
+// FetchedSet is a set converted to the set file format the units loader
+// reads, plus the art it references.
+type FetchedSet struct {
+	Set       int               `json:"set"`
+	Source    string            `json:"source"`
+	Champions []fetchedChampion `json:"champions"`
+	Traits    []cdragonTrait    `json:"traits"`
+
+	Assets []FetchedAsset `json:"-"`
+}
+
+// FetchedAsset is an image to download into one of the asset directories.
+type FetchedAsset struct {
+	Kind string // "unit", "spell" or "trait"
+	Name string // unit or trait name, which the asset indexers key files by
+	URL  string
+}
+
+type fetchedChampion struct {
+	APIName string          `json:"apiName"`
+	Name    string          `json:"name"`
+	Cost    int             `json:"cost"`
+	Role    string          `json:"role,omitempty"`
+	Traits  []string        `json:"traits"`
+	Ability fetchedAbility  `json:"ability"`
+	Icons   setIcons        `json:"icons"`
+	Stats   json.RawMessage `json:"stats,omitempty"`
+}
+
+type fetchedAbility struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Variables   []fetchedVariable `json:"variables,omitempty"`
+}
+
+// fetchedVariable is an ability variable in the list form the loader
+// reads, with one value per star level.
+type fetchedVariable struct {
+	Name  string    `json:"name"`
+	Value []float64 `json:"value"`
+}
+
+// cdragonFile is the part of cdragon/tft/<locale>.json the fetch reads.
+type cdragonFile struct {
+	Sets map[string]struct {
+		Champions []cdragonChampion `json:"champions"`
+		Traits    []cdragonTrait    `json:"traits"`
+	} `json:"sets"`
+}
+
+type cdragonChampion struct {
+	APIName    string          `json:"apiName"`
+	Name       string          `json:"name"`
+	Cost       int             `json:"cost"`
+	Role       string          `json:"role"`
+	Traits     []string        `json:"traits"`
+	SquareIcon string          `json:"squareIcon"`
+	Stats      json.RawMessage `json:"stats"`
+	Ability    struct {
+		Name      string            `json:"name"`
+		Desc      string            `json:"desc"`
+		Icon      string            `json:"icon"`
+		Variables []fetchedVariable `json:"variables"`
+	} `json:"ability"`
+}
+
+type cdragonTrait struct {
+	APIName string           `json:"apiName"`
+	Name    string           `json:"name"`
+	Desc    string           `json:"desc"`
+	Icon    string           `json:"icon,omitempty"`
+	Effects []setTraitEffect `json:"effects"`
+}
+
+func (f CDragonFetch) root() string {
+	if f.Root == "" {
+		return CDragonRoot
+	}
+	return strings.TrimRight(f.Root, "/")
+}
+
+func (f CDragonFetch) version() string {
+	if f.Version == "" {
+		return "latest"
+	}
+	return f.Version
+}
+
+func (f CDragonFetch) client() *http.Client {
+	if f.Client == nil {
+		return http.DefaultClient
+	}
+	return f.Client
+}
+
+// SetData downloads the set data and converts it to the set file format.
+func (f CDragonFetch) SetData(ctx context.Context) (*FetchedSet, error) {
+	locale := f.Locale
+	if locale == "" {
+		locale = "en_us"
+	}
+	source := f.root() + "/" + f.version() + "/cdragon/tft/" + locale + ".json"
+	body, err := f.get(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var file cdragonFile
+	if err := json.NewDecoder(body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", source, err)
+	}
+
+	key, number := "", f.Set
+	for k := range file.Sets {
+		n, err := strconv.Atoi(k)
+		if err != nil {
+			continue // mid-set updates such as "3.5"
+		}
+		if (f.Set == 0 && n > number) || n == f.Set {
+			key, number = k, n
+		}
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%s: set %d not found", source, f.Set)
+	}
+	set := file.Sets[key]
+
+	out := &FetchedSet{Set: number, Source: source, Traits: set.Traits}
+	for _, ch := range set.Champions {
+		champion := fetchedChampion{
+			APIName: ch.APIName,
+			Name:    ch.Name,
+			Cost:    ch.Cost,
+			Role:    ch.Role,
+			Traits:  ch.Traits,
+			Ability: fetchedAbility{Name: ch.Ability.Name, Description: ch.Ability.Desc, Variables: starValues(ch.Ability.Variables)},
+			Stats:   ch.Stats,
+		}
+		// Summons and props stay in the data but get no art, which keeps
+		// them out of the builder; see maxChampionCost.
+		if ch.Cost > maxChampionCost {
+			out.Champions = append(out.Champions, champion)
+			continue
+		}
+		portrait := f.assetURL(ch.SquareIcon)
+		champion.Icons = setIcons{Square: portrait, Portrait: portrait}
+		out.Champions = append(out.Champions, champion)
+		if portrait != "" {
+			out.Assets = append(out.Assets, FetchedAsset{Kind: "unit", Name: ch.Name, URL: portrait})
+		}
+		if icon := f.assetURL(ch.Ability.Icon); icon != "" {
+			out.Assets = append(out.Assets, FetchedAsset{Kind: "spell", Name: ch.Name, URL: icon})
+		}
+	}
+	for _, t := range out.Traits {
+		if icon := f.assetURL(t.Icon); icon != "" {
+			out.Assets = append(out.Assets, FetchedAsset{Kind: "trait", Name: t.Name, URL: icon})
+		}
+	}
+	return out, nil
+}
+
+// starValues keeps the 1-3 star values of each variable. CommunityDragon
+// lists seven, indexed by star level, where 0 and 4-6 are unused.
+func starValues(vars []fetchedVariable) []fetchedVariable {
+	out := make([]fetchedVariable, 0, len(vars))
+	for _, v := range vars {
+		if len(v.Value) > models.MaxStars {
+			v.Value = v.Value[1 : models.MaxStars+1]
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// assetURL maps a game asset path such as ASSETS/UX/TraitIcons/X.tex to
+// its PNG export.
+func (f CDragonFetch) assetURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	p := strings.ToLower(strings.TrimPrefix(path, "/"))
+	if ext := filepath.Ext(p); ext == ".tex" || ext == ".dds" {
+		p = strings.TrimSuffix(p, ext) + ".png"
+	}
+	return f.root() + "/" + f.version() + "/game/" + p
+}
+
+// teamPlannerPath is where CommunityDragon serves the team planner ID
+// mapping, under <root>/<version>.
+const teamPlannerPath = "/plugins/rcp-be-lol-game-data/global/default/v1/tftchampions-teamplanner.json"
+
+// TeamPlanner downloads the team planner ID mapping into path, the file
+// LoadTeamPlannerCodes reads, and returns the number of champions it maps
+// for set (e.g. "TFTSet16"). A mapping without set is an error and leaves
+// path alone.
+func (f CDragonFetch) TeamPlanner(ctx context.Context, path, set string) (int, error) {
+	source := f.root() + "/" + f.version() + teamPlannerPath
+	body, err := f.get(ctx, source)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return 0, fmt.Errorf("get %s: %w", source, err)
+	}
+	codes, err := parseTeamPlannerCodes(source, data, set)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+	return len(codes), writeFileAtomic(path, bytes.NewReader(data))
+}
+
+// Download saves asset into dir as <name>.png, named so the asset
+// indexers find it. Unless force is set, it keeps any file already
+// indexed under the same name, so local art wins, and reports false.
+func (f CDragonFetch) Download(ctx context.Context, asset FetchedAsset, dir string, force bool) (bool, error) {
+	indexer, slug, base := UnitIndexer, unitSlug(asset.Name), assetFileBase(asset.Name)
+	switch asset.Kind {
+	case "spell":
+		indexer = SpellIndexer
+	case "trait":
+		indexer, slug, base = TraitIndexer, traitSlug(asset.Name), traitSlug(asset.Name)
+	}
+	if _, ok := indexer.Index(dir)[slug]; ok && !force {
+		return false, nil
+	}
+
+	body, err := f.get(ctx, asset.URL)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, err
+	}
+	return true, writeFileAtomic(filepath.Join(dir, base+filepath.Ext(asset.URL)), body)
+}
+
+func (f CDragonFetch) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("get %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// assetFileBase turns a unit name into a file name the indexers read
+// back as the same unit: they cut names at the first dot.
+func assetFileBase(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`./\:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// WriteSetFile writes set to path in the indented layout of the checked-in
+// set files, replacing path only once the whole file is written.
+func WriteSetFile(path string, set *FetchedSet) error {
+	data, err := json.MarshalIndent(set, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, strings.NewReader(string(data)+"\n"))
+}
+
+func writeFileAtomic(path string, r io.Reader) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const cdragonSetJSON = `{"sets": {
+	"15": {"champions": [], "traits": []},
+	"16": {
+		"champions": [
+			{"apiName": "TFT16_Ahri", "name": "Ahri", "cost": 2, "traits": ["Arcanist"],
+			 "squareIcon": "ASSETS/Characters/TFT16_Ahri/HUD/TFT16_Ahri_Square.TFT_Set16.tex",
+			 "stats": {"hp": 500, "damage": 40, "mana": 60},
+			 "ability": {"name": "Orb", "desc": "Deal @Damage@ magic damage.", "icon": "ASSETS/Spells/Ahri.tex",
+			             "variables": [{"name": "Damage", "value": [0, 200, 300, 450, 600, 750, 900]}]}},
+			{"apiName": "TFT16_AnnieTibbers", "name": "Tibbers", "cost": 11, "traits": [],
+			 "squareIcon": "ASSETS/Characters/Tibbers.tex", "ability": {"name": "Rage"}}
+		],
+		"traits": [{"apiName": "TFT16_Arcanist", "name": "Arcanist", "desc": "Ability power", "icon": "ASSETS/UX/Arcanist.tex",
+		            "effects": [{"minUnits": 2, "maxUnits": 3, "style": 1}]}]
+	}
+}}`
+
+func TestCDragonFetch(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/latest/cdragon/tft/en_us.json":
+			w.Write([]byte(cdragonSetJSON))
+			return
+		case "/latest" + teamPlannerPath:
+			w.Write([]byte(`{"TFTSet16": [{"character_id": "TFT16_Ahri", "team_planner_code": 12}]}`))
+			return
+		}
+		w.Write([]byte("png"))
+	}))
+	defer srv.Close()
+
+	fetch := CDragonFetch{Root: srv.URL}
+	set, err := fetch.SetData(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Set != 16 || len(set.Champions) != 2 || len(set.Traits) != 1 {
+		t.Fatalf("set %d with %d champions, %d traits; want set 16 with 2 and 1", set.Set, len(set.Champions), len(set.Traits))
+	}
+	// Tibbers is a summon: kept in the data, but without art to download.
+	if len(set.Assets) != 3 {
+		t.Errorf("assets = %+v, want Ahri's portrait and spell and the trait icon", set.Assets)
+	}
+
+	dir := t.TempDir()
+	dirs := map[string]string{"unit": filepath.Join(dir, "units"), "spell": filepath.Join(dir, "spells"), "trait": filepath.Join(dir, "traits")}
+	if err := os.MkdirAll(dirs["trait"], 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dirs["trait"], "arcanist.svg"), []byte("<svg/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range set.Assets {
+		saved, err := fetch.Download(context.Background(), a, dirs[a.Kind], false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved != (a.Kind != "trait") {
+			t.Errorf("%s %s: saved = %v; local art must win", a.Kind, a.Name, saved)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dirs["unit"], "Ahri.png")); err != nil {
+		t.Error(err)
+	}
+
+	// The written file must load as the server would load it.
+	path := filepath.Join(dir, "set.json")
+	if err := WriteSetFile(path, set); err != nil {
+		t.Fatal(err)
+	}
+	data, err := NewUnitsLoader(LoadUnitsConfig{
+		SetDataPath: path, UnitDir: dirs["unit"], SpellDir: dirs["spell"], TraitDir: dirs["trait"],
+	}).LoadUnits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Units) != 1 || data.Units[0].URL != filepath.ToSlash(filepath.Join(dirs["unit"], "Ahri.png")) {
+		t.Fatalf("units = %+v", data.Units)
+	}
+	if got := data.Units[0].Ability.Variables["Damage"].Values; !reflect.DeepEqual(got, []float64{200, 300, 450}) {
+		t.Errorf("Damage = %v, want the 1-3 star values", got)
+	}
+
+	planner := filepath.Join(dir, "data", "planner.json")
+	if n, err := fetch.TeamPlanner(context.Background(), planner, "TFTSet16"); err != nil || n != 1 {
+		t.Fatalf("team planner: %d champions, %v", n, err)
+	}
+	codes, err := LoadTeamPlannerCodes(planner, "TFTSet16")
+	if err != nil || codes[12] != "TFT16_Ahri" {
+		t.Errorf("codes = %v, %v", codes, err)
+	}
+	if _, err := fetch.TeamPlanner(context.Background(), planner, "TFTSet99"); err == nil {
+		t.Error("expected an error for a set the mapping lacks")
+	}
+}
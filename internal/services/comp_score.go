@@ -0,0 +1,176 @@
+package services
+
+import (
+	"math"
+
+	"sft/internal/models"
+)
+
+// frontlineMaxRange is the attack range, in hexes, up to which a unit is
+// counted as frontline.
+const frontlineMaxRange = 2
+
+// CompScore is a breakdown of how a board is put together. Each part scores
+// 0-100 and Total is their rounded mean.
+type CompScore struct {
+	Total     int          `json:"total"`
+	Units     int          `json:"units"` // distinct units fielded, bench excluded
+	Synergies SynergyScore `json:"synergies"`
+	Balance   LineBalance  `json:"balance"`
+	CostCurve CostCurve    `json:"costCurve"`
+}
+
+// SynergyScore rates how many traits the board activates against the
+// roughly one active trait per two units a finished comp reaches.
+type SynergyScore struct {
+	Score  int       `json:"score"`
+	Active int       `json:"active"`
+	Target int       `json:"target"`
+	Traits []Synergy `json:"traits"`
+}
+
+// LineBalance splits fielded units by attack range. An even split scores 100.
+type LineBalance struct {
+	Score int `json:"score"`
+	Front int `json:"front"`
+	Back  int `json:"back"`
+}
+
+// CostCurve compares the board's average unit cost with what the shop
+// offers at the level needed to field it. Expected is 0 without shop odds,
+// in which case the curve is not scored and Score is 100.
+type CostCurve struct {
+	Score    int         `json:"score"`
+	Counts   []CostCount `json:"counts"`
+	Average  float64     `json:"average"`
+	Expected float64     `json:"expected,omitempty"`
+}
+
+// CostCount is the number of fielded units of one cost.
+type CostCount struct {
+	Cost  int `json:"cost"`
+	Count int `json:"count"`
+}
+
+// ScoreComp evaluates the fielded units of board against data. Unknown unit
+// slugs and bench placements are ignored.
+func ScoreComp(board models.BoardState, data *models.UnitsData) CompScore {
+	bySlug := make(map[string]models.Unit, len(data.Units))
+	for _, u := range data.Units {
+		bySlug[u.Slug] = u
+	}
+
+	var fielded []models.Unit
+	seen := make(map[string]bool)
+	for _, p := range board.Placements {
+		u, ok := bySlug[p.Unit]
+		if !ok || seen[p.Unit] || p.Row < 0 || p.Row >= models.BoardRows {
+			continue
+		}
+		seen[p.Unit] = true
+		fielded = append(fielded, u)
+	}
+
+	score := CompScore{
+		Units:     len(fielded),
+		Synergies: scoreSynergies(board, data.Traits, len(fielded)),
+		Balance:   scoreBalance(fielded),
+		CostCurve: scoreCostCurve(fielded, data.Shop),
+	}
+	if len(fielded) > 0 {
+		score.Total = roundScore(float64(score.Synergies.Score+score.Balance.Score+score.CostCurve.Score) / 3)
+	}
+	return score
+}
+
+func scoreSynergies(board models.BoardState, traits []models.TraitInfo, units int) SynergyScore {
+	s := SynergyScore{Traits: ComputeSynergies(board, traits), Target: max(1, (units+1)/2)}
+	if s.Traits == nil {
+		s.Traits = []Synergy{}
+	}
+	for _, syn := range s.Traits {
+		if syn.Active {
+			s.Active++
+		}
+	}
+	if units > 0 {
+		s.Score = roundScore(100 * float64(min(s.Active, s.Target)) / float64(s.Target))
+	}
+	return s
+}
+
+func scoreBalance(units []models.Unit) LineBalance {
+	var b LineBalance
+	for _, u := range units {
+		if u.Stats.Range <= frontlineMaxRange {
+			b.Front++
+		} else {
+			b.Back++
+		}
+	}
+	if n := b.Front + b.Back; n > 0 {
+		diff := math.Abs(float64(b.Front - b.Back))
+		b.Score = roundScore(100 * (1 - diff/float64(n)))
+	}
+	return b
+}
+
+func scoreCostCurve(units []models.Unit, shop *models.ShopOdds) CostCurve {
+	c := CostCurve{Counts: []CostCount{}, Score: 100}
+	if len(units) == 0 {
+		c.Score = 0
+		return c
+	}
+
+	counts := make(map[int]int)
+	total := 0
+	maxCost := 0
+	for _, u := range units {
+		counts[u.Cost]++
+		total += u.Cost
+		maxCost = max(maxCost, u.Cost)
+	}
+	for cost := 1; cost <= maxCost; cost++ {
+		c.Counts = append(c.Counts, CostCount{Cost: cost, Count: counts[cost]})
+	}
+	c.Average = math.Round(100*float64(total)/float64(len(units))) / 100
+
+	level, ok := shopLevel(shop, len(units))
+	if !ok {
+		return c
+	}
+	weighted, sum := 0, 0
+	for i, odds := range level.Odds {
+		weighted += (i + 1) * odds
+		sum += odds
+	}
+	if sum == 0 {
+		return c
+	}
+	c.Expected = math.Round(100*float64(weighted)/float64(sum)) / 100
+	// A full cost off the shop's average loses 50 points.
+	c.Score = roundScore(100 - 50*math.Abs(c.Average-c.Expected))
+	return c
+}
+
+// shopLevel returns the odds for the level that fields n units, or the
+// highest level listed when n exceeds it.
+func shopLevel(shop *models.ShopOdds, n int) (models.ShopLevel, bool) {
+	if shop == nil || len(shop.Levels) == 0 {
+		return models.ShopLevel{}, false
+	}
+	best := shop.Levels[0]
+	for _, l := range shop.Levels {
+		if l.Level == n {
+			return l, true
+		}
+		if l.Level > best.Level && l.Level < n {
+			best = l
+		}
+	}
+	return best, true
+}
+
+func roundScore(v float64) int {
+	return int(math.Round(math.Max(0, math.Min(100, v))))
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestScoreComp(t *testing.T) {
+	data := &models.UnitsData{
+		Units: []models.Unit{
+			{Slug: "sion", Cost: 1, Stats: models.UnitStats{Range: 1}},
+			{Slug: "vi", Cost: 2, Stats: models.UnitStats{Range: 1}},
+			{Slug: "lux", Cost: 3, Stats: models.UnitStats{Range: 4}},
+			{Slug: "jinx", Cost: 2, Stats: models.UnitStats{Range: 4}},
+		},
+		Traits: []models.TraitInfo{
+			{Name: "Bruiser", Slug: "bruiser", Units: []string{"sion", "vi"},
+				Breakpoints: []models.TraitBreakpoint{{MinUnits: 2, Style: 1}}},
+		},
+		Shop: &models.ShopOdds{Levels: []models.ShopLevel{
+			{Level: 3, Odds: []int{75, 25}},
+			{Level: 4, Odds: []int{50, 50}},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		board      models.BoardState
+		units      int
+		synergy    int
+		front      int
+		back       int
+		balance    int
+		expected   float64
+		costScore  int
+		totalScore int
+	}{
+		{
+			name:  "empty board",
+			board: models.BoardState{},
+		},
+		{
+			name: "balanced board",
+			board: models.BoardState{Placements: []models.Placement{
+				{Row: 0, Col: 0, Unit: "sion"},
+				{Row: 0, Col: 1, Unit: "vi"},
+				{Row: 3, Col: 0, Unit: "lux"},
+				{Row: 3, Col: 1, Unit: "jinx"},
+				{Row: 3, Col: 2, Unit: "jinx"},             // duplicate counts once
+				{Row: models.BoardRows, Col: 0, Unit: "x"}, // bench
+			}},
+			units: 4, synergy: 50, front: 2, back: 2, balance: 100,
+			expected: 1.5, costScore: 75, totalScore: 75,
+		},
+		{
+			name: "all frontline",
+			board: models.BoardState{Placements: []models.Placement{
+				{Row: 0, Col: 0, Unit: "sion"},
+				{Row: 0, Col: 1, Unit: "vi"},
+			}},
+			units: 2, synergy: 100, front: 2, balance: 0,
+			expected: 1.25, costScore: 88, totalScore: 63,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreComp(tt.board, data)
+			if got.Units != tt.units || got.Total != tt.totalScore {
+				t.Errorf("units/total = %d/%d, want %d/%d", got.Units, got.Total, tt.units, tt.totalScore)
+			}
+			if got.Synergies.Score != tt.synergy {
+				t.Errorf("synergy score = %d, want %d (%+v)", got.Synergies.Score, tt.synergy, got.Synergies)
+			}
+			if b := got.Balance; b.Front != tt.front || b.Back != tt.back || b.Score != tt.balance {
+				t.Errorf("balance = %+v, want %d/%d scoring %d", b, tt.front, tt.back, tt.balance)
+			}
+			if c := got.CostCurve; c.Expected != tt.expected || c.Score != tt.costScore {
+				t.Errorf("cost curve = %+v, want expected %.2f scoring %d", c, tt.expected, tt.costScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"sft/internal/models"
+)
+
+// RefreshStats records how the scheduled data refreshes went.
+type RefreshStats struct {
+	LastAttempt time.Time `json:"lastAttempt"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	LastFailure time.Time `json:"lastFailure"`
+	LastError   string    `json:"lastError,omitempty"`
+	Successes   int       `json:"successes"`
+	Failures    int       `json:"failures"`
+	Swaps       int       `json:"swaps"`   // successes that brought new data
+	Version     string    `json:"version"` // dataset version of the last successful fetch
+}
+
+// DataRefresher re-downloads the set data every Interval, validates it and
+// swaps it in: the set file at Path is replaced in one rename and Reload
+// then loads it. A failed fetch or check leaves the file and the served
+// data untouched until the next attempt.
+type DataRefresher struct {
+	Fetch    func(ctx context.Context) (*FetchedSet, error)
+	Path     string
+	Interval time.Duration
+	Reload   func(ctx context.Context) error
+	// Adapt converts downloaded set JSON to the data it would serve, for
+	// ValidateUnits; Current returns the data served now. Without Adapt
+	// only the set file's shape is checked.
+	Adapt   func(ctx context.Context, raw []byte) (*models.UnitsData, error)
+	Current func(ctx context.Context) (*models.UnitsData, error)
+
+	mu     sync.Mutex
+	stats  RefreshStats
+	logger *log.Logger
+}
+
+// NewDataRefresher refreshes the set file of loader from CommunityDragon.
+func NewDataRefresher(loader *LocalUnitsLoader, fetch CDragonFetch, interval time.Duration) *DataRefresher {
+	return &DataRefresher{
+		Fetch:    fetch.SetData,
+		Path:     loader.cfg.SetDataPath,
+		Interval: interval,
+		Reload:   loader.Reload,
+		Adapt:    loader.adaptSetData,
+		Current:  loader.LoadUnits,
+		logger:   log.Default(),
+	}
+}
+
+// Run refreshes every Interval until ctx is done. The first refresh waits
+// one Interval, as the data was just loaded from disk.
+func (d *DataRefresher) Run(ctx context.Context) {
+	logger := d.logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		swapped, err := d.Refresh(ctx)
+		switch {
+		case err != nil:
+			logger.Printf("Data refresh failed, keeping the current data: %v", err)
+		case swapped:
+			logger.Printf("Data refresh swapped in dataset %s", d.Stats().Version)
+		}
+	}
+}
+
+// Refresh runs one fetch, check and swap. It reports whether new data was
+// swapped in; unchanged data is a success without a swap.
+func (d *DataRefresher) Refresh(ctx context.Context) (swapped bool, err error) {
+	started := time.Now()
+	version, swapped, err := d.refresh(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stats.LastAttempt = started
+	if err != nil {
+		d.stats.LastFailure = started
+		d.stats.LastError = err.Error()
+		d.stats.Failures++
+		return false, err
+	}
+	d.stats.LastSuccess = started
+	d.stats.LastError = ""
+	d.stats.Successes++
+	d.stats.Version = version
+	if swapped {
+		d.stats.Swaps++
+	}
+	return swapped, nil
+}
+
+// Stats returns a copy of the refresh counters.
+func (d *DataRefresher) Stats() RefreshStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+func (d *DataRefresher) refresh(ctx context.Context) (version string, swapped bool, err error) {
+	set, err := d.Fetch(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	// Encoded as WriteSetFile does, so `sft fetch` output compares equal.
+	raw, err := json.MarshalIndent(set, "", "    ")
+	if err != nil {
+		return "", false, err
+	}
+	raw = append(raw, '\n')
+	version = datasetVersion(raw)
+
+	current, err := os.ReadFile(d.Path)
+	if err == nil && datasetVersion(current) == version {
+		return version, false, nil
+	}
+	if err := checkRefreshedSet(raw); err != nil {
+		return "", false, err
+	}
+	if err := d.validate(ctx, raw); err != nil {
+		return "", false, err
+	}
+
+	if err := writeFileAtomic(d.Path, bytes.NewReader(raw)); err != nil {
+		return "", false, err
+	}
+	if err := d.Reload(ctx); err != nil {
+		// Put the served data's file back, so the next attempt retries.
+		if current != nil {
+			_ = writeFileAtomic(d.Path, bytes.NewReader(current))
+		}
+		return "", false, fmt.Errorf("reload refreshed data: %w", err)
+	}
+	return version, true, nil
+}
+
+// checkRefreshedSet rejects downloads the builder could not serve: no
+// champions, or champions without a name or cost.
+func checkRefreshedSet(raw []byte) error {
+	var set setFile
+	if err := json.NewDecoder(bytes.NewReader(raw)).Decode(&set); err != nil {
+		return fmt.Errorf("refreshed data: %w", err)
+	}
+	if len(set.Champions) == 0 {
+		return errors.New("refreshed data has no champions")
+	}
+	for _, ch := range set.Champions {
+		if ch.Name == "" || ch.Cost <= 0 {
+			return fmt.Errorf("refreshed data: champion %q has no name or cost", ch.APIName)
+		}
+	}
+	return nil
+}
+
+// validate runs the adapted download through ValidateUnits and rejects it
+// for any problem the served data does not already have. Missing art does
+// not count: a refresh brings no art, and pages fall back without it.
+func (d *DataRefresher) validate(ctx context.Context, raw []byte) error {
+	if d.Adapt == nil {
+		return nil
+	}
+	data, err := d.Adapt(ctx, raw)
+	if err != nil {
+		return fmt.Errorf("refreshed data: %w", err)
+	}
+	if len(data.Units) == 0 {
+		return errors.New("refreshed data has no champions the builder can show")
+	}
+	known := make(map[DataProblem]bool)
+	if d.Current != nil {
+		if current, err := d.Current(ctx); err == nil {
+			for _, p := range ValidateUnits(current) {
+				known[p] = true
+			}
+		}
+	}
+	for _, p := range ValidateUnits(data) {
+		if known[p] || p.Kind == ProblemMissingImage || p.Kind == ProblemTraitWithoutIcon {
+			continue
+		}
+		return fmt.Errorf("refreshed data: %s %s %s", p.Kind, p.Subject, p.Detail)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataRefresher_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "set.json")
+	initial := &FetchedSet{Set: 16, Champions: []fetchedChampion{{APIName: "TFT16_Ahri", Name: "Ahri", Cost: 3}}}
+	if err := WriteSetFile(path, initial); err != nil {
+		t.Fatal(err)
+	}
+	loader := NewUnitsLoader(LoadUnitsConfig{SetDataPath: path})
+	ctx := context.Background()
+	if _, err := loader.LoadUnits(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var next *FetchedSet
+	var fetchErr error
+	r := NewDataRefresher(loader, CDragonFetch{}, 0)
+	r.Fetch = func(context.Context) (*FetchedSet, error) { return next, fetchErr }
+
+	next = initial
+	if swapped, err := r.Refresh(ctx); err != nil || swapped {
+		t.Fatalf("unchanged data: swapped=%v err=%v", swapped, err)
+	}
+
+	next = &FetchedSet{Set: 16, Champions: []fetchedChampion{{APIName: "TFT16_Ahri", Name: "Ahri", Cost: 4}}}
+	if swapped, err := r.Refresh(ctx); err != nil || !swapped {
+		t.Fatalf("new data: swapped=%v err=%v", swapped, err)
+	}
+	data, _ := loader.LoadUnits(ctx)
+	if data.Units[0].Cost != 4 {
+		t.Errorf("served cost = %d, want the refreshed 4", data.Units[0].Cost)
+	}
+
+	for name, bad := range map[string]*FetchedSet{
+		"empty":      {Set: 16},
+		"no cost":    {Set: 16, Champions: []fetchedChampion{{APIName: "TFT16_Ahri", Name: "Ahri"}}},
+		"duplicate":  {Set: 16, Champions: []fetchedChampion{{APIName: "TFT16_X", Name: "A", Cost: 1}, {APIName: "TFT16_X", Name: "B", Cost: 1}}},
+		"unservable": {Set: 16, Champions: []fetchedChampion{{APIName: "X", Name: "A", Cost: 1}}},
+		"unresolved token": {Set: 16, Champions: []fetchedChampion{{APIName: "TFT16_Ahri", Name: "Ahri", Cost: 4,
+			Ability: fetchedAbility{Name: "Orb", Description: "Deal @Damage@ magic damage."}}}},
+	} {
+		next = bad
+		if _, err := r.Refresh(ctx); err == nil {
+			t.Errorf("%s: expected the check to reject the data", name)
+		}
+	}
+	fetchErr = errors.New("offline")
+	if _, err := r.Refresh(ctx); err == nil {
+		t.Error("expected the fetch error")
+	}
+
+	data, _ = loader.LoadUnits(ctx)
+	raw, _ := os.ReadFile(path)
+	if data.Units[0].Cost != 4 || datasetVersion(raw) != data.Version {
+		t.Error("failed refreshes should leave the file and served data alone")
+	}
+	stats := r.Stats()
+	if stats.Successes != 2 || stats.Swaps != 1 || stats.Failures != 6 || stats.LastError != "offline" || stats.Version != data.Version {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// Dataset problem kinds reported by ValidateUnits.
+const (
+	// ProblemMissingImage is a unit with no local portrait; pages fall back
+	// to a remote image or, failing that, drop the unit.
+	ProblemMissingImage = "missing-image"
+	// ProblemUnresolvedToken is an ability placeholder no variable fills,
+	// shown to players as written, e.g. "@Damage@".
+	ProblemUnresolvedToken = "unresolved-token"
+	// ProblemEmptyDescription is an ability without description text.
+	ProblemEmptyDescription = "empty-description"
+	// ProblemDuplicateAPIName is an apiName shared by several units.
+	ProblemDuplicateAPIName = "duplicate-api-name"
+	// ProblemTraitWithoutIcon is a trait with no icon file.
+	ProblemTraitWithoutIcon = "trait-without-icon"
+)
+
+// DataProblem is a defect in the loaded dataset that shows up on pages.
+type DataProblem struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"` // unit or trait name
+	Detail  string `json:"detail,omitempty"`
+}
+
+// ValidateUnits checks data for the defects players would otherwise find
+// in tooltips. Problems are sorted by kind, then subject.
+func ValidateUnits(data *models.UnitsData) []DataProblem {
+	problems := []DataProblem{}
+	byAPIName := make(map[string][]string)
+
+	for _, u := range data.Units {
+		if u.URL == "" || isRemoteURL(u.URL) {
+			problems = append(problems, DataProblem{Kind: ProblemMissingImage, Subject: u.Name, Detail: u.URL})
+		}
+		if strings.TrimSpace(u.Ability.Description) == "" && strings.TrimSpace(u.Ability.DescriptionRaw) == "" {
+			problems = append(problems, DataProblem{Kind: ProblemEmptyDescription, Subject: u.Name, Detail: u.Ability.Name})
+		}
+		for _, tok := range unresolvedTokens(u.Ability) {
+			problems = append(problems, DataProblem{Kind: ProblemUnresolvedToken, Subject: u.Name, Detail: tok})
+		}
+		if u.APIName != "" {
+			byAPIName[u.APIName] = append(byAPIName[u.APIName], u.Name)
+		}
+	}
+
+	for apiName, names := range byAPIName {
+		if len(names) > 1 {
+			problems = append(problems, DataProblem{
+				Kind: ProblemDuplicateAPIName, Subject: apiName, Detail: strings.Join(names, ", "),
+			})
+		}
+	}
+
+	for _, t := range data.Traits {
+		if t.Icon == "" {
+			problems = append(problems, DataProblem{Kind: ProblemTraitWithoutIcon, Subject: t.Name})
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Kind != problems[j].Kind {
+			return problems[i].Kind < problems[j].Kind
+		}
+		return problems[i].Subject < problems[j].Subject
+	})
+	return problems
+}
+
+// unresolvedTokens returns the placeholders left in the rendered ability
+// description, once each.
+func unresolvedTokens(ability models.Ability) []string {
+	rendered := string(DefaultFormatter.Format(ability))
+	var out []string
+	seen := make(map[string]bool)
+	for _, re := range []*regexp.Regexp{abilityAtTokenRe, abilityBraceTokenRe} {
+		for _, tok := range re.FindAllString(rendered, -1) {
+			if !seen[tok] {
+				seen[tok] = true
+				out = append(out, tok)
+			}
+		}
+	}
+	return out
+}
+
+func isRemoteURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestValidateUnits(t *testing.T) {
+	data := &models.UnitsData{
+		Units: []models.Unit{
+			{
+				Name: "Ahri", APIName: "TFT16_Ahri", URL: "static/ahri.png",
+				Ability: models.Ability{
+					Name:        "Spirit Rush",
+					Description: "Deal @Damage@ and @Missing@ damage",
+					Variables:   map[string]models.AbilityVariable{"Damage": {Values: []float64{100, 150, 200}}},
+				},
+			},
+			{Name: "Ahri Clone", APIName: "TFT16_Ahri", URL: CDragonBaseURL + "/ahri.png", Ability: models.Ability{Name: "Mimic"}},
+		},
+		Traits: []models.TraitInfo{{Name: "Arcanist", Icon: "static/arcanist.svg"}, {Name: "Bruiser"}},
+	}
+
+	want := []DataProblem{
+		{Kind: ProblemDuplicateAPIName, Subject: "TFT16_Ahri", Detail: "Ahri, Ahri Clone"},
+		{Kind: ProblemEmptyDescription, Subject: "Ahri Clone", Detail: "Mimic"},
+		{Kind: ProblemMissingImage, Subject: "Ahri Clone", Detail: CDragonBaseURL + "/ahri.png"},
+		{Kind: ProblemTraitWithoutIcon, Subject: "Bruiser"},
+		{Kind: ProblemUnresolvedToken, Subject: "Ahri", Detail: "@Missing@"},
+	}
+	if got := ValidateUnits(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateUnits() =\n%+v\nwant\n%+v", got, want)
+	}
+}
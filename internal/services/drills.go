@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	"sft/internal/models"
+)
+
+// Drill kinds served by GenerateDrill.
+const (
+	DrillPositioning = "positioning"
+	DrillEcon        = "econ"
+)
+
+// ErrUnknownDrill is returned for an unsupported drill kind.
+var ErrUnknownDrill = errors.New("unknown drill kind")
+
+const (
+	positioningUnits = 8
+	passiveIncome    = 5
+	maxInterest      = 5
+	pvpWinGold       = 1
+)
+
+// Drill is a generated practice scenario. The same kind, seed and dataset
+// always produce the same drill, so answers can reference it by seed.
+type Drill struct {
+	Kind   string `json:"kind"`
+	Seed   uint64 `json:"seed"`
+	Prompt string `json:"prompt"`
+
+	// Positioning drills: units to place (slugs) and the threat to play around.
+	Units    []string `json:"units,omitempty"`
+	Opponent string   `json:"opponent,omitempty"`
+
+	// Econ drills: the state at the end of a round.
+	Gold   int  `json:"gold,omitempty"`
+	Streak int  `json:"streak,omitempty"` // positive win streak, negative loss streak
+	Won    bool `json:"won,omitempty"`
+}
+
+// GenerateDrill builds the scenario of the given kind for seed.
+func GenerateDrill(kind string, seed uint64, units []models.Unit) (Drill, error) {
+	rng := rand.New(rand.NewPCG(seed, seed>>32|1))
+
+	switch kind {
+	case DrillPositioning:
+		return positioningDrill(rng, seed, units)
+	case DrillEcon:
+		return econDrill(rng, seed), nil
+	default:
+		return Drill{}, fmt.Errorf("%w: %q", ErrUnknownDrill, kind)
+	}
+}
+
+func positioningDrill(rng *rand.Rand, seed uint64, units []models.Unit) (Drill, error) {
+	if len(units) < positioningUnits+1 {
+		return Drill{}, fmt.Errorf("positioning drill needs %d units, dataset has %d", positioningUnits+1, len(units))
+	}
+
+	// Sort a copy so the pick only depends on the dataset contents, not load order.
+	pool := make([]models.Unit, len(units))
+	copy(pool, units)
+	sort.Slice(pool, func(i, j int) bool { return pool[i].Slug < pool[j].Slug })
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	opponent := pool[0]
+	d := Drill{
+		Kind:     DrillPositioning,
+		Seed:     seed,
+		Opponent: opponent.Slug,
+		Prompt:   fmt.Sprintf("Place these %d units to play around an enemy %s.", positioningUnits, opponent.Name),
+	}
+	for _, u := range pool[1 : positioningUnits+1] {
+		d.Units = append(d.Units, u.Slug)
+	}
+	return d, nil
+}
+
+func econDrill(rng *rand.Rand, seed uint64) Drill {
+	streak := rng.IntN(13) - 6 // -6..6
+	d := Drill{
+		Kind:   DrillEcon,
+		Seed:   seed,
+		Gold:   rng.IntN(81),
+		Streak: streak,
+		Won:    streak > 0 || (streak == 0 && rng.IntN(2) == 0),
+	}
+
+	result := "lost"
+	if d.Won {
+		result = "won"
+	}
+	d.Prompt = fmt.Sprintf("You have %d gold, a streak of %d and just %s a player round. How much gold do you start the next round with?",
+		d.Gold, abs(streak), result)
+	return d
+}
+
+// EconAnswer returns the gold at the start of the next round for an econ drill:
+// passive income, interest (1 per 10 gold, max 5), streak bonus and the PvP win gold.
+func EconAnswer(d Drill) int {
+	gold := d.Gold + passiveIncome + min(d.Gold/10, maxInterest) + streakBonus(abs(d.Streak))
+	if d.Won {
+		gold += pvpWinGold
+	}
+	return gold
+}
+
+func streakBonus(streak int) int {
+	switch {
+	case streak >= 6:
+		return 3
+	case streak == 5:
+		return 2
+	case streak >= 3:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
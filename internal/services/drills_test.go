@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestGenerateDrill_Deterministic(t *testing.T) {
+	var units []models.Unit
+	for i := 0; i < 20; i++ {
+		units = append(units, models.Unit{Name: fmt.Sprintf("Unit %d", i), Slug: fmt.Sprintf("unit%d", i)})
+	}
+
+	for _, kind := range []string{DrillPositioning, DrillEcon} {
+		a, err := GenerateDrill(kind, 42, units)
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		// Reversed input order must not change the scenario.
+		reversed := make([]models.Unit, len(units))
+		for i, u := range units {
+			reversed[len(units)-1-i] = u
+		}
+		b, _ := GenerateDrill(kind, 42, reversed)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("%s drill differs for the same seed:\n%+v\n%+v", kind, a, b)
+		}
+	}
+
+	d, _ := GenerateDrill(DrillPositioning, 7, units)
+	if len(d.Units) != positioningUnits || d.Opponent == "" {
+		t.Errorf("unexpected positioning drill: %+v", d)
+	}
+	for _, slug := range d.Units {
+		if slug == d.Opponent {
+			t.Errorf("opponent %q is also a unit to place", slug)
+		}
+	}
+
+	if _, err := GenerateDrill("draft", 1, units); !errors.Is(err, ErrUnknownDrill) {
+		t.Errorf("expected ErrUnknownDrill, got %v", err)
+	}
+	if _, err := GenerateDrill(DrillPositioning, 1, units[:3]); err == nil {
+		t.Error("expected error for a dataset that is too small")
+	}
+}
+
+func TestEconAnswer(t *testing.T) {
+	tests := []struct {
+		drill Drill
+		want  int
+	}{
+		{Drill{Gold: 0}, 5},
+		{Drill{Gold: 34, Won: true, Streak: 1}, 34 + 5 + 3 + 1},
+		{Drill{Gold: 80, Streak: -6}, 80 + 5 + 5 + 3},
+		{Drill{Gold: 50, Streak: 5, Won: true}, 50 + 5 + 5 + 2 + 1},
+		{Drill{Gold: 19, Streak: -3}, 19 + 5 + 1 + 1},
+	}
+	for _, tt := range tests {
+		if got := EconAnswer(tt.drill); got != tt.want {
+			t.Errorf("EconAnswer(%+v) = %d, want %d", tt.drill, got, tt.want)
+		}
+	}
+}
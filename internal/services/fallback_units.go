@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"sft/internal/models"
+)
+
+// NamedSource is implemented by sources that report a readable name for
+// FallbackSource.Served, e.g. "bucket" or "local file".
+type NamedSource interface {
+	SourceName() string
+}
+
+// FallbackSource loads units from the first of several sources that
+// succeeds, e.g. remote, then a cached file, then a bundled snapshot.
+type FallbackSource struct {
+	sources []UnitsSource
+
+	mu     sync.Mutex
+	served string
+}
+
+// FallbackUnitsSource tries sources in order on every load.
+func FallbackUnitsSource(sources ...UnitsSource) *FallbackSource {
+	return &FallbackSource{sources: sources}
+}
+
+// LoadUnits returns the data of the first source that loads. When all
+// fail, it returns their errors joined.
+func (f *FallbackSource) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	var errs []error
+	for _, source := range f.sources {
+		data, err := source.LoadUnits(ctx)
+		if err == nil {
+			f.mu.Lock()
+			f.served = sourceName(source)
+			f.mu.Unlock()
+			return data, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", sourceName(source), err))
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no units sources")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Served names the source of the last successful load, "" before one.
+func (f *FallbackSource) Served() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.served
+}
+
+// Reload reloads every source that supports it. It fails only when all of
+// them fail, since the others can still serve.
+func (f *FallbackSource) Reload(ctx context.Context) error {
+	var errs []error
+	reloaded := false
+	for _, source := range f.sources {
+		r, ok := source.(interface{ Reload(context.Context) error })
+		if !ok {
+			continue
+		}
+		if err := r.Reload(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sourceName(source), err))
+			continue
+		}
+		reloaded = true
+	}
+	if reloaded {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+func sourceName(source UnitsSource) string {
+	if named, ok := source.(NamedSource); ok {
+		return named.SourceName()
+	}
+	return fmt.Sprintf("%T", source)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"sft/internal/models"
+)
+
+type stubSource struct {
+	name     string
+	data     *models.UnitsData
+	err      error
+	reloaded bool
+}
+
+func (s *stubSource) LoadUnits(context.Context) (*models.UnitsData, error) { return s.data, s.err }
+func (s *stubSource) SourceName() string                                   { return s.name }
+func (s *stubSource) Reload(context.Context) error                         { s.reloaded = true; return s.err }
+
+func TestFallbackUnitsSource(t *testing.T) {
+	remote := &stubSource{name: "remote", err: errors.New("timeout")}
+	file := &stubSource{name: "file", data: &models.UnitsData{Version: "file"}}
+	snapshot := &stubSource{name: "snapshot", data: &models.UnitsData{Version: "snapshot"}}
+	source := FallbackUnitsSource(remote, file, snapshot)
+
+	if source.Served() != "" {
+		t.Errorf("served before any load = %q", source.Served())
+	}
+	data, err := source.LoadUnits(context.Background())
+	if err != nil || data.Version != "file" || source.Served() != "file" {
+		t.Fatalf("got %+v, %v from %q; want the file", data, err, source.Served())
+	}
+
+	remote.err, remote.data = nil, &models.UnitsData{Version: "remote"}
+	if data, _ := source.LoadUnits(context.Background()); data.Version != "remote" || source.Served() != "remote" {
+		t.Errorf("recovered remote: got %q from %q", data.Version, source.Served())
+	}
+
+	if err := source.Reload(context.Background()); err != nil || !remote.reloaded || !snapshot.reloaded {
+		t.Errorf("reload: %v", err)
+	}
+
+	for _, s := range []*stubSource{remote, file, snapshot} {
+		s.err = errors.New(s.name + " down")
+	}
+	_, err = source.LoadUnits(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "remote: remote down") || !strings.Contains(err.Error(), "snapshot: snapshot down") {
+		t.Errorf("all failing: %v", err)
+	}
+	if err := source.Reload(context.Background()); err == nil {
+		t.Error("expected an error when no source reloads")
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPClientOptions is the policy of outbound HTTP requests.
+type HTTPClientOptions struct {
+	// Timeout bounds a whole request: every attempt, the waits between
+	// them and reading the body. 0 means no timeout.
+	Timeout time.Duration
+	// Retries is how many times a failed GET or HEAD is tried again after
+	// a network error or a 429, 502, 503 or 504 response.
+	Retries int
+	// Backoff is the wait before the first retry; it doubles for each
+	// further retry, with up to 50% jitter. A Retry-After header wins
+	// when it asks for longer.
+	Backoff time.Duration
+	// PerHost caps the connections open to one host; 0 is unlimited.
+	PerHost int
+}
+
+// NewHTTPClient returns a client for outbound fetchers that follows opts.
+func NewHTTPClient(opts HTTPClientOptions) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = opts.PerHost
+	return &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &retryTransport{
+			next:    transport,
+			retries: opts.Retries,
+			backoff: opts.Backoff,
+		},
+	}
+}
+
+// retryTransport retries idempotent requests that failed transiently.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !retryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff << attempt
+		wait += time.Duration(rand.Int64N(int64(wait/2) + 1))
+		if resp != nil {
+			if after := retryAfter(resp); after > wait {
+				wait = after
+			}
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a response or error may go away on retry.
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_Retries(t *testing.T) {
+	var calls atomic.Int32
+	failures := int32(2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(HTTPClientOptions{Timeout: 5 * time.Second, Retries: 2, Backoff: time.Millisecond, PerHost: 1})
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("got %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+
+	// Out of retries, the last response is returned.
+	calls.Store(0)
+	failures = 10
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 3 {
+		t.Errorf("got %d after %d calls, want 503 after 3", resp.StatusCode, calls.Load())
+	}
+
+	// Requests with side effects are sent once.
+	calls.Store(0)
+	resp, err = client.Post(srv.URL, "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("POST sent %d times, want 1", calls.Load())
+	}
+}
+
+func TestNewHTTPClient_StopsWaitingOnCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	_, err := NewHTTPClient(HTTPClientOptions{Retries: 3, Backoff: time.Millisecond}).Do(req)
+	if err == nil || time.Since(start) > 5*time.Second {
+		t.Errorf("expected the cancelled wait to end the request, got %v after %s", err, time.Since(start))
+	}
+}
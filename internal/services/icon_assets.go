@@ -0,0 +1,84 @@
+package services
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Icon problems reported by CheckScalingIcons.
+const (
+	IconMissingRule  = "missing-css-rule"
+	IconMissingAsset = "missing-asset"
+)
+
+// IconIssue is a scaling icon that would render invisibly.
+type IconIssue struct {
+	Scaling string `json:"scaling"`
+	Class   string `json:"class"`
+	Problem string `json:"problem"`
+	Asset   string `json:"asset,omitempty"`
+}
+
+// staticURLPrefix is how stylesheets reference files under the static root.
+const staticURLPrefix = "/static/"
+
+// CheckScalingIcons verifies that every class in the scaling icon registry
+// has a mask-image rule in css and that the referenced file exists under
+// staticRoot. Issues are sorted by scaling key.
+func CheckScalingIcons(css string, staticRoot string) []IconIssue {
+	icons := scalingIcons()
+	keys := make([]string, 0, len(icons))
+	for k := range icons {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var issues []IconIssue
+	for _, key := range keys {
+		class := iconClass(icons[key])
+		asset, ok := iconAssetURL(css, class)
+		if !ok {
+			issues = append(issues, IconIssue{Scaling: key, Class: class, Problem: IconMissingRule})
+			continue
+		}
+		if !staticFileExists(staticRoot, asset) {
+			issues = append(issues, IconIssue{Scaling: key, Class: class, Problem: IconMissingAsset, Asset: asset})
+		}
+	}
+	return issues
+}
+
+// iconClass returns the icon-specific class from a registry entry,
+// e.g. "ability-icon-ap" from "ability-token ability-icon ability-icon-ap".
+func iconClass(classes string) string {
+	fields := strings.Fields(classes)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// iconAssetURL finds the mask-image URL of the class's ::before rule.
+// Both the source (::before) and minified (:before) spellings are accepted.
+func iconAssetURL(css, class string) (string, bool) {
+	re := regexp.MustCompile(`\.` + regexp.QuoteMeta(class) + `::?before\s*\{[^}]*?mask-image:\s*url\(\s*['"]?([^'")\s]+)`)
+	m := re.FindStringSubmatch(css)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func staticFileExists(root, url string) bool {
+	rel, ok := strings.CutPrefix(url, staticURLPrefix)
+	if !ok {
+		// Remote or unusual URLs cannot be checked locally; trust them.
+		return strings.Contains(url, "://")
+	}
+	info, err := os.Stat(filepath.Join(root, filepath.FromSlash(path.Clean(rel))))
+	return err == nil && !info.IsDir()
+}
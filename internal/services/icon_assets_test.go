@@ -0,0 +1,49 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckScalingIcons(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "assets", "Stats"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "assets", "Stats", "AP.svg"), []byte("<svg/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	css := `.ability-icon-ap::before { mask-image: url('/static/assets/Stats/AP.svg'); }
+.ability-icon-ad:before{-webkit-mask-image:url(/static/assets/Stats/AD.svg);mask-image:url(/static/assets/Stats/AD.svg)}`
+
+	issues := CheckScalingIcons(css, root)
+
+	byKey := make(map[string]IconIssue)
+	for _, issue := range issues {
+		byKey[issue.Scaling] = issue
+	}
+	if _, ok := byKey["AP"]; ok {
+		t.Errorf("AP has rule and asset, got issue %+v", byKey["AP"])
+	}
+	if got := byKey["AD"]; got.Problem != IconMissingAsset || got.Asset != "/static/assets/Stats/AD.svg" {
+		t.Errorf("AD issue = %+v, want missing asset", got)
+	}
+	if got := byKey["MR"]; got.Problem != IconMissingRule || got.Class != "ability-icon-mr" {
+		t.Errorf("MR issue = %+v, want missing rule", got)
+	}
+	if len(issues) != len(scalingIcons())-1 {
+		t.Errorf("got %d issues, want %d", len(issues), len(scalingIcons())-1)
+	}
+}
+
+func TestCheckScalingIcons_BundledAssets(t *testing.T) {
+	css, err := os.ReadFile("../../static/dist/app.css")
+	if err != nil {
+		t.Skipf("built stylesheet unavailable: %v", err)
+	}
+	for _, issue := range CheckScalingIcons(string(css), "../../static") {
+		t.Errorf("scaling icon %s: %s %s", issue.Scaling, issue.Problem, issue.Asset)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// setItemsFile is the generated item JSON (CommunityDragon items for one set).
+type setItemsFile struct {
+	Items []setItem `json:"items"`
+}
+
+type setItem struct {
+	APIName     string             `json:"apiName"`
+	Name        string             `json:"name"`
+	Desc        string             `json:"desc"`
+	Composition []string           `json:"composition"`
+	Effects     map[string]float64 `json:"effects"`
+}
+
+// readItems loads the item catalog, keyed by name slug like board codes.
+// When several items share a name, the first one wins.
+func readItems(path string, icons map[string]string) ([]models.Item, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var file setItemsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(file.Items))
+	items := make([]models.Item, 0, len(file.Items))
+	for _, it := range file.Items {
+		name := strings.TrimSpace(it.Name)
+		slug := unitSlug(name)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		icon := icons[slug]
+		if icon == "" {
+			icon = icons[unitSlug(it.APIName)]
+		}
+		items = append(items, models.Item{
+			Name:        name,
+			Slug:        slug,
+			APIName:     it.APIName,
+			Icon:        icon,
+			Description: itemDescription(it),
+			Components:  it.Composition,
+		})
+	}
+	return items, nil
+}
+
+// fillDescValues replaces @Name@ and @Name*100@ placeholders with values,
+// rounded to two decimals. Unknown placeholders are left as written.
+func fillDescValues(desc string, values map[string]float64) string {
+	return descVarRef.ReplaceAllStringFunc(desc, func(token string) string {
+		m := descVarRef.FindStringSubmatch(token)
+		v, ok := values[m[1]]
+		if !ok {
+			return token
+		}
+		if strings.Contains(token, "*100") {
+			v *= 100
+		}
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	})
+}
+
+// itemDescription fills @Effect@ placeholders from the item's effects and
+// strips the client markup. Unknown placeholders are left as written.
+func itemDescription(it setItem) string {
+	desc := fillDescValues(it.Desc, it.Effects)
+	desc = descScaleMarker.ReplaceAllString(desc, "")
+	return plainTraitDescription(strings.ReplaceAll(desc, "&nbsp;", " "))
+}
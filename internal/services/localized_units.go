@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// LocalizedPath returns the per-locale variant of a data file, e.g.
+// data/set16_champions.json becomes data/set16_champions.fr.json.
+func LocalizedPath(path, locale string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + locale + ext
+}
+
+// loadLocalized translates base into every configured locale that has a
+// set file next to the default one. Locales without a file are skipped and
+// fall back to base.
+func (l *LocalUnitsLoader) loadLocalized(ctx context.Context, base *models.UnitsData, baseSet *setFile) (map[string]*models.UnitsData, error) {
+	localized := make(map[string]*models.UnitsData, len(l.cfg.Locales))
+	for _, locale := range l.cfg.Locales {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path := LocalizedPath(l.cfg.SetDataPath, locale)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		loc, err := readSetFile(path)
+		if err != nil {
+			return nil, err
+		}
+		localized[locale] = localizeUnits(base, baseSet, loc)
+	}
+	return localized, nil
+}
+
+// localizeUnits copies base with the names and descriptions from loc.
+// Slugs, icons and numbers stay those of base, so board codes and URLs are
+// the same in every language. Anything loc lacks stays in English.
+func localizeUnits(base *models.UnitsData, baseSet, loc *setFile) *models.UnitsData {
+	champions := make(map[string]setChampion, len(loc.Champions))
+	for _, ch := range loc.Champions {
+		champions[ch.APIName] = ch
+	}
+	baseChampions := make(map[string]setChampion, len(baseSet.Champions))
+	for _, ch := range baseSet.Champions {
+		baseChampions[ch.APIName] = ch
+	}
+
+	// Champions list trait names, so translations pair up by position.
+	traitNames := make(map[string]string)
+	for apiName, ch := range champions {
+		baseCh, ok := baseChampions[apiName]
+		if !ok || len(baseCh.Traits) != len(ch.Traits) {
+			continue
+		}
+		for i, name := range baseCh.Traits {
+			if translated := strings.TrimSpace(ch.Traits[i]); translated != "" {
+				traitNames[strings.TrimSpace(name)] = translated
+			}
+		}
+	}
+	traitDescs := make(map[string]string, len(loc.Traits))
+	for _, t := range loc.Traits {
+		traitDescs[strings.TrimSpace(t.Name)] = plainTraitDescription(t.Desc)
+	}
+
+	out := *base
+	out.Version = base.Version + "." + loc.version
+	out.Units = make([]models.Unit, len(base.Units))
+	for i, u := range base.Units {
+		if ch, ok := champions[u.APIName]; ok {
+			if name := strings.TrimSpace(ch.Name); name != "" {
+				u.Name = name
+			}
+			if ch.Ability.Description != "" || ch.Ability.DescriptionRaw != "" {
+				u.Ability = adaptAbility(ch.Ability, u.Ability.Icon)
+			}
+			if ch.UnlockDescription != "" {
+				u.UnlockDescription = ch.UnlockDescription
+			}
+			if ch.Role != "" {
+				u.Role = ch.Role
+			}
+		}
+		traits := make([]models.Trait, len(u.Traits))
+		for j, t := range u.Traits {
+			if name, ok := traitNames[t.Name]; ok {
+				t.Name = name
+			}
+			traits[j] = t
+		}
+		u.Traits = traits
+		out.Units[i] = u
+	}
+
+	out.Traits = make([]models.TraitInfo, len(base.Traits))
+	for i, t := range base.Traits {
+		if name, ok := traitNames[t.Name]; ok {
+			t.Name = name
+			if desc := traitDescs[name]; desc != "" {
+				t.Description = desc
+			}
+		}
+		out.Traits[i] = t
+	}
+	return &out
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// MetaDescriptionMax is the length search engines show before truncating.
+const MetaDescriptionMax = 160
+
+var (
+	htmlTag = regexp.MustCompile(`<[^>]*>`)
+	// Removing tags can leave spaces inside brackets and before punctuation.
+	spaceBeforePunct = regexp.MustCompile(`\s+([,.;:!?)])`)
+	spaceAfterParen  = regexp.MustCompile(`\(\s+`)
+)
+
+// ClampDescription turns text or HTML into a single line of at most max
+// runes, cutting at a word boundary and ending with an ellipsis if needed.
+func ClampDescription(text string, max int) string {
+	text = htmlTag.ReplaceAllString(html.UnescapeString(text), " ")
+	text = strings.Join(strings.Fields(text), " ")
+	text = spaceBeforePunct.ReplaceAllString(text, "$1")
+	text = spaceAfterParen.ReplaceAllString(text, "(")
+
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	cut := string(runes[:max-1])
+	if i := strings.LastIndexByte(cut, ' '); i > max/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:–-") + "…"
+}
+
+// UnitMetaDescription summarizes a unit's cost, traits and ability.
+func UnitMetaDescription(u models.Unit) string {
+	traits := make([]string, len(u.Traits))
+	for i, t := range u.Traits {
+		traits[i] = t.Name
+	}
+	desc := fmt.Sprintf("%s is a %d-cost %s champion.", u.Name, u.Cost, strings.Join(traits, " / "))
+	if u.Ability.Name != "" {
+		desc += fmt.Sprintf(" %s: %s", u.Ability.Name, FormatAbilityDescription(u.Ability))
+	}
+	return ClampDescription(desc, MetaDescriptionMax)
+}
+
+// TraitMetaDescription summarizes a trait's effect and the units carrying it.
+func TraitMetaDescription(t models.TraitInfo, units []models.Unit) string {
+	names := make([]string, len(units))
+	for i, u := range units {
+		names[i] = u.Name
+	}
+	desc := t.Name + " trait."
+	if len(names) > 0 {
+		desc += " Units: " + strings.Join(names, ", ") + "."
+	}
+	if t.Description != "" {
+		desc += " " + t.Description
+	}
+	return ClampDescription(desc, MetaDescriptionMax)
+}
+
+// CompMetaDescription lists the units fielded on board and its synergies.
+// It returns "" for an empty board so pages fall back to the site default.
+func CompMetaDescription(board models.BoardState, units []models.Unit, synergies []Synergy) string {
+	bySlug := make(map[string]string, len(units))
+	for _, u := range units {
+		bySlug[u.Slug] = u.Name
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, p := range board.Placements {
+		name, ok := bySlug[p.Unit]
+		if !ok || seen[p.Unit] || p.Row >= models.BoardRows {
+			continue
+		}
+		seen[p.Unit] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	desc := fmt.Sprintf("Team comp with %d units: %s.", len(names), strings.Join(names, ", "))
+	var traits []string
+	for _, s := range synergies {
+		traits = append(traits, fmt.Sprintf("%s %d", s.Name, s.Count))
+	}
+	if len(traits) > 0 {
+		desc += " Traits: " + strings.Join(traits, ", ") + "."
+	}
+	return ClampDescription(desc, MetaDescriptionMax)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"sft/internal/models"
+)
+
+func TestClampDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"short", "Deals damage.", 20, "Deals damage."},
+		{"strips markup", "Deals <b>100</b>&nbsp;damage<br>to   all", 40, "Deals 100 damage to all"},
+		{"cuts at a word", "one two three four five", 14, "one two three…"},
+		{"trims punctuation", "alpha, beta gamma", 13, "alpha, beta…"},
+		{"long word", "supercalifragilistic", 10, "supercali…"},
+		{"tidies spacing", "hits <i>the</i> target , dealing ( <span>AP</span> ) damage", 60, "hits the target, dealing (AP) damage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClampDescription(tt.in, tt.max)
+			if got != tt.want {
+				t.Errorf("ClampDescription(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.max {
+				t.Errorf("result has %d runes, max %d", n, tt.max)
+			}
+		})
+	}
+}
+
+func TestUnitMetaDescription(t *testing.T) {
+	u := models.Unit{
+		Name: "Lux", Cost: 4,
+		Traits:  []models.Trait{{Name: "Demacia"}, {Name: "Arcanist"}},
+		Ability: models.Ability{Name: "Final Spark", Description: "Fire a <b>laser</b> " + strings.Repeat("through enemies ", 20)},
+	}
+	got := UnitMetaDescription(u)
+	if !strings.HasPrefix(got, "Lux is a 4-cost Demacia / Arcanist champion. Final Spark: Fire a laser through") {
+		t.Errorf("unexpected description %q", got)
+	}
+	if utf8.RuneCountInString(got) > MetaDescriptionMax || !strings.HasSuffix(got, "…") {
+		t.Errorf("description not clamped: %q", got)
+	}
+}
+
+func TestCompMetaDescription(t *testing.T) {
+	units := []models.Unit{{Name: "Sion", Slug: "sion"}, {Name: "Vi", Slug: "vi"}}
+	board := models.BoardState{Placements: []models.Placement{
+		{Row: 0, Unit: "sion"}, {Row: 1, Unit: "vi"}, {Row: models.BoardRows, Unit: "vi"},
+	}}
+	got := CompMetaDescription(board, units, []Synergy{{Name: "Bruiser", Count: 2}})
+	if want := "Team comp with 2 units: Sion, Vi. Traits: Bruiser 2."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := CompMetaDescription(models.BoardState{}, units, nil); got != "" {
+		t.Errorf("empty board should have no description, got %q", got)
+	}
+}
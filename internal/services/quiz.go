@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// Quiz question kinds.
+const (
+	QuizCost    = "cost"
+	QuizTrait   = "trait"
+	QuizAbility = "ability"
+)
+
+const quizChoices = 4
+
+var quizKinds = []string{QuizCost, QuizTrait, QuizAbility}
+
+// QuizQuestion is a multiple-choice question about the dataset.
+// Like drills, a question is fully determined by its seed and the dataset.
+type QuizQuestion struct {
+	Seed    uint64   `json:"seed"`
+	Kind    string   `json:"kind"`
+	Unit    string   `json:"unit,omitempty"` // slug of the unit the question is about
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices"`
+	Answer  string   `json:"-"`
+}
+
+// Check reports whether answer matches the correct choice, ignoring case and spacing.
+func (q QuizQuestion) Check(answer string) bool {
+	return strings.EqualFold(strings.TrimSpace(answer), q.Answer)
+}
+
+// GenerateQuizQuestion builds the question for seed from units.
+func GenerateQuizQuestion(seed uint64, units []models.Unit) (QuizQuestion, error) {
+	if len(units) < quizChoices {
+		return QuizQuestion{}, errors.New("quiz needs at least 4 units")
+	}
+	rng := rand.New(rand.NewPCG(seed, seed>>32|1))
+
+	pool := make([]models.Unit, len(units))
+	copy(pool, units)
+	sort.Slice(pool, func(i, j int) bool { return pool[i].Slug < pool[j].Slug })
+
+	q := QuizQuestion{Seed: seed, Kind: quizKinds[rng.IntN(len(quizKinds))]}
+	unit := pool[rng.IntN(len(pool))]
+	q.Unit = unit.Slug
+
+	switch q.Kind {
+	case QuizCost:
+		q.Prompt = fmt.Sprintf("How much does %s cost?", unit.Name)
+		q.Answer = strconv.Itoa(unit.Cost)
+		others := distinctValues(pool, func(u models.Unit) []string {
+			if u.Cost == unit.Cost {
+				return nil
+			}
+			return []string{strconv.Itoa(u.Cost)}
+		})
+		q.Choices = pickChoices(rng, q.Answer, others)
+		sort.Strings(q.Choices) // costs read best in order
+
+	case QuizTrait:
+		if len(unit.Traits) == 0 {
+			return abilityQuestion(q, unit, pool, rng), nil
+		}
+		q.Prompt = fmt.Sprintf("Which trait does %s have?", unit.Name)
+		q.Answer = unit.Traits[rng.IntN(len(unit.Traits))].Name
+		own := make(map[string]bool, len(unit.Traits))
+		for _, t := range unit.Traits {
+			own[t.Name] = true
+		}
+		others := distinctValues(pool, func(u models.Unit) []string {
+			var names []string
+			for _, t := range u.Traits {
+				if !own[t.Name] {
+					names = append(names, t.Name)
+				}
+			}
+			return names
+		})
+		q.Choices = pickChoices(rng, q.Answer, others)
+
+	case QuizAbility:
+		return abilityQuestion(q, unit, pool, rng), nil
+	}
+	return q, nil
+}
+
+// abilityQuestion asks which unit casts an ability. Every unit has one,
+// so it also stands in for trait questions about traitless units.
+func abilityQuestion(q QuizQuestion, unit models.Unit, pool []models.Unit, rng *rand.Rand) QuizQuestion {
+	q.Kind = QuizAbility
+	q.Prompt = fmt.Sprintf("Which unit casts %s?", unit.Ability.Name)
+	q.Answer = unit.Name
+	others := distinctValues(pool, func(u models.Unit) []string {
+		if u.Slug == unit.Slug || u.Ability.Name == unit.Ability.Name {
+			return nil
+		}
+		return []string{u.Name}
+	})
+	q.Choices = pickChoices(rng, q.Answer, others)
+	return q
+}
+
+// pickChoices returns the answer plus up to quizChoices-1 shuffled distractors.
+func pickChoices(rng *rand.Rand, answer string, distractors []string) []string {
+	rng.Shuffle(len(distractors), func(i, j int) { distractors[i], distractors[j] = distractors[j], distractors[i] })
+	choices := append([]string{answer}, distractors[:min(len(distractors), quizChoices-1)]...)
+	rng.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+	return choices
+}
+
+// distinctValues collects the sorted unique values of fn over units.
+func distinctValues(units []models.Unit, fn func(models.Unit) []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, u := range units {
+		for _, v := range fn(u) {
+			if v != "" && !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
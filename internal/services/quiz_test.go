@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func quizUnits() []models.Unit {
+	var units []models.Unit
+	for i := 0; i < 10; i++ {
+		units = append(units, models.Unit{
+			Name:    fmt.Sprintf("Unit %d", i),
+			Slug:    fmt.Sprintf("unit%d", i),
+			Cost:    i%5 + 1,
+			Traits:  []models.Trait{{Name: fmt.Sprintf("Trait %d", i%3)}, {Name: fmt.Sprintf("Origin %d", i%4)}},
+			Ability: models.Ability{Name: fmt.Sprintf("Spell %d", i)},
+		})
+	}
+	return units
+}
+
+func TestGenerateQuizQuestion(t *testing.T) {
+	units := quizUnits()
+	kinds := make(map[string]bool)
+
+	for seed := uint64(0); seed < 50; seed++ {
+		q, err := GenerateQuizQuestion(seed, units)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		kinds[q.Kind] = true
+
+		if !slices.Contains(q.Choices, q.Answer) {
+			t.Errorf("seed %d: answer %q missing from choices %v", seed, q.Answer, q.Choices)
+		}
+		if len(q.Choices) < 2 || len(q.Choices) > quizChoices {
+			t.Errorf("seed %d: got %d choices", seed, len(q.Choices))
+		}
+		if !q.Check(" " + q.Answer + " ") {
+			t.Errorf("seed %d: Check rejected the answer", seed)
+		}
+
+		again, _ := GenerateQuizQuestion(seed, units)
+		if !reflect.DeepEqual(q, again) {
+			t.Errorf("seed %d: question is not deterministic", seed)
+		}
+	}
+
+	for _, kind := range quizKinds {
+		if !kinds[kind] {
+			t.Errorf("no %s question generated in 50 seeds", kind)
+		}
+	}
+
+	if _, err := GenerateQuizQuestion(1, units[:2]); err == nil {
+		t.Error("expected error for a tiny dataset")
+	}
+}
+
+func TestGenerateQuizQuestion_TraitDistractors(t *testing.T) {
+	units := quizUnits()
+	for seed := uint64(0); seed < 50; seed++ {
+		q, _ := GenerateQuizQuestion(seed, units)
+		if q.Kind != QuizTrait {
+			continue
+		}
+		var unit models.Unit
+		for _, u := range units {
+			if u.Slug == q.Unit {
+				unit = u
+			}
+		}
+		for _, c := range q.Choices {
+			if c == q.Answer {
+				continue
+			}
+			for _, tr := range unit.Traits {
+				if tr.Name == c {
+					t.Errorf("seed %d: distractor %q is also a trait of %s", seed, c, unit.Name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// readRecommendedItems loads a supplementary recommendations file mapping a
+// unit API name (or slug) to item names, best first.
+func readRecommendedItems(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var recs map[string][]string
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return recs, nil
+}
+
+// applyRecommendedItems replaces the recommendations from the set data with
+// those in extra, then resolves every item against the catalog. Items the
+// catalog does not know keep just their name and slug.
+func applyRecommendedItems(units []models.Unit, extra map[string][]string, catalog []models.Item) {
+	byKey := make(map[string]models.Item, 2*len(catalog))
+	for _, item := range catalog {
+		byKey[item.Slug] = item
+		if item.APIName != "" {
+			byKey[unitSlug(item.APIName)] = item
+		}
+	}
+
+	for i := range units {
+		u := &units[i]
+		if names, ok := extra[u.APIName]; ok {
+			u.RecommendedItems = namedItems(names)
+		} else if names, ok := extra[u.Slug]; ok {
+			u.RecommendedItems = namedItems(names)
+		}
+		for j, item := range u.RecommendedItems {
+			if full, ok := byKey[item.Slug]; ok {
+				u.RecommendedItems[j] = full
+			}
+		}
+	}
+}
+
+// namedItems turns item names or API names into unresolved catalog references.
+func namedItems(names []string) []models.Item {
+	var items []models.Item
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if slug := unitSlug(name); slug != "" {
+			items = append(items, models.Item{Name: name, Slug: slug})
+		}
+	}
+	return items
+}
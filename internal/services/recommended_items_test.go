@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestApplyRecommendedItems(t *testing.T) {
+	catalog := []models.Item{
+		{Name: "Infinity Edge", Slug: "infinityedge", APIName: "TFT_Item_InfinityEdge", Icon: "ie.png"},
+	}
+	units := []models.Unit{
+		{Slug: "jinx", APIName: "TFT16_Jinx", RecommendedItems: namedItems([]string{"Infinity Edge"})},
+		{Slug: "lux", APIName: "TFT16_Lux", RecommendedItems: namedItems([]string{"Blue Buff"})},
+		{Slug: "vi", APIName: "TFT16_Vi"},
+	}
+	extra := map[string][]string{
+		"TFT16_Lux": {"TFT_Item_InfinityEdge", " Jeweled Gauntlet ", ""},
+		"vi":        {"Titan's Resolve"},
+	}
+
+	applyRecommendedItems(units, extra, catalog)
+
+	if got := units[0].RecommendedItems; len(got) != 1 || got[0].Icon != "ie.png" {
+		t.Errorf("jinx: set data recommendation not resolved: %+v", got)
+	}
+	if got := units[1].RecommendedItems; len(got) != 2 || got[0].Name != "Infinity Edge" || got[1].Name != "Jeweled Gauntlet" || got[1].Icon != "" {
+		t.Errorf("lux: supplement should replace set data and resolve by API name: %+v", got)
+	}
+	if got := units[2].RecommendedItems; len(got) != 1 || got[0].Slug != "titansresolve" {
+		t.Errorf("vi: supplement keyed by slug not applied: %+v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultScalingIconsJSON maps scaling keys (as normalizeScalingKey
+// returns them) to the classes of their icon span.
+//
+//go:embed scaling_icons.json
+var defaultScalingIconsJSON []byte
+
+var scalingIconRegistry atomic.Pointer[map[string]string]
+
+func init() {
+	icons, err := parseScalingIcons(defaultScalingIconsJSON)
+	if err != nil {
+		panic(err) // compiled in, so only a bad edit gets here
+	}
+	scalingIconRegistry.Store(&icons)
+}
+
+// scalingIcons returns the current scaling icon registry. Callers must not
+// modify it.
+func scalingIcons() map[string]string {
+	return *scalingIconRegistry.Load()
+}
+
+// LoadScalingIcons adds the scaling icons in the JSON file at path to the
+// built-in ones, so a set's new scalings need only a data change. Entries in
+// the file win; an empty class removes a built-in icon. On error the
+// registry is left as it was.
+func LoadScalingIcons(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read scaling icons: %w", err)
+	}
+	overrides, err := parseScalingIcons(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	icons, _ := parseScalingIcons(defaultScalingIconsJSON)
+	for key, classes := range overrides {
+		if classes == "" {
+			delete(icons, key)
+			continue
+		}
+		icons[key] = classes
+	}
+	scalingIconRegistry.Store(&icons)
+	return nil
+}
+
+func parseScalingIcons(data []byte) (map[string]string, error) {
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decode scaling icons: %w", err)
+	}
+	icons := make(map[string]string, len(raw))
+	for key, classes := range raw {
+		norm := normalizeScalingKey(key)
+		if norm == "" {
+			return nil, fmt.Errorf("scaling icon key %q has no letters or digits", key)
+		}
+		icons[norm] = strings.Join(strings.Fields(classes), " ")
+	}
+	return icons, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadScalingIcons(t *testing.T) {
+	defaults := scalingIconRegistry.Load()
+	t.Cleanup(func() { scalingIconRegistry.Store(defaults) })
+
+	path := filepath.Join(t.TempDir(), "icons.json")
+	if err := os.WriteFile(path, []byte(`{"omnivamp": "ability-token ability-icon ability-icon-omnivamp", "SOULS": ""}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadScalingIcons(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := scalingIconClass("Omnivamp"); got != "ability-token ability-icon ability-icon-omnivamp" {
+		t.Errorf("added icon = %q", got)
+	}
+	if got := scalingIconClass("AP"); got == "" {
+		t.Error("built-in AP icon lost")
+	}
+	if got := scalingIconClass("Souls"); got != "" {
+		t.Errorf("removed icon = %q, want none", got)
+	}
+
+	before := scalingIcons()
+	if err := os.WriteFile(path, []byte(`{"AP": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadScalingIcons(path); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if len(scalingIcons()) != len(before) {
+		t.Error("failed load replaced the registry")
+	}
+}
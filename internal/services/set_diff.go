@@ -0,0 +1,188 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SetDiff is what changed between two versions of the set data, the
+// comparison patch notes are written from.
+type SetDiff struct {
+	From    string     `json:"from"` // dataset version of the old file
+	To      string     `json:"to"`
+	Added   []UnitRef  `json:"added"`
+	Removed []UnitRef  `json:"removed"`
+	Changed []UnitDiff `json:"changed"`
+}
+
+// UnitRef names a unit in a SetDiff.
+type UnitRef struct {
+	APIName string `json:"apiName"`
+	Name    string `json:"name"`
+	Cost    int    `json:"cost"`
+}
+
+// UnitDiff lists the changed values of a unit present in both versions.
+type UnitDiff struct {
+	UnitRef
+	Stats   []ValueChange `json:"stats,omitempty"`
+	Ability []ValueChange `json:"ability,omitempty"` // one per ability variable
+}
+
+// ValueChange is one value before and after. Old or New is empty when the
+// value was added or removed; star-level values are joined with "/".
+type ValueChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Empty reports whether the versions have no differences.
+func (d *SetDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffSetFiles reads two set JSON files and diffs them.
+func DiffSetFiles(oldPath, newPath string) (*SetDiff, error) {
+	oldSet, err := readSetFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newSet, err := readSetFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return DiffSets(oldSet, newSet), nil
+}
+
+// DiffSets compares champions by apiName (name when it is missing):
+// units only in new are added, units only in old removed, and units in
+// both are changed when a stat or ability variable differs. Every list is
+// sorted by cost, then name.
+func DiffSets(old, new *setFile) *SetDiff {
+	diff := &SetDiff{
+		From:    old.version,
+		To:      new.version,
+		Added:   []UnitRef{},
+		Removed: []UnitRef{},
+		Changed: []UnitDiff{},
+	}
+
+	before := make(map[string]setChampion, len(old.Champions))
+	for _, ch := range old.Champions {
+		before[championKey(ch)] = ch
+	}
+	seen := make(map[string]bool, len(new.Champions))
+	for _, ch := range new.Champions {
+		key := championKey(ch)
+		seen[key] = true
+		prev, ok := before[key]
+		if !ok {
+			diff.Added = append(diff.Added, championRef(ch))
+			continue
+		}
+		stats := diffStats(prev, ch)
+		ability := diffAbility(prev.Ability, ch.Ability)
+		if len(stats) > 0 || len(ability) > 0 {
+			diff.Changed = append(diff.Changed, UnitDiff{UnitRef: championRef(ch), Stats: stats, Ability: ability})
+		}
+	}
+	for _, ch := range old.Champions {
+		if !seen[championKey(ch)] {
+			diff.Removed = append(diff.Removed, championRef(ch))
+		}
+	}
+
+	sortUnitRefs(diff.Added)
+	sortUnitRefs(diff.Removed)
+	sort.SliceStable(diff.Changed, func(i, j int) bool {
+		return unitRefLess(diff.Changed[i].UnitRef, diff.Changed[j].UnitRef)
+	})
+	return diff
+}
+
+func championKey(ch setChampion) string {
+	if ch.APIName != "" {
+		return ch.APIName
+	}
+	return ch.Name
+}
+
+func championRef(ch setChampion) UnitRef {
+	return UnitRef{APIName: ch.APIName, Name: ch.Name, Cost: ch.Cost}
+}
+
+func sortUnitRefs(refs []UnitRef) {
+	sort.SliceStable(refs, func(i, j int) bool { return unitRefLess(refs[i], refs[j]) })
+}
+
+func unitRefLess(a, b UnitRef) bool {
+	if a.Cost != b.Cost {
+		return a.Cost < b.Cost
+	}
+	return a.Name < b.Name
+}
+
+// diffStats compares the cost and base stats of a unit, in tooltip order.
+func diffStats(old, new setChampion) []ValueChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"cost", strconv.Itoa(old.Cost), strconv.Itoa(new.Cost)},
+		{"hp", joinDisplayValues(old.Stats.HP.Display()), joinDisplayValues(new.Stats.HP.Display())},
+		{"damage", joinDisplayValues(old.Stats.Damage.Display()), joinDisplayValues(new.Stats.Damage.Display())},
+		{"attackSpeed", formatFloat(old.Stats.AttackSpeed), formatFloat(new.Stats.AttackSpeed)},
+		{"armor", formatFloat(old.Stats.Armor), formatFloat(new.Stats.Armor)},
+		{"magicResist", formatFloat(old.Stats.MagicResist), formatFloat(new.Stats.MagicResist)},
+		{"range", formatFloat(old.Stats.Range), formatFloat(new.Stats.Range)},
+		{"mana", formatFloat(old.Stats.Mana), formatFloat(new.Stats.Mana)},
+		{"initialMana", formatFloat(old.Stats.InitialMana), formatFloat(new.Stats.InitialMana)},
+		{"critChance", formatFloat(old.Stats.CritChance), formatFloat(new.Stats.CritChance)},
+	}
+
+	var changes []ValueChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, ValueChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// diffAbility compares ability variables by name, sorted by name.
+func diffAbility(old, new setAbility) []ValueChange {
+	before, after := abilityValues(old), abilityValues(new)
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []ValueChange
+	for _, name := range names {
+		if before[name] != after[name] {
+			changes = append(changes, ValueChange{Field: name, Old: before[name], New: after[name]})
+		}
+	}
+	return changes
+}
+
+// abilityValues maps each ability variable to its joined display values,
+// whichever of the two variable formats the file uses.
+func abilityValues(a setAbility) map[string]string {
+	values := make(map[string]string, len(a.Variables.Map)+len(a.Variables.List))
+	for name, v := range a.Variables.Map {
+		values[strings.TrimSpace(name)] = joinDisplayValues(v.Values.Display())
+	}
+	for _, v := range a.Variables.List {
+		values[strings.TrimSpace(v.Name)] = joinDisplayValues(v.Value.Display())
+	}
+	return values
+}
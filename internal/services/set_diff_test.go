@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func decodeSet(t *testing.T, raw string) *setFile {
+	t.Helper()
+	var set setFile
+	if err := json.Unmarshal([]byte(raw), &set); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	set.version = datasetVersion([]byte(raw))
+	return &set
+}
+
+func TestDiffSets(t *testing.T) {
+	old := decodeSet(t, `{"champions": [
+		{"apiName": "TFT16_Ahri", "name": "Ahri", "cost": 3, "stats": {"hp": [650, 1170, 2106], "armor": 30},
+		 "ability": {"variables": {"Damage": {"values": [200, 300, 450]}, "Duration": {"values": [2]}}}},
+		{"apiName": "TFT16_Jinx", "name": "Jinx", "cost": 4, "stats": {"range": 4}},
+		{"apiName": "TFT16_Lux", "name": "Lux", "cost": 1}
+	]}`)
+	new := decodeSet(t, `{"champions": [
+		{"apiName": "TFT16_Ahri", "name": "Ahri", "cost": 3, "stats": {"hp": [700, 1260, 2268], "armor": 30},
+		 "ability": {"variables": {"Damage": {"values": [200, 300, 500]}, "Shield": {"values": [100]}}}},
+		{"apiName": "TFT16_Jinx", "name": "Jinx", "cost": 4, "stats": {"range": 4}},
+		{"apiName": "TFT16_Zed", "name": "Zed", "cost": 5},
+		{"apiName": "TFT16_Annie", "name": "Annie", "cost": 2}
+	]}`)
+
+	diff := DiffSets(old, new)
+	if diff.From != old.version || diff.To != new.version {
+		t.Errorf("versions = %s..%s", diff.From, diff.To)
+	}
+	if len(diff.Added) != 2 || diff.Added[0].Name != "Annie" || diff.Added[1].Name != "Zed" {
+		t.Errorf("added = %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].APIName != "TFT16_Lux" {
+		t.Errorf("removed = %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "Ahri" {
+		t.Fatalf("changed = %+v", diff.Changed)
+	}
+	ahri := diff.Changed[0]
+	if want := []ValueChange{{Field: "hp", Old: "650/1170/2106", New: "700/1260/2268"}}; !reflect.DeepEqual(ahri.Stats, want) {
+		t.Errorf("stats = %+v, want %+v", ahri.Stats, want)
+	}
+	want := []ValueChange{
+		{Field: "Damage", Old: "200/300/450", New: "200/300/500"},
+		{Field: "Duration", Old: "2", New: ""},
+		{Field: "Shield", Old: "", New: "100"},
+	}
+	if !reflect.DeepEqual(ahri.Ability, want) {
+		t.Errorf("ability = %+v, want %+v", ahri.Ability, want)
+	}
+
+	if same := DiffSets(new, new); !same.Empty() {
+		t.Errorf("diffing a set with itself: %+v", same)
+	}
+}
+
+func TestLocalUnitsLoader_LatestDiff(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, cost int) string {
+		path := filepath.Join(dir, name+".json")
+		content := `{"champions": [{"name": "Ahri", "cost": ` + strconv.Itoa(cost) + `, "icons": {"portrait": "https://cdn.example/p.png"}}]}`
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	previous := write("previous", 2)
+	current := write("current", 3)
+
+	loader := NewUnitsLoader(LoadUnitsConfig{SetDataPath: current, PreviousSetDataPath: previous})
+	if _, err := loader.LoadUnits(context.Background()); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	diff := loader.LatestDiff()
+	if diff == nil || len(diff.Changed) != 1 || diff.Changed[0].Stats[0].New != "3" {
+		t.Fatalf("diff against the previous file = %+v", diff)
+	}
+
+	if err := loader.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if loader.LatestDiff() != diff {
+		t.Error("a reload with the same data should keep the diff")
+	}
+
+	write("current", 4)
+	if err := loader.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	diff = loader.LatestDiff()
+	if diff == nil || len(diff.Changed) != 1 || diff.Changed[0].Stats[0].Old != "3" || diff.Changed[0].Stats[0].New != "4" {
+		t.Errorf("diff after reload = %+v", diff)
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrUnknownVersion is returned for a dataset version that is neither
+// loaded nor archived.
+var ErrUnknownVersion = errors.New("unknown dataset version")
+
+// snapshotTimeLayout starts archived file names, so they sort by time.
+const snapshotTimeLayout = "20060102T150405Z"
+
+// SetSnapshot is an archived copy of the set data.
+type SetSnapshot struct {
+	Version  string    `json:"version"`
+	Archived time.Time `json:"archived"`
+
+	path string
+}
+
+// SetHistory archives set data files in a directory, one file per dataset
+// version, named "<UTC time>-<version>.json".
+type SetHistory struct {
+	dir string
+}
+
+// NewSetHistory returns an archive in dir, which is created on the first
+// Archive.
+func NewSetHistory(dir string) *SetHistory {
+	return &SetHistory{dir: dir}
+}
+
+// Archive copies the set file at path into the history unless version is
+// already there. The file must still hash to version, so data replaced
+// since it was loaded is not archived under the wrong version.
+func (h *SetHistory) Archive(path, version string, at time.Time) error {
+	if _, err := h.find(version); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrUnknownVersion) {
+		return err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("archive %s: %w", path, err)
+	}
+	if got := datasetVersion(raw); got != version {
+		return fmt.Errorf("archive %s: file is version %s now, not %s", path, got, version)
+	}
+	if err := os.MkdirAll(h.dir, 0o755); err != nil {
+		return fmt.Errorf("archive %s: %w", path, err)
+	}
+	name := at.UTC().Format(snapshotTimeLayout) + "-" + version + ".json"
+	if err := writeFileAtomic(filepath.Join(h.dir, name), bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("archive %s: %w", path, err)
+	}
+	return nil
+}
+
+// Snapshots lists the archived versions, newest first. A missing
+// directory is an empty history.
+func (h *SetHistory) Snapshots() ([]SetSnapshot, error) {
+	entries, err := os.ReadDir(h.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []SetSnapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	snapshots := []SetSnapshot{}
+	for _, e := range entries {
+		stamp, version, ok := strings.Cut(strings.TrimSuffix(e.Name(), ".json"), "-")
+		if e.IsDir() || !ok || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		archived, err := time.Parse(snapshotTimeLayout, stamp)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, SetSnapshot{
+			Version:  version,
+			Archived: archived,
+			path:     filepath.Join(h.dir, e.Name()),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Archived.After(snapshots[j].Archived)
+	})
+	return snapshots, nil
+}
+
+// read parses the archived set file of version.
+func (h *SetHistory) read(version string) (*setFile, error) {
+	snapshot, err := h.find(version)
+	if err != nil {
+		return nil, err
+	}
+	return readSetFile(snapshot.path)
+}
+
+// find returns the snapshot of version, or ErrUnknownVersion.
+func (h *SetHistory) find(version string) (SetSnapshot, error) {
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return SetSnapshot{}, err
+	}
+	for _, s := range snapshots {
+		if s.Version == version {
+			return s, nil
+		}
+	}
+	return SetSnapshot{}, fmt.Errorf("%w: %q", ErrUnknownVersion, version)
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSetHistory_Archive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set.json")
+	history := NewSetHistory(filepath.Join(dir, "history"))
+
+	if got, err := history.Snapshots(); err != nil || len(got) != 0 {
+		t.Fatalf("empty history = %v, %v", got, err)
+	}
+
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var versions []string
+	for i, body := range []string{`{"champions": []}`, `{"champions": [{"name": "Ahri"}]}`} {
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		version := datasetVersion([]byte(body))
+		versions = append(versions, version)
+		for range 2 { // archiving a version twice keeps one copy
+			if err := history.Archive(path, version, at.Add(time.Duration(i)*time.Hour)); err != nil {
+				t.Fatalf("archive: %v", err)
+			}
+		}
+	}
+	if err := history.Archive(path, "stale", at); err == nil {
+		t.Error("expected an error archiving a file under another version")
+	}
+
+	got, err := history.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Version != versions[1] || got[1].Version != versions[0] || !got[1].Archived.Equal(at) {
+		t.Errorf("snapshots = %+v", got)
+	}
+	if set, err := history.read(versions[1]); err != nil || len(set.Champions) != 1 {
+		t.Errorf("read: %v", err)
+	}
+	if _, err := history.read("missing"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("read missing: expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+func TestLocalUnitsLoader_History(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set.json")
+	write := func(cost int) {
+		content := `{"champions": [{"name": "Ahri", "cost": ` + strconv.Itoa(cost) + `, "icons": {"portrait": "https://cdn.example/p.png"}}]}`
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(2)
+	loader := NewUnitsLoader(LoadUnitsConfig{SetDataPath: path, HistoryDir: filepath.Join(dir, "history")})
+	ctx := context.Background()
+	first, err := loader.LoadUnits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loader.LatestDiff() != nil {
+		t.Error("the first version has nothing to diff against")
+	}
+
+	// Snapshot names have second resolution.
+	time.Sleep(time.Second)
+	write(3)
+	if err := loader.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := loader.Versions(ctx)
+	if err != nil || len(versions) != 2 || versions[1].Version != first.Version {
+		t.Fatalf("versions = %+v, %v", versions, err)
+	}
+
+	past, err := loader.LoadVersion(ctx, first.Version)
+	if err != nil || past.Units[0].Cost != 2 {
+		t.Errorf("past version = %+v, %v", past, err)
+	}
+	if _, err := loader.LoadVersion(ctx, "missing"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("expected ErrUnknownVersion, got %v", err)
+	}
+
+	diff, err := loader.DiffSince(ctx, first.Version)
+	if err != nil || diff.From != first.Version || len(diff.Changed) != 1 {
+		t.Errorf("diff since first = %+v, %v", diff, err)
+	}
+	if diff, err := loader.DiffSince(ctx, versions[0].Version); err != nil || !diff.Empty() {
+		t.Errorf("diff since current = %+v, %v", diff, err)
+	}
+	if _, err := loader.DiffSince(ctx, "missing"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("diff since missing: expected ErrUnknownVersion, got %v", err)
+	}
+
+	// A restart diffs against the version archived before the current one.
+	restarted := NewUnitsLoader(LoadUnitsConfig{SetDataPath: path, HistoryDir: filepath.Join(dir, "history")})
+	if _, err := restarted.LoadUnits(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if diff := restarted.LatestDiff(); diff == nil || diff.From != first.Version {
+		t.Errorf("diff after restart = %+v", diff)
+	}
+}
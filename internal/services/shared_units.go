@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"sft/internal/cache"
+	"sft/internal/i18n"
+	"sft/internal/models"
+)
+
+// SharedUnitsSource keeps the data of a remote UnitsSource in a shared
+// cache, so one instance reads the source per TTL and the others take its
+// copy. Keep a CachedUnitsSource in front of it: every call decodes the
+// cached JSON.
+type SharedUnitsSource struct {
+	source UnitsSource
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *log.Logger
+}
+
+// sharedUnits is the cached form of models.UnitsData, whose
+// AssetsVersion does not marshal.
+type sharedUnits struct {
+	Data          *models.UnitsData `json:"data"`
+	AssetsVersion string            `json:"assetsVersion"`
+}
+
+// NewSharedUnitsSource shares the data of source through c for ttl.
+func NewSharedUnitsSource(source UnitsSource, c cache.Cache, ttl time.Duration) *SharedUnitsSource {
+	return &SharedUnitsSource{source: source, cache: c, ttl: ttl, logger: log.Default()}
+}
+
+// LoadUnits returns the cached data for the locale of ctx, loading and
+// storing it on a miss.
+func (s *SharedUnitsSource) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	key := "units:" + sourceName(s.source) + ":" + i18n.FromContext(ctx)
+	if raw, ok := s.cache.Get(ctx, key); ok {
+		var cached sharedUnits
+		if err := json.Unmarshal(raw, &cached); err == nil && cached.Data != nil {
+			cached.Data.AssetsVersion = cached.AssetsVersion
+			return cached.Data, nil
+		}
+		s.logger.Printf("Ignoring unreadable cached units under %s", key)
+	}
+
+	data, err := s.source.LoadUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err := json.Marshal(sharedUnits{Data: data, AssetsVersion: data.AssetsVersion}); err == nil {
+		s.cache.Set(ctx, key, raw, s.ttl)
+	}
+	return data, nil
+}
+
+// SourceName names the wrapped source, for FallbackSource.
+func (s *SharedUnitsSource) SourceName() string {
+	return sourceName(s.source)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sft/internal/cache"
+	"sft/internal/models"
+)
+
+func TestSharedUnitsSource(t *testing.T) {
+	shared := cache.NewMemory(8)
+	remote := &stubSource{name: "remote", data: &models.UnitsData{Version: "v1", AssetsVersion: "a1"}}
+	first := NewSharedUnitsSource(remote, shared, time.Minute)
+	if _, err := first.LoadUnits(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another instance takes the shared copy without asking the source.
+	remote.data = &models.UnitsData{Version: "v2"}
+	second := NewSharedUnitsSource(remote, shared, time.Minute)
+	data, err := second.LoadUnits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Revision() != "v1+a1" {
+		t.Errorf("revision = %q, want the shared v1+a1", data.Revision())
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"sft/internal/models"
+)
+
+// shopFile is the hand-maintained roll odds file for a set.
+type shopFile struct {
+	Odds   []models.ShopLevel `json:"odds"`
+	Copies map[string]int     `json:"copies"` // cost → copies of each champion
+}
+
+// readShopOdds loads the roll odds and derives pool sizes from units.
+// Unlockable champions are left out of the pool counts: they only join the
+// pool once a player unlocks them.
+func readShopOdds(path string, units []models.Unit) (*models.ShopOdds, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var file shopFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	for _, l := range file.Odds {
+		total := 0
+		for _, p := range l.Odds {
+			total += p
+		}
+		if total != 100 {
+			return nil, fmt.Errorf("decode %s: level %d odds sum to %d%%", path, l.Level, total)
+		}
+	}
+	sort.Slice(file.Odds, func(i, j int) bool { return file.Odds[i].Level < file.Odds[j].Level })
+
+	champions := make(map[int]int)
+	for _, u := range units {
+		if !u.Unlock {
+			champions[u.Cost]++
+		}
+	}
+
+	shop := &models.ShopOdds{Levels: file.Odds}
+	for key, copies := range file.Copies {
+		cost, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: cost %q: %w", path, key, err)
+		}
+		shop.Pools = append(shop.Pools, models.CostPool{
+			Cost:      cost,
+			Copies:    copies,
+			Champions: champions[cost],
+			Total:     copies * champions[cost],
+		})
+	}
+	sort.Slice(shop.Pools, func(i, j int) bool { return shop.Pools[i].Cost < shop.Pools[j].Cost })
+	return shop, nil
+}
@@ -23,6 +23,12 @@ func FormatIntList(values []int) string {
 	return FormatIntListWithSep(values, "/")
 }
 
+// FormatIntListAt shows the value for star (1-3) from a per-star list,
+// falling back to FormatIntList for other stars.
+func FormatIntListAt(values []int, star int) string {
+	return FormatIntList(atStar(values, star))
+}
+
 // FormatIntListWithSep joins ints with a custom separator.
 func FormatIntListWithSep(values []int, sep string) string {
 	if len(values) == 0 {
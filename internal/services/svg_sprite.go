@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultStatIconDir holds the stat and scaling icons drawn by the CSS masks
+// of ability tokens and stat rows.
+const DefaultStatIconDir = "static/assets/Stats"
+
+var (
+	svgOpenTag   = regexp.MustCompile(`(?s)<svg\b[^>]*>`)
+	svgViewBox   = regexp.MustCompile(`\bviewBox\s*=\s*["']([^"']+)["']`)
+	svgNoise     = regexp.MustCompile(`(?s)<\?xml.*?\?>|<!--.*?-->|<style\b.*?</style>|<title\b.*?</title>`)
+	svgPaintAttr = regexp.MustCompile(`\s(?:id|class|style|data-name|fill)\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// SpriteDir is a directory of SVG icons and the prefix of their symbol IDs.
+type SpriteDir struct {
+	Dir    string // e.g. static/assets/Traits/SET16
+	Prefix string // e.g. "trait" gives #trait-arcanist
+}
+
+// Sprite is a set of SVG icons as <symbol> elements, included once per page
+// and drawn with <use>, so a page with dozens of trait chips makes no icon
+// requests. Symbols are recolored to currentColor, which matches how the
+// icons were drawn as CSS masks over a background color.
+type Sprite struct {
+	symbols string
+	ids     map[string]string // icon path as indexed (see AssetIndexer) → symbol ID
+}
+
+// BuildSprite reads every .svg file in dirs. Missing directories are skipped
+// so a set without stat icons still gets its trait icons.
+func BuildSprite(dirs ...SpriteDir) (*Sprite, error) {
+	s := &Sprite{ids: make(map[string]string)}
+	var b strings.Builder
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d.Dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sprite: %w", err)
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".svg") {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			path := filepath.ToSlash(filepath.Join(d.Dir, name))
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("sprite: %w", err)
+			}
+			id := d.Prefix + "-" + traitSlug(strings.TrimSuffix(name, filepath.Ext(name)))
+			symbol, err := svgSymbol(id, string(data))
+			if err != nil {
+				return nil, fmt.Errorf("sprite %s: %w", path, err)
+			}
+			b.WriteString(symbol)
+			s.ids[path] = id
+		}
+	}
+	s.symbols = b.String()
+	return s, nil
+}
+
+// svgSymbol converts a standalone SVG document into a <symbol> with id.
+func svgSymbol(id, doc string) (string, error) {
+	doc = svgNoise.ReplaceAllString(doc, "")
+	loc := svgOpenTag.FindStringIndex(doc)
+	end := strings.LastIndex(doc, "</svg>")
+	if loc == nil || end < loc[1] {
+		return "", fmt.Errorf("no <svg> element")
+	}
+	viewBox := "0 0 32 32"
+	if m := svgViewBox.FindStringSubmatch(doc[loc[0]:loc[1]]); m != nil {
+		viewBox = m[1]
+	}
+	body := svgPaintAttr.ReplaceAllStringFunc(doc[loc[1]:end], func(attr string) string {
+		if strings.Contains(attr, "fill") && strings.Contains(attr, "none") {
+			return attr // keep holes in stroked shapes
+		}
+		return ""
+	})
+	return fmt.Sprintf(`<symbol id="%s" viewBox="%s">%s</symbol>`, id, viewBox, strings.TrimSpace(body)), nil
+}
+
+// HTML returns the hidden <svg> holding every symbol. A nil Sprite has none.
+func (s *Sprite) HTML() template.HTML {
+	if s == nil || s.symbols == "" {
+		return ""
+	}
+	return template.HTML(`<svg xmlns="http://www.w3.org/2000/svg" style="display:none" aria-hidden="true">` + s.symbols + `</svg>`)
+}
+
+// ID returns the symbol ID of the icon at path, as AssetIndexer reports it.
+func (s *Sprite) ID(path string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	id, ok := s.ids[strings.TrimPrefix(filepath.ToSlash(path), "/")]
+	return id, ok
+}
+
+var spriteRegistry atomic.Pointer[Sprite]
+
+// SetIconSprite makes s the sprite pages include. Icons outside it are
+// still drawn, from their files.
+func SetIconSprite(s *Sprite) {
+	spriteRegistry.Store(s)
+}
+
+// IconSprite returns the current sprite, nil until SetIconSprite is called.
+func IconSprite() *Sprite {
+	return spriteRegistry.Load()
+}
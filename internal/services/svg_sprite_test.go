@@ -0,0 +1,50 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildSprite(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	files := map[string]string{
+		"Arcanist.svg": `<?xml version="1.0"?><!-- exported --><svg xmlns="http://www.w3.org/2000/svg" id="Layer_1" viewBox="0 0 24 24"><style>.a{fill:#fff}</style><path class="a" fill="#000" d="M0 0h24v24z"/><path fill="none" d="M1 1"/></svg>`,
+		"notes.txt":    "not an icon",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := BuildSprite(SpriteDir{Dir: dir, Prefix: "trait"}, SpriteDir{Dir: filepath.Join(dir, "missing"), Prefix: "stat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, ok := s.ID("/" + dir + "/Arcanist.svg")
+	if !ok || id != "trait-arcanist" {
+		t.Fatalf("ID = %q, %v; want trait-arcanist", id, ok)
+	}
+	if _, ok := s.ID(dir + "/notes.txt"); ok {
+		t.Error("non-SVG file in sprite")
+	}
+
+	html := string(s.HTML())
+	want := `<symbol id="trait-arcanist" viewBox="0 0 24 24"><path d="M0 0h24v24z"/><path fill="none" d="M1 1"/></symbol>`
+	if !strings.Contains(html, want) {
+		t.Errorf("HTML = %s\nwant symbol %s", html, want)
+	}
+	for _, noise := range []string{"<?xml", "<!--", "<style", "Layer_1", `fill="#000"`} {
+		if strings.Contains(html, noise) {
+			t.Errorf("HTML keeps %q", noise)
+		}
+	}
+
+	var none *Sprite
+	if none.HTML() != "" {
+		t.Error("nil sprite renders symbols")
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"sort"
+
+	"sft/internal/models"
+)
+
+// Synergy is a trait's standing on a board.
+type Synergy struct {
+	Name   string                  `json:"name"`
+	Slug   string                  `json:"slug"`
+	Icon   string                  `json:"icon,omitempty"`
+	Count  int                     `json:"count"`          // unique units on the board with the trait
+	Tier   *models.TraitBreakpoint `json:"tier,omitempty"` // highest reached breakpoint
+	Style  models.TraitStyle       `json:"style"`          // Tier.Style, StyleNone when inactive
+	Next   int                     `json:"next,omitempty"` // unit count of the next breakpoint
+	Active bool                    `json:"active"`
+}
+
+// ComputeSynergies counts unique units per trait among the board's
+// placements and resolves each trait's breakpoint. Bench placements
+// (row models.BoardRows and beyond) do not count. Only traits with at
+// least one unit are returned: active first, then by count and name.
+func ComputeSynergies(board models.BoardState, traits []models.TraitInfo) []Synergy {
+	fielded := make(map[string]bool)
+	for _, p := range board.Placements {
+		if p.Row >= 0 && p.Row < models.BoardRows {
+			fielded[p.Unit] = true
+		}
+	}
+
+	var out []Synergy
+	for _, t := range traits {
+		count := 0
+		for _, slug := range t.Units {
+			if fielded[slug] {
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		tier, next := models.ActiveBreakpoint(t.Breakpoints, count)
+		s := Synergy{Name: t.Name, Slug: t.Slug, Icon: t.Icon, Count: count, Tier: tier, Next: next, Active: tier != nil}
+		if tier != nil {
+			s.Style = tier.Style
+		}
+		out = append(out, s)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Active != out[j].Active {
+			return out[i].Active
+		}
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
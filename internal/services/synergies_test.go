@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestComputeSynergies(t *testing.T) {
+	traits := []models.TraitInfo{
+		{Name: "Bruiser", Slug: "bruiser", Units: []string{"sion", "chogath", "vi"},
+			Breakpoints: []models.TraitBreakpoint{{MinUnits: 2, Style: 1}, {MinUnits: 4, Style: 3}}},
+		{Name: "Sorcerer", Slug: "sorcerer", Units: []string{"lux", "vi"},
+			Breakpoints: []models.TraitBreakpoint{{MinUnits: 2, Style: 1}}},
+		{Name: "Unused", Slug: "unused", Units: []string{"nobody"}},
+	}
+	board := models.BoardState{Placements: []models.Placement{
+		{Row: 0, Col: 0, Unit: "sion"},
+		{Row: 0, Col: 1, Unit: "chogath"},
+		{Row: 1, Col: 0, Unit: "chogath"},            // duplicate unit counts once
+		{Row: 2, Col: 2, Unit: "vi"},                 // carries both traits
+		{Row: models.BoardRows, Col: 0, Unit: "lux"}, // bench
+	}}
+
+	got := ComputeSynergies(board, traits)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d synergies, want 2: %+v", len(got), got)
+	}
+	bruiser, sorc := got[0], got[1]
+	if bruiser.Slug != "bruiser" || bruiser.Count != 3 || !bruiser.Active || bruiser.Style != 1 || bruiser.Next != 4 {
+		t.Errorf("unexpected bruiser synergy %+v", bruiser)
+	}
+	if sorc.Slug != "sorcerer" || sorc.Count != 1 || sorc.Active || sorc.Tier != nil || sorc.Next != 2 {
+		t.Errorf("unexpected sorcerer synergy %+v", sorc)
+	}
+}
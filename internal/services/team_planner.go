@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Team planner codes copied from the in-game planner look like
+// "02" + ten 3-digit hex champion IDs ("000" = empty slot) + "TFTSet16".
+const (
+	teamPlannerVersion = "02"
+	teamPlannerSlots   = 10
+	teamPlannerIDWidth = 3
+	teamPlannerSetTag  = "TFTSet"
+)
+
+// ErrInvalidPlannerCode is returned when a team planner code cannot be decoded.
+var ErrInvalidPlannerCode = errors.New("invalid team planner code")
+
+// TeamPlannerCodes maps in-game team planner champion IDs to apiNames.
+type TeamPlannerCodes map[int]string
+
+// TeamPlannerComp is a decoded team planner code.
+type TeamPlannerComp struct {
+	Set      string   // e.g. "TFTSet16"; empty when the code omits it
+	APINames []string // champions in planner order
+}
+
+type teamPlannerEntry struct {
+	CharacterID string `json:"character_id"`
+	Code        int    `json:"team_planner_code"`
+}
+
+// LoadTeamPlannerCodes reads the CommunityDragon tftchampions-teamplanner.json
+// mapping and returns the IDs for set (e.g. "TFTSet16").
+func LoadTeamPlannerCodes(path, set string) (TeamPlannerCodes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return parseTeamPlannerCodes(path, data, set)
+}
+
+// parseTeamPlannerCodes decodes the mapping read from path.
+func parseTeamPlannerCodes(path string, data []byte, set string) (TeamPlannerCodes, error) {
+	var sets map[string][]teamPlannerEntry
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	entries, ok := sets[set]
+	if !ok {
+		return nil, fmt.Errorf("%s: no entries for %s", path, set)
+	}
+
+	codes := make(TeamPlannerCodes, len(entries))
+	for _, e := range entries {
+		if e.Code > 0 && e.CharacterID != "" {
+			codes[e.Code] = e.CharacterID
+		}
+	}
+	return codes, nil
+}
+
+// Decode parses a team planner code into the apiNames of its champions.
+func (c TeamPlannerCodes) Decode(code string) (TeamPlannerComp, error) {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, teamPlannerVersion) {
+		return TeamPlannerComp{}, fmt.Errorf("%w: unsupported version", ErrInvalidPlannerCode)
+	}
+	body := code[len(teamPlannerVersion):]
+
+	var comp TeamPlannerComp
+	if i := strings.Index(body, teamPlannerSetTag); i >= 0 {
+		comp.Set = body[i:]
+		body = body[:i]
+	}
+	if len(body) != teamPlannerSlots*teamPlannerIDWidth {
+		return TeamPlannerComp{}, fmt.Errorf("%w: expected %d slots", ErrInvalidPlannerCode, teamPlannerSlots)
+	}
+
+	for i := 0; i < len(body); i += teamPlannerIDWidth {
+		id, err := strconv.ParseUint(body[i:i+teamPlannerIDWidth], 16, 16)
+		if err != nil {
+			return TeamPlannerComp{}, fmt.Errorf("%w: slot %q", ErrInvalidPlannerCode, body[i:i+teamPlannerIDWidth])
+		}
+		if id == 0 {
+			continue
+		}
+		apiName, ok := c[int(id)]
+		if !ok {
+			return TeamPlannerComp{}, fmt.Errorf("%w: unknown champion id %d", ErrInvalidPlannerCode, id)
+		}
+		comp.APINames = append(comp.APINames, apiName)
+	}
+	return comp, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTeamPlannerCodes_Decode(t *testing.T) {
+	codes := TeamPlannerCodes{0x01a: "TFT16_Ahri", 0x0c5: "TFT16_Garen"}
+
+	tests := []struct {
+		name    string
+		code    string
+		want    TeamPlannerComp
+		wantErr bool
+	}{
+		{
+			name: "with set suffix",
+			code: "02" + "01a0c5000000000000000000000000" + "TFTSet16",
+			want: TeamPlannerComp{Set: "TFTSet16", APINames: []string{"TFT16_Ahri", "TFT16_Garen"}},
+		},
+		{
+			name: "without suffix, empty slots skipped",
+			code: "02" + "0000000000000000000000000000c5",
+			want: TeamPlannerComp{APINames: []string{"TFT16_Garen"}},
+		},
+		{name: "wrong version", code: "01" + "01a0c5000000000000000000000000", wantErr: true},
+		{name: "short", code: "0201a", wantErr: true},
+		{name: "bad hex", code: "02" + "zzz000000000000000000000000000", wantErr: true},
+		{name: "unknown id", code: "02" + "fff000000000000000000000000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := codes.Decode(tt.code)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPlannerCode) {
+					t.Fatalf("Decode(%q) error = %v, want ErrInvalidPlannerCode", tt.code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode(%q): %v", tt.code, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode(%q) = %+v, want %+v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTeamPlannerCodes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teamplanner.json")
+	data := `{"TFTSet16":[{"character_id":"TFT16_Ahri","team_planner_code":26},{"character_id":"TFT16_Garen","team_planner_code":197}],
+	"TFTSet15":[{"character_id":"TFT15_Ahri","team_planner_code":3}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	codes, err := LoadTeamPlannerCodes(path, "TFTSet16")
+	if err != nil {
+		t.Fatalf("LoadTeamPlannerCodes: %v", err)
+	}
+	want := TeamPlannerCodes{26: "TFT16_Ahri", 197: "TFT16_Garen"}
+	if !reflect.DeepEqual(codes, want) {
+		t.Errorf("codes = %v, want %v", codes, want)
+	}
+
+	if _, err := LoadTeamPlannerCodes(path, "TFTSet99"); err == nil {
+		t.Error("expected error for missing set")
+	}
+}
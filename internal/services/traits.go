@@ -0,0 +1,96 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"sft/internal/models"
+)
+
+var (
+	traitBreakTag  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	traitMarkupTag = regexp.MustCompile(`<[^>]*>`)
+	// traitRow matches the per-breakpoint lines of a trait description.
+	traitRow = regexp.MustCompile(`(?s)<(row|expandRow)>(.*?)</(?:row|expandRow)>`)
+)
+
+// buildTraitInfos lists every trait carried by units, sorted by name.
+// Descriptions and breakpoints come from the set's trait definitions when
+// the source file includes them; otherwise only names, icons and units are known.
+func buildTraitInfos(defs []setTrait, units []models.Unit) []models.TraitInfo {
+	byName := make(map[string]setTrait, len(defs))
+	for _, d := range defs {
+		byName[strings.TrimSpace(d.Name)] = d
+	}
+
+	index := make(map[string]int)
+	var traits []models.TraitInfo
+	for _, u := range units {
+		for _, t := range u.Traits {
+			i, ok := index[t.Slug]
+			if !ok {
+				i = len(traits)
+				index[t.Slug] = i
+				info := models.TraitInfo{Name: t.Name, Slug: t.Slug, Icon: t.Icon}
+				if def, ok := byName[t.Name]; ok {
+					info.Description = plainTraitDescription(def.Desc)
+					rows, shared := traitRows(def.Desc)
+					for i, e := range def.Effects {
+						row := ""
+						if shared {
+							row = rows[0]
+						} else if i < len(rows) {
+							row = rows[i]
+						}
+						info.Breakpoints = append(info.Breakpoints, models.TraitBreakpoint{
+							MinUnits: e.MinUnits,
+							MaxUnits: e.MaxUnits,
+							Style:    models.TraitStyle(e.Style),
+							Effect:   breakpointEffect(row, e),
+						})
+					}
+				}
+				traits = append(traits, info)
+			}
+			traits[i].Units = append(traits[i].Units, u.Slug)
+		}
+	}
+
+	sort.Slice(traits, func(i, j int) bool { return traits[i].Name < traits[j].Name })
+	return traits
+}
+
+// traitRows returns the breakpoint lines of a trait description in order.
+// An <expandRow> is a single line shared by every breakpoint, reported with
+// shared set.
+func traitRows(desc string) (rows []string, shared bool) {
+	for _, m := range traitRow.FindAllStringSubmatch(desc, -1) {
+		if m[1] == "expandRow" {
+			return []string{m[2]}, true
+		}
+		rows = append(rows, m[2])
+	}
+	return rows, false
+}
+
+// breakpointEffect fills a breakpoint's description line from its effect
+// variables.
+func breakpointEffect(row string, e setTraitEffect) string {
+	if row == "" {
+		return ""
+	}
+	values := map[string]float64{"MinUnits": float64(e.MinUnits), "MaxUnits": float64(e.MaxUnits)}
+	for k, v := range e.Variables {
+		values[k] = v
+	}
+	row = descScaleMarker.ReplaceAllString(fillDescValues(row, values), "")
+	return strings.Join(strings.Fields(plainTraitDescription(row)), " ")
+}
+
+// plainTraitDescription strips the client markup from a trait description.
+func plainTraitDescription(desc string) string {
+	desc = traitBreakTag.ReplaceAllString(desc, "\n")
+	desc = traitMarkupTag.ReplaceAllString(desc, "")
+	return strings.TrimSpace(desc)
+}
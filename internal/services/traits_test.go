@@ -0,0 +1,41 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestBuildTraitInfos(t *testing.T) {
+	units := []models.Unit{
+		{Slug: "ahri", Traits: []models.Trait{{Name: "Ionia", Slug: "ionia"}, {Name: "Arcanist", Slug: "arcanist"}}},
+		{Slug: "yasuo", Traits: []models.Trait{{Name: "Ionia", Slug: "ionia"}}},
+	}
+	defs := []setTrait{{
+		Name: "Ionia",
+		Desc: "Ionians gain <magicDamage>bonuses</magicDamage>.<br><row>(@MinUnits@) @AP@ AP</row><br><row>(@MinUnits@) @AP*100@% AP</row>",
+		Effects: []setTraitEffect{
+			{MinUnits: 2, MaxUnits: 3, Style: 1, Variables: map[string]float64{"AP": 15}},
+			{MinUnits: 4, Style: 3, Variables: map[string]float64{"AP": 0.355}},
+		},
+	}}
+
+	got := buildTraitInfos(defs, units)
+	want := []models.TraitInfo{
+		{Name: "Arcanist", Slug: "arcanist", Units: []string{"ahri"}},
+		{
+			Name:        "Ionia",
+			Slug:        "ionia",
+			Description: "Ionians gain bonuses.\n(@MinUnits@) @AP@ AP\n(@MinUnits@) @AP*100@% AP",
+			Breakpoints: []models.TraitBreakpoint{
+				{MinUnits: 2, MaxUnits: 3, Style: models.StyleBronze, Effect: "(2) 15 AP"},
+				{MinUnits: 4, Style: models.StyleSilver, Effect: "(4) 35.5% AP"},
+			},
+			Units: []string{"ahri", "yasuo"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildTraitInfos =\n%+v\nwant\n%+v", got, want)
+	}
+}
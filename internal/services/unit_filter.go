@@ -0,0 +1,99 @@
+package services
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sft/internal/models"
+)
+
+// UnitFilter narrows the unit list shown by the builder.
+// The zero value matches every unit.
+type UnitFilter struct {
+	Costs []int  // any of these costs; empty matches all
+	Trait string // trait slug or name, case-insensitive
+	Role  string // role name, case-insensitive
+	Query string // free-text search; every word must match, see Match
+}
+
+// ParseUnitFilter reads ?cost=, ?trait=, ?role= and ?q= from a query string.
+// Costs may be repeated or comma-separated; values that are not
+// positive integers are ignored.
+func ParseUnitFilter(q url.Values) UnitFilter {
+	var f UnitFilter
+	for _, raw := range q["cost"] {
+		for _, part := range strings.Split(raw, ",") {
+			cost, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || cost < 1 || slices.Contains(f.Costs, cost) {
+				continue
+			}
+			f.Costs = append(f.Costs, cost)
+		}
+	}
+	slices.Sort(f.Costs)
+	f.Trait = strings.TrimSpace(q.Get("trait"))
+	f.Role = strings.TrimSpace(q.Get("role"))
+	f.Query = strings.Join(strings.Fields(q.Get("q")), " ")
+	return f
+}
+
+// Active reports whether the filter excludes anything.
+func (f UnitFilter) Active() bool {
+	return len(f.Costs) > 0 || f.Trait != "" || f.Role != "" || f.Query != ""
+}
+
+// HasCost reports whether cost is one of the selected costs.
+func (f UnitFilter) HasCost(cost int) bool {
+	return slices.Contains(f.Costs, cost)
+}
+
+// Match reports whether u passes every active criterion. Query words are
+// matched case-insensitively against the unit, trait, role and ability names.
+func (f UnitFilter) Match(u models.Unit) bool {
+	if len(f.Costs) > 0 && !f.HasCost(u.Cost) {
+		return false
+	}
+	if f.Role != "" && !strings.EqualFold(u.Role, f.Role) {
+		return false
+	}
+	if f.Trait != "" && !slices.ContainsFunc(u.Traits, func(t models.Trait) bool {
+		return strings.EqualFold(t.Slug, f.Trait) || strings.EqualFold(t.Name, f.Trait)
+	}) {
+		return false
+	}
+	if f.Query != "" && !matchesQuery(u, f.Query) {
+		return false
+	}
+	return true
+}
+
+func matchesQuery(u models.Unit, query string) bool {
+	fields := []string{u.Name, u.Role, u.Ability.Name}
+	for _, t := range u.Traits {
+		fields = append(fields, t.Name)
+	}
+	haystack := strings.ToLower(strings.Join(fields, " "))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the units matching f, preserving order.
+// The input slice is returned unchanged when the filter is inactive.
+func (f UnitFilter) Apply(units []models.Unit) []models.Unit {
+	if !f.Active() {
+		return units
+	}
+	out := make([]models.Unit, 0, len(units))
+	for _, u := range units {
+		if f.Match(u) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
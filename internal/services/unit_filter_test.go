@@ -0,0 +1,65 @@
+package services
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"sft/internal/models"
+)
+
+func TestParseUnitFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  UnitFilter
+	}{
+		{"", UnitFilter{}},
+		{"cost=3", UnitFilter{Costs: []int{3}}},
+		{"cost=4,2&cost=2&cost=x&cost=0", UnitFilter{Costs: []int{2, 4}}},
+		{"trait=%20bruiser%20&role=Tank", UnitFilter{Trait: "bruiser", Role: "Tank"}},
+		{"q=%20final%20%20spark%20", UnitFilter{Query: "final spark"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			if got := ParseUnitFilter(q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUnitFilter(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitFilter_Apply(t *testing.T) {
+	units := []models.Unit{
+		{Name: "Ahri", Slug: "ahri", Cost: 3, Role: "APCaster", Traits: []models.Trait{{Name: "Arcanist", Slug: "arcanist"}}},
+		{Name: "Garen", Slug: "garen", Cost: 1, Role: "Tank", Traits: []models.Trait{{Name: "Juggernaut", Slug: "juggernaut"}}},
+		{Name: "Lux", Slug: "lux", Cost: 1, Role: "APCaster", Traits: []models.Trait{{Name: "Arcanist", Slug: "arcanist"}},
+			Ability: models.Ability{Name: "Final Spark"}},
+	}
+	tests := []struct {
+		name   string
+		filter UnitFilter
+		want   []string
+	}{
+		{"inactive", UnitFilter{}, []string{"ahri", "garen", "lux"}},
+		{"cost", UnitFilter{Costs: []int{1}}, []string{"garen", "lux"}},
+		{"trait by name", UnitFilter{Trait: "arcanist"}, []string{"ahri", "lux"}},
+		{"role", UnitFilter{Role: "tank"}, []string{"garen"}},
+		{"combined", UnitFilter{Costs: []int{1}, Trait: "Arcanist"}, []string{"lux"}},
+		{"no match", UnitFilter{Role: "Marksman"}, []string{}},
+		{"query by name", UnitFilter{Query: "GAR"}, []string{"garen"}},
+		{"query by trait and ability", UnitFilter{Query: "arcanist spark"}, []string{"lux"}},
+		{"query with cost", UnitFilter{Costs: []int{3}, Query: "arcan"}, []string{"ahri"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, u := range tt.filter.Apply(units) {
+				got = append(got, u.Slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"sft/internal/models"
+)
+
+// ErrUnitNotFound is returned for a unit slug the dataset does not have.
+var ErrUnitNotFound = errors.New("unit not found")
+
+// FindUnit returns the unit of data with slug, or ErrUnitNotFound.
+func FindUnit(data *models.UnitsData, slug string) (*models.Unit, error) {
+	for i := range data.Units {
+		if data.Units[i].Slug == slug {
+			return &data.Units[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnitNotFound, slug)
+}
@@ -3,9 +3,19 @@ package services
 import (
 	"math"
 	"sft/internal/models"
+	"strconv"
 	"strings"
 )
 
+// CDragonBaseURL is where champion art is fetched from when neither a local
+// image nor a portrait in the set data is available.
+const CDragonBaseURL = CDragonRoot + "/latest/game"
+
+// maxChampionCost is the highest cost of a champion players can field.
+// The set data lists summons and props such as Tibbers at cost 11; they
+// have no art of their own and stay out of the builder.
+const maxChampionCost = 7
+
 // adaptChampion transforms raw JSON champion data into a domain Unit model.
 func adaptChampion(ch setChampion, traitIcons, unitImages, spellImages map[string]string) (models.Unit, bool) {
 	name := strings.TrimSpace(ch.Name)
@@ -19,17 +29,21 @@ func adaptChampion(ch setChampion, traitIcons, unitImages, spellImages map[strin
 
 	unit := models.Unit{
 		Name:              name,
+		Slug:              imgKey,
+		APIName:           ch.APIName,
 		Cost:              ch.Cost,
 		Unlock:            ch.Unlock,
 		UnlockDescription: ch.UnlockDescription,
 		Role:              ch.Role,
 		URL:               img,
+		RecommendedItems:  namedItems(ch.RecommendedItems),
 	}
 
 	for _, t := range ch.Traits {
 		slug := traitSlug(t)
 		unit.Traits = append(unit.Traits, models.Trait{
 			Name: t,
+			Slug: slug,
 			Icon: traitIcons[slug],
 		})
 	}
@@ -49,6 +63,11 @@ func adaptChampion(ch setChampion, traitIcons, unitImages, spellImages map[strin
 	if unit.URL == "" {
 		unit.URL = ch.Icons.Portrait
 	}
+	// New patches ship champions before their art is downloaded: draw them
+	// from CommunityDragon rather than hide them from the builder.
+	if unit.URL == "" && ch.Cost <= maxChampionCost {
+		unit.URL = cdragonSquareURL(ch.APIName)
+	}
 	// Still nothing usable? Skip to avoid broken thumbnails
 	if unit.URL == "" {
 		return models.Unit{}, false
@@ -57,10 +76,33 @@ func adaptChampion(ch setChampion, traitIcons, unitImages, spellImages map[strin
 	return unit, true
 }
 
+// cdragonSquareURL returns the CommunityDragon square portrait of the
+// champion with apiName (e.g. TFT16_Ahri), or "" if apiName does not name
+// its set.
+func cdragonSquareURL(apiName string) string {
+	prefix, _, ok := strings.Cut(apiName, "_")
+	if !ok || len(prefix) <= 3 || !strings.EqualFold(prefix[:3], "tft") {
+		return ""
+	}
+	set, err := strconv.Atoi(prefix[3:])
+	if err != nil {
+		return ""
+	}
+	id := strings.ToLower(apiName)
+	return CDragonBaseURL + "/assets/characters/" + id + "/hud/" + id + "_square.tft_set" + strconv.Itoa(set) + ".png"
+}
+
+// Per-star multipliers for stats the source omits: each star level has
+// 1.8x the health and 1.5x the attack damage of the one below.
+const (
+	hpStarScale     = 1.8
+	damageStarScale = 1.5
+)
+
 func adaptStats(stats setStats) models.UnitStats {
 	return models.UnitStats{
-		HP:             roundList(stats.HP.Numbers()),
-		Damage:         roundList(stats.Damage.Numbers()),
+		HP:             roundList(perStar(stats.HP.Numbers(), hpStarScale)),
+		Damage:         roundList(perStar(stats.Damage.Numbers(), damageStarScale)),
 		Armor:          roundToInt(stats.Armor),
 		MagicResist:    roundToInt(stats.MagicResist),
 		AttackSpeed:    stats.AttackSpeed,
@@ -73,6 +115,20 @@ func adaptStats(stats setStats) models.UnitStats {
 	}
 }
 
+// perStar extends values to one entry per star level, deriving each
+// missing level from the one below. Values the source provides are kept.
+func perStar(values []float64, scale float64) []float64 {
+	if len(values) == 0 || len(values) >= models.MaxStars {
+		return values
+	}
+	out := make([]float64, models.MaxStars)
+	copy(out, values)
+	for i := len(values); i < models.MaxStars; i++ {
+		out[i] = out[i-1] * scale
+	}
+	return out
+}
+
 func roundToInt(v float64) int {
 	if v == 0 {
 		return 0
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAdaptStats_PerStar(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		hp, damage []int
+	}{
+		{"complete", `{"hp":[550,990,1782],"damage":[55,83,124]}`, []int{550, 990, 1782}, []int{55, 83, 124}},
+		{"single values", `{"hp":600,"damage":"50"}`, []int{600, 1080, 1944}, []int{50, 75, 113}},
+		{"partial list", `{"hp":[500,900],"damage":[40]}`, []int{500, 900, 1620}, []int{40, 60, 90}},
+		{"missing", `{}`, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw setStats
+			if err := json.Unmarshal([]byte(tt.raw), &raw); err != nil {
+				t.Fatal(err)
+			}
+			got := adaptStats(raw)
+			if !reflect.DeepEqual(got.HP, tt.hp) || !reflect.DeepEqual(got.Damage, tt.damage) {
+				t.Errorf("adaptStats(%s) hp=%v damage=%v, want hp=%v damage=%v", tt.raw, got.HP, got.Damage, tt.hp, tt.damage)
+			}
+		})
+	}
+}
+
+func TestAdaptChampion_ImageFallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		ch     setChampion
+		images map[string]string
+		want   string
+		ok     bool
+	}{
+		{"local image", setChampion{Name: "Ahri", APIName: "TFT16_Ahri"}, map[string]string{"ahri": "static/ahri.png"}, "static/ahri.png", true},
+		{"portrait", setChampion{Name: "Ahri", APIName: "TFT16_Ahri", Icons: setIcons{Portrait: "https://img/ahri.png"}}, nil, "https://img/ahri.png", true},
+		{"cdragon", setChampion{Name: "Ahri", APIName: "TFT16_Ahri"}, nil, CDragonBaseURL + "/assets/characters/tft16_ahri/hud/tft16_ahri_square.tft_set16.png", true},
+		{"unknown set", setChampion{Name: "Dummy", APIName: "TrainingDummy"}, nil, "", false},
+		{"summon", setChampion{Name: "Tibbers", APIName: "TFT16_AnnieTibbers", Cost: 11}, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := adaptChampion(tt.ch, nil, tt.images, nil)
+			if ok != tt.ok || got.URL != tt.want {
+				t.Errorf("adaptChampion() URL = %q, %v; want %q, %v", got.URL, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
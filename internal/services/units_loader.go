@@ -2,12 +2,19 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log"
 	"os"
+	"sft/internal/i18n"
 	"sft/internal/models"
 	"sort"
 	"sync"
+	"time"
 )
 
 const (
@@ -15,6 +22,14 @@ const (
 	defaultTraitDir    = "static/assets/Traits/SET16"
 	defaultUnitDir     = "static/assets/Units/SET16"
 	defaultSpellDir    = "static/assets/Spells/SET16/webp-64"
+	defaultItemDir     = "static/assets/Items/SET16"
+)
+
+// Errors of loading set data, for handlers to tell missing data, which
+// a fresh checkout has until `sft fetch` runs, from broken data.
+var (
+	ErrSetFileNotFound = errors.New("set data file not found")
+	ErrSetFileInvalid  = errors.New("set data file is invalid")
 )
 
 // LoadUnitsConfig makes the unit loader configurable and testable.
@@ -23,6 +38,21 @@ type LoadUnitsConfig struct {
 	TraitDir    string
 	UnitDir     string
 	SpellDir    string
+	ItemsPath   string // optional item JSON; empty loads no items
+	ItemDir     string
+	ShopPath    string // optional roll odds JSON; empty leaves Shop nil
+	// Locales are translated set files to look for, see LocalizedPath.
+	Locales []string
+	// RecommendedItemsPath is an optional JSON file of recommended items per
+	// unit that overrides any recommendations in the set data.
+	RecommendedItemsPath string
+	// PreviousSetDataPath is an optional older set file. LatestDiff compares
+	// the first load against it, or against the version archived before the
+	// current one; reloads compare against the data they replace.
+	PreviousSetDataPath string
+	// HistoryDir, if set, archives every loaded dataset version there for
+	// LoadVersion.
+	HistoryDir string
 }
 
 // applyDefaults fills in missing config values with defaults.
@@ -39,6 +69,9 @@ func (c *LoadUnitsConfig) applyDefaults() {
 	if c.SpellDir == "" {
 		c.SpellDir = defaultSpellDir
 	}
+	if c.ItemDir == "" {
+		c.ItemDir = defaultItemDir
+	}
 }
 
 // UnitsSource defines the capability to load champion units.
@@ -49,38 +82,292 @@ type UnitsSource interface {
 // LocalUnitsLoader loads units from local JSON and asset files.
 type LocalUnitsLoader struct {
 	cfg     LoadUnitsConfig
-	once    sync.Once
+	mu      sync.RWMutex
+	loaded  bool
 	data    *models.UnitsData
 	loadErr error
+	changed chan struct{} // closed and replaced whenever the dataset version changes
+
+	set  *setFile // raw set data behind data, kept for diffing
+	diff *SetDiff // changes of the latest dataset version, see LatestDiff
+
+	history *SetHistory                  // nil without HistoryDir
+	pastMu  sync.Mutex                   // guards past
+	past    map[string]*models.UnitsData // archived versions loaded so far
+
+	// localized holds translated copies of data keyed by locale.
+	localized map[string]*models.UnitsData
 }
 
 // NewUnitsLoader returns a file-based loader with sane defaults.
 func NewUnitsLoader(cfg LoadUnitsConfig) *LocalUnitsLoader {
 	cfg.applyDefaults()
-	return &LocalUnitsLoader{cfg: cfg}
+	l := &LocalUnitsLoader{cfg: cfg, changed: make(chan struct{})}
+	if cfg.HistoryDir != "" {
+		l.history = NewSetHistory(cfg.HistoryDir)
+	}
+	return l
 }
 
-// LoadUnits loads and adapts champions from the generated set JSON.
-// Results are cached after the first call.
-func (l *LocalUnitsLoader) LoadUnits(_ context.Context) (*models.UnitsData, error) {
-	l.once.Do(func() {
-		l.data, l.loadErr = l.load()
-	})
+// LoadUnits loads and adapts champions from the generated set JSON, in the
+// locale of ctx (see i18n.FromContext) when a translation was loaded.
+// Results are cached after the first call. When ctx ends during the first
+// load, LoadUnits returns ctx.Err() and the next call loads again.
+func (l *LocalUnitsLoader) LoadUnits(ctx context.Context) (*models.UnitsData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	l.mu.RLock()
+	if !l.loaded {
+		l.mu.RUnlock()
+		l.mu.Lock()
+		if !l.loaded {
+			data, localized, set, err := l.load(ctx)
+			if err == nil {
+				l.diff, err = l.previousDiff(set)
+			}
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				l.mu.Unlock()
+				return nil, err
+			}
+			l.data, l.localized, l.set, l.loadErr = data, localized, set, err
+			l.loaded = true
+		}
+		l.mu.Unlock()
+		l.mu.RLock()
+	}
+	defer l.mu.RUnlock()
+
+	if data, ok := l.localized[i18n.FromContext(ctx)]; ok {
+		return data, nil
+	}
 	return l.data, l.loadErr
 }
 
-// load orchestrates the loading pipeline.
-func (l *LocalUnitsLoader) load() (*models.UnitsData, error) {
-	setData, err := readSetFile(l.cfg.SetDataPath)
+// Reload re-reads the set JSON and asset directories from disk.
+// On failure the previously cached data is kept and the error is returned.
+func (l *LocalUnitsLoader) Reload(ctx context.Context) error {
+	data, localized, set, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	diff := l.diff
+	switch {
+	case l.set == nil:
+		if diff, err = l.previousDiff(set); err != nil {
+			return err
+		}
+	case l.set.version != set.version:
+		diff = DiffSets(l.set, set)
+	}
+	if l.data == nil || l.data.Revision() != data.Revision() {
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+	l.data, l.localized, l.set, l.diff, l.loadErr, l.loaded = data, localized, set, diff, nil, true
+	return nil
+}
+
+// LatestDiff returns what changed in the current dataset version: against
+// the data the last reload replaced, or, before any reload changed it,
+// against PreviousSetDataPath or the previously archived version. It is nil
+// when there is nothing to compare with.
+func (l *LocalUnitsLoader) LatestDiff() *SetDiff {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.diff
+}
+
+// previousDiff diffs set against the configured previous set file, else
+// against the version archived before it, if any.
+func (l *LocalUnitsLoader) previousDiff(set *setFile) (*SetDiff, error) {
+	if l.cfg.PreviousSetDataPath != "" {
+		previous, err := readSetFile(l.cfg.PreviousSetDataPath)
+		if err != nil {
+			return nil, err
+		}
+		return DiffSets(previous, set), nil
+	}
+	if l.history == nil {
+		return nil, nil
+	}
+
+	snapshots, err := l.history.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range snapshots {
+		if s.Version != set.version || i+1 == len(snapshots) {
+			continue
+		}
+		previous, err := readSetFile(snapshots[i+1].path)
+		if err != nil {
+			return nil, err
+		}
+		return DiffSets(previous, set), nil
+	}
+	return nil, nil
+}
+
+// Versions lists the archived dataset versions, newest first. It is empty
+// without HistoryDir.
+func (l *LocalUnitsLoader) Versions(_ context.Context) ([]SetSnapshot, error) {
+	if l.history == nil {
+		return []SetSnapshot{}, nil
+	}
+	return l.history.Snapshots()
+}
+
+// LoadVersion returns the units of a dataset version: the current data, or
+// an archived version adapted with today's assets. Archived versions carry
+// units and traits only. It returns ErrUnknownVersion for any other version.
+func (l *LocalUnitsLoader) LoadVersion(ctx context.Context, version string) (*models.UnitsData, error) {
+	current, err := l.LoadUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version == version {
+		return current, nil
+	}
+	if l.history == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownVersion, version)
+	}
+
+	l.pastMu.Lock()
+	defer l.pastMu.Unlock()
+	if data, ok := l.past[version]; ok {
+		return data, nil
+	}
+	setData, err := l.history.read(version)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := l.buildAssetMaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units, err := l.adaptChampions(ctx, setData.Champions, assets)
+	if err != nil {
+		return nil, err
+	}
+	sortUnitsByCostAndName(units)
+	data := &models.UnitsData{
+		Version: setData.version,
+		Units:   units,
+		Traits:  buildTraitInfos(setData.Traits, units),
+	}
+	if l.past == nil {
+		l.past = make(map[string]*models.UnitsData)
+	}
+	l.past[version] = data
+	return data, nil
+}
+
+// DiffSince diffs the archived dataset version against the current set
+// data. It returns ErrUnknownVersion for a version that is neither
+// current nor archived.
+func (l *LocalUnitsLoader) DiffSince(ctx context.Context, version string) (*SetDiff, error) {
+	if _, err := l.LoadUnits(ctx); err != nil {
+		return nil, err
+	}
+	l.mu.RLock()
+	current := l.set
+	l.mu.RUnlock()
+	if current.version == version {
+		return DiffSets(current, current), nil
+	}
+	if l.history == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownVersion, version)
+	}
+	past, err := l.history.read(version)
 	if err != nil {
 		return nil, err
 	}
+	return DiffSets(past, current), nil
+}
+
+// SourceName names the loader by its set file, for FallbackSource.
+func (l *LocalUnitsLoader) SourceName() string {
+	return l.cfg.SetDataPath
+}
+
+// Changed returns a channel that is closed the next time a reload
+// produces a different dataset version or finds different asset files.
+func (l *LocalUnitsLoader) Changed() <-chan struct{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.changed
+}
+
+// load orchestrates the loading pipeline. It returns the default dataset,
+// its translations keyed by locale and the raw set data. It stops with
+// ctx.Err() between files and champions once ctx ends.
+func (l *LocalUnitsLoader) load(ctx context.Context) (*models.UnitsData, map[string]*models.UnitsData, *setFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	setData, err := readSetFile(l.cfg.SetDataPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if l.history != nil {
+		if err := l.history.Archive(l.cfg.SetDataPath, setData.version, time.Now()); err != nil {
+			log.Printf("Set data history: %v", err)
+		}
+	}
 
-	assets := l.buildAssetMaps()
-	units := l.adaptChampions(setData.Champions, assets)
+	assets, err := l.buildAssetMaps(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	units, err := l.adaptChampions(ctx, setData.Champions, assets)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	sortUnitsByCostAndName(units)
 
-	return &models.UnitsData{Units: units}, nil
+	var items []models.Item
+	if l.cfg.ItemsPath != "" {
+		items, err = readItems(l.cfg.ItemsPath, assets.items)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var recs map[string][]string
+	if l.cfg.RecommendedItemsPath != "" {
+		recs, err = readRecommendedItems(l.cfg.RecommendedItemsPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	applyRecommendedItems(units, recs, items)
+
+	var shop *models.ShopOdds
+	if l.cfg.ShopPath != "" {
+		shop, err = readShopOdds(l.cfg.ShopPath, units)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	data := &models.UnitsData{
+		Version: setData.version,
+		Units:   units,
+		Traits:  buildTraitInfos(setData.Traits, units),
+		Items:   items,
+		Shop:    shop,
+
+		AssetsVersion: assets.fingerprint(),
+	}
+	localized, err := l.loadLocalized(ctx, data, setData)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return data, localized, setData, nil
 }
 
 // assetMaps holds all asset path lookups.
@@ -88,51 +375,121 @@ type assetMaps struct {
 	traits map[string]string
 	units  map[string]string
 	spells map[string]string
+	items  map[string]string
+}
+
+// AssetDirs returns the directories icons and portraits are indexed from.
+func (l *LocalUnitsLoader) AssetDirs() []string {
+	return []string{l.cfg.TraitDir, l.cfg.UnitDir, l.cfg.SpellDir, l.cfg.ItemDir}
 }
 
-// buildAssetMaps creates lookup maps for all asset types.
-func (l *LocalUnitsLoader) buildAssetMaps() assetMaps {
-	spells := SpellIndexer.Index(l.cfg.SpellDir)
-	if len(spells) == 0 && l.cfg.SpellDir != defaultSpellDir {
-		spells = SpellIndexer.Index(defaultSpellDir)
+// fingerprint hashes every indexed path, so it changes when an icon is
+// added, removed or renamed.
+func (a assetMaps) fingerprint() string {
+	h := sha256.New()
+	for _, m := range []map[string]string{a.traits, a.units, a.spells, a.items} {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			h.Write([]byte(k + "=" + m[k] + "\n"))
+		}
+		h.Write([]byte{0})
 	}
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
 
-	return assetMaps{
-		traits: TraitIndexer.Index(l.cfg.TraitDir),
-		units:  UnitIndexer.Index(l.cfg.UnitDir),
-		spells: spells,
+// buildAssetMaps creates lookup maps for all asset types, checking ctx
+// between directories.
+func (l *LocalUnitsLoader) buildAssetMaps(ctx context.Context) (assetMaps, error) {
+	var a assetMaps
+	steps := []func(){
+		func() { a.traits = TraitIndexer.Index(l.cfg.TraitDir) },
+		func() { a.units = UnitIndexer.Index(l.cfg.UnitDir) },
+		func() {
+			a.spells = SpellIndexer.Index(l.cfg.SpellDir)
+			if len(a.spells) == 0 && l.cfg.SpellDir != defaultSpellDir {
+				a.spells = SpellIndexer.Index(defaultSpellDir)
+			}
+		},
+		func() { a.items = UnitIndexer.Index(l.cfg.ItemDir) },
+	}
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return assetMaps{}, err
+		}
+		step()
 	}
+	return a, nil
 }
 
 // adaptChampions converts raw champion data to domain models.
-func (l *LocalUnitsLoader) adaptChampions(champions []setChampion, assets assetMaps) []models.Unit {
+func (l *LocalUnitsLoader) adaptChampions(ctx context.Context, champions []setChampion, assets assetMaps) ([]models.Unit, error) {
 	units := make([]models.Unit, 0, len(champions))
 
 	for _, ch := range champions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		unit, ok := adaptChampion(ch, assets.traits, assets.units, assets.spells)
 		if ok {
 			units = append(units, unit)
 		}
 	}
 
-	return units
+	return units, nil
+}
+
+// adaptSetData converts raw set JSON to the units and traits the loader
+// would serve from it, against the current asset directories.
+func (l *LocalUnitsLoader) adaptSetData(ctx context.Context, raw []byte) (*models.UnitsData, error) {
+	set, err := decodeSetFile(l.cfg.SetDataPath, raw)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := l.buildAssetMaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units, err := l.adaptChampions(ctx, set.Champions, assets)
+	if err != nil {
+		return nil, err
+	}
+	sortUnitsByCostAndName(units)
+	return &models.UnitsData{Version: set.version, Units: units, Traits: buildTraitInfos(set.Traits, units)}, nil
 }
 
 // readSetFile reads and parses the set JSON file.
 func readSetFile(path string) (*setFile, error) {
 	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w", ErrSetFileNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("read %s: %w", path, err)
 	}
+	return decodeSetFile(path, data)
+}
 
+// decodeSetFile parses raw set JSON read from name.
+func decodeSetFile(name string, data []byte) (*setFile, error) {
 	var set setFile
 	if err := json.Unmarshal(data, &set); err != nil {
-		return nil, fmt.Errorf("decode %s: %w", path, err)
+		return nil, fmt.Errorf("%w: decode %s: %w", ErrSetFileInvalid, name, err)
 	}
+	set.version = datasetVersion(data)
 
 	return &set, nil
 }
 
+// datasetVersion derives a short, stable version identifier from the raw set file.
+func datasetVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
 // sortUnitsByCostAndName sorts units by cost (ascending), then by name (alphabetical).
 func sortUnitsByCostAndName(units []models.Unit) {
 	sort.SliceStable(units, func(i, j int) bool {
@@ -1,7 +1,12 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sft/internal/i18n"
 	"sft/internal/models"
 	"testing"
 )
@@ -103,8 +108,8 @@ func TestNewUnitsLoader_AppliesDefaults(t *testing.T) {
 func TestReadSetFile_FileNotFound(t *testing.T) {
 	_, err := readSetFile("nonexistent/file.json")
 
-	if err == nil {
-		t.Error("expected error for missing file")
+	if !errors.Is(err, ErrSetFileNotFound) {
+		t.Errorf("expected ErrSetFileNotFound for missing file, got %v", err)
 	}
 }
 
@@ -117,8 +122,8 @@ func TestReadSetFile_InvalidJSON(t *testing.T) {
 	}
 
 	_, err := readSetFile(tmpFile)
-	if err == nil {
-		t.Error("expected error for invalid JSON")
+	if !errors.Is(err, ErrSetFileInvalid) {
+		t.Errorf("expected ErrSetFileInvalid for invalid JSON, got %v", err)
 	}
 }
 
@@ -143,3 +148,192 @@ func TestReadSetFile_ValidJSON(t *testing.T) {
 		t.Errorf("expected name 'Test', got %q", data.Champions[0].Name)
 	}
 }
+
+func TestLocalUnitsLoader_Reload(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpFile := tmpDir + "/set.json"
+
+	write := func(name string) {
+		content := `{"champions": [{"name": "` + name + `", "cost": 1, "icons": {"portrait": "https://cdn.example/p.png"}}]}`
+		if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("Before")
+	loader := NewUnitsLoader(LoadUnitsConfig{SetDataPath: tmpFile})
+
+	data, err := loader.LoadUnits(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Units[0].Name != "Before" {
+		t.Fatalf("expected 'Before', got %q", data.Units[0].Name)
+	}
+
+	write("After")
+	data, _ = loader.LoadUnits(context.Background())
+	if data.Units[0].Name != "Before" {
+		t.Error("data should stay cached until Reload")
+	}
+
+	if err := loader.Reload(context.Background()); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	data, _ = loader.LoadUnits(context.Background())
+	if data.Units[0].Name != "After" {
+		t.Errorf("expected 'After' after reload, got %q", data.Units[0].Name)
+	}
+
+	if err := os.WriteFile(tmpFile, []byte("broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.Reload(context.Background()); err == nil {
+		t.Error("expected reload error for invalid JSON")
+	}
+	data, err = loader.LoadUnits(context.Background())
+	if err != nil || data.Units[0].Name != "After" {
+		t.Error("failed reload should keep the previous data")
+	}
+}
+
+func TestLocalUnitsLoader_Canceled(t *testing.T) {
+	tmpFile := t.TempDir() + "/set.json"
+	content := `{"champions": [{"name": "Ahri", "cost": 1, "icons": {"portrait": "https://cdn.example/p.png"}}]}`
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loader := NewUnitsLoader(LoadUnitsConfig{SetDataPath: tmpFile})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := loader.LoadUnits(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if err := loader.Reload(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("reload: expected context.Canceled, got %v", err)
+	}
+
+	// A canceled first load is not cached as the loader's error.
+	data, err := loader.LoadUnits(context.Background())
+	if err != nil || len(data.Units) != 1 {
+		t.Fatalf("load after cancellation: %v", err)
+	}
+}
+
+func TestReadItems(t *testing.T) {
+	tmpFile := t.TempDir() + "/items.json"
+	content := `{"items": [
+		{"apiName": "TFT_Item_InfinityEdge", "name": "Infinity Edge", "desc": "Gain <b>@CritChance*100@%</b> Critical Strike Chance and @Unknown@.", "effects": {"CritChance": 0.35}, "composition": ["TFT_Item_BFSword", "TFT_Item_SparringGloves"]},
+		{"apiName": "TFT_Item_InfinityEdge_Radiant", "name": "Infinity Edge"},
+		{"apiName": "TFT_Item_Empty", "name": " "}
+	]}`
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	items, err := readItems(tmpFile, map[string]string{"infinityedge": "/assets/Items/ie.png"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1 (duplicates and blank names skipped)", len(items))
+	}
+	ie := items[0]
+	if ie.Slug != "infinityedge" || ie.APIName != "TFT_Item_InfinityEdge" || ie.Icon != "/assets/Items/ie.png" {
+		t.Errorf("unexpected item %+v", ie)
+	}
+	if ie.Description != "Gain 35% Critical Strike Chance and @Unknown@." || len(ie.Components) != 2 {
+		t.Errorf("unexpected description/components %q %v", ie.Description, ie.Components)
+	}
+}
+
+func TestReadShopOdds(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := dir + "/" + name
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write temp file: %v", err)
+		}
+		return path
+	}
+	units := []models.Unit{{Cost: 1}, {Cost: 1}, {Cost: 2}, {Cost: 1, Unlock: true}}
+
+	shop, err := readShopOdds(write("odds.json", `{
+		"odds": [{"level": 3, "odds": [75, 25]}, {"level": 2, "odds": [100, 0]}],
+		"copies": {"2": 25, "1": 30}
+	}`), units)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shop.Levels[0].Level != 2 || len(shop.Levels) != 2 {
+		t.Errorf("levels not sorted: %+v", shop.Levels)
+	}
+	want := []models.CostPool{{Cost: 1, Copies: 30, Champions: 2, Total: 60}, {Cost: 2, Copies: 25, Champions: 1, Total: 25}}
+	if !reflect.DeepEqual(shop.Pools, want) {
+		t.Errorf("pools = %+v, want %+v", shop.Pools, want)
+	}
+	if costs := shop.Costs(); !reflect.DeepEqual(costs, []int{1, 2}) {
+		t.Errorf("Costs() = %v", costs)
+	}
+
+	if _, err := readShopOdds(write("bad.json", `{"odds": [{"level": 1, "odds": [90]}]}`), units); err == nil {
+		t.Error("odds not summing to 100 should be rejected")
+	}
+}
+
+func TestLocalUnitsLoader_Localized(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set.json")
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(path, `{"champions": [
+		{"apiName": "TFT_Garen", "name": "Garen", "cost": 1, "traits": ["Defender"],
+		 "ability": {"name": "Judgment", "description": "Spins."}, "icons": {"portrait": "p.png"}},
+		{"apiName": "TFT_Lux", "name": "Lux", "cost": 2, "traits": ["Arcanist"], "icons": {"portrait": "l.png"}}
+	], "traits": [{"apiName": "Set_Defender", "name": "Defender", "desc": "Gain armor."}]}`)
+	write(LocalizedPath(path, "fr"), `{"champions": [
+		{"apiName": "TFT_Garen", "name": "Garen", "cost": 1, "traits": ["Défenseur"],
+		 "ability": {"name": "Jugement", "description": "Tournoie."}}
+	], "traits": [{"apiName": "Set_Defender", "name": "Défenseur", "desc": "Gagne de l'armure."}]}`)
+
+	loader := NewUnitsLoader(LoadUnitsConfig{SetDataPath: path, Locales: []string{"fr", "de"}})
+
+	en, err := loader.LoadUnits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := loader.LoadUnits(i18n.WithLocale(context.Background(), "fr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	de, _ := loader.LoadUnits(i18n.WithLocale(context.Background(), "de"))
+	if de != en {
+		t.Error("a locale without a set file should fall back to the default data")
+	}
+	if fr.Version == en.Version {
+		t.Error("translations need their own version")
+	}
+
+	garen := fr.Units[0]
+	if garen.Slug != "garen" || garen.Ability.Name != "Jugement" || garen.Ability.Description != "Tournoie." {
+		t.Errorf("unexpected translated unit %+v", garen)
+	}
+	if garen.Traits[0].Name != "Défenseur" || garen.Traits[0].Slug != "defender" {
+		t.Errorf("trait names should translate but keep their slug: %+v", garen.Traits)
+	}
+	if en.Units[0].Traits[0].Name != "Defender" || en.Units[0].Ability.Name != "Judgment" {
+		t.Error("translating must not modify the default data")
+	}
+	if lux := fr.Units[1]; lux.Name != "Lux" {
+		t.Errorf("untranslated units should stay in English, got %q", lux.Name)
+	}
+	for _, tr := range fr.Traits {
+		if tr.Slug == "defender" && (tr.Name != "Défenseur" || tr.Description != "Gagne de l'armure.") {
+			t.Errorf("unexpected translated trait %+v", tr)
+		}
+	}
+}
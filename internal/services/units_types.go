@@ -10,6 +10,9 @@ import (
 // minimal structs to decode the generated set JSON
 type setFile struct {
 	Champions []setChampion `json:"champions"`
+	Traits    []setTrait    `json:"traits"` // optional CommunityDragon trait definitions
+
+	version string // content hash of the raw file, set by readSetFile
 }
 
 type setChampion struct {
@@ -23,6 +26,7 @@ type setChampion struct {
 	UnlockDescription string     `json:"unlockDescription"`
 	Role              string     `json:"role"`
 	Stats             setStats   `json:"stats"`
+	RecommendedItems  []string   `json:"recommendedItems"` // optional item names, best first
 }
 
 type setAbility struct {
@@ -110,6 +114,20 @@ func (r *rawAbilityVariables) UnmarshalJSON(data []byte) error {
 	}
 }
 
+type setTrait struct {
+	APIName string           `json:"apiName"`
+	Name    string           `json:"name"`
+	Desc    string           `json:"desc"`
+	Effects []setTraitEffect `json:"effects"`
+}
+
+type setTraitEffect struct {
+	MinUnits  int                `json:"minUnits"`
+	MaxUnits  int                `json:"maxUnits"`
+	Style     int                `json:"style"`
+	Variables map[string]float64 `json:"variables"`
+}
+
 type setIcons struct {
 	Square   string `json:"square"`
 	Tile     string `json:"tile"`
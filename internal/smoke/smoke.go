@@ -0,0 +1,310 @@
+// Package smoke checks a running deployment end to end: pages, API,
+// static assets and a saved comp roundtrip, with their status codes,
+// compression and cache headers.
+package smoke
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxBody caps how much of each response is read.
+const maxBody = 8 << 20
+
+// assetRef finds the bundled stylesheet and script URLs in a page.
+var assetRef = regexp.MustCompile(`(?:href|src)="([^"]+/dist/[^"]+\.(?:css|js))"`)
+
+// errSkip marks a check that does not apply to the deployment, e.g. the
+// comp roundtrip when persistence is disabled.
+var errSkip = errors.New("skipped")
+
+// Options configures a run.
+type Options struct {
+	BaseURL string // e.g. https://sft.example.com
+	// ExpectVersion is the dataset version the deployment should serve;
+	// empty accepts any.
+	ExpectVersion string
+	Client        *http.Client // defaults to a client with a 15s timeout
+}
+
+// Result is the outcome of one check. Err is nil on success.
+type Result struct {
+	Name     string
+	Detail   string
+	Err      error
+	Skipped  bool
+	Duration time.Duration
+}
+
+// Failed reports whether any result is a failure.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+type runner struct {
+	base   string
+	expect string
+	client *http.Client
+
+	etag   string
+	assets []string
+	unit   string
+}
+
+// Run performs every check in order. Later checks reuse what earlier ones
+// found, such as the asset URLs in the builder page.
+func Run(ctx context.Context, opts Options) []Result {
+	r := &runner{
+		base:   strings.TrimRight(opts.BaseURL, "/"),
+		expect: opts.ExpectVersion,
+		client: opts.Client,
+	}
+	if r.client == nil {
+		r.client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	checks := []struct {
+		name string
+		fn   func(context.Context) (string, error)
+	}{
+		{"builder page", r.builderPage},
+		{"builder revalidation", r.revalidation},
+		{"static assets", r.staticAssets},
+		{"units API", r.unitsAPI},
+		{"version", r.version},
+		{"health", r.health},
+		{"comp roundtrip", r.compRoundtrip},
+	}
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		start := time.Now()
+		detail, err := c.fn(ctx)
+		res := Result{Name: c.name, Detail: detail, Err: err, Duration: time.Since(start)}
+		if errors.Is(err, errSkip) {
+			res.Err, res.Skipped = nil, true
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func (r *runner) builderPage(ctx context.Context) (string, error) {
+	resp, body, err := r.do(ctx, http.MethodGet, "/", nil, map[string]string{"Accept-Encoding": "gzip"})
+	if err != nil {
+		return "", err
+	}
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return "", err
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		return "", fmt.Errorf("content type %q, want text/html", ct)
+	}
+	if enc := resp.Header.Get("Content-Encoding"); enc != "gzip" {
+		return "", fmt.Errorf("not compressed (Content-Encoding %q)", enc)
+	}
+	if cc := resp.Header.Get("Cache-Control"); !strings.Contains(cc, "no-cache") {
+		return "", fmt.Errorf("Cache-Control %q, want no-cache so pages revalidate", cc)
+	}
+	r.etag = resp.Header.Get("ETag")
+	if r.etag == "" {
+		return "", errors.New("no ETag")
+	}
+	for _, m := range assetRef.FindAllSubmatch(body, -1) {
+		if asset := string(m[1]); !slices.Contains(r.assets, asset) {
+			r.assets = append(r.assets, asset)
+		}
+	}
+	if len(r.assets) == 0 {
+		return "", errors.New("no bundled CSS or JS referenced")
+	}
+	return fmt.Sprintf("%d bytes, ETag %s", len(body), r.etag), nil
+}
+
+func (r *runner) revalidation(ctx context.Context) (string, error) {
+	if r.etag == "" {
+		return "", fmt.Errorf("%w: no ETag from the builder page", errSkip)
+	}
+	resp, _, err := r.do(ctx, http.MethodGet, "/", nil, map[string]string{
+		"Accept-Encoding": "gzip",
+		"If-None-Match":   r.etag,
+	})
+	if err != nil {
+		return "", err
+	}
+	return "304", expectStatus(resp, http.StatusNotModified)
+}
+
+func (r *runner) staticAssets(ctx context.Context) (string, error) {
+	if len(r.assets) == 0 {
+		return "", fmt.Errorf("%w: no assets found in the builder page", errSkip)
+	}
+	var details []string
+	for _, asset := range r.assets {
+		resp, _, err := r.do(ctx, http.MethodGet, asset, nil, map[string]string{"Accept-Encoding": "gzip"})
+		if err != nil {
+			return "", err
+		}
+		if err := expectStatus(resp, http.StatusOK); err != nil {
+			return "", fmt.Errorf("%s: %w", asset, err)
+		}
+		if enc := resp.Header.Get("Content-Encoding"); enc != "gzip" {
+			return "", fmt.Errorf("%s: not compressed", asset)
+		}
+		cc := resp.Header.Get("Cache-Control")
+		if cc == "" {
+			return "", fmt.Errorf("%s: no Cache-Control", asset)
+		}
+		details = append(details, fmt.Sprintf("%s (%s)", asset, cc))
+	}
+	return strings.Join(details, ", "), nil
+}
+
+func (r *runner) unitsAPI(ctx context.Context) (string, error) {
+	resp, body, err := r.do(ctx, http.MethodGet, "/api/v1/units", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return "", err
+	}
+	var payload struct {
+		Version string `json:"version"`
+		Units   []struct {
+			Slug string `json:"slug"`
+		} `json:"units"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+	if len(payload.Units) == 0 {
+		return "", errors.New("no units")
+	}
+	if r.expect != "" && payload.Version != r.expect {
+		return "", fmt.Errorf("dataset version %q, want %q", payload.Version, r.expect)
+	}
+	r.unit = payload.Units[0].Slug
+	return fmt.Sprintf("%d units, dataset %s", len(payload.Units), payload.Version), nil
+}
+
+func (r *runner) version(ctx context.Context) (string, error) {
+	resp, body, err := r.do(ctx, http.MethodGet, "/version", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func (r *runner) health(ctx context.Context) (string, error) {
+	resp, body, err := r.do(ctx, http.MethodGet, "/healthz", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), expectStatus(resp, http.StatusOK)
+}
+
+func (r *runner) compRoundtrip(ctx context.Context) (string, error) {
+	if r.unit == "" {
+		return "", fmt.Errorf("%w: no unit to place", errSkip)
+	}
+	board := "1~001" + r.unit
+	req, _ := json.Marshal(map[string]string{"name": "smoke test", "board": board})
+	resp, body, err := r.do(ctx, http.MethodPost, "/api/v1/comps", req, map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return "", fmt.Errorf("%w: comps are disabled", errSkip)
+	}
+	if err := expectStatus(resp, http.StatusCreated); err != nil {
+		return "", fmt.Errorf("create: %w: %s", err, bytes.TrimSpace(body))
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", errors.New("create: no Location")
+	}
+
+	resp, body, err = r.do(ctx, http.MethodGet, location, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return "", fmt.Errorf("get: %w", err)
+	}
+	var saved struct {
+		Board string `json:"board"`
+	}
+	if err := json.Unmarshal(body, &saved); err != nil || saved.Board != board {
+		return "", fmt.Errorf("get: board %q, want %q", saved.Board, board)
+	}
+
+	resp, _, err = r.do(ctx, http.MethodDelete, location, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("delete: status %d", resp.StatusCode)
+	}
+	return location, nil
+}
+
+// do sends a request and returns the decoded body. A gzip body is
+// decompressed, since an explicit Accept-Encoding turns off the
+// transport's own handling.
+func (r *runner) do(ctx context.Context, method, path string, body []byte, headers map[string]string) (*http.Response, []byte, error) {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = r.base + path
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = io.LimitReader(resp.Body, maxBody)
+	if resp.Header.Get("Content-Encoding") == "gzip" && resp.StatusCode != http.StatusNotModified {
+		zr, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s: invalid gzip body: %w", method, path, err)
+		}
+		defer zr.Close()
+		reader = zr
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: read body: %w", method, path, err)
+	}
+	return resp, data, nil
+}
+
+func expectStatus(resp *http.Response, want int) error {
+	if resp.StatusCode != want {
+		return fmt.Errorf("status %d, want %d", resp.StatusCode, want)
+	}
+	return nil
+}
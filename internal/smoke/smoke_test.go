@@ -0,0 +1,60 @@
+package smoke
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sft/internal/middleware"
+)
+
+func TestRun(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"page"`)
+		if r.Header.Get("If-None-Match") == `"page"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<link href="/static/dist/app.css"><script src="/static/dist/app.js"></script>`))
+	})
+	mux.HandleFunc("GET /static/dist/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.Write([]byte("body{}"))
+	})
+	mux.HandleFunc("GET /api/v1/units", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"v1","units":[{"slug":"ahri"}]}`))
+	})
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"dev"}`))
+	})
+	srv := httptest.NewServer(middleware.Gzip(mux))
+	defer srv.Close()
+
+	results := Run(context.Background(), Options{BaseURL: srv.URL, ExpectVersion: "v2"})
+	byName := make(map[string]Result, len(results))
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+
+	for _, name := range []string{"builder page", "builder revalidation", "static assets", "version"} {
+		if r := byName[name]; r.Err != nil || r.Skipped {
+			t.Errorf("%s: unexpected result %+v", name, r)
+		}
+	}
+	if r := byName["units API"]; r.Err == nil {
+		t.Error("units API: expected a dataset version mismatch")
+	}
+	if r := byName["health"]; r.Err == nil {
+		t.Error("health: expected a failure for the missing endpoint")
+	}
+	if r := byName["comp roundtrip"]; !r.Skipped {
+		t.Errorf("comp roundtrip: expected a skip without a unit, got %+v", r)
+	}
+	if !Failed(results) {
+		t.Error("Failed() = false")
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// MemorySessionData is a SessionDataStore kept in process memory, for
+// deployments without a database. Sessions are lost on restart.
+type MemorySessionData struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	values    map[string]string
+	expiresAt time.Time
+}
+
+// NewMemorySessionData creates an empty in-memory session store.
+func NewMemorySessionData() *MemorySessionData {
+	return &MemorySessionData{sessions: make(map[string]memorySession)}
+}
+
+// GetSessionData returns a copy of an unexpired session's values or ErrNotFound.
+func (m *MemorySessionData) GetSessionData(_ context.Context, id string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || !time.Now().Before(s.expiresAt) {
+		return nil, ErrNotFound
+	}
+	return maps.Clone(s.values), nil
+}
+
+// PutSessionData creates or replaces the session id, sweeping expired ones.
+func (m *MemorySessionData) PutSessionData(_ context.Context, id string, values map[string]string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, s := range m.sessions {
+		if !now.Before(s.expiresAt) {
+			delete(m.sessions, k)
+		}
+	}
+	m.sessions[id] = memorySession{values: maps.Clone(values), expiresAt: expiresAt}
+	return nil
+}
+
+// DeleteSessionData removes the session id, if it exists.
+func (m *MemorySessionData) DeleteSessionData(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
@@ -0,0 +1,14 @@
+CREATE TABLE users (
+	id            BIGSERIAL   PRIMARY KEY,
+	username      TEXT        NOT NULL,
+	password_hash TEXT        NOT NULL,
+	created_at    TIMESTAMPTZ NOT NULL
+);
+-- Usernames are unique regardless of case, as in SQLite's COLLATE NOCASE.
+CREATE UNIQUE INDEX users_username ON users (lower(username));
+
+CREATE TABLE sessions (
+	token_hash TEXT        PRIMARY KEY,
+	user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	expires_at TIMESTAMPTZ NOT NULL
+);
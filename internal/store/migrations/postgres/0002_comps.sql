@@ -0,0 +1,21 @@
+CREATE TABLE comps (
+	id          BIGSERIAL   PRIMARY KEY,
+	owner_id    BIGINT      REFERENCES users(id) ON DELETE SET NULL,
+	name        TEXT        NOT NULL,
+	board       TEXT        NOT NULL,
+	notes       TEXT        NOT NULL DEFAULT '',
+	set_version TEXT        NOT NULL DEFAULT '',
+	votes       BIGINT      NOT NULL DEFAULT 0,
+	created_at  TIMESTAMPTZ NOT NULL,
+	updated_at  TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX comps_owner_id ON comps (owner_id);
+CREATE INDEX comps_updated ON comps (updated_at DESC, id DESC);
+CREATE INDEX comps_votes ON comps (votes DESC, updated_at DESC);
+
+CREATE TABLE comp_votes (
+	comp_id    BIGINT      NOT NULL REFERENCES comps(id) ON DELETE CASCADE,
+	voter      TEXT        NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (comp_id, voter)
+);
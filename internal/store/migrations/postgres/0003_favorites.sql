@@ -0,0 +1,7 @@
+CREATE TABLE favorites (
+	id         BIGSERIAL   PRIMARY KEY,
+	user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	api_name   TEXT        NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	UNIQUE (user_id, api_name)
+);
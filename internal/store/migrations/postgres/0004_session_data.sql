@@ -0,0 +1,7 @@
+CREATE TABLE session_data (
+	id         TEXT        PRIMARY KEY,
+	data       TEXT        NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX session_data_expires_at ON session_data (expires_at);
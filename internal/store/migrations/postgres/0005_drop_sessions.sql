@@ -0,0 +1,2 @@
+-- Logins moved into session_data; see auth.Sessions.
+DROP TABLE sessions;
@@ -0,0 +1,61 @@
+CREATE TABLE lobbies (
+	id         BIGSERIAL   PRIMARY KEY,
+	owner_id   BIGINT      REFERENCES users(id) ON DELETE SET NULL,
+	name       TEXT        NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE lobby_players (
+	lobby_id BIGINT  NOT NULL REFERENCES lobbies(id) ON DELETE CASCADE,
+	slot     INTEGER NOT NULL,
+	name     TEXT    NOT NULL DEFAULT '',
+	notes    TEXT    NOT NULL DEFAULT '',
+	comps    TEXT    NOT NULL DEFAULT '',
+	PRIMARY KEY (lobby_id, slot)
+);
+
+CREATE TABLE drill_attempts (
+	id         BIGSERIAL   PRIMARY KEY,
+	user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	kind       TEXT        NOT NULL,
+	seed       BIGINT      NOT NULL,
+	scenario   TEXT        NOT NULL,
+	answer     TEXT        NOT NULL,
+	correct    BOOLEAN,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX drill_attempts_user_id ON drill_attempts (user_id, created_at);
+
+CREATE TABLE short_links (
+	code       TEXT        PRIMARY KEY,
+	board      TEXT        NOT NULL UNIQUE,
+	created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE render_jobs (
+	code       TEXT        PRIMARY KEY,
+	status     TEXT        NOT NULL,
+	image      BYTEA,
+	error      TEXT        NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX render_jobs_status ON render_jobs (status, created_at);
+
+CREATE TABLE redirects (
+	from_path  TEXT        PRIMARY KEY,
+	to_path    TEXT        NOT NULL,
+	status     INTEGER     NOT NULL,
+	hits       BIGINT      NOT NULL DEFAULT 0,
+	last_hit   TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE changelog (
+	version      TEXT        PRIMARY KEY,
+	title        TEXT        NOT NULL,
+	body         TEXT        NOT NULL,
+	published_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX changelog_published ON changelog (published_at DESC);
@@ -0,0 +1,17 @@
+-- Render jobs are keyed by dataset version and site too; queued renders
+-- are only a cache, so the old table is dropped rather than converted.
+DROP TABLE render_jobs;
+
+CREATE TABLE render_jobs (
+	code       TEXT        NOT NULL,
+	version    TEXT        NOT NULL,
+	site       TEXT        NOT NULL,
+	status     TEXT        NOT NULL,
+	image      BYTEA,
+	error      TEXT        NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (code, version, site)
+);
+CREATE INDEX render_jobs_status ON render_jobs (status, created_at);
+CREATE INDEX render_jobs_updated ON render_jobs (updated_at);
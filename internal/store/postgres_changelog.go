@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListChangelog returns up to limit entries, newest first.
+func (s *PostgresStore) ListChangelog(ctx context.Context, limit int) ([]ChangelogEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT version, title, body, published_at FROM changelog
+		 ORDER BY published_at DESC, version DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list changelog: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []ChangelogEntry{}
+	for rows.Next() {
+		var e ChangelogEntry
+		if err := rows.Scan(&e.Version, &e.Title, &e.Body, &e.Published); err != nil {
+			return nil, fmt.Errorf("scan changelog entry: %w", err)
+		}
+		e.Published = e.Published.UTC()
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// PutChangelogEntry creates or replaces the entry for e.Version. A zero
+// Published is set to now.
+func (s *PostgresStore) PutChangelogEntry(ctx context.Context, e *ChangelogEntry) error {
+	if e.Published.IsZero() {
+		e.Published = time.Now()
+	}
+	e.Published = e.Published.UTC().Truncate(time.Second)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO changelog (version, title, body, published_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (version) DO UPDATE SET
+		   title = excluded.title, body = excluded.body, published_at = excluded.published_at`,
+		e.Version, e.Title, e.Body, e.Published)
+	if err != nil {
+		return fmt.Errorf("put changelog entry %q: %w", e.Version, err)
+	}
+	return nil
+}
+
+// DeleteChangelogEntry removes the entry for version. Returns ErrNotFound
+// if it does not exist.
+func (s *PostgresStore) DeleteChangelogEntry(ctx context.Context, version string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM changelog WHERE version = $1`, version)
+	if err != nil {
+		return fmt.Errorf("delete changelog entry %q: %w", version, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
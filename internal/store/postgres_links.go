@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateShortLink returns the code for board, generating one on first use.
+func (s *PostgresStore) CreateShortLink(ctx context.Context, board string) (*ShortLink, error) {
+	if link, err := s.shortLinkByBoard(ctx, board); !errors.Is(err, ErrNotFound) {
+		return link, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < shortCodeAttempts; i++ {
+		code, err := newShortCode()
+		if err != nil {
+			return nil, fmt.Errorf("generate short code: %w", err)
+		}
+		_, err = s.db.ExecContext(ctx,
+			`INSERT INTO short_links (code, board, created_at) VALUES ($1, $2, $3)`,
+			code, board, now)
+		if err == nil {
+			return &ShortLink{Code: code, Board: board, CreatedAt: now}, nil
+		}
+		if !isPostgresUniqueViolation(err) {
+			return nil, fmt.Errorf("insert short link: %w", err)
+		}
+		// Either the code collided or another request just linked the same board.
+		if link, err := s.shortLinkByBoard(ctx, board); !errors.Is(err, ErrNotFound) {
+			return link, err
+		}
+	}
+	return nil, fmt.Errorf("insert short link: %w", ErrConflict)
+}
+
+// GetShortLink loads the link for code. Returns ErrNotFound if it does not exist.
+func (s *PostgresStore) GetShortLink(ctx context.Context, code string) (*ShortLink, error) {
+	return scanPostgresShortLink(s.db.QueryRowContext(ctx,
+		`SELECT code, board, created_at FROM short_links WHERE code = $1`, code), code)
+}
+
+func (s *PostgresStore) shortLinkByBoard(ctx context.Context, board string) (*ShortLink, error) {
+	return scanPostgresShortLink(s.db.QueryRowContext(ctx,
+		`SELECT code, board, created_at FROM short_links WHERE board = $1`, board), board)
+}
+
+func scanPostgresShortLink(row *sql.Row, key string) (*ShortLink, error) {
+	var link ShortLink
+	if err := row.Scan(&link.Code, &link.Board, &link.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get short link %q: %w", key, err)
+	}
+	link.CreatedAt = link.CreatedAt.UTC()
+	return &link, nil
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CreateLobby inserts l with LobbySize player seats and fills in its ID and timestamps.
+// Seats missing from l.Players are created empty.
+func (s *PostgresStore) CreateLobby(ctx context.Context, l *Lobby) error {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO lobbies (owner_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		nullID(l.OwnerID), l.Name, now, now,
+	).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+
+	players := seatPlayers(l.Players)
+	for _, p := range players {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO lobby_players (lobby_id, slot, name, notes, comps) VALUES ($1, $2, $3, $4, $5)`,
+			id, p.Slot, p.Name, p.Notes, strings.Join(p.Comps, lobbyCompSep),
+		); err != nil {
+			return fmt.Errorf("insert lobby player: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+
+	l.ID = id
+	l.Players = players
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	return nil
+}
+
+// GetLobby returns the lobby and its players or ErrNotFound.
+func (s *PostgresStore) GetLobby(ctx context.Context, id int64) (*Lobby, error) {
+	var l Lobby
+	var owner sql.NullInt64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, owner_id, name, created_at, updated_at FROM lobbies WHERE id = $1`, id,
+	).Scan(&l.ID, &owner, &l.Name, &l.CreatedAt, &l.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lobby %d: %w", id, err)
+	}
+	l.OwnerID = owner.Int64
+	l.CreatedAt = l.CreatedAt.UTC()
+	l.UpdatedAt = l.UpdatedAt.UTC()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT slot, name, notes, comps FROM lobby_players WHERE lobby_id = $1 ORDER BY slot`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get lobby %d players: %w", id, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p LobbyPlayer
+		var comps string
+		if err := rows.Scan(&p.Slot, &p.Name, &p.Notes, &comps); err != nil {
+			return nil, fmt.Errorf("get lobby %d players: %w", id, err)
+		}
+		p.Comps = splitLobbyComps(comps)
+		l.Players = append(l.Players, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get lobby %d players: %w", id, err)
+	}
+	return &l, nil
+}
+
+// UpdateLobbyPlayer replaces the seat p.Slot of a lobby or returns ErrNotFound.
+func (s *PostgresStore) UpdateLobbyPlayer(ctx context.Context, lobbyID int64, p LobbyPlayer) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE lobby_players SET name = $1, notes = $2, comps = $3 WHERE lobby_id = $4 AND slot = $5`,
+		p.Name, p.Notes, strings.Join(p.Comps, lobbyCompSep), lobbyID, p.Slot,
+	)
+	if err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE lobbies SET updated_at = $1 WHERE id = $2`, time.Now().UTC(), lobbyID,
+	); err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ListRedirects returns every redirect ordered by source path.
+func (s *PostgresStore) ListRedirects(ctx context.Context) ([]Redirect, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT from_path, to_path, status, hits, last_hit, created_at FROM redirects ORDER BY from_path`)
+	if err != nil {
+		return nil, fmt.Errorf("list redirects: %w", err)
+	}
+	defer rows.Close()
+
+	redirects := []Redirect{}
+	for rows.Next() {
+		var r Redirect
+		var lastHit sql.NullTime
+		if err := rows.Scan(&r.From, &r.To, &r.Status, &r.Hits, &lastHit, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan redirect: %w", err)
+		}
+		if lastHit.Valid {
+			t := lastHit.Time.UTC()
+			r.LastHit = &t
+		}
+		r.CreatedAt = r.CreatedAt.UTC()
+		redirects = append(redirects, r)
+	}
+	return redirects, rows.Err()
+}
+
+// PutRedirect inserts r or replaces the target and status of an existing
+// redirect from the same path.
+func (s *PostgresStore) PutRedirect(ctx context.Context, r *Redirect) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO redirects (from_path, to_path, status, created_at) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (from_path) DO UPDATE SET to_path = excluded.to_path, status = excluded.status
+		 RETURNING hits, created_at`,
+		r.From, r.To, r.Status, now).Scan(&r.Hits, &r.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("put redirect %q: %w", r.From, err)
+	}
+	r.CreatedAt = r.CreatedAt.UTC()
+	return nil
+}
+
+// DeleteRedirect removes the redirect from path. Returns ErrNotFound if it does not exist.
+func (s *PostgresStore) DeleteRedirect(ctx context.Context, from string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM redirects WHERE from_path = $1`, from)
+	if err != nil {
+		return fmt.Errorf("delete redirect %q: %w", from, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordRedirectHit counts one use of the redirect from path.
+func (s *PostgresStore) RecordRedirectHit(ctx context.Context, from string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE redirects SET hits = hits + 1, last_hit = $1 WHERE from_path = $2`,
+		time.Now().UTC(), from)
+	if err != nil {
+		return fmt.Errorf("record redirect hit %q: %w", from, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EnqueueRender queues key; pending, running and done jobs are left as
+// they are, a failed one is queued again.
+func (s *PostgresStore) EnqueueRender(ctx context.Context, key RenderKey) error {
+	now := time.Now().UTC()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO render_jobs (code, version, site, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (code, version, site) DO UPDATE SET
+			status = excluded.status, error = '', created_at = excluded.created_at, updated_at = excluded.updated_at
+		WHERE render_jobs.status = $7`,
+		key.Code, key.Version, key.Site, RenderPending, now, now, RenderFailed)
+	if err != nil {
+		return fmt.Errorf("enqueue render %q: %w", key.Code, err)
+	}
+	return nil
+}
+
+// ClaimRender atomically moves the oldest claimable job to running. SKIP
+// LOCKED lets workers on several instances claim different jobs at once.
+func (s *PostgresStore) ClaimRender(ctx context.Context, stale time.Duration) (RenderKey, error) {
+	now := time.Now().UTC()
+	var key RenderKey
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE render_jobs SET status = $1, updated_at = $2
+		WHERE (code, version, site) = (
+			SELECT code, version, site FROM render_jobs
+			WHERE status = $3 OR (status = $4 AND updated_at < $5)
+			ORDER BY created_at LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING code, version, site`,
+		RenderRunning, now, RenderPending, RenderRunning, now.Add(-stale),
+	).Scan(&key.Code, &key.Version, &key.Site)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RenderKey{}, ErrNotFound
+	}
+	if err != nil {
+		return RenderKey{}, fmt.Errorf("claim render: %w", err)
+	}
+	return key, nil
+}
+
+// CompleteRender records the outcome of a claimed job.
+func (s *PostgresStore) CompleteRender(ctx context.Context, key RenderKey, png []byte, errMsg string) error {
+	status := RenderDone
+	if errMsg != "" {
+		status, png = RenderFailed, nil
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE render_jobs SET status = $1, image = $2, error = $3, updated_at = $4
+		WHERE code = $5 AND version = $6 AND site = $7`,
+		status, png, errMsg, time.Now().UTC(), key.Code, key.Version, key.Site)
+	if err != nil {
+		return fmt.Errorf("complete render %q: %w", key.Code, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetRender returns the job for key or ErrNotFound.
+func (s *PostgresStore) GetRender(ctx context.Context, key RenderKey) (*RenderJob, error) {
+	job := RenderJob{RenderKey: key}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT status, image, error, updated_at FROM render_jobs WHERE code = $1 AND version = $2 AND site = $3`,
+		key.Code, key.Version, key.Site,
+	).Scan(&job.Status, &job.Image, &job.Error, &job.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get render %q: %w", key.Code, err)
+	}
+	job.UpdatedAt = job.UpdatedAt.UTC()
+	return &job, nil
+}
+
+// PruneRenders deletes jobs last updated before before.
+func (s *PostgresStore) PruneRenders(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM render_jobs WHERE updated_at < $1`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("prune renders: %w", err)
+	}
+	return res.RowsAffected()
+}
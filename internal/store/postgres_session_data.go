@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetSessionData returns the values of an unexpired session or ErrNotFound.
+func (s *PostgresStore) GetSessionData(ctx context.Context, id string) (map[string]string, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data FROM session_data WHERE id = $1 AND expires_at > $2`, id, time.Now().UTC(),
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get session data: %w", err)
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, fmt.Errorf("get session data: %w", err)
+	}
+	return values, nil
+}
+
+// PutSessionData creates or replaces the session id, sweeping expired
+// sessions on the way.
+func (s *PostgresStore) PutSessionData(ctx context.Context, id string, values map[string]string, expiresAt time.Time) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("put session data: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_data WHERE expires_at <= $1`, time.Now().UTC()); err != nil {
+		return fmt.Errorf("sweep session data: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO session_data (id, data, expires_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (id) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at`,
+		id, string(data), expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("put session data: %w", err)
+	}
+	return nil
+}
+
+// DeleteSessionData removes the session id, if it exists.
+func (s *PostgresStore) DeleteSessionData(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_data WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete session data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+// openPostgresTestStore migrates a fresh schema of the database named by
+// SFT_TEST_POSTGRES_DSN (a postgres:// URL) and drops it after the test.
+// Run with: SFT_TEST_POSTGRES_DSN=postgres://... go test ./internal/store
+func openPostgresTestStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	dsn := os.Getenv("SFT_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("SFT_TEST_POSTGRES_DSN not set")
+	}
+
+	admin, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	schema := fmt.Sprintf("sft_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("dsn: %v", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	s, err := OpenPostgres(u.String())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestPostgresStore_CompLifecycle(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	comp := &Comp{Name: "Yordle reroll", Board: "1~001tristana", SetVersion: "abc"}
+	if err := s.CreateComp(ctx, comp); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if comp.ID == 0 || comp.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and timestamps, got %+v", comp)
+	}
+
+	got, err := s.GetComp(ctx, comp.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != comp.Name || got.Board != comp.Board || got.SetVersion != "abc" || !got.CreatedAt.Equal(comp.CreatedAt) {
+		t.Errorf("unexpected comp: %+v", got)
+	}
+
+	list, err := s.ListComps(ctx, CompSortNew, 10)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list: %v (%d comps)", err, len(list))
+	}
+
+	if err := s.DeleteComp(ctx, comp.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.GetComp(ctx, comp.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := s.DeleteComp(ctx, comp.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second delete, got %v", err)
+	}
+}
+
+func TestPostgresStore_Users(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	u := &User{Username: "Tristana", PasswordHash: "hash"}
+	if err := s.CreateUser(ctx, u); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := s.CreateUser(ctx, &User{Username: "tristana", PasswordHash: "x"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for a case-insensitive duplicate, got %v", err)
+	}
+	if got, err := s.GetUserByUsername(ctx, "TRISTANA"); err != nil || got.ID != u.ID {
+		t.Errorf("lookup by username: %+v, %v", got, err)
+	}
+
+	comp := &Comp{Name: "Mine", Board: "1~", OwnerID: u.ID}
+	if err := s.CreateComp(ctx, comp); err != nil {
+		t.Fatalf("create comp: %v", err)
+	}
+	if owned, err := s.ListCompsByOwner(ctx, u.ID, 10); err != nil || len(owned) != 1 || owned[0].OwnerID != u.ID {
+		t.Errorf("owned comps: %+v, %v", owned, err)
+	}
+}
+
+func TestPostgresStore_SessionData(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	if err := s.PutSessionData(ctx, "sid", map[string]string{"theme": "dark"}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := s.PutSessionData(ctx, "sid", map[string]string{"theme": "light"}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if got, err := s.GetSessionData(ctx, "sid"); err != nil || got["theme"] != "light" {
+		t.Errorf("get: %v, %v", got, err)
+	}
+	if err := s.PutSessionData(ctx, "old", map[string]string{}, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("put expired: %v", err)
+	}
+	if _, err := s.GetSessionData(ctx, "old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expired session: %v", err)
+	}
+	if err := s.DeleteSessionData(ctx, "sid"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.GetSessionData(ctx, "sid"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted session: %v", err)
+	}
+}
+
+func TestPostgresStore_VotesAndFavorites(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	comp := &Comp{Name: "Voted", Board: "1~"}
+	if err := s.CreateComp(ctx, comp); err != nil {
+		t.Fatalf("create comp: %v", err)
+	}
+	for _, voter := range []string{"a", "a", "b"} {
+		if _, err := s.VoteComp(ctx, comp.ID, voter); err != nil {
+			t.Fatalf("vote: %v", err)
+		}
+	}
+	if votes, err := s.UnvoteComp(ctx, comp.ID, "a"); err != nil || votes != 1 {
+		t.Errorf("unvote: %d, %v; want 1", votes, err)
+	}
+	if _, err := s.VoteComp(ctx, comp.ID+1, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("vote on a missing comp: %v", err)
+	}
+
+	u := &User{Username: "fan", PasswordHash: "hash"}
+	if err := s.CreateUser(ctx, u); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	for _, apiName := range []string{"TFT16_Ahri", "TFT16_Zed", "TFT16_Ahri"} {
+		if err := s.AddFavorite(ctx, u.ID, apiName); err != nil {
+			t.Fatalf("add favorite: %v", err)
+		}
+	}
+	if err := s.RemoveFavorite(ctx, u.ID, "TFT16_Ahri"); err != nil {
+		t.Fatalf("remove favorite: %v", err)
+	}
+	if favs, err := s.ListFavorites(ctx, u.ID); err != nil || len(favs) != 1 || favs[0] != "TFT16_Zed" {
+		t.Errorf("favorites: %v, %v", favs, err)
+	}
+}
+
+func TestPostgresStore_LobbiesAndDrills(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	lobby := &Lobby{Name: "Finals", Players: []LobbyPlayer{{Slot: 3, Name: "Milk"}}}
+	if err := s.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("create lobby: %v", err)
+	}
+	update := LobbyPlayer{Slot: 3, Name: "Milk", Notes: "fast 8", Comps: []string{"1~001ahri", "1~032garen"}}
+	if err := s.UpdateLobbyPlayer(ctx, lobby.ID, update); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := s.GetLobby(ctx, lobby.ID)
+	if err != nil || len(got.Players) != LobbySize || got.Players[2].Notes != "fast 8" || len(got.Players[2].Comps) != 2 {
+		t.Errorf("get lobby: %+v, %v", got, err)
+	}
+	if err := s.UpdateLobbyPlayer(ctx, lobby.ID, LobbyPlayer{Slot: 9}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid slot: %v", err)
+	}
+
+	u := &User{Username: "drill", PasswordHash: "hash"}
+	if err := s.CreateUser(ctx, u); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	correct := true
+	a := &DrillAttempt{UserID: u.ID, Kind: "positioning", Seed: 1 << 63, Scenario: []byte(`{}`), Answer: "a", Correct: &correct}
+	if err := s.CreateDrillAttempt(ctx, a); err != nil {
+		t.Fatalf("create attempt: %v", err)
+	}
+	attempts, err := s.ListDrillAttempts(ctx, u.ID, 10)
+	if err != nil || len(attempts) != 1 || attempts[0].Seed != 1<<63 || attempts[0].Correct == nil || !*attempts[0].Correct {
+		t.Errorf("attempts: %+v, %v", attempts, err)
+	}
+}
+
+func TestPostgresStore_LinksRedirectsAndChangelog(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	link, err := s.CreateShortLink(ctx, "1~001ahri")
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+	if again, err := s.CreateShortLink(ctx, "1~001ahri"); err != nil || again.Code != link.Code {
+		t.Errorf("same board should reuse code %q, got %+v, %v", link.Code, again, err)
+	}
+	if got, err := s.GetShortLink(ctx, link.Code); err != nil || got.Board != "1~001ahri" {
+		t.Errorf("get link: %+v, %v", got, err)
+	}
+
+	if err := s.PutRedirect(ctx, &Redirect{From: "/units/old", To: "/units/new", Status: 301}); err != nil {
+		t.Fatalf("put redirect: %v", err)
+	}
+	if err := s.RecordRedirectHit(ctx, "/units/old"); err != nil {
+		t.Fatalf("hit: %v", err)
+	}
+	if err := s.PutRedirect(ctx, &Redirect{From: "/units/old", To: "/units/newer", Status: 302}); err != nil {
+		t.Fatalf("replace redirect: %v", err)
+	}
+	redirects, err := s.ListRedirects(ctx)
+	if err != nil || len(redirects) != 1 || redirects[0].To != "/units/newer" || redirects[0].Hits != 1 || redirects[0].LastHit == nil {
+		t.Errorf("redirects: %+v, %v", redirects, err)
+	}
+
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []*ChangelogEntry{
+		{Version: "16.1", Title: "Launch", Published: day},
+		{Version: "16.2", Title: "Balance", Published: day.AddDate(0, 0, 14)},
+	} {
+		if err := s.PutChangelogEntry(ctx, e); err != nil {
+			t.Fatalf("put %s: %v", e.Version, err)
+		}
+	}
+	entries, err := s.ListChangelog(ctx, 10)
+	if err != nil || len(entries) != 2 || entries[0].Version != "16.2" || !entries[1].Published.Equal(day) {
+		t.Errorf("changelog: %+v, %v", entries, err)
+	}
+	if err := s.DeleteChangelogEntry(ctx, "16.1"); err != nil {
+		t.Fatalf("delete entry: %v", err)
+	}
+}
+
+func TestPostgresStore_RenderQueue(t *testing.T) {
+	s := openPostgresTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.ClaimRender(ctx, time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("empty queue: %v", err)
+	}
+	ahri := RenderKey{Code: "1~001ahri", Version: "v1", Site: "SFT"}
+	for i := 0; i < 2; i++ {
+		if err := s.EnqueueRender(ctx, ahri); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	key, err := s.ClaimRender(ctx, time.Minute)
+	if err != nil || key != ahri {
+		t.Fatalf("claim: %+v, %v", key, err)
+	}
+	if _, err := s.ClaimRender(ctx, time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Errorf("duplicate enqueue should not add a job, got %v", err)
+	}
+	if err := s.CompleteRender(ctx, key, nil, "boom"); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	if err := s.EnqueueRender(ctx, key); err != nil {
+		t.Fatalf("re-enqueue: %v", err)
+	}
+	if retried, err := s.ClaimRender(ctx, time.Minute); err != nil || retried != key {
+		t.Fatalf("failed job should be retried, got %+v, %v", retried, err)
+	}
+	if err := s.CompleteRender(ctx, key, []byte{0x89, 'P', 'N', 'G'}, ""); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if job, err := s.GetRender(ctx, key); err != nil || job.Status != RenderDone || string(job.Image) != "\x89PNG" {
+		t.Errorf("done job: %+v, %v", job, err)
+	}
+	if _, err := s.GetRender(ctx, RenderKey{Code: key.Code, Version: "v2", Site: "SFT"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("other version: %v", err)
+	}
+	if n, err := s.PruneRenders(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("prune = %d, %v; want 1", n, err)
+	}
+}
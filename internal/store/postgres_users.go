@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateUser inserts u and fills in its ID and creation time.
+// Returns ErrConflict if the username is taken (case-insensitive).
+func (s *PostgresStore) CreateUser(ctx context.Context, u *User) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO users (username, password_hash, created_at) VALUES ($1, $2, $3) RETURNING id`,
+		u.Username, u.PasswordHash, now,
+	).Scan(&u.ID)
+	if isPostgresUniqueViolation(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("insert user: %w", err)
+	}
+
+	u.CreatedAt = now
+	return nil
+}
+
+// GetUser returns the user with the given ID or ErrNotFound.
+func (s *PostgresStore) GetUser(ctx context.Context, id int64) (*User, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+	return scanPostgresUser(row, fmt.Sprintf("get user %d", id))
+}
+
+// GetUserByUsername looks a user up case-insensitively or returns ErrNotFound.
+func (s *PostgresStore) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE lower(username) = lower($1)`, username)
+	return scanPostgresUser(row, fmt.Sprintf("get user %q", username))
+}
+
+func scanPostgresUser(row *sql.Row, op string) (*User, error) {
+	var u User
+	err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	u.CreatedAt = u.CreatedAt.UTC()
+	return &u, nil
+}
+
+// isPostgresUniqueViolation reports SQLSTATE 23505. Drivers expose the
+// code through a SQLState method, so no driver package is imported.
+func isPostgresUniqueViolation(err error) bool {
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == "23505"
+}
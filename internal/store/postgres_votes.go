@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// VoteComp adds voter's vote and returns the comp's vote count; voting
+// twice keeps one vote. Returns ErrNotFound if the comp does not exist.
+func (s *PostgresStore) VoteComp(ctx context.Context, compID int64, voter string) (int64, error) {
+	return s.updateVote(ctx, compID, fmt.Sprintf("vote for comp %d", compID),
+		`INSERT INTO comp_votes (comp_id, voter, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		compID, voter, time.Now().UTC())
+}
+
+// UnvoteComp withdraws voter's vote, if any, and returns the vote count.
+func (s *PostgresStore) UnvoteComp(ctx context.Context, compID int64, voter string) (int64, error) {
+	return s.updateVote(ctx, compID, fmt.Sprintf("unvote comp %d", compID),
+		`DELETE FROM comp_votes WHERE comp_id = $1 AND voter = $2`, compID, voter)
+}
+
+// updateVote runs change on comp_votes and keeps comps.votes in step with
+// it. The comp row is locked first, so concurrent votes on it queue up
+// rather than skew the count.
+func (s *PostgresStore) updateVote(ctx context.Context, compID int64, op, change string, args ...any) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var votes int64
+	err = tx.QueryRowContext(ctx, `SELECT votes FROM comps WHERE id = $1 FOR UPDATE`, compID).Scan(&votes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, change, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return votes, nil
+	}
+	err = tx.QueryRowContext(ctx,
+		`UPDATE comps SET votes = (SELECT COUNT(*) FROM comp_votes WHERE comp_id = $1) WHERE id = $1 RETURNING votes`,
+		compID).Scan(&votes)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return votes, nil
+}
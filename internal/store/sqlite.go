@@ -0,0 +1,315 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// sqliteMigrations are applied in order; PRAGMA user_version tracks progress.
+// Append new statements, never edit existing ones.
+var sqliteMigrations = []string{
+	`CREATE TABLE comps (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		name        TEXT    NOT NULL,
+		board       TEXT    NOT NULL,
+		notes       TEXT    NOT NULL DEFAULT '',
+		set_version TEXT    NOT NULL DEFAULT '',
+		created_at  INTEGER NOT NULL,
+		updated_at  INTEGER NOT NULL
+	)`,
+	`CREATE TABLE users (
+		id            INTEGER PRIMARY KEY AUTOINCREMENT,
+		username      TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+		password_hash TEXT    NOT NULL,
+		created_at    INTEGER NOT NULL
+	)`,
+	`CREATE TABLE sessions (
+		token_hash TEXT    PRIMARY KEY,
+		user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		expires_at INTEGER NOT NULL
+	)`,
+	`ALTER TABLE comps ADD COLUMN owner_id INTEGER REFERENCES users(id) ON DELETE SET NULL`,
+	`CREATE INDEX comps_owner_id ON comps(owner_id)`,
+	`CREATE TABLE lobbies (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		owner_id   INTEGER REFERENCES users(id) ON DELETE SET NULL,
+		name       TEXT    NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE lobby_players (
+		lobby_id INTEGER NOT NULL REFERENCES lobbies(id) ON DELETE CASCADE,
+		slot     INTEGER NOT NULL,
+		name     TEXT    NOT NULL DEFAULT '',
+		notes    TEXT    NOT NULL DEFAULT '',
+		comps    TEXT    NOT NULL DEFAULT '',
+		PRIMARY KEY (lobby_id, slot)
+	)`,
+	`CREATE TABLE drill_attempts (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		kind       TEXT    NOT NULL,
+		seed       INTEGER NOT NULL,
+		scenario   TEXT    NOT NULL,
+		answer     TEXT    NOT NULL,
+		correct    INTEGER,
+		created_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX drill_attempts_user_id ON drill_attempts(user_id, created_at)`,
+	`CREATE TABLE short_links (
+		code       TEXT    PRIMARY KEY,
+		board      TEXT    NOT NULL UNIQUE,
+		created_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE render_jobs (
+		code       TEXT    PRIMARY KEY,
+		status     TEXT    NOT NULL,
+		image      BLOB,
+		error      TEXT    NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX render_jobs_status ON render_jobs(status, created_at)`,
+	`CREATE TABLE redirects (
+		from_path  TEXT    PRIMARY KEY,
+		to_path    TEXT    NOT NULL,
+		status     INTEGER NOT NULL,
+		hits       INTEGER NOT NULL DEFAULT 0,
+		last_hit   INTEGER,
+		created_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE session_data (
+		id         TEXT    PRIMARY KEY,
+		data       TEXT    NOT NULL,
+		expires_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX session_data_expires_at ON session_data(expires_at)`,
+	`CREATE TABLE favorites (
+		user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		api_name   TEXT    NOT NULL,
+		created_at INTEGER NOT NULL,
+		PRIMARY KEY (user_id, api_name)
+	)`,
+	`ALTER TABLE comps ADD COLUMN votes INTEGER NOT NULL DEFAULT 0`,
+	`CREATE INDEX comps_votes ON comps(votes DESC, updated_at DESC)`,
+	`CREATE TABLE comp_votes (
+		comp_id    INTEGER NOT NULL REFERENCES comps(id) ON DELETE CASCADE,
+		voter      TEXT    NOT NULL,
+		created_at INTEGER NOT NULL,
+		PRIMARY KEY (comp_id, voter)
+	)`,
+	`CREATE TABLE changelog (
+		version      TEXT    PRIMARY KEY,
+		title        TEXT    NOT NULL,
+		body         TEXT    NOT NULL,
+		published_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX changelog_published ON changelog(published_at DESC)`,
+	// Logins moved into session_data; see auth.Sessions.
+	`DROP TABLE sessions`,
+	// Render jobs are keyed by dataset version and site too; queued renders
+	// are only a cache, so the old table is dropped rather than converted.
+	`DROP TABLE render_jobs`,
+	`CREATE TABLE render_jobs (
+		code       TEXT    NOT NULL,
+		version    TEXT    NOT NULL,
+		site       TEXT    NOT NULL,
+		status     TEXT    NOT NULL,
+		image      BLOB,
+		error      TEXT    NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		PRIMARY KEY (code, version, site)
+	)`,
+	`CREATE INDEX render_jobs_status ON render_jobs(status, created_at)`,
+	`CREATE INDEX render_jobs_updated ON render_jobs(updated_at)`,
+}
+
+// SQLiteStore implements the store interfaces on a SQLite database file.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (or creates) the database at path and applies pending migrations.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	// SQLite allows a single writer; serializing avoids SQLITE_BUSY under load.
+	db.SetMaxOpenConns(1)
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// migrateSQLite applies pending migrations under a write lock, so several
+// processes opening the same file (web and render workers) cannot race.
+func migrateSQLite(db *sql.DB) (err error) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_, _ = conn.ExecContext(ctx, `ROLLBACK`)
+		}
+	}()
+
+	var version int
+	if err := conn.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	for i := version; i < len(sqliteMigrations); i++ {
+		if _, err := conn.ExecContext(ctx, sqliteMigrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	if version < len(sqliteMigrations) {
+		// PRAGMA does not accept bound parameters.
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, len(sqliteMigrations))); err != nil {
+			return err
+		}
+	}
+	_, err = conn.ExecContext(ctx, `COMMIT`)
+	return err
+}
+
+// Ping checks that the database is reachable.
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close releases the underlying database handle.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// CreateComp inserts c and fills in its ID and timestamps.
+func (s *SQLiteStore) CreateComp(ctx context.Context, c *Comp) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO comps (name, board, notes, set_version, owner_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.Name, c.Board, c.Notes, c.SetVersion, nullID(c.OwnerID), now.Unix(), now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert comp: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert comp: %w", err)
+	}
+
+	c.ID = id
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return nil
+}
+
+// GetComp returns the comp with the given ID or ErrNotFound.
+func (s *SQLiteStore) GetComp(ctx context.Context, id int64) (*Comp, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+compColumns+` FROM comps WHERE id = ?`, id)
+
+	c, err := scanComp(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get comp %d: %w", id, err)
+	}
+	return c, nil
+}
+
+// ListComps returns comps in the given order; an unknown order is CompSortNew.
+func (s *SQLiteStore) ListComps(ctx context.Context, sort CompSort, limit int) ([]Comp, error) {
+	order := `updated_at DESC, id DESC`
+	if sort == CompSortTop {
+		order = `votes DESC, ` + order
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+compColumns+` FROM comps ORDER BY `+order+` LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list comps: %w", err)
+	}
+	return collectComps(rows)
+}
+
+// ListCompsByOwner returns a user's comps, most recently updated first.
+func (s *SQLiteStore) ListCompsByOwner(ctx context.Context, ownerID int64, limit int) ([]Comp, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+compColumns+` FROM comps WHERE owner_id = ? ORDER BY updated_at DESC, id DESC LIMIT ?`, ownerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list comps for user %d: %w", ownerID, err)
+	}
+	return collectComps(rows)
+}
+
+func collectComps(rows *sql.Rows) ([]Comp, error) {
+	defer rows.Close()
+
+	comps := []Comp{}
+	for rows.Next() {
+		c, err := scanComp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list comps: %w", err)
+		}
+		comps = append(comps, *c)
+	}
+	return comps, rows.Err()
+}
+
+// DeleteComp removes the comp with the given ID or returns ErrNotFound.
+func (s *SQLiteStore) DeleteComp(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM comps WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete comp %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete comp %d: %w", id, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const compColumns = `id, name, board, notes, set_version, owner_id, votes, created_at, updated_at`
+
+func scanComp(row rowScanner) (*Comp, error) {
+	var c Comp
+	var owner sql.NullInt64
+	var created, updated int64
+	if err := row.Scan(&c.ID, &c.Name, &c.Board, &c.Notes, &c.SetVersion, &owner, &c.Votes, &created, &updated); err != nil {
+		return nil, err
+	}
+	c.OwnerID = owner.Int64
+	c.CreatedAt = time.Unix(created, 0).UTC()
+	c.UpdatedAt = time.Unix(updated, 0).UTC()
+	return &c, nil
+}
+
+// nullID maps the zero ID to SQL NULL for optional foreign keys.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListChangelog returns up to limit entries, newest first.
+func (s *SQLiteStore) ListChangelog(ctx context.Context, limit int) ([]ChangelogEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT version, title, body, published_at FROM changelog
+		 ORDER BY published_at DESC, version DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list changelog: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []ChangelogEntry{}
+	for rows.Next() {
+		var e ChangelogEntry
+		var published int64
+		if err := rows.Scan(&e.Version, &e.Title, &e.Body, &published); err != nil {
+			return nil, fmt.Errorf("scan changelog entry: %w", err)
+		}
+		e.Published = time.Unix(published, 0).UTC()
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// PutChangelogEntry creates or replaces the entry for e.Version. A zero
+// Published is set to now.
+func (s *SQLiteStore) PutChangelogEntry(ctx context.Context, e *ChangelogEntry) error {
+	if e.Published.IsZero() {
+		e.Published = time.Now()
+	}
+	e.Published = e.Published.UTC().Truncate(time.Second)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO changelog (version, title, body, published_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (version) DO UPDATE SET
+		   title = excluded.title, body = excluded.body, published_at = excluded.published_at`,
+		e.Version, e.Title, e.Body, e.Published.Unix())
+	if err != nil {
+		return fmt.Errorf("put changelog entry %q: %w", e.Version, err)
+	}
+	return nil
+}
+
+// DeleteChangelogEntry removes the entry for version. Returns ErrNotFound
+// if it does not exist.
+func (s *SQLiteStore) DeleteChangelogEntry(ctx context.Context, version string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM changelog WHERE version = ?`, version)
+	if err != nil {
+		return fmt.Errorf("delete changelog entry %q: %w", version, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CreateDrillAttempt inserts a and fills in its ID and creation time.
+func (s *SQLiteStore) CreateDrillAttempt(ctx context.Context, a *DrillAttempt) error {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	var correct sql.NullBool
+	if a.Correct != nil {
+		correct = sql.NullBool{Bool: *a.Correct, Valid: true}
+	}
+	// SQLite integers are signed; the seed round-trips through int64 bit for bit.
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO drill_attempts (user_id, kind, seed, scenario, answer, correct, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.UserID, a.Kind, int64(a.Seed), string(a.Scenario), a.Answer, correct, now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert drill attempt: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert drill attempt: %w", err)
+	}
+
+	a.ID = id
+	a.CreatedAt = now
+	return nil
+}
+
+// ListDrillAttempts returns a user's answers, newest first.
+func (s *SQLiteStore) ListDrillAttempts(ctx context.Context, userID int64, limit int) ([]DrillAttempt, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, kind, seed, scenario, answer, correct, created_at
+		FROM drill_attempts WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ?`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list drill attempts for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	attempts := []DrillAttempt{}
+	for rows.Next() {
+		var a DrillAttempt
+		var seed, created int64
+		var scenario string
+		var correct sql.NullBool
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Kind, &seed, &scenario, &a.Answer, &correct, &created); err != nil {
+			return nil, fmt.Errorf("list drill attempts for user %d: %w", userID, err)
+		}
+		a.Seed = uint64(seed)
+		a.Scenario = []byte(scenario)
+		if correct.Valid {
+			a.Correct = &correct.Bool
+		}
+		a.CreatedAt = time.Unix(created, 0).UTC()
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
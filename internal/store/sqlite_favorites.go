@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListFavorites returns a user's favorite units, most recently added first.
+func (s *SQLiteStore) ListFavorites(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT api_name FROM favorites WHERE user_id = ? ORDER BY created_at DESC, rowid DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list favorites for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	favorites := []string{}
+	for rows.Next() {
+		var apiName string
+		if err := rows.Scan(&apiName); err != nil {
+			return nil, fmt.Errorf("list favorites for user %d: %w", userID, err)
+		}
+		favorites = append(favorites, apiName)
+	}
+	return favorites, rows.Err()
+}
+
+// AddFavorite stars apiName for userID; starring it again is a no-op.
+func (s *SQLiteStore) AddFavorite(ctx context.Context, userID int64, apiName string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO favorites (user_id, api_name, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+		userID, apiName, time.Now().UTC().Unix())
+	if err != nil {
+		return fmt.Errorf("add favorite %q for user %d: %w", apiName, userID, err)
+	}
+	return nil
+}
+
+// RemoveFavorite unstars apiName; removing a missing favorite is a no-op.
+func (s *SQLiteStore) RemoveFavorite(ctx context.Context, userID int64, apiName string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM favorites WHERE user_id = ? AND api_name = ?`, userID, apiName)
+	if err != nil {
+		return fmt.Errorf("remove favorite %q for user %d: %w", apiName, userID, err)
+	}
+	return nil
+}
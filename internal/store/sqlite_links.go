@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// shortCodeAlphabet avoids look-alike characters (0/O, 1/l/I) so codes
+// survive being read aloud or retyped from a screenshot.
+const shortCodeAlphabet = "23456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// shortCodeLen gives 57^7 ≈ 2e12 codes; collisions are retried.
+const (
+	shortCodeLen      = 7
+	shortCodeAttempts = 5
+)
+
+// CreateShortLink returns the code for board, generating one on first use.
+func (s *SQLiteStore) CreateShortLink(ctx context.Context, board string) (*ShortLink, error) {
+	if link, err := s.shortLinkByBoard(ctx, board); !errors.Is(err, ErrNotFound) {
+		return link, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < shortCodeAttempts; i++ {
+		code, err := newShortCode()
+		if err != nil {
+			return nil, fmt.Errorf("generate short code: %w", err)
+		}
+		_, err = s.db.ExecContext(ctx,
+			`INSERT INTO short_links (code, board, created_at) VALUES (?, ?, ?)`,
+			code, board, now.Unix())
+		if err == nil {
+			return &ShortLink{Code: code, Board: board, CreatedAt: now}, nil
+		}
+		if !isUniqueViolation(err) {
+			return nil, fmt.Errorf("insert short link: %w", err)
+		}
+		// Either the code collided or another request just linked the same board.
+		if link, err := s.shortLinkByBoard(ctx, board); !errors.Is(err, ErrNotFound) {
+			return link, err
+		}
+	}
+	return nil, fmt.Errorf("insert short link: %w", ErrConflict)
+}
+
+// GetShortLink loads the link for code. Returns ErrNotFound if it does not exist.
+func (s *SQLiteStore) GetShortLink(ctx context.Context, code string) (*ShortLink, error) {
+	return s.scanShortLink(s.db.QueryRowContext(ctx,
+		`SELECT code, board, created_at FROM short_links WHERE code = ?`, code), code)
+}
+
+func (s *SQLiteStore) shortLinkByBoard(ctx context.Context, board string) (*ShortLink, error) {
+	return s.scanShortLink(s.db.QueryRowContext(ctx,
+		`SELECT code, board, created_at FROM short_links WHERE board = ?`, board), board)
+}
+
+func (s *SQLiteStore) scanShortLink(row *sql.Row, key string) (*ShortLink, error) {
+	var link ShortLink
+	var created int64
+	if err := row.Scan(&link.Code, &link.Board, &created); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get short link %q: %w", key, err)
+	}
+	link.CreatedAt = time.Unix(created, 0).UTC()
+	return &link, nil
+}
+
+func newShortCode() (string, error) {
+	buf := make([]byte, shortCodeLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	// The modulo bias over 57 symbols is negligible for non-secret codes.
+	for i, b := range buf {
+		buf[i] = shortCodeAlphabet[int(b)%len(shortCodeAlphabet)]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// lobbyCompSep joins board codes in lobby_players.comps; codes never contain whitespace.
+const lobbyCompSep = "\n"
+
+// CreateLobby inserts l with LobbySize player seats and fills in its ID and timestamps.
+// Seats missing from l.Players are created empty.
+func (s *SQLiteStore) CreateLobby(ctx context.Context, l *Lobby) error {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO lobbies (owner_id, name, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+		nullID(l.OwnerID), l.Name, now.Unix(), now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+
+	players := seatPlayers(l.Players)
+	for _, p := range players {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO lobby_players (lobby_id, slot, name, notes, comps) VALUES (?, ?, ?, ?, ?)`,
+			id, p.Slot, p.Name, p.Notes, strings.Join(p.Comps, lobbyCompSep),
+		); err != nil {
+			return fmt.Errorf("insert lobby player: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("insert lobby: %w", err)
+	}
+
+	l.ID = id
+	l.Players = players
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	return nil
+}
+
+// GetLobby returns the lobby and its players or ErrNotFound.
+func (s *SQLiteStore) GetLobby(ctx context.Context, id int64) (*Lobby, error) {
+	var l Lobby
+	var owner sql.NullInt64
+	var created, updated int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, owner_id, name, created_at, updated_at FROM lobbies WHERE id = ?`, id,
+	).Scan(&l.ID, &owner, &l.Name, &created, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lobby %d: %w", id, err)
+	}
+	l.OwnerID = owner.Int64
+	l.CreatedAt = time.Unix(created, 0).UTC()
+	l.UpdatedAt = time.Unix(updated, 0).UTC()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT slot, name, notes, comps FROM lobby_players WHERE lobby_id = ? ORDER BY slot`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get lobby %d players: %w", id, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p LobbyPlayer
+		var comps string
+		if err := rows.Scan(&p.Slot, &p.Name, &p.Notes, &comps); err != nil {
+			return nil, fmt.Errorf("get lobby %d players: %w", id, err)
+		}
+		p.Comps = splitLobbyComps(comps)
+		l.Players = append(l.Players, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get lobby %d players: %w", id, err)
+	}
+	return &l, nil
+}
+
+// UpdateLobbyPlayer replaces the seat p.Slot of a lobby or returns ErrNotFound.
+func (s *SQLiteStore) UpdateLobbyPlayer(ctx context.Context, lobbyID int64, p LobbyPlayer) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE lobby_players SET name = ?, notes = ?, comps = ? WHERE lobby_id = ? AND slot = ?`,
+		p.Name, p.Notes, strings.Join(p.Comps, lobbyCompSep), lobbyID, p.Slot,
+	)
+	if err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE lobbies SET updated_at = ? WHERE id = ?`, time.Now().UTC().Unix(), lobbyID,
+	); err != nil {
+		return fmt.Errorf("update lobby %d: %w", lobbyID, err)
+	}
+	return tx.Commit()
+}
+
+// seatPlayers returns exactly LobbySize players, keeping provided seats by slot.
+func seatPlayers(given []LobbyPlayer) []LobbyPlayer {
+	players := make([]LobbyPlayer, LobbySize)
+	for i := range players {
+		players[i].Slot = i + 1
+		players[i].Comps = []string{}
+	}
+	for _, p := range given {
+		if p.Slot >= 1 && p.Slot <= LobbySize {
+			if p.Comps == nil {
+				p.Comps = []string{}
+			}
+			players[p.Slot-1] = p
+		}
+	}
+	return players
+}
+
+func splitLobbyComps(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, lobbyCompSep)
+}
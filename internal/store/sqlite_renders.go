@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EnqueueRender queues key; pending, running and done jobs are left as
+// they are, a failed one is queued again.
+func (s *SQLiteStore) EnqueueRender(ctx context.Context, key RenderKey) error {
+	now := time.Now().UTC().Unix()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO render_jobs (code, version, site, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (code, version, site) DO UPDATE SET
+			status = excluded.status, error = '', created_at = excluded.created_at, updated_at = excluded.updated_at
+		WHERE render_jobs.status = ?`,
+		key.Code, key.Version, key.Site, RenderPending, now, now, RenderFailed)
+	if err != nil {
+		return fmt.Errorf("enqueue render %q: %w", key.Code, err)
+	}
+	return nil
+}
+
+// ClaimRender atomically moves the oldest claimable job to running.
+func (s *SQLiteStore) ClaimRender(ctx context.Context, stale time.Duration) (RenderKey, error) {
+	now := time.Now().UTC()
+	var key RenderKey
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE render_jobs SET status = ?, updated_at = ?
+		WHERE (code, version, site) = (
+			SELECT code, version, site FROM render_jobs
+			WHERE status = ? OR (status = ? AND updated_at < ?)
+			ORDER BY created_at LIMIT 1
+		)
+		RETURNING code, version, site`,
+		RenderRunning, now.Unix(), RenderPending, RenderRunning, now.Add(-stale).Unix(),
+	).Scan(&key.Code, &key.Version, &key.Site)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RenderKey{}, ErrNotFound
+	}
+	if err != nil {
+		return RenderKey{}, fmt.Errorf("claim render: %w", err)
+	}
+	return key, nil
+}
+
+// CompleteRender records the outcome of a claimed job.
+func (s *SQLiteStore) CompleteRender(ctx context.Context, key RenderKey, png []byte, errMsg string) error {
+	status := RenderDone
+	if errMsg != "" {
+		status, png = RenderFailed, nil
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE render_jobs SET status = ?, image = ?, error = ?, updated_at = ?
+		WHERE code = ? AND version = ? AND site = ?`,
+		status, png, errMsg, time.Now().UTC().Unix(), key.Code, key.Version, key.Site)
+	if err != nil {
+		return fmt.Errorf("complete render %q: %w", key.Code, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetRender returns the job for key or ErrNotFound.
+func (s *SQLiteStore) GetRender(ctx context.Context, key RenderKey) (*RenderJob, error) {
+	job := RenderJob{RenderKey: key}
+	var updated int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT status, image, error, updated_at FROM render_jobs WHERE code = ? AND version = ? AND site = ?`,
+		key.Code, key.Version, key.Site,
+	).Scan(&job.Status, &job.Image, &job.Error, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get render %q: %w", key.Code, err)
+	}
+	job.UpdatedAt = time.Unix(updated, 0).UTC()
+	return &job, nil
+}
+
+// PruneRenders deletes jobs last updated before before.
+func (s *SQLiteStore) PruneRenders(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM render_jobs WHERE updated_at < ?`, before.UTC().Unix())
+	if err != nil {
+		return 0, fmt.Errorf("prune renders: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetSessionData returns the values of an unexpired session or ErrNotFound.
+func (s *SQLiteStore) GetSessionData(ctx context.Context, id string) (map[string]string, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data FROM session_data WHERE id = ? AND expires_at > ?`, id, time.Now().Unix(),
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get session data: %w", err)
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, fmt.Errorf("get session data: %w", err)
+	}
+	return values, nil
+}
+
+// PutSessionData creates or replaces the session id. Expired sessions are
+// swept on the way, so the table does not grow with one-time visitors.
+func (s *SQLiteStore) PutSessionData(ctx context.Context, id string, values map[string]string, expiresAt time.Time) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("put session data: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_data WHERE expires_at <= ?`, time.Now().Unix()); err != nil {
+		return fmt.Errorf("sweep session data: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO session_data (id, data, expires_at) VALUES (?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at`,
+		id, string(data), expiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("put session data: %w", err)
+	}
+	return nil
+}
+
+// DeleteSessionData removes the session id, if it exists.
+func (s *SQLiteStore) DeleteSessionData(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_data WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete session data: %w", err)
+	}
+	return nil
+}
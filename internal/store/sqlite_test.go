@@ -0,0 +1,408 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	s, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteStore_CompLifecycle(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	comp := &Comp{Name: "Yordle reroll", Board: "1~001tristana", SetVersion: "abc"}
+	if err := s.CreateComp(ctx, comp); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if comp.ID == 0 || comp.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and timestamps, got %+v", comp)
+	}
+
+	got, err := s.GetComp(ctx, comp.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != comp.Name || got.Board != comp.Board || got.SetVersion != "abc" {
+		t.Errorf("unexpected comp: %+v", got)
+	}
+
+	list, err := s.ListComps(ctx, CompSortNew, 10)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list: %v (%d comps)", err, len(list))
+	}
+
+	if err := s.DeleteComp(ctx, comp.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.GetComp(ctx, comp.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := s.DeleteComp(ctx, comp.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second delete, got %v", err)
+	}
+}
+
+func TestOpenSQLite_ReopenKeepsData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	ctx := context.Background()
+
+	s, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	comp := &Comp{Name: "Saved", Board: "1~"}
+	if err := s.CreateComp(ctx, comp); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	s.Close()
+
+	s, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.GetComp(ctx, comp.ID); err != nil {
+		t.Errorf("expected comp to survive reopen: %v", err)
+	}
+}
+
+func TestSQLiteStore_LobbyLifecycle(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	lobby := &Lobby{Name: "Finals", Players: []LobbyPlayer{{Slot: 3, Name: "Milk"}}}
+	if err := s.CreateLobby(ctx, lobby); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if lobby.ID == 0 || len(lobby.Players) != LobbySize {
+		t.Fatalf("expected ID and %d seats, got %+v", LobbySize, lobby)
+	}
+
+	update := LobbyPlayer{Slot: 3, Name: "Milk", Notes: "fast 8", Comps: []string{"1~001ahri", "1~032garen"}}
+	if err := s.UpdateLobbyPlayer(ctx, lobby.ID, update); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	got, err := s.GetLobby(ctx, lobby.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	seat := got.Players[2]
+	if seat.Name != "Milk" || seat.Notes != "fast 8" || len(seat.Comps) != 2 || seat.Comps[1] != "1~032garen" {
+		t.Errorf("unexpected seat: %+v", seat)
+	}
+	if len(got.Players[0].Comps) != 0 {
+		t.Errorf("expected empty seat 1, got %+v", got.Players[0])
+	}
+
+	if err := s.UpdateLobbyPlayer(ctx, lobby.ID, LobbyPlayer{Slot: 9}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for invalid slot, got %v", err)
+	}
+	if _, err := s.GetLobby(ctx, lobby.ID+1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSQLiteStore_ShortLinks(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	link, err := s.CreateShortLink(ctx, "1~001ahri")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(link.Code) != shortCodeLen {
+		t.Errorf("code %q has length %d, want %d", link.Code, len(link.Code), shortCodeLen)
+	}
+
+	again, err := s.CreateShortLink(ctx, "1~001ahri")
+	if err != nil || again.Code != link.Code {
+		t.Errorf("same board should reuse code %q, got %+v, %v", link.Code, again, err)
+	}
+	other, err := s.CreateShortLink(ctx, "1~001garen")
+	if err != nil || other.Code == link.Code {
+		t.Errorf("different board should get a new code, got %+v, %v", other, err)
+	}
+
+	got, err := s.GetShortLink(ctx, link.Code)
+	if err != nil || got.Board != "1~001ahri" {
+		t.Errorf("get: %+v, %v", got, err)
+	}
+	if _, err := s.GetShortLink(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSQLiteStore_RenderQueue(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.ClaimRender(ctx, time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("empty queue: expected ErrNotFound, got %v", err)
+	}
+	ahri := RenderKey{Code: "1~001ahri", Version: "v1", Site: "SFT"}
+	garen := RenderKey{Code: "1~001garen", Version: "v1", Site: "SFT"}
+	for _, key := range []RenderKey{ahri, garen, ahri} {
+		if err := s.EnqueueRender(ctx, key); err != nil {
+			t.Fatalf("enqueue %s: %v", key.Code, err)
+		}
+	}
+
+	key, err := s.ClaimRender(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if job, _ := s.GetRender(ctx, key); job.Status != RenderRunning {
+		t.Errorf("claimed job status = %q", job.Status)
+	}
+	if err := s.CompleteRender(ctx, key, []byte("png"), ""); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	job, err := s.GetRender(ctx, key)
+	if err != nil || job.Status != RenderDone || string(job.Image) != "png" {
+		t.Errorf("done job = %+v, %v", job, err)
+	}
+	// Another dataset version or site is another image.
+	for _, other := range []RenderKey{{Code: key.Code, Version: "v2", Site: "SFT"}, {Code: key.Code, Version: "v1", Site: "Other"}} {
+		if _, err := s.GetRender(ctx, other); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetRender(%+v) = %v, want ErrNotFound", other, err)
+		}
+	}
+
+	second, err := s.ClaimRender(ctx, time.Minute)
+	if err != nil || second == key {
+		t.Fatalf("second claim = %+v, %v", second, err)
+	}
+	if _, err := s.ClaimRender(ctx, time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Errorf("duplicate enqueue should not add a job, got %v", err)
+	}
+	// A negative staleness window treats every running job as abandoned.
+	if again, err := s.ClaimRender(ctx, -time.Minute); err != nil || again != second {
+		t.Errorf("stale job should be reclaimed, got %+v, %v", again, err)
+	}
+	if err := s.CompleteRender(ctx, second, nil, "boom"); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	if job, _ := s.GetRender(ctx, second); job.Status != RenderFailed || job.Error != "boom" {
+		t.Errorf("failed job = %+v", job)
+	}
+
+	// Enqueueing a failed job queues it again; a done job stays done.
+	if err := s.EnqueueRender(ctx, second); err != nil {
+		t.Fatalf("re-enqueue: %v", err)
+	}
+	if err := s.EnqueueRender(ctx, key); err != nil {
+		t.Fatalf("re-enqueue done: %v", err)
+	}
+	if retried, err := s.ClaimRender(ctx, time.Minute); err != nil || retried != second {
+		t.Errorf("failed job should be retried, got %+v, %v", retried, err)
+	}
+	if job, _ := s.GetRender(ctx, key); job.Status != RenderDone {
+		t.Errorf("done job was requeued: %+v", job)
+	}
+
+	if n, err := s.PruneRenders(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("prune recent = %d, %v", n, err)
+	}
+	if n, err := s.PruneRenders(ctx, time.Now().Add(time.Hour)); err != nil || n != 2 {
+		t.Errorf("prune all = %d, %v; want 2", n, err)
+	}
+	if _, err := s.GetRender(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("pruned job: %v", err)
+	}
+}
+
+func TestSQLiteStore_Redirects(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	r := &Redirect{From: "/units/old", To: "/units/new", Status: 301}
+	if err := s.PutRedirect(ctx, r); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.RecordRedirectHit(ctx, "/units/old"); err != nil {
+			t.Fatalf("hit: %v", err)
+		}
+	}
+	// Replacing the target keeps the hit count.
+	if err := s.PutRedirect(ctx, &Redirect{From: "/units/old", To: "/units/newer", Status: 302}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+
+	got, err := s.ListRedirects(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 1 || got[0].To != "/units/newer" || got[0].Status != 302 || got[0].Hits != 2 || got[0].LastHit == nil {
+		t.Errorf("unexpected redirects: %+v", got)
+	}
+
+	if err := s.DeleteRedirect(ctx, "/units/old"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteRedirect(ctx, "/units/old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSessionDataStores(t *testing.T) {
+	stores := map[string]SessionDataStore{
+		"sqlite": openTestStore(t),
+		"memory": NewMemorySessionData(),
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			later := time.Now().Add(time.Hour)
+
+			if err := s.PutSessionData(ctx, "a", map[string]string{"locale": "fr"}, later); err != nil {
+				t.Fatalf("put: %v", err)
+			}
+			if err := s.PutSessionData(ctx, "a", map[string]string{"locale": "de", "theme": "dark"}, later); err != nil {
+				t.Fatalf("replace: %v", err)
+			}
+			got, err := s.GetSessionData(ctx, "a")
+			if err != nil || got["locale"] != "de" || got["theme"] != "dark" {
+				t.Errorf("get = %v, %v", got, err)
+			}
+
+			if err := s.PutSessionData(ctx, "old", map[string]string{"locale": "fr"}, time.Now().Add(-time.Second)); err != nil {
+				t.Fatalf("put expired: %v", err)
+			}
+			if _, err := s.GetSessionData(ctx, "old"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expired session: err = %v, want ErrNotFound", err)
+			}
+
+			if err := s.DeleteSessionData(ctx, "a"); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if _, err := s.GetSessionData(ctx, "a"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("deleted session: err = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestSQLiteStore_Favorites(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	u := &User{Username: "scout", PasswordHash: "x"}
+	if err := s.CreateUser(ctx, u); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	for _, apiName := range []string{"TFT16_Ahri", "TFT16_Jinx", "TFT16_Ahri"} {
+		if err := s.AddFavorite(ctx, u.ID, apiName); err != nil {
+			t.Fatalf("add %s: %v", apiName, err)
+		}
+	}
+	if err := s.RemoveFavorite(ctx, u.ID, "TFT16_Missing"); err != nil {
+		t.Fatalf("remove missing: %v", err)
+	}
+
+	got, err := s.ListFavorites(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 || got[0] != "TFT16_Jinx" || got[1] != "TFT16_Ahri" {
+		t.Errorf("favorites = %v, want [TFT16_Jinx TFT16_Ahri]", got)
+	}
+
+	if err := s.RemoveFavorite(ctx, u.ID, "TFT16_Jinx"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if got, _ := s.ListFavorites(ctx, u.ID); len(got) != 1 || got[0] != "TFT16_Ahri" {
+		t.Errorf("after remove = %v", got)
+	}
+}
+
+func TestSQLiteStore_CompVotes(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	older := &Comp{Name: "Older", Board: "1~001ahri"}
+	newer := &Comp{Name: "Newer", Board: "1~001jinx"}
+	for _, c := range []*Comp{older, newer} {
+		if err := s.CreateComp(ctx, c); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	steps := []struct {
+		vote  func(context.Context, int64, string) (int64, error)
+		voter string
+		want  int64
+	}{
+		{s.VoteComp, "user:1", 1},
+		{s.VoteComp, "user:1", 1}, // a second vote from the same voter is ignored
+		{s.VoteComp, "ip:abc", 2},
+		{s.UnvoteComp, "user:1", 1},
+		{s.UnvoteComp, "user:1", 1},
+	}
+	for i, step := range steps {
+		got, err := step.vote(ctx, older.ID, step.voter)
+		if err != nil || got != step.want {
+			t.Fatalf("step %d: votes = %d, %v; want %d", i, got, err, step.want)
+		}
+	}
+
+	if _, err := s.VoteComp(ctx, 999, "user:1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("vote on missing comp: expected ErrNotFound, got %v", err)
+	}
+
+	top, err := s.ListComps(ctx, CompSortTop, 10)
+	if err != nil || len(top) != 2 {
+		t.Fatalf("list top: %v (%d comps)", err, len(top))
+	}
+	if top[0].ID != older.ID || top[0].Votes != 1 {
+		t.Errorf("top comp = %+v, want %q with 1 vote", top[0], older.Name)
+	}
+	if latest, _ := s.ListComps(ctx, CompSortNew, 10); len(latest) != 2 || latest[0].ID != newer.ID {
+		t.Errorf("new order = %+v", latest)
+	}
+}
+
+func TestSQLiteStore_Changelog(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []*ChangelogEntry{
+		{Version: "16.1", Title: "Launch", Body: "First set.", Published: day},
+		{Version: "16.2", Title: "Balance", Body: "Nerfs.", Published: day.AddDate(0, 0, 14)},
+		{Version: "16.1", Title: "Launch day", Body: "First set!", Published: day},
+	} {
+		if err := s.PutChangelogEntry(ctx, e); err != nil {
+			t.Fatalf("put %s: %v", e.Version, err)
+		}
+	}
+
+	got, err := s.ListChangelog(ctx, 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 || got[0].Version != "16.2" || got[1].Title != "Launch day" || !got[1].Published.Equal(day) {
+		t.Errorf("changelog = %+v", got)
+	}
+
+	if err := s.DeleteChangelogEntry(ctx, "16.2"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteChangelogEntry(ctx, "16.2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete: expected ErrNotFound, got %v", err)
+	}
+}
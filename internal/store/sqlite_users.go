@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// CreateUser inserts u and fills in its ID and creation time.
+// Returns ErrConflict if the username is taken (case-insensitive).
+func (s *SQLiteStore) CreateUser(ctx context.Context, u *User) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO users (username, password_hash, created_at) VALUES (?, ?, ?)`,
+		u.Username, u.PasswordHash, now.Unix(),
+	)
+	if isUniqueViolation(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("insert user: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert user: %w", err)
+	}
+
+	u.ID = id
+	u.CreatedAt = now
+	return nil
+}
+
+// GetUser returns the user with the given ID or ErrNotFound.
+func (s *SQLiteStore) GetUser(ctx context.Context, id int64) (*User, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+	return scanUserRow(row, fmt.Sprintf("get user %d", id))
+}
+
+// GetUserByUsername looks a user up case-insensitively or returns ErrNotFound.
+func (s *SQLiteStore) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE username = ?`, username)
+	return scanUserRow(row, fmt.Sprintf("get user %q", username))
+}
+
+const userColumns = `id, username, password_hash, created_at`
+
+func scanUserRow(row *sql.Row, op string) (*User, error) {
+	var u User
+	var created int64
+	err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	u.CreatedAt = time.Unix(created, 0).UTC()
+	return &u, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// VoteComp adds voter's vote and returns the comp's vote count; voting
+// twice keeps one vote. Returns ErrNotFound if the comp does not exist.
+func (s *SQLiteStore) VoteComp(ctx context.Context, compID int64, voter string) (int64, error) {
+	return s.updateVote(ctx, compID, fmt.Sprintf("vote for comp %d", compID),
+		`INSERT INTO comp_votes (comp_id, voter, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+		compID, voter, time.Now().UTC().Unix())
+}
+
+// UnvoteComp withdraws voter's vote, if any, and returns the vote count.
+func (s *SQLiteStore) UnvoteComp(ctx context.Context, compID int64, voter string) (int64, error) {
+	return s.updateVote(ctx, compID, fmt.Sprintf("unvote comp %d", compID),
+		`DELETE FROM comp_votes WHERE comp_id = ? AND voter = ?`, compID, voter)
+}
+
+// updateVote runs change on comp_votes and keeps comps.votes in step with
+// it, in one transaction so concurrent votes cannot skew the count.
+func (s *SQLiteStore) updateVote(ctx context.Context, compID int64, op, change string, args ...any) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var votes int64
+	err = tx.QueryRowContext(ctx, `SELECT votes FROM comps WHERE id = ?`, compID).Scan(&votes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, change, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return votes, nil
+	}
+	err = tx.QueryRowContext(ctx,
+		`UPDATE comps SET votes = (SELECT COUNT(*) FROM comp_votes WHERE comp_id = ?) WHERE id = ? RETURNING votes`,
+		compID, compID).Scan(&votes)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return votes, nil
+}
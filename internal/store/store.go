@@ -0,0 +1,231 @@
+// Package store provides persistence for user-created data such as saved comps.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a requested record does not exist.
+	ErrNotFound = errors.New("store: not found")
+	// ErrConflict is returned when a unique constraint (e.g. username) is violated.
+	ErrConflict = errors.New("store: conflict")
+)
+
+// Comp is a saved team composition.
+type Comp struct {
+	ID         int64     `json:"id"`
+	OwnerID    int64     `json:"ownerId,omitempty"` // 0 for anonymous comps
+	Name       string    `json:"name"`
+	Board      string    `json:"board"` // encoded models.BoardState
+	Notes      string    `json:"notes"`
+	SetVersion string    `json:"setVersion"` // dataset version the comp was saved against
+	Votes      int64     `json:"votes"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// CompSort orders the comps gallery.
+type CompSort string
+
+// Comp gallery orders.
+const (
+	CompSortNew CompSort = "new" // most recently updated first
+	CompSortTop CompSort = "top" // most votes first, then newest
+)
+
+// CompStore persists saved comps.
+type CompStore interface {
+	CreateComp(ctx context.Context, c *Comp) error
+	GetComp(ctx context.Context, id int64) (*Comp, error)
+	ListComps(ctx context.Context, sort CompSort, limit int) ([]Comp, error)
+	ListCompsByOwner(ctx context.Context, ownerID int64, limit int) ([]Comp, error)
+	DeleteComp(ctx context.Context, id int64) error
+	Close() error
+}
+
+// CompVoteStore records upvotes on comps. A voter is an opaque key, such
+// as a user ID or a hashed IP address, that votes at most once per comp.
+type CompVoteStore interface {
+	// VoteComp adds voter's vote and returns the comp's vote count; voting
+	// twice keeps one vote. Returns ErrNotFound if the comp does not exist.
+	VoteComp(ctx context.Context, compID int64, voter string) (int64, error)
+	// UnvoteComp withdraws voter's vote, if any, and returns the vote count.
+	UnvoteComp(ctx context.Context, compID int64, voter string) (int64, error)
+}
+
+// User is a registered account.
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// UserStore persists accounts.
+type UserStore interface {
+	CreateUser(ctx context.Context, u *User) error
+	GetUser(ctx context.Context, id int64) (*User, error)
+	GetUserByUsername(ctx context.Context, username string) (*User, error)
+}
+
+// SessionDataStore persists the values of cookie sessions, such as an
+// anonymous visitor's locale and theme, keyed by session ID.
+type SessionDataStore interface {
+	// GetSessionData returns the values of an unexpired session or ErrNotFound.
+	GetSessionData(ctx context.Context, id string) (map[string]string, error)
+	PutSessionData(ctx context.Context, id string, values map[string]string, expiresAt time.Time) error
+	DeleteSessionData(ctx context.Context, id string) error
+}
+
+// FavoriteStore persists the units each user has starred, by unit apiName.
+type FavoriteStore interface {
+	// ListFavorites returns a user's favorites, most recently added first.
+	ListFavorites(ctx context.Context, userID int64) ([]string, error)
+	// AddFavorite stars apiName for userID; starring it again is a no-op.
+	AddFavorite(ctx context.Context, userID int64, apiName string) error
+	// RemoveFavorite unstars apiName; removing a missing favorite is a no-op.
+	RemoveFavorite(ctx context.Context, userID int64, apiName string) error
+}
+
+// LobbySize is the number of players in a TFT lobby.
+const LobbySize = 8
+
+// Lobby is a tournament lobby used to scout opponents.
+type Lobby struct {
+	ID        int64         `json:"id"`
+	OwnerID   int64         `json:"ownerId,omitempty"`
+	Name      string        `json:"name"`
+	Players   []LobbyPlayer `json:"players"` // always LobbySize entries, ordered by slot
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// LobbyPlayer holds the scouting notes for one seat of a lobby.
+type LobbyPlayer struct {
+	Slot  int      `json:"slot"` // 1-based seat number
+	Name  string   `json:"name"`
+	Notes string   `json:"notes"`
+	Comps []string `json:"comps"` // encoded models.BoardState codes
+}
+
+// LobbyStore persists tournament lobbies.
+type LobbyStore interface {
+	CreateLobby(ctx context.Context, l *Lobby) error
+	GetLobby(ctx context.Context, id int64) (*Lobby, error)
+	UpdateLobbyPlayer(ctx context.Context, lobbyID int64, p LobbyPlayer) error
+}
+
+// DrillAttempt is a user's answer to a generated practice drill.
+type DrillAttempt struct {
+	ID        int64           `json:"id"`
+	UserID    int64           `json:"-"`
+	Kind      string          `json:"kind"`
+	Seed      uint64          `json:"seed"`
+	Scenario  json.RawMessage `json:"scenario"` // the drill as generated when answered
+	Answer    string          `json:"answer"`
+	Correct   *bool           `json:"correct"` // nil for ungraded drills
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// DrillStore persists drill answers for later review.
+type DrillStore interface {
+	CreateDrillAttempt(ctx context.Context, a *DrillAttempt) error
+	ListDrillAttempts(ctx context.Context, userID int64, limit int) ([]DrillAttempt, error)
+}
+
+// ShortLink maps a short random code to an encoded board.
+type ShortLink struct {
+	Code      string    `json:"code"`
+	Board     string    `json:"board"` // encoded models.BoardState
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ShortLinkStore persists short permalink codes for boards.
+type ShortLinkStore interface {
+	// CreateShortLink returns the code for board, reusing an existing one.
+	CreateShortLink(ctx context.Context, board string) (*ShortLink, error)
+	GetShortLink(ctx context.Context, code string) (*ShortLink, error)
+}
+
+// Render job states.
+const (
+	RenderPending = "pending"
+	RenderRunning = "running"
+	RenderDone    = "done"
+	RenderFailed  = "failed"
+)
+
+// RenderKey identifies a preview image: the board, the dataset version
+// it is drawn from and the site name printed on it. A data reload changes
+// the version, so stale images are never served for the new data.
+type RenderKey struct {
+	Code    string
+	Version string
+	Site    string
+}
+
+// RenderJob is a queued preview image render.
+type RenderJob struct {
+	RenderKey
+	Status    string
+	Image     []byte // PNG, set once Status is RenderDone
+	Error     string // set when Status is RenderFailed
+	UpdatedAt time.Time
+}
+
+// RenderQueue hands preview renders from web processes to worker processes.
+type RenderQueue interface {
+	// EnqueueRender queues key unless a job for it already exists. A
+	// failed job is queued again, so callers decide when to retry one.
+	EnqueueRender(ctx context.Context, key RenderKey) error
+	// ClaimRender marks the oldest pending job running and returns its key.
+	// Jobs left running longer than stale are reclaimed. Returns ErrNotFound
+	// when the queue is empty.
+	ClaimRender(ctx context.Context, stale time.Duration) (RenderKey, error)
+	// CompleteRender stores the result; a non-empty errMsg marks the job failed.
+	CompleteRender(ctx context.Context, key RenderKey, png []byte, errMsg string) error
+	GetRender(ctx context.Context, key RenderKey) (*RenderJob, error)
+	// PruneRenders deletes jobs not updated since before and returns how
+	// many it removed.
+	PruneRenders(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Redirect sends requests for an old path to its replacement.
+type Redirect struct {
+	From      string     `json:"from"`   // exact path, or a prefix ending in "/*"
+	To        string     `json:"to"`     // target; a trailing "*" receives the matched suffix
+	Status    int        `json:"status"` // http.StatusMovedPermanently or http.StatusFound
+	Hits      int64      `json:"hits"`
+	LastHit   *time.Time `json:"lastHit,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// RedirectStore persists the redirect table and its hit counts.
+type RedirectStore interface {
+	ListRedirects(ctx context.Context) ([]Redirect, error)
+	// PutRedirect creates or replaces the redirect for r.From, keeping its hits.
+	PutRedirect(ctx context.Context, r *Redirect) error
+	DeleteRedirect(ctx context.Context, from string) error
+	RecordRedirectHit(ctx context.Context, from string) error
+}
+
+// ChangelogEntry describes what changed in one data patch.
+type ChangelogEntry struct {
+	Version   string    `json:"version"` // patch label, e.g. "16.2"
+	Title     string    `json:"title"`
+	Body      string    `json:"body"` // markdown
+	Published time.Time `json:"published"`
+}
+
+// ChangelogStore persists patch notes.
+type ChangelogStore interface {
+	// ListChangelog returns up to limit entries, newest first.
+	ListChangelog(ctx context.Context, limit int) ([]ChangelogEntry, error)
+	// PutChangelogEntry creates or replaces the entry for e.Version.
+	PutChangelogEntry(ctx context.Context, e *ChangelogEntry) error
+	DeleteChangelogEntry(ctx context.Context, version string) error
+}
@@ -0,0 +1,176 @@
+// Package tenant lets one deployment serve several branded sites keyed by Host.
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sft/internal/config"
+)
+
+// Known feature names for Profile.Features.
+const (
+	FeatureComps    = "comps"
+	FeatureAccounts = "accounts"
+	FeatureAdmin    = "admin"
+	FeatureLobbies  = "lobbies"
+	FeatureDrills   = "drills"
+	FeatureLinks    = "links" // short permalinks
+)
+
+// Profile describes one site served by the deployment.
+// Empty fields inherit the base configuration.
+type Profile struct {
+	ID          string   `json:"id"`
+	Hosts       []string `json:"hosts"`
+	SiteName    string   `json:"siteName"`
+	SiteURL     string   `json:"siteUrl"`
+	Theme       string   `json:"theme"`
+	SetDataPath string   `json:"setDataPath"`
+	// AssetsDir holds files that shadow the shared static assets for this site.
+	// Defaults to tenants/<id>; it lives outside ./static so sites can't read each other's overrides.
+	AssetsDir string `json:"assetsDir"`
+	// Features lists enabled optional features; nil enables all of them.
+	Features []string `json:"features"`
+	Indexing *bool    `json:"indexing"`
+}
+
+// Enabled reports whether an optional feature is turned on for the profile.
+func (p Profile) Enabled(feature string) bool {
+	if p.Features == nil {
+		return true
+	}
+	for _, f := range p.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply overlays the profile onto a base configuration.
+func (p Profile) Apply(cfg config.Config) config.Config {
+	if p.SiteName != "" {
+		cfg.SiteName = p.SiteName
+	}
+	if p.SiteURL != "" {
+		cfg.SiteURL = p.SiteURL
+	}
+	if p.Theme != "" {
+		cfg.Theme = p.Theme
+	}
+	if p.SetDataPath != "" {
+		cfg.SetDataPath = p.SetDataPath
+	}
+	cfg.StaticOverride = p.AssetsDir
+	if cfg.StaticOverride == "" && p.ID != "" {
+		cfg.StaticOverride = filepath.Join("tenants", p.ID)
+	}
+	if p.Indexing != nil {
+		cfg.Indexing = *p.Indexing
+	}
+	if !p.Enabled(FeatureAdmin) {
+		cfg.AdminToken = ""
+	}
+	return cfg
+}
+
+type profilesFile struct {
+	Sites []Profile `json:"sites"`
+}
+
+// LoadProfiles reads site profiles from a JSON file of the form {"sites": [...]}.
+// The first profile is the default for unknown hosts.
+func LoadProfiles(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var file profilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if len(file.Sites) == 0 {
+		return nil, errors.New("no sites defined in " + path)
+	}
+
+	seen := make(map[string]string)
+	for i, p := range file.Sites {
+		if p.ID == "" {
+			return nil, fmt.Errorf("%s: site %d has no id", path, i)
+		}
+		for _, h := range p.Hosts {
+			h = normalizeHost(h)
+			if other, ok := seen[h]; ok {
+				return nil, fmt.Errorf("%s: host %q used by both %q and %q", path, h, other, p.ID)
+			}
+			seen[h] = p.ID
+		}
+	}
+	return file.Sites, nil
+}
+
+type contextKey struct{}
+
+// FromContext returns the profile serving the request, if any.
+func FromContext(ctx context.Context) (Profile, bool) {
+	p, ok := ctx.Value(contextKey{}).(Profile)
+	return p, ok
+}
+
+// WithProfile returns a copy of ctx carrying the profile.
+func WithProfile(ctx context.Context, p Profile) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// Site pairs a profile with the handler serving it.
+type Site struct {
+	Profile Profile
+	Handler http.Handler
+}
+
+// Router dispatches requests to a site handler by Host header.
+type Router struct {
+	byHost   map[string]Site
+	fallback Site
+}
+
+// NewRouter builds a host router. The first site handles unknown hosts.
+func NewRouter(sites []Site) *Router {
+	r := &Router{byHost: make(map[string]Site)}
+	for i, s := range sites {
+		if i == 0 {
+			r.fallback = s
+		}
+		for _, h := range s.Profile.Hosts {
+			r.byHost[normalizeHost(h)] = s
+		}
+	}
+	return r
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	site, ok := rt.byHost[normalizeHost(r.Host)]
+	if !ok {
+		site = rt.fallback
+	}
+	site.Handler.ServeHTTP(w, r.WithContext(WithProfile(r.Context(), site.Profile)))
+}
+
+// normalizeHost lowercases and strips any port.
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
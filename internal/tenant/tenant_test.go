@@ -0,0 +1,112 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sft/internal/config"
+)
+
+func TestRouter_DispatchesByHost(t *testing.T) {
+	site := func(id string, hosts ...string) Site {
+		return Site{
+			Profile: Profile{ID: id, Hosts: hosts},
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p, _ := FromContext(r.Context())
+				w.Write([]byte(p.ID))
+			}),
+		}
+	}
+	router := NewRouter([]Site{
+		site("main", "example.com"),
+		site("cup", "cup.example.com"),
+	})
+
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{"example.com", "main"},
+		{"CUP.example.com:8080", "cup"},
+		{"unknown.test", "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.expected {
+				t.Errorf("host %q served by %q, want %q", tt.host, rec.Body.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestProfile_Apply(t *testing.T) {
+	indexing := false
+	p := Profile{
+		SiteName: "Cup Builder",
+		SiteURL:  "https://cup.example.com",
+		Features: []string{FeatureComps},
+		Indexing: &indexing,
+	}
+	base := config.Default()
+	base.AdminToken = "secret"
+
+	cfg := p.Apply(base)
+
+	if cfg.SiteName != "Cup Builder" || cfg.SiteURL != "https://cup.example.com" {
+		t.Errorf("branding not applied: %+v", cfg)
+	}
+	if cfg.SetDataPath != base.SetDataPath {
+		t.Error("empty profile fields should inherit the base config")
+	}
+	if cfg.Indexing {
+		t.Error("expected indexing override")
+	}
+	if cfg.AdminToken != "" {
+		t.Error("admin should be disabled when not listed in features")
+	}
+	if !p.Enabled(FeatureComps) || p.Enabled(FeatureAccounts) {
+		t.Error("unexpected feature flags")
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "sites.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	profiles, err := LoadProfiles(write(`{"sites": [{"id": "main", "hosts": ["example.com"]}, {"id": "cup", "hosts": ["cup.example.com"]}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].ID != "main" {
+		t.Errorf("unexpected profiles: %+v", profiles)
+	}
+
+	invalid := map[string]string{
+		"no sites":       `{"sites": []}`,
+		"missing id":     `{"sites": [{"hosts": ["a.com"]}]}`,
+		"duplicate host": `{"sites": [{"id": "a", "hosts": ["a.com"]}, {"id": "b", "hosts": ["A.com"]}]}`,
+		"invalid json":   `{`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadProfiles(write(content)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
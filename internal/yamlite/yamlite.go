@@ -0,0 +1,262 @@
+// Package yamlite parses the small subset of YAML the settings and
+// redirect files need: nested mappings, scalars, lists of scalars in block
+// or [a, b] form, and block lists of mappings. Anchors, multi-line
+// scalars, flow mappings and multiple documents are not supported.
+package yamlite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a Node.
+type Kind int
+
+// Node kinds.
+const (
+	Scalar Kind = iota
+	List
+	Map
+)
+
+// Node is a parsed value. A key without a value is an empty Scalar.
+type Node struct {
+	Kind  Kind
+	Line  int     // 1-based line the value starts on
+	Value string  // Scalar, unquoted
+	Items []*Node // List
+	Keys  []Pair  // Map, in file order
+}
+
+// Pair is one entry of a Map.
+type Pair struct {
+	Key   string
+	Value *Node
+}
+
+type line struct {
+	n      int
+	indent int
+	text   string // without indentation and comment
+}
+
+// Parse reads one document. It returns nil for a document without content.
+func Parse(r io.Reader) (*Node, error) {
+	var lines []line
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		raw := StripComment(scanner.Text())
+		text := strings.TrimSpace(raw)
+		if text == "" || text == "---" {
+			continue
+		}
+		unindented := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(unindented, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", n)
+		}
+		lines = append(lines, line{n: n, indent: len(raw) - len(unindented), text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	p := parser{lines: lines}
+	doc, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.pos].n)
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+// block parses the list or mapping whose entries start at indent.
+func (p *parser) block(indent int) (*Node, error) {
+	if isItem(p.lines[p.pos].text) {
+		return p.list(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *parser) list(indent int) (*Node, error) {
+	node := &Node{Kind: List, Line: p.lines[p.pos].n}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent || (l.indent == indent && !isItem(l.text)) {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.n)
+		}
+
+		content := strings.TrimSpace(strings.TrimPrefix(l.text, "-"))
+		var item *Node
+		var err error
+		switch {
+		case content == "":
+			p.pos++
+			item, err = p.value(indent, l.n)
+		case isMapping(content):
+			// The mapping starts on the item's line, at the column of its
+			// first key.
+			column := indent + len(l.text) - len(content)
+			p.lines[p.pos] = line{n: l.n, indent: column, text: content}
+			item, err = p.mapping(column)
+		default:
+			p.pos++
+			item, err = scalar(content, l.n)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.Items = append(node.Items, item)
+	}
+	return node, nil
+}
+
+func (p *parser) mapping(indent int) (*Node, error) {
+	node := &Node{Kind: Map, Line: p.lines[p.pos].n}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.n)
+		}
+		if isItem(l.text) {
+			return nil, fmt.Errorf("line %d: list item without a key", l.n)
+		}
+		key, value, ok := splitKey(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: want key: value", l.n)
+		}
+		p.pos++
+
+		var child *Node
+		var err error
+		if value == "" {
+			child, err = p.value(indent, l.n)
+		} else {
+			child, err = scalar(value, l.n)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.Keys = append(node.Keys, Pair{Key: key, Value: child})
+	}
+	return node, nil
+}
+
+// value parses what follows a key or list marker without an inline value:
+// a deeper block, a list at the key's own indent, or nothing.
+func (p *parser) value(indent, n int) (*Node, error) {
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if next.indent > indent || (next.indent == indent && isItem(next.text)) {
+			return p.block(next.indent)
+		}
+	}
+	return &Node{Kind: Scalar, Line: n}, nil
+}
+
+// scalar parses an inline value: a scalar or a [a, b] list of scalars.
+func scalar(value string, n int) (*Node, error) {
+	inner, ok := strings.CutPrefix(value, "[")
+	if !ok {
+		v, err := Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		return &Node{Kind: Scalar, Line: n, Value: v}, nil
+	}
+	inner, ok = strings.CutSuffix(inner, "]")
+	if !ok {
+		return nil, fmt.Errorf("line %d: unterminated list %s", n, value)
+	}
+	node := &Node{Kind: List, Line: n}
+	for _, item := range strings.Split(inner, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		v, err := Unquote(item)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		node.Items = append(node.Items, &Node{Kind: Scalar, Line: n, Value: v})
+	}
+	return node, nil
+}
+
+func isItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isMapping(text string) bool {
+	_, _, ok := splitKey(text)
+	return ok
+}
+
+// splitKey splits "key: value" or "key:". A colon inside a scalar such as
+// a URL is not followed by a space, so it does not start a value.
+func splitKey(text string) (key, value string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' || text[0] == '[' {
+		return "", "", false
+	}
+	i := strings.Index(text, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false
+		}
+		i = len(text) - 1
+	}
+	key = strings.TrimSpace(text[:i])
+	return key, strings.TrimSpace(text[i+1:]), key != ""
+}
+
+// Unquote strips double or single quotes from a scalar.
+func Unquote(s string) (string, error) {
+	switch {
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("bad string %s", s)
+		}
+		return v, nil
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return s, nil
+}
+
+// StripComment drops a '#' comment outside quotes. A '#' only starts a
+// comment at the start of a line or after a space, so URL fragments
+// survive.
+func StripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
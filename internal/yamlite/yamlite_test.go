@@ -0,0 +1,84 @@
+package yamlite
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(strings.NewReader(`
+# comment
+site_url: https://tft.example.com/#top
+name: 'It''s TFT'   # quoted
+static:
+  cache: 3600
+origins: [a, "b"]
+hosts:
+- one
+- two
+rules:
+  - from: /help
+    to: /?tab=help
+  - from: /old
+empty:
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Kind != Map || len(doc.Keys) != 7 {
+		t.Fatalf("doc = %+v", doc)
+	}
+	get := func(key string) *Node {
+		for _, p := range doc.Keys {
+			if p.Key == key {
+				return p.Value
+			}
+		}
+		t.Fatalf("missing key %q", key)
+		return nil
+	}
+	if v := get("site_url"); v.Value != "https://tft.example.com/#top" || v.Line != 3 {
+		t.Errorf("site_url = %+v", v)
+	}
+	if v := get("name"); v.Value != "It's TFT" {
+		t.Errorf("name = %q", v.Value)
+	}
+	if v := get("static"); v.Kind != Map || v.Keys[0].Key != "cache" || v.Keys[0].Value.Value != "3600" {
+		t.Errorf("static = %+v", v)
+	}
+	for _, key := range []string{"origins", "hosts"} {
+		if v := get(key); v.Kind != List || len(v.Items) != 2 {
+			t.Errorf("%s = %+v", key, v)
+		}
+	}
+	rules := get("rules")
+	if rules.Kind != List || len(rules.Items) != 2 {
+		t.Fatalf("rules = %+v", rules)
+	}
+	if first := rules.Items[0]; first.Kind != Map || len(first.Keys) != 2 || first.Keys[1].Value.Value != "/?tab=help" {
+		t.Errorf("first rule = %+v", first)
+	}
+	if v := get("empty"); v.Kind != Scalar || v.Value != "" {
+		t.Errorf("empty = %+v", v)
+	}
+
+	if doc, err := Parse(strings.NewReader("# nothing\n---\n")); doc != nil || err != nil {
+		t.Errorf("empty document = %+v, %v", doc, err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	docs := map[string]string{
+		"tab":             "a:\n\tb: 1\n",
+		"no colon":        "a\n",
+		"item in mapping": "a: 1\n- b\n",
+		"indentation":     "a: 1\n  b: 2\n",
+		"open list":       "a: [1, 2\n",
+		"bad string":      `a: "\q"` + "\n",
+	}
+	for name, doc := range docs {
+		if _, err := Parse(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -28,7 +28,7 @@ function ensureDistDir() {
 function cleanOldBundles() {
   if (!fs.existsSync(outdir)) return;
   for (const file of fs.readdirSync(outdir)) {
-    if (/^app-.*\.(js|css|js\.map|css\.map)$/i.test(file) || file === 'manifest.json') {
+    if (/^app-.*\.(js|css|js\.map|css\.map)$/i.test(file)) {
       fs.rmSync(path.join(outdir, file));
     }
   }
@@ -53,7 +53,14 @@ function extractEntryOutput(meta, entryPoint) {
 }
 
 function writeManifest(paths) {
-  const json = JSON.stringify(paths, null, isProd ? 0 : 2);
+  // Keep entries written by other tools, e.g. image variants from `sft gen-images`.
+  let existing = {};
+  try {
+    existing = JSON.parse(fs.readFileSync(manifestPath, 'utf8'));
+  } catch {
+    existing = {};
+  }
+  const json = JSON.stringify({ ...existing, ...paths }, null, isProd ? 0 : 2);
   fs.writeFileSync(manifestPath, json, 'utf8');
 }
 
@@ -7,3 +7,4 @@
 
 import './search-filter.js';
 import './tooltip-floating-ui.js';
+import './data-refresh.js';
@@ -0,0 +1,45 @@
+/**
+ * Data Refresh - prompt to reload after a patch update
+ * Location: static/js/data-refresh.js
+ *
+ * Listens to the server's reload events and reveals the refresh banner
+ * when the champion data or the frontend bundle changed, instead of
+ * letting the tab keep showing stale champions.
+ */
+
+const SELECTORS = {
+  banner: '[data-js="data-refresh"]',
+  button: '[data-js="data-refresh-button"]',
+};
+
+const EVENTS_URL = '/api/events';
+
+function init() {
+  const banner = document.querySelector(SELECTORS.banner);
+  if (!banner || typeof EventSource === 'undefined') return;
+
+  const revision = banner.dataset.revision || '';
+  const url = revision ? `${EVENTS_URL}?current=${encodeURIComponent(revision)}` : EVENTS_URL;
+  const source = new EventSource(url);
+
+  const prompt = () => {
+    banner.hidden = false;
+    source.close();
+  };
+
+  source.addEventListener('dataset', (event) => {
+    const { revision: latest } = JSON.parse(event.data);
+    if (latest !== revision) prompt();
+  });
+  source.addEventListener('assets', prompt);
+
+  banner.querySelector(SELECTORS.button)?.addEventListener('click', () => {
+    window.location.reload();
+  });
+}
+
+if (document.readyState === 'loading') {
+  document.addEventListener('DOMContentLoaded', init);
+} else {
+  init();
+}
@@ -35,7 +35,6 @@ const SELECTORS = {
   tabPanel: '[data-js="tab-panel"]',
   tabContainer: '[data-js="tab-container"]',
   lockIndicator: '[data-js="lock-indicator"]',
-  traitIcon: '.trait-icon[data-icon]',
 };
 
 const STATE_ATTRS = {
@@ -330,20 +329,6 @@ function lockTooltip(tooltip) {
   tooltip.removeAttribute(STATE_ATTRS.locking);
 }
 
-// ============================================
-// TRAIT ICONS INITIALIZATION
-// ============================================
-function initTraitIcons(tooltip) {
-  const icons = tooltip.querySelectorAll(SELECTORS.traitIcon);
-  icons.forEach(icon => {
-    const iconUrl = icon.dataset.icon;
-    if (iconUrl) {
-      icon.style.maskImage = `url('${iconUrl}')`;
-      icon.style.webkitMaskImage = `url('${iconUrl}')`;
-    }
-  });
-}
-
 // ============================================
 // EVENT BINDING
 // ============================================
@@ -545,9 +530,6 @@ function init() {
     tooltip.removeAttribute(STATE_ATTRS.locked);
     tooltip.removeAttribute(STATE_ATTRS.locking);
 
-    // Initialize trait icons
-    initTraitIcons(tooltip);
-
     // Bind events
     bindContainerEvents(container, tooltip);
     bindTooltipEvents(container, tooltip);